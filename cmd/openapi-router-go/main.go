@@ -10,16 +10,35 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/cirocosta/openapi-router-go/internal/admin"
 	"github.com/cirocosta/openapi-router-go/internal/api"
+	"github.com/cirocosta/openapi-router-go/internal/changelog"
+	"github.com/cirocosta/openapi-router-go/internal/conformance"
+	"github.com/cirocosta/openapi-router-go/internal/merge"
 	"github.com/cirocosta/openapi-router-go/internal/repository"
 	"github.com/cirocosta/openapi-router-go/internal/service"
+	"github.com/cirocosta/openapi-router-go/internal/storage"
 	"github.com/cirocosta/openapi-router-go/pkg/router"
 )
 
+// build metadata of the binary, stamped at release with e.g.
+//
+//	-ldflags "-X main.buildVersion=v1.2.3 -X main.buildCommit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%FT%TZ)"
+//
+// and documented in the generated and served specs
+var (
+	buildVersion string
+	buildCommit  string
+	buildTime    string
+)
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -34,6 +53,14 @@ func main() {
 		runServer()
 	case "openapi-gen":
 		generateOpenAPI()
+	case "changelog":
+		generateChangelog()
+	case "conformance":
+		checkConformance()
+	case "merge":
+		mergeSpecs()
+	case "errors-gen":
+		generateErrorCatalog()
 	default:
 		fmt.Printf("Unknown command: %s\n", cmd)
 		printUsage()
@@ -48,6 +75,10 @@ Usage: openapi-router-go <command> [options]
 Commands:
   run          Start the HTTP server
   openapi-gen  Generate OpenAPI documentation
+  changelog    Summarize API changes between two OpenAPI specs
+  conformance  Score an OpenAPI spec against an API style guide profile
+  merge        Combine several services' OpenAPI specs into one gateway document
+  errors-gen   Generate the catalog of the API's error codes
 
 Run 'openapi-router-go <command> -h' for more information on a command.
 `)
@@ -56,6 +87,20 @@ Run 'openapi-router-go <command> -h' for more information on a command.
 func runServer() {
 	// define command-line flags
 	addr := flag.String("addr", ":8080", "HTTP server address")
+	attachmentsDir := flag.String("attachments-dir", filepath.Join(os.TempDir(), "openapi-router-go", "attachments"), "Directory storing attachments when no S3 endpoint is set")
+	s3Endpoint := flag.String("s3-endpoint", "", "S3-compatible endpoint storing attachments (credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+	s3Region := flag.String("s3-region", "us-east-1", "Region of the S3-compatible endpoint")
+	s3Bucket := flag.String("s3-bucket", "attachments", "Bucket storing attachments")
+	strictRoutes := flag.Bool("strict-routes", false, "Refuse to start when a route's handler disagrees with its documentation or a route was never registered")
+	adminAddr := flag.String("admin-addr", "", "Admin API address, disabled when empty (the bearer token is read from ADMIN_TOKEN)")
+	allow := flag.String("allow", "", "Comma-separated CIDR ranges allowed to call the API, all when empty")
+	deny := flag.String("deny", "", "Comma-separated CIDR ranges denied from calling the API")
+	trustedProxies := flag.String("trusted-proxies", "", "Comma-separated CIDR ranges of proxies trusted to report client addresses in Forwarded and X-Forwarded-For")
+	slowThreshold := flag.Duration("slow-request-threshold", 0, "Log requests taking longer than this, for routes without their own threshold (0 disables it)")
+	maxConcurrency := flag.Int("max-concurrency", 0, "Requests served at once before rejecting with 503 (0 for no limit)")
+	middlewareTiming := flag.Bool("middleware-timing", false, "Measure the time spent in each middleware layer and handler, listed by the admin API")
+	corsOrigins := flag.String("cors-origins", "", "Comma-separated origins allowed to call the API from browsers, documented in the served spec; CORS is disabled when empty")
+	adminAllow := flag.String("admin-allow", "", "Comma-separated CIDR ranges allowed to call the admin API, all when empty")
 	flag.Parse()
 
 	// setup logger
@@ -67,8 +112,85 @@ func runServer() {
 	todoRepo := repository.NewInMemoryTodoRepository()
 	todoService := service.NewTodoService(todoRepo)
 
+	var store storage.Storage
+	if *s3Endpoint != "" {
+		store = storage.NewS3Storage(storage.S3Config{
+			Endpoint:        *s3Endpoint,
+			Region:          *s3Region,
+			Bucket:          *s3Bucket,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		})
+	} else {
+		fsStore, err := storage.NewFilesystemStorage(*attachmentsDir)
+		if err != nil {
+			logger.Error("storage setup error", "error", err)
+			os.Exit(1)
+		}
+		store = fsStore
+	}
+
+	attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(),
+		store, service.DefaultAttachmentLimits)
+
+	commentService := service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository())
+
 	// create router
-	r := api.NewRouter(todoService)
+	r := api.NewRouter(todoService, attachmentService, commentService)
+
+	// serve the spec of the running server, generated on first request
+	r.WithInfo("Sample Router API", "A sample API using the custom router wrapper", "1.0.0").
+		WithBuildInfo(buildVersion, buildCommit, buildTime).
+		ServeSpec("/openapi.json")
+
+	proxies, err := router.ParsePrefixes(strings.Split(*trustedProxies, ","))
+	if err != nil {
+		logger.Error("trusted proxies setup error", "error", err)
+		os.Exit(1)
+	}
+	r.WithTrustedProxies(proxies...)
+
+	if *maxConcurrency > 0 {
+		r.WithConcurrencyLimit(*maxConcurrency)
+	}
+
+	if *slowThreshold > 0 {
+		r.WithSlowRequestThreshold(*slowThreshold)
+	}
+
+	if *middlewareTiming {
+		r.WithMiddlewareTiming()
+	}
+
+	if *allow != "" || *deny != "" {
+		filter, err := router.ParseIPFilter(strings.Split(*allow, ","), strings.Split(*deny, ","))
+		if err != nil {
+			logger.Error("ip filter setup error", "error", err)
+			os.Exit(1)
+		}
+		r.WithIPFilter(filter)
+	}
+
+	if *corsOrigins != "" {
+		r.WithCORS(router.CORSPolicy{
+			AllowedOrigins: strings.Split(*corsOrigins, ","),
+			AllowedHeaders: []string{"Authorization", "Content-Type", "X-Timezone", router.DefaultVersionHeader},
+			ExposedHeaders: []string{"Location", "Content-Disposition", "Retry-After"},
+			MaxAge:         10 * time.Minute,
+			Document:       true,
+		})
+	}
+
+	// check that typed handlers match their documentation and every route was registered
+	if issues := r.Verify(); len(issues) > 0 {
+		for _, issue := range issues {
+			logger.Warn("route verification issue", "route", issue.Route, "issue", issue.Message)
+		}
+		if *strictRoutes {
+			logger.Error("route verification failed", "issues", len(issues))
+			os.Exit(1)
+		}
+	}
 
 	// create server
 	server := &http.Server{
@@ -76,6 +198,28 @@ func runServer() {
 		Handler: r,
 	}
 
+	// create the admin server on its own listener, so it is never exposed
+	// together with the public API
+	var adminServer *http.Server
+	if *adminAddr != "" {
+		token := os.Getenv("ADMIN_TOKEN")
+		if token == "" {
+			logger.Error("admin setup error", "error", "ADMIN_TOKEN must be set when -admin-addr is")
+			os.Exit(1)
+		}
+
+		adminFilter, err := router.ParseIPFilter(strings.Split(*adminAllow, ","), nil)
+		if err != nil {
+			logger.Error("admin setup error", "error", err)
+			os.Exit(1)
+		}
+
+		adminServer = &http.Server{
+			Addr:    *adminAddr,
+			Handler: adminFilter.Middleware(admin.NewHandler(r, token)),
+		}
+	}
+
 	// create context that listens for interrupts
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -89,6 +233,16 @@ func runServer() {
 		}
 	}()
 
+	if adminServer != nil {
+		go func() {
+			logger.Info("starting admin server", "addr", *adminAddr)
+			if err := adminServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("admin server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// wait for interrupt
 	<-ctx.Done()
 
@@ -97,6 +251,12 @@ func runServer() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if adminServer != nil {
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("admin server shutdown error", "error", err)
+		}
+	}
+
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("server shutdown error", "error", err)
 		os.Exit(1)
@@ -110,21 +270,111 @@ func generateOpenAPI() {
 	output := flag.String("o", "openapi.json", "Output file path")
 	title := flag.String("title", "OpenAPI Router Go", "API title")
 	description := flag.String("description", "An API using the OpenAPI router generator", "API description")
-	version := flag.String("version", "1.0.0", "API version")
+	version := flag.String("version", defaultVersion(), "API version (defaults to the version the binary was built as)")
+	tags := flag.String("tag", "", "Only include operations with these tags (comma-separated)")
+	codeSamples := flag.String("code-samples", "", "Emit x-codeSamples in these languages (comma-separated: curl, go)")
+	serverURL := flag.String("server-url", "http://localhost:8080", "Server URL used in code samples")
+	omitZero := flag.String("omitzero", "optional", "How fields tagged omitzero are documented: optional, nullable or required")
+	pointers := flag.String("pointers", "required", "How pointer fields without omitempty are documented: required, nullable or optional")
+	strictExamples := flag.Bool("strict-examples", false, "Fail when an example tag doesn't conform to its field's schema")
+	strictOperationIDs := flag.Bool("strict-operation-ids", false, "Fail when routes derive the same operation ID instead of suffixing it")
+	charset := flag.String("charset", "", "Declare this charset on JSON and text media types, e.g. utf-8 for application/json; charset=utf-8")
+	namespace := flag.String("component-namespace", "", "Prefix component names with this service identifier, e.g. TodoService for TodoService_Todo")
+	declarationOrder := flag.Bool("declaration-order", false, "Keep required, enum and tags arrays in declaration order instead of sorting them")
+	examples := flag.String("examples", "", "Merge the examples recorded from real traffic in this file into the spec")
+	fragment := flag.String("fragment", "", "Deep-merge the hand-written OpenAPI fragment in this JSON or YAML file into the spec")
+	parallelism := flag.Int("parallelism", 0, "Reflect the routes' types on this many goroutines before generating the spec (0 generates sequentially)")
 	flag.Parse()
 
 	// TODO(cc): this is not amazing, we should be able to arrive at
 	// openapi.json without having to truly instantiate anything...
 	todoRepo := repository.NewInMemoryTodoRepository()
 	todoService := service.NewTodoService(todoRepo)
+	attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(),
+		nil, service.DefaultAttachmentLimits)
+
+	commentService := service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository())
 
 	// create router to get routes
-	r := api.NewRouter(todoService)
+	r := api.NewRouter(todoService, attachmentService, commentService)
 
 	// create OpenAPI generator
-	generator := router.NewOpenAPIGenerator(*title, *description, *version, r.GetRoutes())
+	generator := specGenerator(r, *title, *description, *version)
+	switch *omitZero {
+	case "optional":
+		generator.SchemaOptions.OmitZero = router.OmitZeroOptional
+	case "nullable":
+		generator.SchemaOptions.OmitZero = router.OmitZeroNullable
+	case "required":
+		generator.SchemaOptions.OmitZero = router.OmitZeroRequired
+	default:
+		panic(fmt.Errorf("unknown omitzero policy '%s'", *omitZero))
+	}
+	switch *pointers {
+	case "required":
+		generator.SchemaOptions.Pointers = router.PointersRequired
+	case "nullable":
+		generator.SchemaOptions.Pointers = router.PointersNullable
+	case "optional":
+		generator.SchemaOptions.Pointers = router.PointersOptional
+	default:
+		panic(fmt.Errorf("unknown pointer policy '%s'", *pointers))
+	}
+	generator.ComponentNamespace = *namespace
+	generator.Charset = *charset
+	generator.DeclarationOrder = *declarationOrder
+	generator.Parallelism = *parallelism
+	if *examples != "" {
+		recorded, err := router.ReadRecordedExamples(*examples)
+		if err != nil {
+			panic(err)
+		}
+		generator.RecordedExamples = recorded
+	}
+	if *fragment != "" {
+		merged, err := router.ReadSpecFragment(*fragment)
+		if err != nil {
+			panic(err)
+		}
+		generator.Fragments = append(generator.Fragments, merged)
+	}
+	if *codeSamples != "" {
+		if err := generator.RegisterCodeSamples(*serverURL, strings.Split(*codeSamples, ",")...); err != nil {
+			panic(fmt.Errorf("register code samples: %w", err))
+		}
+	}
+
+	spec := generator.Generate()
+
+	if issues := generator.ExampleIssues(); len(issues) > 0 {
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", issue)
+		}
+		if *strictExamples {
+			fmt.Fprintf(os.Stderr, "%d invalid examples\n", len(issues))
+			os.Exit(1)
+		}
+	}
+
+	if collisions := generator.OperationIDCollisions(); len(collisions) > 0 {
+		for _, collision := range collisions {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", collision)
+		}
+		if *strictOperationIDs {
+			fmt.Fprintf(os.Stderr, "%d operation ID collisions\n", len(collisions))
+			os.Exit(1)
+		}
+	}
 
-	data, err := json.MarshalIndent(generator.Generate(), "", "  ")
+	for _, conflict := range generator.FragmentConflicts() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", conflict)
+	}
+
+	if *tags != "" {
+		spec = router.FilterByTags(spec, strings.Split(*tags, ",")...)
+	}
+
+	data, err := json.MarshalIndent(spec, "", "  ")
 	if err != nil {
 		panic(fmt.Errorf("marshal openapi spec: %w", err))
 	}
@@ -136,3 +386,238 @@ func generateOpenAPI() {
 
 	fmt.Printf("OpenAPI spec generated at %s\n", *output)
 }
+
+// specGenerator returns the generator of the router's spec, documenting
+// everything the router serves in it, such as its security schemes, servers
+// and fragments, under the info given on the command line
+func specGenerator(r *router.DocRouter, title, description, version string) *router.OpenAPIGenerator {
+	generator := r.OpenAPI()
+	generator.Title = title
+	generator.Description = description
+	generator.Version = version
+	generator.BuildInfo = router.BuildInfo{Version: version, Commit: buildCommit, Time: buildTime}
+	return generator
+}
+
+// defaultVersion is the version the binary was built as, or 1.0.0 for
+// unreleased builds
+func defaultVersion() string {
+	if buildVersion != "" {
+		return buildVersion
+	}
+	return "1.0.0"
+}
+
+func generateErrorCatalog() {
+	// define command-line flags
+	output := flag.String("o", "errors.md", "Output file path")
+	format := flag.String("format", "markdown", "Output format (markdown or json)")
+	title := flag.String("title", "Error codes", "Heading of the Markdown catalog")
+	flag.Parse()
+
+	catalog := api.ErrorCatalog()
+
+	var data []byte
+	switch *format {
+	case "markdown":
+		data = []byte(catalog.Markdown(*title))
+	case "json":
+		var err error
+		if data, err = json.MarshalIndent(catalog.Codes(), "", "  "); err != nil {
+			panic(fmt.Errorf("marshal error catalog: %w", err))
+		}
+	default:
+		fmt.Printf("Unknown format: %s\n", *format)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		panic(fmt.Errorf("write error catalog to file '%s': %w", *output, err))
+	}
+
+	fmt.Printf("Error catalog generated at %s\n", *output)
+}
+
+func generateChangelog() {
+	// define command-line flags
+	title := flag.String("title", "API changes", "Heading of the generated section")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: openapi-router-go changelog [options] <old> <new>\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Specs are file paths or git revisions in the form <ref>:<path>.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	oldSpec, err := loadSpec(flag.Arg(0))
+	if err != nil {
+		panic(fmt.Errorf("load old spec: %w", err))
+	}
+
+	newSpec, err := loadSpec(flag.Arg(1))
+	if err != nil {
+		panic(fmt.Errorf("load new spec: %w", err))
+	}
+
+	fmt.Print(changelog.Diff(oldSpec, newSpec).Markdown(*title))
+}
+
+func checkConformance() {
+	// define command-line flags
+	profilePath := flag.String("profile", "", "JSON profile selecting the enforced rules (defaults to all rules)")
+	format := flag.String("format", "text", "Output format (text or json)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: openapi-router-go conformance [options] <spec>\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "The spec is a file path or a git revision in the form <ref>:<path>.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	profile := conformance.DefaultProfile()
+	if *profilePath != "" {
+		var err error
+		if profile, err = conformance.LoadProfile(*profilePath); err != nil {
+			panic(fmt.Errorf("load profile: %w", err))
+		}
+	}
+
+	spec, err := loadSpec(flag.Arg(0))
+	if err != nil {
+		panic(fmt.Errorf("load spec: %w", err))
+	}
+
+	report := conformance.Check(spec, profile)
+
+	switch *format {
+	case "text":
+		fmt.Print(report.Text())
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			panic(fmt.Errorf("marshal conformance report: %w", err))
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Printf("Unknown format: %s\n", *format)
+		os.Exit(1)
+	}
+}
+
+func mergeSpecs() {
+	// define command-line flags
+	output := flag.String("o", "gateway.json", "Output file path")
+	title := flag.String("title", "API Gateway", "API title")
+	description := flag.String("description", "", "API description")
+	version := flag.String("version", "1.0.0", "API version")
+	servers := flag.String("servers", "", "Gateway server URLs replacing those of the services (comma-separated)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: openapi-router-go merge [options] [<prefix>=]<spec>...\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "Specs are file paths or git revisions in the form <ref>:<path>, optionally\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "preceded by the path prefix the gateway serves them under, e.g. /todos=todos.json.\n\n")
+		flag.PrintDefaults()
+	}
+
+	// options may follow the specs
+	var sources []string
+	for args := os.Args[1:]; ; args = flag.Args()[1:] {
+		if err := flag.CommandLine.Parse(args); err != nil {
+			os.Exit(2)
+		}
+		if flag.NArg() == 0 {
+			break
+		}
+		sources = append(sources, flag.Arg(0))
+	}
+
+	if len(sources) < 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var services []merge.Service
+	for _, source := range sources {
+		prefix, path, found := strings.Cut(source, "=")
+		if !found {
+			prefix, path = "", source
+		}
+
+		spec, err := loadSpec(path)
+		if err != nil {
+			panic(fmt.Errorf("load spec: %w", err))
+		}
+
+		services = append(services, merge.Service{
+			Name:   serviceName(prefix, path),
+			Prefix: prefix,
+			Spec:   spec,
+		})
+	}
+
+	info := map[string]any{"title": *title, "version": *version}
+	if *description != "" {
+		info["description"] = *description
+	}
+
+	var serverURLs []string
+	if *servers != "" {
+		serverURLs = strings.Split(*servers, ",")
+	}
+
+	spec, err := merge.Merge(info, serverURLs, services)
+	if err != nil {
+		panic(err)
+	}
+
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		panic(fmt.Errorf("marshal merged spec: %w", err))
+	}
+
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		panic(fmt.Errorf("write merged spec to file '%s': %w", *output, err))
+	}
+
+	fmt.Printf("Merged spec generated at %s\n", *output)
+}
+
+// serviceName names a merged service after its prefix, or else its spec's
+// file name, e.g. "todos" for /todos=specs/todos.json
+func serviceName(prefix, path string) string {
+	name := strings.ReplaceAll(strings.Trim(prefix, "/"), "/", "_")
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return name
+}
+
+// loadSpec reads a JSON spec from a file, or from git when given <ref>:<path>
+// and no such file exists
+func loadSpec(source string) (map[string]any, error) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) || !strings.Contains(source, ":") {
+			return nil, fmt.Errorf("read '%s': %w", source, err)
+		}
+
+		data, err = exec.Command("git", "show", source).Output()
+		if err != nil {
+			return nil, fmt.Errorf("git show '%s': %w", source, err)
+		}
+	}
+
+	var spec map[string]any
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("decode '%s': %w", source, err)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+func TestSpecGenerator(t *testing.T) {
+	t.Parallel()
+
+	r := router.NewDocRouter().
+		WithInfo("Router API", "", "0.1.0").
+		WithContact("API Team", "api@example.com", "").
+		WithServer("https://api.example.com", "Production").
+		WithSecurityScheme(router.BearerAuth, router.SecurityScheme{Type: "http", Scheme: "bearer"}).
+		MergeSpec(map[string]any{"x-audience": "public"}).
+		WithSpecTransform(func(spec map[string]any) map[string]any {
+			spec["x-transformed"] = true
+			return spec
+		})
+	r.Get("/things", func(w http.ResponseWriter, r *http.Request) {}).WithName("List Things").Register()
+
+	spec := specGenerator(r, "CLI API", "Described on the command line", "2.0.0").Generate()
+
+	// the info given on the command line replaces the router's own
+	assert.Equal(t, map[string]any{
+		"title":       "CLI API",
+		"description": "Described on the command line",
+		"version":     "2.0.0",
+		"contact":     map[string]any{"name": "API Team", "email": "api@example.com"},
+	}, spec["info"])
+
+	assert.Equal(t, []any{map[string]any{"url": "https://api.example.com", "description": "Production"}}, spec["servers"])
+	assert.Contains(t, spec["components"].(map[string]any)["securitySchemes"], router.BearerAuth)
+	assert.Equal(t, "public", spec["x-audience"])
+	assert.Equal(t, true, spec["x-transformed"])
+	assert.Contains(t, spec["paths"], "/things")
+}
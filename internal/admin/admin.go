@@ -0,0 +1,197 @@
+// package admin provides the operational API served on the admin listener
+package admin
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+// CanaryUpdate is the request body changing the traffic split of a canary
+type CanaryUpdate struct {
+	Route   string `json:"route"`   // Method and path of the route, e.g. "GET /todos"
+	Percent int    `json:"percent"` // Share of requests to send to the canary
+}
+
+// RouteEntry describes a route the router serves
+type RouteEntry struct {
+	Method          string   `json:"method"`
+	Path            string   `json:"path"`
+	Name            string   `json:"name,omitempty"`
+	Version         string   `json:"version,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	QueryValidation bool     `json:"query_validation"`
+	TenantScoped    bool     `json:"tenant_scoped"`
+	Shadowed        bool     `json:"shadowed"`
+	CanaryPercent   *int     `json:"canary_percent,omitempty"` // Current split, for routes with a canary
+}
+
+// RouteTable is the live route table of a running instance
+type RouteTable struct {
+	Routes     []RouteEntry `json:"routes"`
+	Middleware []string     `json:"middleware"`  // Router middleware in the order it runs
+	SpecDigest string       `json:"spec_digest"` // SHA-256 of the spec served by the router, whose first half is its ETag
+}
+
+// ErrorResponse is the body of the admin API's errors
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the admin API of a router
+type Handler struct {
+	router *router.DocRouter
+	mux    *http.ServeMux
+	token  string
+
+	digestOnce sync.Once
+	digest     string
+	digestErr  error
+}
+
+// NewHandler creates the admin API for the given router. Every request must
+// carry the token as a bearer token.
+func NewHandler(r *router.DocRouter, token string) *Handler {
+	h := &Handler{
+		router: r,
+		mux:    http.NewServeMux(),
+		token:  token,
+	}
+
+	h.mux.HandleFunc("GET /admin/routes", h.listRoutes)
+	h.mux.HandleFunc("GET /admin/canaries", h.listCanaries)
+	h.mux.HandleFunc("PUT /admin/canaries", h.updateCanary)
+	h.mux.HandleFunc("GET /admin/concurrency", h.listConcurrency)
+	h.mux.HandleFunc("GET /admin/middleware", h.listMiddleware)
+
+	return h
+}
+
+// ServeHTTP authenticates the request before dispatching it
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || h.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// listRoutes handles GET /admin/routes
+func (h *Handler) listRoutes(w http.ResponseWriter, r *http.Request) {
+	percents := map[string]int{}
+	for _, status := range h.router.Canaries() {
+		percents[status.Route] = status.Percent
+	}
+
+	routes := h.router.GetRoutes()
+	table := RouteTable{
+		Routes:     make([]RouteEntry, 0, len(routes)),
+		Middleware: h.router.MiddlewareNames(),
+	}
+
+	for _, route := range routes {
+		entry := RouteEntry{
+			Method:          route.Method,
+			Path:            route.Path,
+			Name:            route.Name,
+			Version:         route.Version,
+			Tags:            route.Tags,
+			QueryValidation: route.QueryValidation,
+			TenantScoped:    route.TenantScoped,
+			Shadowed:        route.Shadowed,
+		}
+		if percent, ok := percents[route.Key()]; ok {
+			entry.CanaryPercent = &percent
+		}
+		table.Routes = append(table.Routes, entry)
+	}
+
+	h.digestOnce.Do(func() {
+		h.digest, h.digestErr = specDigest(h.router)
+	})
+	if h.digestErr != nil {
+		writeError(w, "error generating spec", http.StatusInternalServerError)
+		return
+	}
+	table.SpecDigest = h.digest
+
+	writeJSON(w, table, http.StatusOK)
+}
+
+// specDigest hashes the spec the router serves, encoded as ServeSpec does.
+// Like that spec, it is generated once, on the first request.
+func specDigest(dr *router.DocRouter) (string, error) {
+	// maps are encoded with sorted keys, so the encoding is stable
+	data, err := json.Marshal(dr.OpenAPI().Generate())
+	if err != nil {
+		return "", fmt.Errorf("encode spec: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// listCanaries handles GET /admin/canaries
+func (h *Handler) listCanaries(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.router.Canaries(), http.StatusOK)
+}
+
+// updateCanary handles PUT /admin/canaries
+func (h *Handler) updateCanary(w http.ResponseWriter, r *http.Request) {
+	var update CanaryUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, "invalid request format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.router.SetCanaryPercent(update.Route, update.Percent); err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, h.router.Canaries(), http.StatusOK)
+}
+
+// listConcurrency handles GET /admin/concurrency
+func (h *Handler) listConcurrency(w http.ResponseWriter, r *http.Request) {
+	statuses := h.router.ConcurrencyLimits()
+	if statuses == nil {
+		statuses = []router.ConcurrencyStatus{}
+	}
+
+	writeJSON(w, statuses, http.StatusOK)
+}
+
+// listMiddleware handles GET /admin/middleware
+func (h *Handler) listMiddleware(w http.ResponseWriter, r *http.Request) {
+	timings := h.router.MiddlewareTimings()
+	if timings == nil {
+		timings = []router.MiddlewareTiming{}
+	}
+
+	writeJSON(w, timings, http.StatusOK)
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, data any, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "error encoding response", http.StatusInternalServerError)
+	}
+}
+
+// writeError writes an error response with the given status code
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	writeJSON(w, ErrorResponse{Error: message}, statusCode)
+}
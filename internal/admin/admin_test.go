@@ -0,0 +1,192 @@
+package admin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+func TestCanaries(t *testing.T) {
+	t.Parallel()
+
+	r := router.NewDocRouter()
+	r.Route("GET", "/things", func(w http.ResponseWriter, r *http.Request) {}).
+		WithCanary(func(w http.ResponseWriter, r *http.Request) {}, 5).
+		Register()
+
+	h := NewHandler(r, "secret")
+
+	for name, tc := range map[string]struct {
+		method     string
+		token      string
+		body       string
+		wantStatus int
+		wantBody   any
+	}{
+		"missing token": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   ErrorResponse{Error: "authentication required"},
+		},
+		"wrong token": {
+			method:     http.MethodGet,
+			token:      "guess",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   ErrorResponse{Error: "authentication required"},
+		},
+		"list": {
+			method:     http.MethodGet,
+			token:      "secret",
+			wantStatus: http.StatusOK,
+			wantBody:   []router.CanaryStatus{{Route: "GET /things", Percent: 5}},
+		},
+		"invalid update": {
+			method:     http.MethodPut,
+			token:      "secret",
+			body:       `{"route": "GET /things", "percent": 150}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   ErrorResponse{Error: "percent must be between 0 and 100, got 150"},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, "/admin/canaries", strings.NewReader(tc.body))
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+
+			expected, err := json.Marshal(tc.wantBody)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), rec.Body.String())
+		})
+	}
+}
+
+func TestUpdateCanary(t *testing.T) {
+	t.Parallel()
+
+	r := router.NewDocRouter()
+	r.Route("GET", "/things", func(w http.ResponseWriter, r *http.Request) {}).
+		WithCanary(func(w http.ResponseWriter, r *http.Request) {}, 5).
+		Register()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/canaries", strings.NewReader(`{"route": "GET /things", "percent": 50}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	NewHandler(r, "secret").ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []router.CanaryStatus{{Route: "GET /things", Percent: 50}}, r.Canaries())
+}
+
+func TestRoutes(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := router.NewDocRouter()
+	r.Use(testMiddleware)
+	r.Route("GET", "/things", noop).
+		WithName("List Things").
+		WithTags("Things").
+		WithQueryValidation().
+		WithCanary(noop, 20).
+		Register()
+	r.Route("POST", "/things", noop).
+		WithShadow(noop).
+		Register()
+	r.WithInfo("Things API", "", "1.0.0").ServeSpec("/openapi.json")
+
+	get := func() RouteTable {
+		req := httptest.NewRequest(http.MethodGet, "/admin/routes", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		NewHandler(r, "secret").ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var table RouteTable
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &table))
+		return table
+	}
+
+	table := get()
+
+	percent := 20
+	assert.Equal(t, []RouteEntry{
+		{Method: "GET", Path: "/things", Name: "List Things", Tags: []string{"Things"}, QueryValidation: true, CanaryPercent: &percent},
+		{Method: "POST", Path: "/things", Shadowed: true},
+	}, table.Routes)
+	assert.Equal(t, []string{"github.com/cirocosta/openapi-router-go/internal/admin.testMiddleware"}, table.Middleware)
+
+	// the digest is the hash of the spec the router serves
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	sum := sha256.Sum256(rec.Body.Bytes())
+	assert.Equal(t, hex.EncodeToString(sum[:]), table.SpecDigest)
+	assert.Equal(t, `"`+table.SpecDigest[:32]+`"`, rec.Header().Get("ETag"))
+
+	// the digest is stable for an unchanged route table
+	assert.Equal(t, table.SpecDigest, get().SpecDigest)
+}
+
+func testMiddleware(next http.Handler) http.Handler {
+	return next
+}
+
+func TestConcurrency(t *testing.T) {
+	t.Parallel()
+
+	r := router.NewDocRouter().WithConcurrencyLimit(100)
+	r.Route("GET", "/things", func(w http.ResponseWriter, r *http.Request) {}).
+		WithConcurrencyLimit(10).
+		Register()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/concurrency", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	NewHandler(r, "secret").ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `[
+		{"route": "*", "limit": 100, "in_flight": 0, "rejected": 0},
+		{"route": "GET /things", "limit": 10, "in_flight": 0, "rejected": 0}
+	]`, rec.Body.String())
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	r := router.NewDocRouter().WithMiddlewareTiming()
+	r.Use(testMiddleware)
+	r.Route("GET", "/things", func(w http.ResponseWriter, r *http.Request) {}).Register()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/things", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/middleware", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	NewHandler(r, "secret").ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var timings []router.MiddlewareTiming
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &timings))
+	require.Len(t, timings, 2)
+	assert.Equal(t, "github.com/cirocosta/openapi-router-go/internal/admin.testMiddleware", timings[0].Name)
+	assert.Equal(t, int64(1), timings[0].Calls)
+	assert.Equal(t, router.MiddlewareTiming{Route: "GET /things", Name: router.MiddlewareHandler, Calls: 1, Total: timings[1].Total}, timings[1])
+}
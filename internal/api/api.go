@@ -4,19 +4,27 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/service"
+	"github.com/cirocosta/openapi-router-go/pkg/reqctx"
 	"github.com/cirocosta/openapi-router-go/pkg/router"
 )
 
 // TodoService defines the minimal interface needed by the API
 type TodoService interface {
-	// ListTodos returns all todos
-	ListTodos(ctx context.Context) ([]model.Todo, error)
+	// ListTodos returns the todos matching the filter
+	ListTodos(ctx context.Context, filter model.TodoFilter) ([]model.Todo, error)
+
+	// GetStats counts todos per group
+	GetStats(ctx context.Context, query model.TodoStatsQuery) (model.TodoStatsResponse, error)
 
 	// GetTodo returns a todo by ID
 	GetTodo(ctx context.Context, id string) (model.Todo, error)
@@ -31,33 +39,109 @@ type TodoService interface {
 	DeleteTodo(ctx context.Context, id string) error
 }
 
+// AttachmentService defines the attachment operations needed by the API
+type AttachmentService interface {
+	// Limits returns the limits enforced on uploads
+	Limits() service.AttachmentLimits
+
+	// Upload stores a file and attaches it to a todo
+	Upload(ctx context.Context, todoID, filename, contentType string, size int64, r io.Reader) (model.Attachment, error)
+
+	// Open returns an attachment together with a reader of its contents
+	Open(ctx context.Context, todoID, id string) (model.Attachment, io.ReadCloser, error)
+
+	// Delete removes an attachment and its contents
+	Delete(ctx context.Context, todoID, id string) error
+}
+
+// CommentService defines the comment operations needed by the API
+type CommentService interface {
+	// ListComments returns a page of comments of a todo
+	ListComments(ctx context.Context, todoID, cursor string, limit int) (model.CommentPage, error)
+
+	// GetComment returns a comment of a todo
+	GetComment(ctx context.Context, todoID, id string) (model.Comment, error)
+
+	// CreateComment adds a comment to a todo
+	CreateComment(ctx context.Context, todoID string, req model.CommentRequest) (model.Comment, error)
+
+	// UpdateComment replaces the body of a comment
+	UpdateComment(ctx context.Context, todoID, id string, req model.CommentRequest) (model.Comment, error)
+
+	// DeleteComment removes a comment of a todo
+	DeleteComment(ctx context.Context, todoID, id string) error
+}
+
 // errorSchema is used for documentation of error responses
 type errorSchema struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty" doc:"ID of the request, also sent in the X-Request-ID header; quote it when reporting the error"`
 }
 
 // API holds the components needed to register routes
 type API struct {
-	router      *router.DocRouter
-	todoHandler *TodoHandler
+	router            *router.DocRouter
+	todoHandler       *TodoHandler
+	attachmentHandler *AttachmentHandler
+	attachmentLimits  service.AttachmentLimits
+	commentHandler    *CommentHandler
 }
 
 // NewRouter creates a new router with all routes configured
-func NewRouter(todoService TodoService) *router.DocRouter {
-	// create handler with the provided service
+func NewRouter(todoService TodoService, attachmentService AttachmentService, commentService CommentService) *router.DocRouter {
+	// create handlers with the provided services
 	todoHandler := NewTodoHandler(todoService)
+	attachmentHandler := NewAttachmentHandler(attachmentService)
+	commentHandler := NewCommentHandler(commentService)
 
 	r := router.NewDocRouter()
 
 	// add middleware
-	r.Use(loggerMiddleware)
+	r.Use(loggerMiddleware(r.ClientIP))
 	r.Use(recovererMiddleware)
+	r.Use(localeMiddleware)
+	r.Use(deadlineMiddleware)
+	r.WithSecurityHeaders(router.DefaultSecurityHeaders())
+
+	// page sizes of every paginated listing
+	r.WithCollectionPolicy(router.CollectionPolicy{DefaultPageSize: 20, MaxPageSize: 100, MaxFilterTerms: 10})
+
+	// failures no route documents, such as recovered panics
+	r.WithDefaultResponse("Unexpected error", &model.ErrorResponse{})
+
+	// document the headers consumed by middleware on every route
+	r.WithParameter(router.Parameter{
+		Name:        "Accept-Language",
+		In:          "header",
+		Description: "Preferred language for localized values (e.g. de-DE, en;q=0.8); defaults to en",
+		Schema:      "",
+	})
+	r.WithParameter(router.Parameter{
+		Name:        "X-Timezone",
+		In:          "header",
+		Description: "IANA time zone used for localized times (e.g. Europe/Berlin); unknown zones fall back to UTC",
+		Schema:      "",
+	})
+	r.WithParameter(router.Parameter{
+		Name:        "X-Request-Timeout",
+		In:          "header",
+		Description: "Time the client waits for the response (e.g. 1.5s), after which the server stops working on the request; grpc-timeout is accepted too",
+		Schema:      "",
+	})
+
+	// document the path parameters shared by the todo routes
+	r.WithPathParam("id", "Identifier of the todo")
+	r.WithPathParam("commentId", "Identifier of the comment on the todo")
+	r.WithPathParam("attachmentId", "Identifier of the attachment on the todo")
 
 	// register standard responses with the router
 	api := &API{
-		router:      r,
-		todoHandler: todoHandler,
+		router:            r,
+		todoHandler:       todoHandler,
+		attachmentHandler: attachmentHandler,
+		attachmentLimits:  attachmentService.Limits(),
+		commentHandler:    commentHandler,
 	}
 
 	// define routes
@@ -66,27 +150,33 @@ func NewRouter(todoService TodoService) *router.DocRouter {
 	return r
 }
 
-// loggerMiddleware logs the incoming HTTP request and response
-func loggerMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Wrap the response writer to capture the status code
-		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
-
-		// Call the next handler
-		next.ServeHTTP(ww, r)
-
-		// Log the request
-		duration := time.Since(start)
-		slog.Info("http request",
-			"method", r.Method,
-			"path", r.URL.Path,
-			"status", ww.statusCode,
-			"duration", duration.String(),
-			"user_agent", r.UserAgent(),
-		)
-	})
+// loggerMiddleware logs the incoming HTTP request and response, along with
+// the client address resolved by clientIP
+func loggerMiddleware(clientIP func(r *http.Request) (netip.Addr, bool)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Wrap the response writer to capture the status code
+			ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			// Call the next handler
+			next.ServeHTTP(ww, r)
+
+			// Log the request
+			duration := time.Since(start)
+			addr, _ := clientIP(r)
+			slog.Info("http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", ww.statusCode,
+				"duration", duration.String(),
+				"client_ip", addr.String(),
+				"user_agent", r.UserAgent(),
+				"request_id", router.RequestID(r.Context()),
+			)
+		})
+	}
 }
 
 // responseWriter is a wrapper around http.ResponseWriter that captures the status code
@@ -113,11 +203,10 @@ func recovererMiddleware(next http.Handler) http.Handler {
 					"stack", string(stack),
 					"method", r.Method,
 					"path", r.URL.Path,
+					"request_id", router.RequestID(r.Context()),
 				)
 
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(`{"error":"internal server error"}`))
+				writeError(w, r, "internal server error", http.StatusInternalServerError)
 			}
 		}()
 
@@ -125,20 +214,90 @@ func recovererMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// localeMiddleware stores the request locale and time zone in the request context
+func localeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if locale := reqctx.PreferredLanguage(r.Header.Get("Accept-Language")); locale != "" {
+			ctx = reqctx.WithLocale(ctx, locale)
+		}
+
+		if tz := r.Header.Get("X-Timezone"); tz != "" {
+			loc, err := time.LoadLocation(tz)
+			if err != nil {
+				slog.Debug("ignoring unknown time zone", "timezone", tz, "error", err)
+			} else {
+				ctx = reqctx.WithLocation(ctx, loc)
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// deadlineMiddleware bounds the request context by the timeout the client
+// asked for, so that services and repositories stop working on requests whose
+// client has given up
+func deadlineMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout, ok := reqctx.RequestTimeout(r.Header)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // registerRoutes configures all API routes with documentation
 func (api *API) registerRoutes() {
 	// error schema for documentation
 	errSchema := &errorSchema{}
 
+	// todo routes also serve JSON:API and HAL documents when the client
+	// accepts them; HAL errors stay plain JSON
+	jsonAPIErrors := &model.JSONAPIErrorDocument{}
+
+	// sparse fieldset parameter shared by the todo read routes
+	explode := false
+	fieldsParam := router.Parameter{
+		Name:        "fields",
+		In:          "query",
+		Description: fieldsParamDescription,
+		Schema:      []string{},
+		Enum:        todoFields,
+		Style:       router.StyleForm,
+		Explode:     &explode,
+	}
+
+	// due date range parameters of the todo listing
+	dueBeforeParam := router.Parameter{
+		Name:        "due_before",
+		In:          "query",
+		Description: "Only include todos due before this time (RFC 3339)",
+		Schema:      time.Time{},
+	}
+	dueAfterParam := router.Parameter{
+		Name:        "due_after",
+		In:          "query",
+		Description: "Only include todos due after this time (RFC 3339)",
+		Schema:      time.Time{},
+	}
+
 	// home and health routes with declarative API
-	api.router.Route("GET", "/", homeHandler).
+	api.router.Get("/", homeHandler).
 		WithName("Home").
 		WithDescription("Home page").
 		WithResponse(nil).
 		WithTags("Core").
 		Register()
 
-	api.router.Route("GET", "/health", healthHandler).
+	api.router.Get("/health", healthHandler).
 		WithName("Health Check").
 		WithDescription("API health check endpoint").
 		WithResponse(nil).
@@ -146,29 +305,62 @@ func (api *API) registerRoutes() {
 		Register()
 
 	// todo routes with new declarative API
-	api.router.Route("GET", "/todos", api.todoHandler.ListTodos).
+	todos := api.router.Group("/todos").WithTags("Todos")
+
+	todos.Get("", api.todoHandler.ListTodos).
 		WithName("List Todos").
 		WithDescription("Get all todo items").
+		WithParameter(fieldsParam).
+		WithParameter(dueBeforeParam).
+		WithParameter(dueAfterParam).
+		WithQueryValidation().
 		WithResponse(&model.TodoListResponse{}).
-		WithErrorResponse("400", "Bad Request", errSchema,
-			router.Example{
-				ContentType: "application/json",
-				Value:       `{"code": 400, "message": "invalid query parameters"}`,
-			}).
 		WithErrorResponse("401", "Unauthorized", errSchema,
 			router.Example{
 				ContentType: "application/json",
 				Value:       `{"code": 401, "message": "authentication required"}`,
 			}).
 		WithErrorResponse("500", "Internal Server Error", errSchema).
-		WithTags("Todos").
+		WithAlternateContent("200", model.JSONAPIMediaType, &model.TodoListDocument{}).
+		WithAlternateContent("200", model.HALMediaType, &model.TodoListHAL{}).
+		WithAlternateContent("400", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("401", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("500", model.JSONAPIMediaType, jsonAPIErrors).
+		Register()
+
+	todos.Get("/stats", api.todoHandler.GetStats).
+		WithName("Todo Statistics").
+		WithDescription("Count todo items by status or by creation time bucket").
+		WithParameter(router.Parameter{
+			Name:        "group_by",
+			In:          "query",
+			Description: "Grouping of the counts; time buckets use the creation time in UTC (default status)",
+			Schema:      "",
+			Enum:        model.StatsGroups,
+		}).
+		WithParameter(router.Parameter{
+			Name:        "created_after",
+			In:          "query",
+			Description: "Only count todos created at or after this time (RFC 3339)",
+			Schema:      time.Time{},
+		}).
+		WithParameter(router.Parameter{
+			Name:        "created_before",
+			In:          "query",
+			Description: "Only count todos created before this time (RFC 3339)",
+			Schema:      time.Time{},
+		}).
+		WithQueryValidation().
+		WithResponse(&model.TodoStatsResponse{}).
+		WithErrorResponse("500", "Internal Server Error", errSchema).
 		Register()
 
-	api.router.Route("POST", "/todos", api.todoHandler.CreateTodo).
+	todos.Post("", api.todoHandler.CreateTodo).
 		WithName("Create Todo").
 		WithDescription("Create a new todo item").
 		WithRequest(&model.CreateTodoRequest{}).
 		WithResponse(&model.TodoResponse{}).
+		WithResponseStatus(http.StatusCreated).
 		WithErrorResponse("400", "Bad Request", errSchema,
 			router.Example{
 				ContentType: "application/json",
@@ -178,26 +370,50 @@ func (api *API) registerRoutes() {
 		WithErrorResponse("422", "Unprocessable Entity", errSchema,
 			router.Example{
 				ContentType: "application/json",
-				Value:       `{"code": 422, "message": "title is required"}`,
+				Value:       `{"code": 422, "message": "recurrence requires a due date"}`,
+			}).
+		WithErrorResponse("409", "Conflict", &model.ConflictResponse{},
+			router.Example{
+				ContentType: "application/json",
+				Value:       `{"error": "todo with external id order-1234 already exists", "existing_id": "todo-1", "location": "/todos/todo-1"}`,
 			}).
-		WithTags("Todos").
+		WithResponseHeader("409", "Location", "", "Path of the existing todo item with the same external ID").
+		WithErrorCodes(errInvalidRequestFormat, errTodoExists).
+		WithAlternateContent("201", model.JSONAPIMediaType, &model.TodoDocument{}).
+		WithAlternateContent("201", model.HALMediaType, &model.TodoHAL{}).
+		WithAlternateContent("400", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("401", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("422", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("409", model.JSONAPIMediaType, jsonAPIErrors).
 		Register()
 
-	api.router.Route("GET", "/todos/{id}", api.todoHandler.GetTodo).
+	todos.Get("/{id}", api.todoHandler.GetTodo).
 		WithName("Get Todo").
 		WithDescription("Get a todo item by ID").
+		WithParameter(fieldsParam).
+		WithQueryValidation().
 		WithResponse(&model.TodoResponse{}).
-		WithErrorResponse("400", "Bad Request", errSchema).
+		WithLink("200", "comments", router.Link{
+			Method:      "GET",
+			Path:        "/todos/{id}/comments",
+			Parameters:  map[string]string{"id": "$response.body#/todo/id"},
+			Description: "Comments left on the todo item",
+		}).
 		WithErrorResponse("401", "Unauthorized", errSchema).
 		WithErrorResponse("404", "Not Found", errSchema,
 			router.Example{
 				ContentType: "application/json",
 				Value:       `{"code": 404, "message": "todo item not found"}`,
 			}).
-		WithTags("Todos").
+		WithErrorCodes(errTodoNotFound).
+		WithAlternateContent("200", model.JSONAPIMediaType, &model.TodoDocument{}).
+		WithAlternateContent("200", model.HALMediaType, &model.TodoHAL{}).
+		WithAlternateContent("400", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("401", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("404", model.JSONAPIMediaType, jsonAPIErrors).
 		Register()
 
-	api.router.Route("PUT", "/todos/{id}", api.todoHandler.UpdateTodo).
+	todos.Put("/{id}", api.todoHandler.UpdateTodo).
 		WithName("Update Todo").
 		WithDescription("Update a todo item").
 		WithRequest(&model.UpdateTodoRequest{}).
@@ -206,16 +422,144 @@ func (api *API) registerRoutes() {
 		WithErrorResponse("401", "Unauthorized", errSchema).
 		WithErrorResponse("404", "Not Found", errSchema).
 		WithErrorResponse("422", "Unprocessable Entity", errSchema).
-		WithTags("Todos").
+		WithErrorCodes(errInvalidRequestFormat, errTodoNotFound).
+		WithAlternateContent("200", model.JSONAPIMediaType, &model.TodoDocument{}).
+		WithAlternateContent("200", model.HALMediaType, &model.TodoHAL{}).
+		WithAlternateContent("400", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("401", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("404", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("422", model.JSONAPIMediaType, jsonAPIErrors).
 		Register()
 
-	api.router.Route("DELETE", "/todos/{id}", api.todoHandler.DeleteTodo).
+	todos.Delete("/{id}", api.todoHandler.DeleteTodo).
 		WithName("Delete Todo").
 		WithDescription("Delete a todo item").
+		WithResponseStatus(http.StatusNoContent).
 		WithErrorResponse("400", "Bad Request", errSchema).
 		WithErrorResponse("401", "Unauthorized", errSchema).
 		WithErrorResponse("404", "Not Found", errSchema).
-		WithTags("Todos").
+		WithErrorCodes(errTodoNotFound).
+		WithAlternateContent("400", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("401", model.JSONAPIMediaType, jsonAPIErrors).
+		WithAlternateContent("404", model.JSONAPIMediaType, jsonAPIErrors).
+		Register()
+
+	// comment routes, nested under their todo
+	commentLink := router.Link{
+		Method: "GET",
+		Path:   "/todos/{id}/comments/{commentId}",
+		Parameters: map[string]string{
+			"id":        "$request.path.id",
+			"commentId": "$response.body#/comment/id",
+		},
+		Description: "The comment itself",
+	}
+	todoLink := router.Link{
+		Method:      "GET",
+		Path:        "/todos/{id}",
+		Parameters:  map[string]string{"id": "$request.path.id"},
+		Description: "The todo item the comment belongs to",
+	}
+
+	comments := api.router.Group("/todos/{id}/comments").
+		WithTags("Comments").
+		WithErrorResponse("404", "Not Found", errSchema).
+		WithErrorCodes(errTodoNotFound)
+
+	comments.Get("", api.commentHandler.ListComments).
+		WithName("List Comments").
+		WithDescription("List the comments of a todo item in creation order, one page at a time").
+		WithParameter(router.Parameter{
+			Name:        "cursor",
+			In:          "query",
+			Description: "Opaque cursor returned as next_cursor by the previous page",
+			Schema:      "",
+		}).
+		WithPagination().
+		WithResponse(&model.CommentPage{}).
+		WithLink("200", "nextPage", router.Link{
+			Method: "GET",
+			Path:   "/todos/{id}/comments",
+			Parameters: map[string]string{
+				"id":     "$request.path.id",
+				"cursor": "$response.body#/next_cursor",
+			},
+			Description: "The next page of comments, while next_cursor is present",
+		}).
+		WithLink("200", "todo", todoLink).
+		WithErrorResponse("400", "Bad Request", errSchema).
+		Register()
+
+	comments.Post("", api.commentHandler.CreateComment).
+		WithName("Create Comment").
+		WithDescription("Add a comment to a todo item").
+		WithRequest(&model.CommentRequest{}).
+		WithResponse(&model.CommentResponse{}).
+		WithResponseStatus(http.StatusCreated).
+		WithLink("201", "comment", commentLink).
+		WithLink("201", "todo", todoLink).
+		WithErrorResponse("400", "Bad Request", errSchema).
+		WithErrorResponse("422", "Unprocessable Entity", errSchema).
+		WithErrorCodes(errInvalidRequestFormat).
+		Register()
+
+	comments.Get("/{commentId}", api.commentHandler.GetComment).
+		WithName("Get Comment").
+		WithDescription("Get a comment of a todo item").
+		WithResponse(&model.CommentResponse{}).
+		WithLink("200", "todo", todoLink).
+		WithErrorCodes(errCommentNotFound).
+		Register()
+
+	comments.Put("/{commentId}", api.commentHandler.UpdateComment).
+		WithName("Update Comment").
+		WithDescription("Replace the body of a comment").
+		WithRequest(&model.CommentRequest{}).
+		WithResponse(&model.CommentResponse{}).
+		WithLink("200", "todo", todoLink).
+		WithErrorResponse("400", "Bad Request", errSchema).
+		WithErrorResponse("422", "Unprocessable Entity", errSchema).
+		WithErrorCodes(errInvalidRequestFormat, errCommentNotFound).
+		Register()
+
+	comments.Delete("/{commentId}", api.commentHandler.DeleteComment).
+		WithName("Delete Comment").
+		WithDescription("Delete a comment of a todo item").
+		WithResponseStatus(http.StatusNoContent).
+		WithErrorCodes(errCommentNotFound).
+		Register()
+
+	// attachment routes
+	attachments := api.router.Group("/todos/{id}/attachments").
+		WithTags("Attachments").
+		WithErrorResponse("404", "Not Found", errSchema)
+
+	attachments.Post("", api.attachmentHandler.UploadAttachment).
+		WithName("Upload Attachment").
+		WithDescription(fmt.Sprintf("Attach a file to a todo item. Files may be up to %d bytes and of type %s.",
+			api.attachmentLimits.MaxSize, strings.Join(api.attachmentLimits.ContentTypes, ", "))).
+		WithRequest(&model.AttachmentUpload{}).
+		WithRequestContentType("multipart/form-data").
+		WithResponse(&model.AttachmentResponse{}).
+		WithResponseStatus(http.StatusCreated).
+		WithErrorResponse("400", "Bad Request", errSchema).
+		WithErrorResponse("413", "Payload Too Large", errSchema).
+		WithErrorResponse("415", "Unsupported Media Type", errSchema).
+		WithErrorCodes(errTodoNotFound).
+		Register()
+
+	attachments.Get("/{attachmentId}", api.attachmentHandler.DownloadAttachment).
+		WithName("Download Attachment").
+		WithDescription("Download the contents of an attachment").
+		WithContentResponse("200", "Contents of the file, served with its original media type", "application/octet-stream", nil).
+		WithErrorCodes(errAttachmentNotFound).
+		Register()
+
+	attachments.Delete("/{attachmentId}", api.attachmentHandler.DeleteAttachment).
+		WithName("Delete Attachment").
+		WithDescription("Delete an attachment and its contents").
+		WithResponseStatus(http.StatusNoContent).
+		WithErrorCodes(errAttachmentNotFound).
 		Register()
 }
 
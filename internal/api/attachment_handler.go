@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/service"
+)
+
+// multipartOverhead is the room left for multipart headers and boundaries
+// on top of the maximum attachment size
+const multipartOverhead = 1 << 20
+
+// AttachmentHandler handles HTTP requests for todo attachments
+type AttachmentHandler struct {
+	attachmentService AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler with the given service
+func NewAttachmentHandler(attachmentService AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadAttachment handles POST /todos/{id}/attachments
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	todoID := r.PathValue("id")
+	maxSize := h.attachmentService.Limits().MaxSize
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, r, "file must not be larger than "+strconv.FormatInt(maxSize, 10)+" bytes", http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeError(w, r, "invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, r, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	attachment, err := h.attachmentService.Upload(r.Context(), todoID, header.Filename, contentType, header.Size, file)
+	if err != nil {
+		var notFoundErr repository.ErrTodoNotFound
+		if errors.As(err, &notFoundErr) {
+			writeCodedError(w, r, errTodoNotFound.New())
+			return
+		}
+
+		var invalidErr service.ErrInvalidAttachment
+		if errors.As(err, &invalidErr) {
+			status := http.StatusUnsupportedMediaType
+			if invalidErr.TooLarge {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeError(w, r, invalidErr.Error(), status)
+			return
+		}
+
+		writeError(w, r, "error uploading attachment", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, model.AttachmentResponse{Attachment: attachment}, http.StatusCreated)
+}
+
+// DownloadAttachment handles GET /todos/{id}/attachments/{attachmentId},
+// streaming the file contents
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	attachment, contents, err := h.attachmentService.Open(r.Context(), r.PathValue("id"), r.PathValue("attachmentId"))
+	if err != nil {
+		var notFoundErr repository.ErrAttachmentNotFound
+		if errors.As(err, &notFoundErr) {
+			writeCodedError(w, r, errAttachmentNotFound.New())
+			return
+		}
+		writeError(w, r, "error downloading attachment", http.StatusInternalServerError)
+		return
+	}
+	defer contents.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.Filename,
+	}))
+	w.WriteHeader(http.StatusOK)
+
+	// headers are already sent, so a failed copy can only abort the response
+	io.Copy(w, contents)
+}
+
+// DeleteAttachment handles DELETE /todos/{id}/attachments/{attachmentId}
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	err := h.attachmentService.Delete(r.Context(), r.PathValue("id"), r.PathValue("attachmentId"))
+	if err != nil {
+		var notFoundErr repository.ErrAttachmentNotFound
+		if errors.As(err, &notFoundErr) {
+			writeCodedError(w, r, errAttachmentNotFound.New())
+			return
+		}
+		writeError(w, r, "error deleting attachment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/service"
+	"github.com/cirocosta/openapi-router-go/internal/storage"
+)
+
+// newMultipartRequest builds an upload request with a single file part
+func newMultipartRequest(t *testing.T, path, filename, contentType string, contents []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestAttachments(t *testing.T) {
+	t.Parallel()
+
+	store, err := storage.NewFilesystemStorage(t.TempDir())
+	require.NoError(t, err)
+
+	todoRepo := repository.NewInMemoryTodoRepository()
+	attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(), store,
+		service.AttachmentLimits{MaxSize: 16, ContentTypes: []string{"text/plain"}})
+	r := NewRouter(service.NewTodoService(todoRepo), attachmentService,
+		service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository()))
+
+	// upload
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, newMultipartRequest(t, "/todos/sample-todo-1/attachments", "notes.txt", "text/plain", []byte("hello")))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var created model.AttachmentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "notes.txt", created.Attachment.Filename)
+	assert.Equal(t, int64(5), created.Attachment.Size)
+
+	attachmentPath := "/todos/sample-todo-1/attachments/" + created.Attachment.ID
+
+	// download
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, attachmentPath, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "hello", rec.Body.String())
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=notes.txt`, rec.Header().Get("Content-Disposition"))
+
+	// delete
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, attachmentPath, nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, attachmentPath, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestUploadAttachmentLimits(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		path        string
+		contentType string
+		contents    []byte
+		wantStatus  int
+		wantErr     string
+	}{
+		"unknown todo": {
+			path:        "/todos/missing/attachments",
+			contentType: "text/plain",
+			contents:    []byte("hello"),
+			wantStatus:  http.StatusNotFound,
+			wantErr:     "todo not found",
+		},
+		"unsupported content type": {
+			path:        "/todos/sample-todo-1/attachments",
+			contentType: "application/zip",
+			contents:    []byte("PK"),
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantErr:     "content type must be one of: text/plain",
+		},
+		"too large": {
+			path:        "/todos/sample-todo-1/attachments",
+			contentType: "text/plain",
+			contents:    bytes.Repeat([]byte("a"), 17),
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantErr:     "file must not be larger than 16 bytes",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := storage.NewFilesystemStorage(t.TempDir())
+			require.NoError(t, err)
+
+			todoRepo := repository.NewInMemoryTodoRepository()
+			attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(), store,
+				service.AttachmentLimits{MaxSize: 16, ContentTypes: []string{"text/plain"}})
+			r := NewRouter(service.NewTodoService(todoRepo), attachmentService,
+				service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository()))
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, newMultipartRequest(t, tc.path, "file", tc.contentType, tc.contents))
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+
+			var errResp model.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+			assert.Equal(t, tc.wantErr, errResp.Error)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/service"
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+// CommentHandler handles HTTP requests for comments on todos
+type CommentHandler struct {
+	commentService CommentService
+}
+
+// NewCommentHandler creates a new comment handler with the given service
+func NewCommentHandler(commentService CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// ListComments handles GET /todos/{id}/comments
+func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	// the limit is checked against the router's collection policy
+	requested, _ := router.Pagination(r.Context())
+
+	page, err := h.commentService.ListComments(r.Context(), r.PathValue("id"), requested.Cursor, requested.Limit)
+	if err != nil {
+		writeCommentError(w, r, err, "error listing comments")
+		return
+	}
+
+	writeJSON(w, page, http.StatusOK)
+}
+
+// GetComment handles GET /todos/{id}/comments/{commentId}
+func (h *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
+	comment, err := h.commentService.GetComment(r.Context(), r.PathValue("id"), r.PathValue("commentId"))
+	if err != nil {
+		writeCommentError(w, r, err, "error getting comment")
+		return
+	}
+
+	writeJSON(w, model.CommentResponse{Comment: comment}, http.StatusOK)
+}
+
+// CreateComment handles POST /todos/{id}/comments
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	var req model.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeCodedError(w, r, errInvalidRequestFormat.New())
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(r.Context(), r.PathValue("id"), req)
+	if err != nil {
+		writeCommentError(w, r, err, "error creating comment")
+		return
+	}
+
+	writeJSON(w, model.CommentResponse{Comment: comment}, http.StatusCreated)
+}
+
+// UpdateComment handles PUT /todos/{id}/comments/{commentId}
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	var req model.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeCodedError(w, r, errInvalidRequestFormat.New())
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(r.Context(), r.PathValue("id"), r.PathValue("commentId"), req)
+	if err != nil {
+		writeCommentError(w, r, err, "error updating comment")
+		return
+	}
+
+	writeJSON(w, model.CommentResponse{Comment: comment}, http.StatusOK)
+}
+
+// DeleteComment handles DELETE /todos/{id}/comments/{commentId}
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	if err := h.commentService.DeleteComment(r.Context(), r.PathValue("id"), r.PathValue("commentId")); err != nil {
+		writeCommentError(w, r, err, "error deleting comment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCommentError maps comment service errors to responses
+func writeCommentError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	var (
+		todoNotFound    repository.ErrTodoNotFound
+		commentNotFound repository.ErrCommentNotFound
+		invalidCursor   service.ErrInvalidCursor
+		invalidComment  service.ErrInvalidComment
+	)
+
+	switch {
+	case errors.As(err, &todoNotFound):
+		writeCodedError(w, r, errTodoNotFound.New())
+	case errors.As(err, &commentNotFound):
+		writeCodedError(w, r, errCommentNotFound.New())
+	case errors.As(err, &invalidCursor):
+		writeError(w, r, invalidCursor.Error(), http.StatusBadRequest)
+	case errors.As(err, &invalidComment):
+		writeError(w, r, invalidComment.Error(), http.StatusUnprocessableEntity)
+	default:
+		writeError(w, r, fallback, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/service"
+	"github.com/cirocosta/openapi-router-go/internal/storage"
+)
+
+func TestCommentPagination(t *testing.T) {
+	t.Parallel()
+
+	store, err := storage.NewFilesystemStorage(t.TempDir())
+	require.NoError(t, err)
+
+	todoRepo := repository.NewInMemoryTodoRepository()
+	r := NewRouter(service.NewTodoService(todoRepo),
+		service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(), store, service.DefaultAttachmentLimits),
+		service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository()))
+
+	for _, body := range []string{"first", "second", "third"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/todos/sample-todo-1/comments",
+			strings.NewReader(`{"body":"`+body+`"}`)))
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	var bodies []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3, "pagination should terminate")
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/todos/sample-todo-1/comments?limit=2&cursor="+cursor, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var page model.CommentPage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		for _, comment := range page.Comments {
+			bodies = append(bodies, comment.Body)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, bodies)
+
+	for name, tc := range map[string]struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantErr    string
+	}{
+		"invalid cursor": {
+			method:     http.MethodGet,
+			path:       "/todos/sample-todo-1/comments?cursor=bm9wZQ",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid cursor",
+		},
+		"unknown todo": {
+			method:     http.MethodGet,
+			path:       "/todos/missing/comments",
+			wantStatus: http.StatusNotFound,
+			wantErr:    "todo not found",
+		},
+		"unknown comment": {
+			method:     http.MethodGet,
+			path:       "/todos/sample-todo-1/comments/comment-99",
+			wantStatus: http.StatusNotFound,
+			wantErr:    "comment not found",
+		},
+		"empty body": {
+			method:     http.MethodPut,
+			path:       "/todos/sample-todo-1/comments/comment-1",
+			body:       `{"body":" "}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErr:    "body is required",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+
+			var errResp model.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+			assert.Equal(t, tc.wantErr, errResp.Error)
+		})
+	}
+}
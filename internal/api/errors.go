@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+// errorDocsURL is where the error catalog generated by errors-gen is
+// published; each code has a section anchored by its name
+const errorDocsURL = "https://github.com/cirocosta/openapi-router-go/blob/main/docs/errors.md#"
+
+// errorCatalog holds the error codes the API responds with
+var errorCatalog = router.NewErrorCatalog()
+
+var (
+	errInvalidRequestFormat = registerErrorCode("invalid_request_format", http.StatusBadRequest,
+		"invalid request format")
+	errTodoNotFound = registerErrorCode("todo_not_found", http.StatusNotFound,
+		"todo not found")
+	errCommentNotFound = registerErrorCode("comment_not_found", http.StatusNotFound,
+		"comment not found")
+	errAttachmentNotFound = registerErrorCode("attachment_not_found", http.StatusNotFound,
+		"attachment not found")
+	errTodoExists = registerErrorCode("todo_exists", http.StatusConflict,
+		"todo with external id %s already exists")
+)
+
+// ErrorCatalog returns the error codes the API responds with
+func ErrorCatalog() *router.ErrorCatalog {
+	return errorCatalog
+}
+
+// registerErrorCode adds an error code documented in the published catalog
+func registerErrorCode(code string, status int, message string) router.ErrorCode {
+	return errorCatalog.Register(router.ErrorCode{
+		Code:    code,
+		Status:  status,
+		Message: message,
+		DocsURL: errorDocsURL + code,
+	})
+}
+
+// codedErrorResponse is the body of an error of the catalog
+func codedErrorResponse(r *http.Request, err *router.CodedError) model.ErrorResponse {
+	return model.ErrorResponse{
+		Error:     err.Error(),
+		Code:      err.Code,
+		DocsURL:   err.DocsURL,
+		RequestID: router.RequestID(r.Context()),
+	}
+}
+
+// writeCodedError writes an error of the catalog with its status code
+func writeCodedError(w http.ResponseWriter, r *http.Request, err *router.CodedError) {
+	writeJSON(w, codedErrorResponse(r, err), err.Status)
+}
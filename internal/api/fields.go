@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+)
+
+// fieldsParamDescription documents the sparse fieldset query parameter
+const fieldsParamDescription = "Comma-separated list of todo fields to include in the response (e.g. id,title). " +
+	"When set, fields that are not selected are omitted even if the schema marks them as required."
+
+// todoFields lists the fields that can be selected through the fields query parameter
+var todoFields = jsonFieldNames(reflect.TypeOf(model.Todo{}))
+
+// jsonFieldNames returns the JSON names of the exported fields of a struct type
+func jsonFieldNames(typ reflect.Type) []string {
+	var names []string
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// parseFields extracts the sparse fieldset requested through the fields query
+// parameter, serialized as a comma-separated (non-exploded form) list
+func parseFields(r *http.Request, allowed []string) ([]string, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("unknown field '%s'", field)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// projectFields reduces the JSON representation of v to the selected fields
+func projectFields(v any, fields []string) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal value: %w", err)
+	}
+
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("unmarshal value: %w", err)
+	}
+
+	projected := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if value, exists := all[field]; exists {
+			projected[field] = value
+		}
+	}
+
+	return projected, nil
+}
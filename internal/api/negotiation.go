@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+// jsonMediaType is the media type of the plain JSON representation
+const jsonMediaType = "application/json"
+
+// negotiate picks the media type todo resources are served in from the
+// Accept header. The highest quality wins, ties keep header order, and plain
+// JSON is used when no hypermedia format is accepted. JSON:API instances
+// with parameters other than q are ignored, as that specification requires.
+func negotiate(r *http.Request) string {
+	best, bestQuality := jsonMediaType, 0.0
+
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(part)
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				quality, err = strconv.ParseFloat(q, 64)
+				if err != nil {
+					continue
+				}
+				delete(params, "q")
+			}
+
+			switch mediaType {
+			case jsonMediaType, model.HALMediaType:
+			case model.JSONAPIMediaType:
+				if len(params) > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+
+			if quality > bestQuality {
+				best, bestQuality = mediaType, quality
+			}
+		}
+	}
+
+	return best
+}
+
+// writeTodo writes a single todo item in the given representation
+func writeTodo(w http.ResponseWriter, mediaType string, todo model.Todo, statusCode int) {
+	switch mediaType {
+	case model.JSONAPIMediaType:
+		writeDocument(w, mediaType, model.TodoDocument{Data: model.NewTodoResource(todo)}, statusCode)
+	case model.HALMediaType:
+		writeDocument(w, mediaType, model.NewTodoHAL(todo), statusCode)
+	default:
+		writeJSON(w, model.TodoResponse{Todo: todo}, statusCode)
+	}
+}
+
+// writeTodoList writes a collection of todo items in the given representation
+func writeTodoList(w http.ResponseWriter, mediaType string, todos []model.Todo) {
+	switch mediaType {
+	case model.JSONAPIMediaType:
+		document := model.TodoListDocument{
+			Data: make([]model.TodoResource, 0, len(todos)),
+		}
+		for _, todo := range todos {
+			document.Data = append(document.Data, model.NewTodoResource(todo))
+		}
+
+		writeDocument(w, mediaType, document, http.StatusOK)
+	case model.HALMediaType:
+		document := model.TodoListHAL{
+			Links: model.TodoListHALLinks{Self: model.HALLink{Href: "/todos"}},
+			Embedded: model.TodoListHALEmbedded{
+				Todos: make([]model.TodoHAL, 0, len(todos)),
+			},
+		}
+		for _, todo := range todos {
+			document.Embedded.Todos = append(document.Embedded.Todos, model.NewTodoHAL(todo))
+		}
+
+		writeDocument(w, mediaType, document, http.StatusOK)
+	default:
+		writeJSON(w, model.TodoListResponse{Todos: todos}, http.StatusOK)
+	}
+}
+
+// writeDocument writes a response body of the given media type
+func writeDocument(w http.ResponseWriter, mediaType string, document any, statusCode int) {
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(document); err != nil {
+		http.Error(w, "error encoding response", http.StatusInternalServerError)
+	}
+}
+
+// writeTodoError writes an error response, as JSON:API error objects when
+// the request negotiated JSON:API; HAL has no error format of its own
+func writeTodoError(w http.ResponseWriter, r *http.Request, message string, statusCode int) {
+	writeTodoErrorResponse(w, r, model.ErrorResponse{Error: message, RequestID: router.RequestID(r.Context())}, statusCode)
+}
+
+// writeTodoCodedError writes an error of the catalog like writeTodoError
+func writeTodoCodedError(w http.ResponseWriter, r *http.Request, err *router.CodedError) {
+	writeTodoErrorResponse(w, r, codedErrorResponse(r, err), err.Status)
+}
+
+// writeTodoErrorResponse writes an error body, translated to JSON:API error
+// objects when the request negotiated JSON:API
+func writeTodoErrorResponse(w http.ResponseWriter, r *http.Request, body model.ErrorResponse, statusCode int) {
+	if negotiate(r) != model.JSONAPIMediaType {
+		writeJSON(w, body, statusCode)
+		return
+	}
+
+	writeDocument(w, model.JSONAPIMediaType, model.JSONAPIErrorDocument{
+		Errors: []model.JSONAPIError{{
+			Status: strconv.Itoa(statusCode),
+			Code:   body.Code,
+			Detail: body.Error,
+			Meta:   model.JSONAPIErrorMeta{RequestID: body.RequestID},
+		}},
+	}, statusCode)
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/service"
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		accept string
+		want   string
+	}{
+		"no header":           {accept: "", want: "application/json"},
+		"plain json":          {accept: "application/json", want: "application/json"},
+		"anything":            {accept: "*/*", want: "application/json"},
+		"json api":            {accept: "application/vnd.api+json", want: model.JSONAPIMediaType},
+		"hal":                 {accept: "application/hal+json", want: model.HALMediaType},
+		"among others":        {accept: "text/html, application/vnd.api+json;q=0.9", want: model.JSONAPIMediaType},
+		"highest quality":     {accept: "application/json;q=0.5, application/hal+json", want: model.HALMediaType},
+		"header order on tie": {accept: "application/hal+json, application/vnd.api+json", want: model.HALMediaType},
+		"refused":             {accept: "application/hal+json;q=0", want: "application/json"},
+		"json api extension":  {accept: `application/vnd.api+json; ext="https://example.com/ext"`, want: "application/json"},
+		"malformed header":    {accept: "application/vnd.api+json;;", want: "application/json"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			assert.Equal(t, tc.want, negotiate(req))
+		})
+	}
+}
+
+func TestTodoJSONAPI(t *testing.T) {
+	t.Parallel()
+
+	todoRepo := repository.NewInMemoryTodoRepository()
+	attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(), nil, service.DefaultAttachmentLimits)
+	r := NewRouter(service.NewTodoService(todoRepo), attachmentService,
+		service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository()))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", model.JSONAPIMediaType)
+		req.Header.Set(router.RequestIDHeader, "req-1")
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// single resource
+	rec := get("/todos/sample-todo-1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.JSONAPIMediaType, rec.Header().Get("Content-Type"))
+
+	var document model.TodoDocument
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, "todos", document.Data.Type)
+	assert.Equal(t, "sample-todo-1", document.Data.ID)
+	assert.Equal(t, "Sample Todo", document.Data.Attributes.Title)
+	assert.Equal(t, "/todos/sample-todo-1", document.Data.Links.Self)
+	assert.Equal(t, "/todos/sample-todo-1/comments", document.Data.Relationships.Comments.Links.Related)
+
+	// collection
+	rec = get("/todos")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var list model.TodoListDocument
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "sample-todo-1", list.Data[0].ID)
+
+	// errors
+	for path, want := range map[string]model.JSONAPIErrorDocument{
+		"/todos/missing": {Errors: []model.JSONAPIError{{
+			Status: "404",
+			Code:   "todo_not_found",
+			Detail: "todo not found",
+			Meta:   model.JSONAPIErrorMeta{RequestID: "req-1"},
+		}}},
+		"/todos/sample-todo-1?fields=title": {Errors: []model.JSONAPIError{{
+			Status: "400",
+			Detail: "fields is only supported for application/json responses",
+			Meta:   model.JSONAPIErrorMeta{RequestID: "req-1"},
+		}}},
+	} {
+		rec = get(path)
+		assert.Equal(t, model.JSONAPIMediaType, rec.Header().Get("Content-Type"))
+
+		var errDocument model.JSONAPIErrorDocument
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errDocument))
+		assert.Empty(t, cmp.Diff(want, errDocument), path)
+	}
+}
+
+func TestTodoHAL(t *testing.T) {
+	t.Parallel()
+
+	todoRepo := repository.NewInMemoryTodoRepository()
+	attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(), nil, service.DefaultAttachmentLimits)
+	r := NewRouter(service.NewTodoService(todoRepo), attachmentService,
+		service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository()))
+
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("Accept", model.HALMediaType)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.HALMediaType, rec.Header().Get("Content-Type"))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, map[string]any{"self": map[string]any{"href": "/todos"}}, document["_links"])
+
+	todos := document["_embedded"].(map[string]any)["todos"].([]any)
+	require.Len(t, todos, 1)
+
+	todo := todos[0].(map[string]any)
+	assert.Equal(t, "sample-todo-1", todo["id"])
+	assert.Equal(t, "Sample Todo", todo["title"])
+	assert.Equal(t, map[string]any{
+		"self":     map[string]any{"href": "/todos/sample-todo-1"},
+		"comments": map[string]any{"href": "/todos/sample-todo-1/comments"},
+	}, todo["_links"])
+}
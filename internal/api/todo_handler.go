@@ -4,10 +4,14 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/cirocosta/openapi-router-go/internal/model"
 	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/service"
+	"github.com/cirocosta/openapi-router-go/pkg/router"
 )
 
 // TodoHandler handles HTTP requests for todo operations
@@ -24,63 +28,157 @@ func NewTodoHandler(todoService TodoService) *TodoHandler {
 
 // ListTodos handles GET /todos
 func (h *TodoHandler) ListTodos(w http.ResponseWriter, r *http.Request) {
-	todos, err := h.todoService.ListTodos(r.Context())
+	mediaType := negotiate(r)
+
+	fields, err := parseFields(r, todoFields)
+	if err != nil {
+		writeTodoError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(fields) > 0 && mediaType != jsonMediaType {
+		writeTodoError(w, r, "fields is only supported for application/json responses", http.StatusBadRequest)
+		return
+	}
+
+	var filter model.TodoFilter
+	if err := parseTimeParams(r, map[string]**time.Time{
+		"due_before": &filter.DueBefore,
+		"due_after":  &filter.DueAfter,
+	}); err != nil {
+		writeTodoError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	todos, err := h.todoService.ListTodos(r.Context(), filter)
 	if err != nil {
-		writeError(w, "error listing todos", http.StatusInternalServerError)
+		writeTodoError(w, r, "error listing todos", http.StatusInternalServerError)
 		return
 	}
 
-	response := model.TodoListResponse{
-		Todos: todos,
+	if len(fields) > 0 {
+		projected := make([]map[string]any, 0, len(todos))
+		for _, todo := range todos {
+			item, err := projectFields(todo, fields)
+			if err != nil {
+				writeTodoError(w, r, "error listing todos", http.StatusInternalServerError)
+				return
+			}
+			projected = append(projected, item)
+		}
+
+		writeJSON(w, map[string]any{"todos": projected}, http.StatusOK)
+		return
 	}
 
-	writeJSON(w, response, http.StatusOK)
+	writeTodoList(w, mediaType, todos)
+}
+
+// GetStats handles GET /todos/stats
+func (h *TodoHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	query := model.TodoStatsQuery{
+		GroupBy: r.URL.Query().Get("group_by"),
+	}
+	if err := parseTimeParams(r, map[string]**time.Time{
+		"created_after":  &query.CreatedAfter,
+		"created_before": &query.CreatedBefore,
+	}); err != nil {
+		writeError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.todoService.GetStats(r.Context(), query)
+	if err != nil {
+		var invalidErr service.ErrInvalidTodo
+		if errors.As(err, &invalidErr) {
+			writeError(w, r, invalidErr.Error(), http.StatusBadRequest)
+			return
+		}
+		writeError(w, r, "error computing stats", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, stats, http.StatusOK)
 }
 
 // GetTodo handles GET /todos/{id}
 func (h *TodoHandler) GetTodo(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
+	mediaType := negotiate(r)
+
+	fields, err := parseFields(r, todoFields)
+	if err != nil {
+		writeTodoError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(fields) > 0 && mediaType != jsonMediaType {
+		writeTodoError(w, r, "fields is only supported for application/json responses", http.StatusBadRequest)
+		return
+	}
 
 	todo, err := h.todoService.GetTodo(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, repository.ErrTodoNotFound{ID: id}) {
-			writeError(w, "todo not found", http.StatusNotFound)
+			writeTodoCodedError(w, r, errTodoNotFound.New())
 			return
 		}
-		writeError(w, "error getting todo", http.StatusInternalServerError)
+		writeTodoError(w, r, "error getting todo", http.StatusInternalServerError)
 		return
 	}
 
-	response := model.TodoResponse{
-		Todo: todo,
+	if len(fields) > 0 {
+		projected, err := projectFields(todo, fields)
+		if err != nil {
+			writeTodoError(w, r, "error getting todo", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, map[string]any{"todo": projected}, http.StatusOK)
+		return
 	}
 
-	writeJSON(w, response, http.StatusOK)
+	writeTodo(w, mediaType, todo, http.StatusOK)
 }
 
 // CreateTodo handles POST /todos
 func (h *TodoHandler) CreateTodo(w http.ResponseWriter, r *http.Request) {
 	var req model.CreateTodoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, "invalid request format", http.StatusBadRequest)
+		writeTodoCodedError(w, r, errInvalidRequestFormat.New())
 		return
 	}
 
 	todo, err := h.todoService.CreateTodo(r.Context(), req)
 	if err != nil {
-		if err.Error() == "title is required" {
-			writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		var invalidErr service.ErrInvalidTodo
+		if errors.As(err, &invalidErr) {
+			writeTodoError(w, r, invalidErr.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		var existsErr repository.ErrTodoExists
+		if errors.As(err, &existsErr) {
+			location := "/todos/" + existsErr.ID
+			w.Header().Set("Location", location)
+			conflict := errTodoExists.New(existsErr.ExternalID)
+			if negotiate(r) == model.JSONAPIMediaType {
+				writeTodoCodedError(w, r, conflict)
+				return
+			}
+			writeJSON(w, model.ConflictResponse{
+				Error:      conflict.Error(),
+				Code:       conflict.Code,
+				RequestID:  router.RequestID(r.Context()),
+				ExistingID: existsErr.ID,
+				Location:   location,
+			}, http.StatusConflict)
 			return
 		}
-		writeError(w, "error creating todo", http.StatusInternalServerError)
-		return
-	}
 
-	response := model.TodoResponse{
-		Todo: todo,
+		writeTodoError(w, r, "error creating todo", http.StatusInternalServerError)
+		return
 	}
 
-	writeJSON(w, response, http.StatusCreated)
+	writeTodo(w, negotiate(r), todo, http.StatusCreated)
 }
 
 // UpdateTodo handles PUT /todos/{id}
@@ -89,26 +187,28 @@ func (h *TodoHandler) UpdateTodo(w http.ResponseWriter, r *http.Request) {
 
 	var req model.UpdateTodoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, "invalid request format", http.StatusBadRequest)
+		writeTodoCodedError(w, r, errInvalidRequestFormat.New())
 		return
 	}
 
 	todo, err := h.todoService.UpdateTodo(r.Context(), id, req)
 	if err != nil {
+		var invalidErr service.ErrInvalidTodo
+		if errors.As(err, &invalidErr) {
+			writeTodoError(w, r, invalidErr.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
 		var notFoundErr repository.ErrTodoNotFound
 		if errors.As(err, &notFoundErr) {
-			writeError(w, "todo not found", http.StatusNotFound)
+			writeTodoCodedError(w, r, errTodoNotFound.New())
 			return
 		}
-		writeError(w, "error updating todo", http.StatusInternalServerError)
+		writeTodoError(w, r, "error updating todo", http.StatusInternalServerError)
 		return
 	}
 
-	response := model.TodoResponse{
-		Todo: todo,
-	}
-
-	writeJSON(w, response, http.StatusOK)
+	writeTodo(w, negotiate(r), todo, http.StatusOK)
 }
 
 // DeleteTodo handles DELETE /todos/{id}
@@ -119,16 +219,34 @@ func (h *TodoHandler) DeleteTodo(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		var notFoundErr repository.ErrTodoNotFound
 		if errors.As(err, &notFoundErr) {
-			writeError(w, "todo not found", http.StatusNotFound)
+			writeTodoCodedError(w, r, errTodoNotFound.New())
 			return
 		}
-		writeError(w, "error deleting todo", http.StatusInternalServerError)
+		writeTodoError(w, r, "error deleting todo", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// parseTimeParams parses optional RFC 3339 query parameters into their targets
+func parseTimeParams(r *http.Request, targets map[string]**time.Time) error {
+	for name, target := range targets {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("%s must be an RFC 3339 date-time", name)
+		}
+		*target = &t
+	}
+
+	return nil
+}
+
 // writeJSON writes a JSON response with the given status code
 func writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -138,11 +256,13 @@ func writeJSON(w http.ResponseWriter, data interface{}, statusCode int) {
 	}
 }
 
-// writeError writes an error response with the given status code
-func writeError(w http.ResponseWriter, message string, statusCode int) {
+// writeError writes an error response with the given status code, carrying
+// the request ID for support to find the request in the logs
+func writeError(w http.ResponseWriter, r *http.Request, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(model.ErrorResponse{
-		Error: message,
+		Error:     message,
+		RequestID: router.RequestID(r.Context()),
 	})
 }
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/cirocosta/openapi-router-go/internal/model"
 	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/service"
 )
 
 // mockTodoService is a mock implementation of TodoService
@@ -23,11 +25,16 @@ type mockTodoService struct {
 	mock.Mock
 }
 
-func (m *mockTodoService) ListTodos(ctx context.Context) ([]model.Todo, error) {
-	args := m.Called(ctx)
+func (m *mockTodoService) ListTodos(ctx context.Context, filter model.TodoFilter) ([]model.Todo, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]model.Todo), args.Error(1)
 }
 
+func (m *mockTodoService) GetStats(ctx context.Context, query model.TodoStatsQuery) (model.TodoStatsResponse, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).(model.TodoStatsResponse), args.Error(1)
+}
+
 func (m *mockTodoService) GetTodo(ctx context.Context, id string) (model.Todo, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(model.Todo), args.Error(1)
@@ -51,7 +58,10 @@ func (m *mockTodoService) DeleteTodo(ctx context.Context, id string) error {
 func TestListTodos(t *testing.T) {
 	t.Parallel()
 
+	dueBefore := time.Date(2023, time.January, 10, 0, 0, 0, 0, time.UTC)
+
 	for name, tc := range map[string]struct {
+		query        string
 		setupMock    func(m *mockTodoService)
 		wantStatus   int
 		wantResponse model.TodoListResponse
@@ -63,7 +73,7 @@ func TestListTodos(t *testing.T) {
 					{ID: "1", Title: "Todo 1", Completed: false},
 					{ID: "2", Title: "Todo 2", Completed: true},
 				}
-				m.On("ListTodos", mock.Anything).Return(todos, nil)
+				m.On("ListTodos", mock.Anything, model.TodoFilter{}).Return(todos, nil)
 			},
 			wantStatus: http.StatusOK,
 			wantResponse: model.TodoListResponse{
@@ -75,11 +85,28 @@ func TestListTodos(t *testing.T) {
 		},
 		"service error": {
 			setupMock: func(m *mockTodoService) {
-				m.On("ListTodos", mock.Anything).Return([]model.Todo{}, errors.New("database error"))
+				m.On("ListTodos", mock.Anything, model.TodoFilter{}).Return([]model.Todo{}, errors.New("database error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 			wantErr:    "error listing todos",
 		},
+		"due date filter": {
+			query: "?due_before=2023-01-10T00:00:00Z",
+			setupMock: func(m *mockTodoService) {
+				todos := []model.Todo{{ID: "1", Title: "Todo 1", DueDate: &dueBefore}}
+				m.On("ListTodos", mock.Anything, model.TodoFilter{DueBefore: &dueBefore}).Return(todos, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantResponse: model.TodoListResponse{
+				Todos: []model.Todo{{ID: "1", Title: "Todo 1", DueDate: &dueBefore}},
+			},
+		},
+		"invalid due date filter": {
+			query:      "?due_after=tomorrow",
+			setupMock:  func(m *mockTodoService) {},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "due_after must be an RFC 3339 date-time",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
@@ -89,7 +116,7 @@ func TestListTodos(t *testing.T) {
 			tc.setupMock(mockService)
 
 			handler := NewTodoHandler(mockService)
-			req := httptest.NewRequest(http.MethodGet, "/todos", nil).WithContext(ctx)
+			req := httptest.NewRequest(http.MethodGet, "/todos"+tc.query, nil).WithContext(ctx)
 			rec := httptest.NewRecorder()
 
 			handler.ListTodos(rec, req)
@@ -122,10 +149,12 @@ func TestGetTodo(t *testing.T) {
 
 	for name, tc := range map[string]struct {
 		todoID     string
+		query      string
 		setupMock  func(m *mockTodoService)
 		wantStatus int
 		wantTodo   model.Todo
 		wantErr    string
+		wantCode   string
 	}{
 		"success": {
 			todoID: "123",
@@ -136,6 +165,23 @@ func TestGetTodo(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantTodo:   model.Todo{ID: "123", Title: "Test Todo", Completed: false},
 		},
+		"sparse fieldset": {
+			todoID: "123",
+			query:  "?fields=id,title",
+			setupMock: func(m *mockTodoService) {
+				todo := model.Todo{ID: "123", Title: "Test Todo", Description: "hidden", Completed: true}
+				m.On("GetTodo", mock.Anything, "123").Return(todo, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantTodo:   model.Todo{ID: "123", Title: "Test Todo"},
+		},
+		"unknown field": {
+			todoID:     "123",
+			query:      "?fields=id,secret",
+			setupMock:  func(m *mockTodoService) {},
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "unknown field 'secret'",
+		},
 		"not found": {
 			todoID: "999",
 			setupMock: func(m *mockTodoService) {
@@ -143,6 +189,7 @@ func TestGetTodo(t *testing.T) {
 			},
 			wantStatus: http.StatusNotFound,
 			wantErr:    "todo not found",
+			wantCode:   "todo_not_found",
 		},
 		"service error": {
 			todoID: "123",
@@ -162,7 +209,7 @@ func TestGetTodo(t *testing.T) {
 			handler := NewTodoHandler(mockService)
 
 			// Create a custom request with URL parameters that can be accessed by r.PathValue()
-			req := httptest.NewRequest(http.MethodGet, "/todos/"+tc.todoID, nil)
+			req := httptest.NewRequest(http.MethodGet, "/todos/"+tc.todoID+tc.query, nil)
 
 			// Create a custom request context to mock URL parameters
 			ctx := req.Context()
@@ -185,6 +232,7 @@ func TestGetTodo(t *testing.T) {
 				err := json.Unmarshal(rec.Body.Bytes(), &errResp)
 				require.NoError(t, err)
 				assert.Equal(t, tc.wantErr, errResp.Error)
+				assert.Equal(t, tc.wantCode, errResp.Code)
 				return
 			}
 
@@ -205,11 +253,12 @@ func TestCreateTodo(t *testing.T) {
 	t.Parallel()
 
 	for name, tc := range map[string]struct {
-		requestBody string
-		setupMock   func(m *mockTodoService)
-		wantStatus  int
-		wantTodo    model.Todo
-		wantErr     string
+		requestBody  string
+		setupMock    func(m *mockTodoService)
+		wantStatus   int
+		wantTodo     model.Todo
+		wantErr      string
+		wantLocation string
 	}{
 		"success": {
 			requestBody: `{"title": "New Todo"}`,
@@ -231,11 +280,21 @@ func TestCreateTodo(t *testing.T) {
 			requestBody: `{"completed": true}`,
 			setupMock: func(m *mockTodoService) {
 				expectedReq := model.CreateTodoRequest{Title: ""}
-				m.On("CreateTodo", mock.Anything, expectedReq).Return(model.Todo{}, errors.New("title is required"))
+				m.On("CreateTodo", mock.Anything, expectedReq).Return(model.Todo{}, service.ErrInvalidTodo{Reason: "title is required"})
 			},
 			wantStatus: http.StatusUnprocessableEntity,
 			wantErr:    "title is required",
 		},
+		"duplicate external id": {
+			requestBody: `{"title": "New Todo", "external_id": "order-1"}`,
+			setupMock: func(m *mockTodoService) {
+				expectedReq := model.CreateTodoRequest{Title: "New Todo", ExternalID: "order-1"}
+				m.On("CreateTodo", mock.Anything, expectedReq).Return(model.Todo{}, repository.ErrTodoExists{ID: "existing-id", ExternalID: "order-1"})
+			},
+			wantStatus:   http.StatusConflict,
+			wantErr:      "todo with external id order-1 already exists",
+			wantLocation: "/todos/existing-id",
+		},
 		"service error": {
 			requestBody: `{"title": "New Todo"}`,
 			setupMock: func(m *mockTodoService) {
@@ -262,6 +321,7 @@ func TestCreateTodo(t *testing.T) {
 			handler.CreateTodo(rec, req)
 
 			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, tc.wantLocation, rec.Header().Get("Location"))
 
 			if tc.wantErr != "" {
 				var errResp model.ErrorResponse
@@ -474,7 +534,7 @@ func TestHelperFunctions(t *testing.T) {
 		rec := httptest.NewRecorder()
 		errorMsg := "test error"
 
-		writeError(rec, errorMsg, http.StatusBadRequest)
+		writeError(rec, httptest.NewRequest(http.MethodGet, "/", nil), errorMsg, http.StatusBadRequest)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
@@ -485,3 +545,109 @@ func TestHelperFunctions(t *testing.T) {
 		assert.Equal(t, errorMsg, result.Error)
 	})
 }
+
+func TestTodoStats(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		query      string
+		wantStatus int
+		wantStats  model.TodoStatsResponse
+		wantErr    string
+	}{
+		"defaults to status": {
+			query:      "",
+			wantStatus: http.StatusOK,
+			wantStats: model.TodoStatsResponse{
+				GroupBy: model.StatsGroupStatus,
+				Total:   2,
+				Buckets: []model.TodoStatsBucket{{Key: "completed", Count: 1}, {Key: "open", Count: 1}},
+			},
+		},
+		"by month": {
+			query:      "?group_by=month",
+			wantStatus: http.StatusOK,
+			wantStats: model.TodoStatsResponse{
+				GroupBy: model.StatsGroupMonth,
+				Total:   2,
+				Buckets: []model.TodoStatsBucket{{Key: time.Now().UTC().Format("2006-01"), Count: 2}},
+			},
+		},
+		"empty range": {
+			query:      "?created_before=2000-01-01T00:00:00Z",
+			wantStatus: http.StatusOK,
+			wantStats: model.TodoStatsResponse{
+				GroupBy: model.StatsGroupStatus,
+				Buckets: []model.TodoStatsBucket{},
+			},
+		},
+		"unknown grouping": {
+			query:      "?group_by=label",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "invalid request parameters",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			todoRepo := repository.NewInMemoryTodoRepository()
+			todoService := service.NewTodoService(todoRepo)
+			todo, err := todoService.CreateTodo(context.Background(), model.CreateTodoRequest{Title: "Done"})
+			require.NoError(t, err)
+			_, err = todoService.UpdateTodo(context.Background(), todo.ID, model.UpdateTodoRequest{Completed: true})
+			require.NoError(t, err)
+
+			attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(), nil, service.DefaultAttachmentLimits)
+			r := NewRouter(todoService, attachmentService, service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository()))
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/todos/stats"+tc.query, nil))
+			require.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+
+			if tc.wantErr != "" {
+				var errResp model.ErrorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errResp))
+				assert.Equal(t, tc.wantErr, errResp.Error)
+				return
+			}
+
+			var stats model.TodoStatsResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+			assert.Empty(t, cmp.Diff(tc.wantStats, stats))
+		})
+	}
+}
+
+func TestRequestDeadline(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		header     http.Header
+		wantStatus int
+	}{
+		"no timeout":        {wantStatus: http.StatusOK},
+		"generous timeout":  {header: http.Header{"X-Request-Timeout": {"1m"}}, wantStatus: http.StatusOK},
+		"malformed timeout": {header: http.Header{"X-Request-Timeout": {"soon"}}, wantStatus: http.StatusOK},
+		"expired timeout":   {header: http.Header{"X-Request-Timeout": {"1ns"}}, wantStatus: http.StatusInternalServerError},
+		"expired grpc":      {header: http.Header{"Grpc-Timeout": {"1n"}}, wantStatus: http.StatusInternalServerError},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			todoRepo := repository.NewInMemoryTodoRepository()
+			attachmentService := service.NewAttachmentService(todoRepo, repository.NewInMemoryAttachmentRepository(), nil, service.DefaultAttachmentLimits)
+			r := NewRouter(service.NewTodoService(todoRepo), attachmentService, service.NewCommentService(todoRepo, repository.NewInMemoryCommentRepository()))
+
+			req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,310 @@
+// package changelog computes human-readable changes between two OpenAPI specs
+package changelog
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Changes groups the differences between two specs by release-note section
+type Changes struct {
+	Added      []string
+	Changed    []string
+	Deprecated []string
+	Removed    []string
+}
+
+// Empty reports whether there are no changes at all
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Deprecated) == 0 && len(c.Removed) == 0
+}
+
+// Diff compares the endpoints and component schemas of two decoded specs
+func Diff(oldSpec, newSpec map[string]any) Changes {
+	var changes Changes
+
+	diffOperations(&changes, operations(oldSpec), operations(newSpec))
+	diffSchemas(&changes, schemas(oldSpec), schemas(newSpec))
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Changed)
+	sort.Strings(changes.Deprecated)
+	sort.Strings(changes.Removed)
+
+	return changes
+}
+
+// Markdown renders the changes as a CHANGELOG section
+func (c Changes) Markdown(title string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s\n", title)
+
+	if c.Empty() {
+		sb.WriteString("\nNo API changes.\n")
+		return sb.String()
+	}
+
+	for _, section := range []struct {
+		name  string
+		items []string
+	}{
+		{"Added", c.Added},
+		{"Changed", c.Changed},
+		{"Deprecated", c.Deprecated},
+		{"Removed", c.Removed},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "\n### %s\n\n", section.name)
+		for _, item := range section.items {
+			fmt.Fprintf(&sb, "- %s\n", item)
+		}
+	}
+
+	return sb.String()
+}
+
+// httpMethods lists the path item keys that hold operations
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// operations indexes a spec's operations by "METHOD /path"
+func operations(spec map[string]any) map[string]map[string]any {
+	ops := map[string]map[string]any{}
+
+	paths, _ := spec["paths"].(map[string]any)
+	for path, item := range paths {
+		pathItem, _ := item.(map[string]any)
+		for method, value := range pathItem {
+			op, ok := value.(map[string]any)
+			if !ok || !slices.Contains(httpMethods, method) {
+				continue
+			}
+			ops[strings.ToUpper(method)+" "+path] = op
+		}
+	}
+
+	return ops
+}
+
+// schemas returns the component schemas of a spec
+func schemas(spec map[string]any) map[string]map[string]any {
+	result := map[string]map[string]any{}
+
+	components, _ := spec["components"].(map[string]any)
+	schemaSection, _ := components["schemas"].(map[string]any)
+	for name, value := range schemaSection {
+		if schema, ok := value.(map[string]any); ok {
+			result[name] = schema
+		}
+	}
+
+	return result
+}
+
+// diffOperations records added, removed, deprecated and changed endpoints
+func diffOperations(changes *Changes, oldOps, newOps map[string]map[string]any) {
+	for key, newOp := range newOps {
+		oldOp, exists := oldOps[key]
+		if !exists {
+			changes.Added = append(changes.Added, fmt.Sprintf("Endpoint `%s`%s", key, summarySuffix(newOp)))
+			continue
+		}
+
+		if newOp["deprecated"] == true && oldOp["deprecated"] != true {
+			changes.Deprecated = append(changes.Deprecated, fmt.Sprintf("Endpoint `%s`%s", key, summarySuffix(newOp)))
+		}
+
+		for _, change := range operationChanges(oldOp, newOp) {
+			changes.Changed = append(changes.Changed, fmt.Sprintf("Endpoint `%s`: %s", key, change))
+		}
+	}
+
+	for key, oldOp := range oldOps {
+		if _, exists := newOps[key]; !exists {
+			changes.Removed = append(changes.Removed, fmt.Sprintf("Endpoint `%s`%s", key, summarySuffix(oldOp)))
+		}
+	}
+}
+
+// summarySuffix formats an operation summary for appending to a line
+func summarySuffix(op map[string]any) string {
+	if summary, _ := op["summary"].(string); summary != "" {
+		return " (" + summary + ")"
+	}
+	return ""
+}
+
+// operationChanges describes the differences between two versions of an operation
+func operationChanges(oldOp, newOp map[string]any) []string {
+	var changes []string
+
+	oldParams, newParams := parameters(oldOp), parameters(newOp)
+	for _, name := range sortedKeys(newParams) {
+		if _, exists := oldParams[name]; !exists {
+			changes = append(changes, fmt.Sprintf("added %s", name))
+		}
+	}
+	for _, name := range sortedKeys(oldParams) {
+		if _, exists := newParams[name]; !exists {
+			changes = append(changes, fmt.Sprintf("removed %s", name))
+		}
+	}
+
+	oldResponses, _ := oldOp["responses"].(map[string]any)
+	newResponses, _ := newOp["responses"].(map[string]any)
+	for _, status := range sortedKeys(newResponses) {
+		if _, exists := oldResponses[status]; !exists {
+			changes = append(changes, fmt.Sprintf("added response %s", status))
+		}
+	}
+	for _, status := range sortedKeys(oldResponses) {
+		if _, exists := newResponses[status]; !exists {
+			changes = append(changes, fmt.Sprintf("removed response %s", status))
+		}
+	}
+
+	_, hadBody := oldOp["requestBody"]
+	_, hasBody := newOp["requestBody"]
+	switch {
+	case hasBody && !hadBody:
+		changes = append(changes, "added request body")
+	case hadBody && !hasBody:
+		changes = append(changes, "removed request body")
+	}
+
+	return changes
+}
+
+// parameters indexes an operation's parameters by a "<in> parameter `<name>`" label
+func parameters(op map[string]any) map[string]any {
+	result := map[string]any{}
+
+	params, _ := op["parameters"].([]any)
+	for _, value := range params {
+		param, _ := value.(map[string]any)
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if name != "" {
+			result[fmt.Sprintf("%s parameter `%s`", in, name)] = param
+		}
+	}
+
+	return result
+}
+
+// diffSchemas records added and removed schemas and fields, and field type changes
+func diffSchemas(changes *Changes, oldSchemas, newSchemas map[string]map[string]any) {
+	for name, newSchema := range newSchemas {
+		oldSchema, exists := oldSchemas[name]
+		if !exists {
+			changes.Added = append(changes.Added, fmt.Sprintf("Schema `%s`", name))
+			continue
+		}
+
+		oldProps, _ := oldSchema["properties"].(map[string]any)
+		newProps, _ := newSchema["properties"].(map[string]any)
+
+		for _, field := range sortedKeys(newProps) {
+			oldProp, exists := oldProps[field]
+			if !exists {
+				changes.Added = append(changes.Added, fmt.Sprintf("Field `%s.%s`", name, field))
+				continue
+			}
+
+			if oldType, newType := schemaType(oldProp), schemaType(newProps[field]); oldType != newType {
+				changes.Changed = append(changes.Changed, fmt.Sprintf("Field `%s.%s`: type %s → %s", name, field, oldType, newType))
+			}
+
+			if prop, _ := newProps[field].(map[string]any); prop["deprecated"] == true {
+				if old, _ := oldProp.(map[string]any); old["deprecated"] != true {
+					changes.Deprecated = append(changes.Deprecated, fmt.Sprintf("Field `%s.%s`", name, field))
+				}
+			}
+		}
+
+		for _, field := range sortedKeys(oldProps) {
+			if _, exists := newProps[field]; !exists {
+				changes.Removed = append(changes.Removed, fmt.Sprintf("Field `%s.%s`", name, field))
+			}
+		}
+
+		oldRequired, newRequired := stringSet(oldSchema["required"]), stringSet(newSchema["required"])
+		for _, field := range sortedKeys(newRequired) {
+			if _, wasRequired := oldRequired[field]; !wasRequired {
+				if _, existed := oldProps[field]; existed {
+					changes.Changed = append(changes.Changed, fmt.Sprintf("Field `%s.%s`: now required", name, field))
+				}
+			}
+		}
+		for _, field := range sortedKeys(oldRequired) {
+			if _, isRequired := newRequired[field]; !isRequired {
+				if _, exists := newProps[field]; exists {
+					changes.Changed = append(changes.Changed, fmt.Sprintf("Field `%s.%s`: now optional", name, field))
+				}
+			}
+		}
+	}
+
+	for name := range oldSchemas {
+		if _, exists := newSchemas[name]; !exists {
+			changes.Removed = append(changes.Removed, fmt.Sprintf("Schema `%s`", name))
+		}
+	}
+}
+
+// schemaType describes the type of a property schema, following references
+func schemaType(value any) string {
+	schema, _ := value.(map[string]any)
+
+	if ref, ok := schema["$ref"].(string); ok {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+
+	// a single-schema allOf wraps a reference to document the field itself
+	if allOf, ok := schema["allOf"].([]any); ok && len(allOf) == 1 {
+		return schemaType(allOf[0])
+	}
+
+	typ, _ := schema["type"].(string)
+	if format, ok := schema["format"].(string); ok {
+		return typ + " (" + format + ")"
+	}
+	if typ == "array" {
+		return "array of " + schemaType(schema["items"])
+	}
+	return typ
+}
+
+// stringSet converts a decoded JSON string array into a set
+func stringSet(value any) map[string]any {
+	set := map[string]any{}
+
+	switch values := value.(type) {
+	case []any:
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				set[s] = true
+			}
+		}
+	case []string:
+		for _, s := range values {
+			set[s] = true
+		}
+	}
+
+	return set
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	oldSpec := map[string]any{
+		"paths": map[string]any{
+			"/todos": map[string]any{
+				"get": map[string]any{
+					"summary":   "List Todos",
+					"responses": map[string]any{"200": map[string]any{}},
+				},
+			},
+			"/legacy": map[string]any{
+				"get": map[string]any{"summary": "Legacy"},
+			},
+		},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Todo": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":       map[string]any{"type": "string"},
+						"priority": map[string]any{"type": "string"},
+						"notes":    map[string]any{"type": "string"},
+					},
+					"required": []any{"id"},
+				},
+				"Old": map[string]any{"type": "object"},
+			},
+		},
+	}
+
+	newSpec := map[string]any{
+		"paths": map[string]any{
+			"/todos": map[string]any{
+				"get": map[string]any{
+					"summary":    "List Todos",
+					"deprecated": true,
+					"parameters": []any{
+						map[string]any{"name": "fields", "in": "query"},
+					},
+					"responses": map[string]any{"200": map[string]any{}, "400": map[string]any{}},
+				},
+				"post": map[string]any{"summary": "Create Todo"},
+			},
+		},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Todo": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":       map[string]any{"type": "string"},
+						"priority": map[string]any{"type": "integer"},
+						"due":      map[string]any{"type": "string", "format": "date"},
+					},
+					"required": []any{"id", "priority"},
+				},
+			},
+		},
+	}
+
+	expected := Changes{
+		Added: []string{
+			"Endpoint `POST /todos` (Create Todo)",
+			"Field `Todo.due`",
+		},
+		Changed: []string{
+			"Endpoint `GET /todos`: added query parameter `fields`",
+			"Endpoint `GET /todos`: added response 400",
+			"Field `Todo.priority`: now required",
+			"Field `Todo.priority`: type string → integer",
+		},
+		Deprecated: []string{
+			"Endpoint `GET /todos` (List Todos)",
+		},
+		Removed: []string{
+			"Endpoint `GET /legacy` (Legacy)",
+			"Field `Todo.notes`",
+			"Schema `Old`",
+		},
+	}
+
+	if diff := cmp.Diff(expected, Diff(oldSpec, newSpec)); diff != "" {
+		t.Errorf("changes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sections", func(t *testing.T) {
+		t.Parallel()
+
+		changes := Changes{
+			Added:   []string{"Endpoint `POST /todos`"},
+			Removed: []string{"Schema `Old`"},
+		}
+
+		expected := "## v2.0.0\n\n### Added\n\n- Endpoint `POST /todos`\n\n### Removed\n\n- Schema `Old`\n"
+		assert.Equal(t, expected, changes.Markdown("v2.0.0"))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "## v2.0.0\n\nNo API changes.\n", Changes{}.Markdown("v2.0.0"))
+	})
+}
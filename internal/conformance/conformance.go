@@ -0,0 +1,382 @@
+// package conformance scores an OpenAPI spec against an API style guide profile
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule identifiers reported in conformance results
+const (
+	RuleOperationDescription = "operation-description"
+	RuleCollectionPagination = "collection-pagination"
+	RuleProblemJSONErrors    = "problem-json-errors"
+	RuleRateLimitDocumented  = "rate-limit-documented"
+	RuleSummaryLength        = "summary-length"
+	RuleUniqueSummary        = "unique-summary"
+)
+
+// problemJSON is the media type of RFC 9457 problem details
+const problemJSON = "application/problem+json"
+
+// Profile configures which style guide rules are enforced
+type Profile struct {
+	OperationDescriptions bool     `json:"operation_descriptions"`
+	DescriptionsOnPublic  bool     `json:"descriptions_on_public"` // Only require descriptions on public operations
+	CollectionPagination  bool     `json:"collection_pagination"`
+	PaginationParams      []string `json:"pagination_params"`
+	ProblemJSONErrors     bool     `json:"problem_json_errors"`
+	RateLimitOnPublic     bool     `json:"rate_limit_on_public"`
+	MaxSummaryLength      int      `json:"max_summary_length"` // Zero disables the summary length rule
+	UniqueSummaries       bool     `json:"unique_summaries"`
+}
+
+// DefaultProfile enables every rule
+func DefaultProfile() Profile {
+	return Profile{
+		OperationDescriptions: true,
+		CollectionPagination:  true,
+		PaginationParams:      []string{"limit", "offset", "cursor", "page"},
+		ProblemJSONErrors:     true,
+		RateLimitOnPublic:     true,
+		MaxSummaryLength:      80,
+		UniqueSummaries:       true,
+	}
+}
+
+// LoadProfile reads a JSON profile, using the default profile for omitted fields
+func LoadProfile(path string) (Profile, error) {
+	profile := DefaultProfile()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("read profile '%s': %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return Profile{}, fmt.Errorf("decode profile '%s': %w", path, err)
+	}
+
+	return profile, nil
+}
+
+// Report is the scored result of checking a spec against a profile
+type Report struct {
+	Score      float64      `json:"score"`
+	Rules      []RuleResult `json:"rules"`
+	Violations []Violation  `json:"violations"`
+}
+
+// RuleResult summarizes how many checks of a rule passed
+type RuleResult struct {
+	Rule   string  `json:"rule"`
+	Passed int     `json:"passed"`
+	Failed int     `json:"failed"`
+	Score  float64 `json:"score"`
+}
+
+// Violation describes a single failed check
+type Violation struct {
+	Rule      string `json:"rule"`
+	Operation string `json:"operation"`
+	Message   string `json:"message"`
+}
+
+// Check scores a decoded spec against the profile. The overall score is the
+// percentage of passed checks across all enabled rules
+func Check(spec map[string]any, profile Profile) Report {
+	c := &checker{results: map[string]*RuleResult{}}
+
+	for _, rule := range []struct {
+		id      string
+		enabled bool
+	}{
+		{RuleOperationDescription, profile.OperationDescriptions},
+		{RuleCollectionPagination, profile.CollectionPagination},
+		{RuleProblemJSONErrors, profile.ProblemJSONErrors},
+		{RuleRateLimitDocumented, profile.RateLimitOnPublic},
+		{RuleSummaryLength, profile.MaxSummaryLength > 0},
+		{RuleUniqueSummary, profile.UniqueSummaries},
+	} {
+		if rule.enabled {
+			c.results[rule.id] = &RuleResult{Rule: rule.id}
+			c.order = append(c.order, rule.id)
+		}
+	}
+
+	_, globalSecurity := spec["security"]
+
+	ops := operations(spec)
+	summaries := summaryOperations(ops)
+
+	for _, op := range ops {
+		public := isPublic(op.value, globalSecurity)
+
+		if profile.OperationDescriptions && (public || !profile.DescriptionsOnPublic) {
+			description, _ := op.value["description"].(string)
+			c.record(RuleOperationDescription, op.key, strings.TrimSpace(description) != "",
+				"operation has no description")
+		}
+
+		if profile.CollectionPagination && op.method == "get" && isCollection(spec, op.value) {
+			c.record(RuleCollectionPagination, op.key, hasQueryParam(op.value, profile.PaginationParams),
+				fmt.Sprintf("collection has none of the pagination parameters: %s", strings.Join(profile.PaginationParams, ", ")))
+		}
+
+		responses, _ := op.value["responses"].(map[string]any)
+
+		if profile.ProblemJSONErrors {
+			for _, status := range sortedKeys(responses) {
+				if !strings.HasPrefix(status, "4") && !strings.HasPrefix(status, "5") {
+					continue
+				}
+				c.record(RuleProblemJSONErrors, op.key, hasContentType(responses[status], problemJSON),
+					fmt.Sprintf("response %s is not described as %s", status, problemJSON))
+			}
+		}
+
+		if profile.RateLimitOnPublic && public {
+			_, documented := responses["429"]
+			c.record(RuleRateLimitDocumented, op.key, documented,
+				"public operation does not document a 429 response")
+		}
+
+		summary, _ := op.value["summary"].(string)
+
+		if profile.MaxSummaryLength > 0 && summary != "" {
+			length := utf8.RuneCountInString(summary)
+			c.record(RuleSummaryLength, op.key, length <= profile.MaxSummaryLength,
+				fmt.Sprintf("summary is %d characters long, more than %d", length, profile.MaxSummaryLength))
+		}
+
+		if profile.UniqueSummaries && summary != "" {
+			others := slices.DeleteFunc(slices.Clone(summaries[summary]), func(key string) bool {
+				return key == op.key
+			})
+			c.record(RuleUniqueSummary, op.key, len(others) == 0,
+				fmt.Sprintf("summary %q is also used by %s", summary, strings.Join(others, ", ")))
+		}
+	}
+
+	return c.report()
+}
+
+// checker accumulates check outcomes per rule
+type checker struct {
+	order      []string
+	results    map[string]*RuleResult
+	violations []Violation
+}
+
+// record stores the outcome of a single check
+func (c *checker) record(rule, operation string, passed bool, message string) {
+	result := c.results[rule]
+	if passed {
+		result.Passed++
+		return
+	}
+
+	result.Failed++
+	c.violations = append(c.violations, Violation{Rule: rule, Operation: operation, Message: message})
+}
+
+// report computes the per-rule and overall scores
+func (c *checker) report() Report {
+	report := Report{Violations: c.violations, Rules: []RuleResult{}}
+	if report.Violations == nil {
+		report.Violations = []Violation{}
+	}
+
+	passed, total := 0, 0
+	for _, rule := range c.order {
+		result := *c.results[rule]
+		result.Score = score(result.Passed, result.Passed+result.Failed)
+		report.Rules = append(report.Rules, result)
+
+		passed += result.Passed
+		total += result.Passed + result.Failed
+	}
+	report.Score = score(passed, total)
+
+	return report
+}
+
+// score returns the percentage of passed checks, rounded to one decimal
+func score(passed, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return math.Round(float64(passed)/float64(total)*1000) / 10
+}
+
+// Text renders the report for terminals
+func (r Report) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Conformance score: %.1f%%\n\n", r.Score)
+
+	for _, rule := range r.Rules {
+		fmt.Fprintf(&sb, "  %-24s %5.1f%% (%d/%d)\n", rule.Rule, rule.Score, rule.Passed, rule.Passed+rule.Failed)
+	}
+
+	if len(r.Violations) > 0 {
+		sb.WriteString("\nViolations:\n")
+		for _, v := range r.Violations {
+			fmt.Fprintf(&sb, "  [%s] %s: %s\n", v.Rule, v.Operation, v.Message)
+		}
+	}
+
+	return sb.String()
+}
+
+// httpMethods lists the path item keys that hold operations
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// operation is a spec operation together with its location
+type operation struct {
+	key    string
+	path   string
+	method string
+	value  map[string]any
+}
+
+// operations returns the operations of a spec sorted by path and method
+func operations(spec map[string]any) []operation {
+	var ops []operation
+
+	paths, _ := spec["paths"].(map[string]any)
+	for path, item := range paths {
+		pathItem, _ := item.(map[string]any)
+		for method, value := range pathItem {
+			op, ok := value.(map[string]any)
+			if !ok || !slices.Contains(httpMethods, method) {
+				continue
+			}
+			ops = append(ops, operation{
+				key:    strings.ToUpper(method) + " " + path,
+				path:   path,
+				method: method,
+				value:  op,
+			})
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].path != ops[j].path {
+			return ops[i].path < ops[j].path
+		}
+		return ops[i].method < ops[j].method
+	})
+
+	return ops
+}
+
+// summaryOperations maps each summary to the operations using it
+func summaryOperations(ops []operation) map[string][]string {
+	summaries := map[string][]string{}
+	for _, op := range ops {
+		summary, _ := op.value["summary"].(string)
+		summaries[summary] = append(summaries[summary], op.key)
+	}
+	return summaries
+}
+
+// isCollection reports whether an operation returns a collection, i.e. its
+// 200 response is an array or an object wrapping an array
+func isCollection(spec map[string]any, op map[string]any) bool {
+	responses, _ := op["responses"].(map[string]any)
+	response, _ := responses["200"].(map[string]any)
+	mediaType, _ := mediaTypeObject(response, "application/json")
+
+	schema := resolveSchema(spec, mediaType["schema"])
+	if schema["type"] == "array" {
+		return true
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	for _, property := range properties {
+		if resolveSchema(spec, property)["type"] == "array" {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSchema follows a "#/components/schemas/<name>" reference
+func resolveSchema(spec map[string]any, value any) map[string]any {
+	schema, _ := value.(map[string]any)
+
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+
+	components, _ := spec["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	resolved, _ := schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any)
+	return resolved
+}
+
+// isPublic reports whether an operation can be called without credentials
+func isPublic(op map[string]any, globalSecurity bool) bool {
+	security, ok := op["security"].([]any)
+	if !ok {
+		return !globalSecurity
+	}
+
+	// an empty requirement object makes authentication optional
+	for _, requirement := range security {
+		if m, _ := requirement.(map[string]any); len(m) == 0 {
+			return true
+		}
+	}
+	return len(security) == 0
+}
+
+// hasQueryParam reports whether an operation declares any of the query parameters
+func hasQueryParam(op map[string]any, names []string) bool {
+	params, _ := op["parameters"].([]any)
+	for _, value := range params {
+		param, _ := value.(map[string]any)
+		name, _ := param["name"].(string)
+		if param["in"] == "query" && slices.Contains(names, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasContentType reports whether a response documents the given media type
+func hasContentType(response any, contentType string) bool {
+	_, ok := mediaTypeObject(response, contentType)
+	return ok
+}
+
+// mediaTypeObject returns the content a response documents for a media
+// type, ignoring parameters such as "; charset=utf-8"
+func mediaTypeObject(response any, contentType string) (map[string]any, bool) {
+	r, _ := response.(map[string]any)
+	content, _ := r["content"].(map[string]any)
+	for mediaType, object := range content {
+		if name, _, _ := strings.Cut(mediaType, ";"); strings.TrimSpace(name) == contentType {
+			object, _ := object.(map[string]any)
+			return object, true
+		}
+	}
+	return nil, false
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,192 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	spec := map[string]any{
+		"paths": map[string]any{
+			"/todos": map[string]any{
+				"get": map[string]any{
+					"description": "Lists todos",
+					"parameters": []any{
+						map[string]any{"name": "limit", "in": "query"},
+					},
+					"responses": map[string]any{
+						"200": map[string]any{
+							"content": map[string]any{
+								"application/json; charset=utf-8": map[string]any{
+									"schema": map[string]any{"$ref": "#/components/schemas/TodoList"},
+								},
+							},
+						},
+						"429": map[string]any{
+							"content": map[string]any{"application/problem+json": map[string]any{}},
+						},
+					},
+				},
+				"post": map[string]any{
+					"security": []any{map[string]any{"bearerAuth": []any{}}},
+					"responses": map[string]any{
+						"201": map[string]any{},
+						"400": map[string]any{
+							"content": map[string]any{"application/problem+json; charset=utf-8": map[string]any{}},
+						},
+						"409": map[string]any{
+							"content": map[string]any{"application/json": map[string]any{}},
+						},
+					},
+				},
+			},
+			"/users": map[string]any{
+				"get": map[string]any{
+					"description": "Lists users",
+					"responses": map[string]any{
+						"200": map[string]any{
+							"content": map[string]any{
+								"application/json": map[string]any{
+									"schema": map[string]any{"type": "array"},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/health": map[string]any{
+				"get": map[string]any{
+					"description": "Reports service health",
+					"security":    []any{map[string]any{"bearerAuth": []any{}}},
+					"responses":   map[string]any{"200": map[string]any{}},
+				},
+			},
+		},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"TodoList": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"todos": map[string]any{"type": "array"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		profile  Profile
+		expected Report
+	}{
+		"default profile": {
+			profile: DefaultProfile(),
+			expected: Report{
+				Score: 63.6,
+				Rules: []RuleResult{
+					{Rule: RuleOperationDescription, Passed: 3, Failed: 1, Score: 75},
+					{Rule: RuleCollectionPagination, Passed: 1, Failed: 1, Score: 50},
+					{Rule: RuleProblemJSONErrors, Passed: 2, Failed: 1, Score: 66.7},
+					{Rule: RuleRateLimitDocumented, Passed: 1, Failed: 1, Score: 50},
+					{Rule: RuleSummaryLength, Score: 100},
+					{Rule: RuleUniqueSummary, Score: 100},
+				},
+				Violations: []Violation{
+					{Rule: RuleOperationDescription, Operation: "POST /todos", Message: "operation has no description"},
+					{Rule: RuleProblemJSONErrors, Operation: "POST /todos", Message: "response 409 is not described as application/problem+json"},
+					{Rule: RuleCollectionPagination, Operation: "GET /users", Message: "collection has none of the pagination parameters: limit, offset, cursor, page"},
+					{Rule: RuleRateLimitDocumented, Operation: "GET /users", Message: "public operation does not document a 429 response"},
+				},
+			},
+		},
+		"descriptions only": {
+			profile: Profile{OperationDescriptions: true},
+			expected: Report{
+				Score: 75,
+				Rules: []RuleResult{
+					{Rule: RuleOperationDescription, Passed: 3, Failed: 1, Score: 75},
+				},
+				Violations: []Violation{
+					{Rule: RuleOperationDescription, Operation: "POST /todos", Message: "operation has no description"},
+				},
+			},
+		},
+		"descriptions on public operations": {
+			profile: Profile{OperationDescriptions: true, DescriptionsOnPublic: true},
+			expected: Report{
+				Score: 100,
+				Rules: []RuleResult{
+					{Rule: RuleOperationDescription, Passed: 2, Score: 100},
+				},
+				Violations: []Violation{},
+			},
+		},
+		"no rules": {
+			profile:  Profile{},
+			expected: Report{Score: 100, Rules: []RuleResult{}, Violations: []Violation{}},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expected, Check(spec, tc.profile)); diff != "" {
+				t.Errorf("report mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckSummaries(t *testing.T) {
+	t.Parallel()
+
+	operation := func(summary string) map[string]any {
+		return map[string]any{"summary": summary, "responses": map[string]any{}}
+	}
+
+	spec := map[string]any{
+		"paths": map[string]any{
+			"/todos": map[string]any{
+				"get":  operation("List todos"),
+				"post": operation("Create a todo item, assigning it to the current user unless another owner is given explicitly"),
+			},
+			"/todos/archived": map[string]any{
+				"get": operation("List todos"),
+			},
+			"/health": map[string]any{
+				"get": operation(""),
+			},
+		},
+	}
+
+	expected := Report{
+		Score: 50,
+		Rules: []RuleResult{
+			{Rule: RuleSummaryLength, Passed: 2, Failed: 1, Score: 66.7},
+			{Rule: RuleUniqueSummary, Passed: 1, Failed: 2, Score: 33.3},
+		},
+		Violations: []Violation{
+			{Rule: RuleUniqueSummary, Operation: "GET /todos", Message: `summary "List todos" is also used by GET /todos/archived`},
+			{Rule: RuleSummaryLength, Operation: "POST /todos", Message: "summary is 93 characters long, more than 80"},
+			{Rule: RuleUniqueSummary, Operation: "GET /todos/archived", Message: `summary "List todos" is also used by GET /todos`},
+		},
+	}
+
+	report := Check(spec, Profile{MaxSummaryLength: 80, UniqueSummaries: true})
+	if diff := cmp.Diff(expected, report); diff != "" {
+		t.Errorf("report mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isPublic(map[string]any{}, false))
+	assert.False(t, isPublic(map[string]any{}, true))
+	assert.True(t, isPublic(map[string]any{"security": []any{}}, true))
+	assert.True(t, isPublic(map[string]any{"security": []any{map[string]any{}}}, true))
+	assert.False(t, isPublic(map[string]any{"security": []any{map[string]any{"apiKey": []any{}}}}, false))
+}
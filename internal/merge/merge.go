@@ -0,0 +1,309 @@
+// package merge combines the OpenAPI specs of several services into one
+// document, such as the spec of an API gateway exposing them side by side
+package merge
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Service is a spec to merge along with where the gateway exposes it
+type Service struct {
+	Name   string         // Identifies the service; prefixes its components and operation IDs on collisions
+	Prefix string         // Path prefix of the service's operations on the gateway, e.g. "/todos" (optional)
+	Spec   map[string]any // Decoded OpenAPI document of the service
+}
+
+// Merge combines the services' specs into a document described by info.
+// Paths are prefixed per service; components and operation IDs defined
+// differently by several services are renamed to "<service>_<name>" (and
+// references to them rewritten), identical ones are shared. The servers
+// replace those of the services when given, otherwise the services' distinct
+// servers are kept.
+func Merge(info map[string]any, servers []string, services []Service) (map[string]any, error) {
+	merged := map[string]any{
+		"info":       info,
+		"paths":      map[string]any{},
+		"components": map[string]any{},
+	}
+
+	m := &merger{
+		paths:        merged["paths"].(map[string]any),
+		components:   merged["components"].(map[string]any),
+		operationIDs: map[string]bool{},
+	}
+
+	for _, service := range services {
+		version, _ := service.Spec["openapi"].(string)
+		if existing, ok := merged["openapi"]; ok && existing != version {
+			return nil, fmt.Errorf("merge %s: openapi version %s differs from %s", service.Name, version, existing)
+		}
+		merged["openapi"] = version
+
+		if err := m.add(service); err != nil {
+			return nil, fmt.Errorf("merge %s: %w", service.Name, err)
+		}
+	}
+
+	if len(m.components) == 0 {
+		delete(merged, "components")
+	}
+	if len(m.tags) > 0 {
+		merged["tags"] = m.tags
+	}
+
+	if len(servers) > 0 {
+		m.servers = nil
+		for _, url := range servers {
+			m.servers = append(m.servers, map[string]any{"url": url})
+		}
+	}
+	if len(m.servers) > 0 {
+		merged["servers"] = m.servers
+	}
+
+	return merged, nil
+}
+
+// httpMethods lists the path item keys that hold operations
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// merger accumulates the sections of the merged document
+type merger struct {
+	paths        map[string]any
+	components   map[string]any
+	operationIDs map[string]bool
+	tags         []any
+	servers      []any
+}
+
+// renames maps the component references and operation IDs of a service to
+// their names in the merged document
+type renames struct {
+	refs         map[string]string
+	operationIDs map[string]string
+}
+
+// add merges a service's spec into the document
+func (m *merger) add(service Service) error {
+	r := renames{refs: map[string]string{}, operationIDs: map[string]string{}}
+
+	paths, _ := service.Spec["paths"].(map[string]any)
+	if err := m.renameOperationIDs(service.Name, paths, r); err != nil {
+		return err
+	}
+
+	components, _ := service.Spec["components"].(map[string]any)
+	m.renameComponents(service.Name, components, r)
+	if err := m.addComponents(components, r); err != nil {
+		return err
+	}
+
+	for _, path := range sortedKeys(paths) {
+		if err := m.addPath(joinPath(service.Prefix, path), r.rewrite(paths[path])); err != nil {
+			return err
+		}
+	}
+
+	tags, _ := service.Spec["tags"].([]any)
+	for _, tag := range tags {
+		name := tagName(tag)
+		if !slices.ContainsFunc(m.tags, func(existing any) bool { return tagName(existing) == name }) {
+			m.tags = append(m.tags, tag)
+		}
+	}
+
+	servers, _ := service.Spec["servers"].([]any)
+	for _, server := range servers {
+		if !slices.ContainsFunc(m.servers, func(existing any) bool { return reflect.DeepEqual(existing, server) }) {
+			m.servers = append(m.servers, server)
+		}
+	}
+
+	return nil
+}
+
+// renameOperationIDs prefixes the service's operation IDs already used by
+// previously merged services
+func (m *merger) renameOperationIDs(service string, paths map[string]any, r renames) error {
+	var ids []string
+	for _, path := range sortedKeys(paths) {
+		pathItem, _ := paths[path].(map[string]any)
+		for _, method := range httpMethods {
+			op, _ := pathItem[method].(map[string]any)
+			if id, ok := op["operationId"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if m.operationIDs[id] {
+			r.operationIDs[id] = service + "_" + id
+		}
+	}
+	for _, id := range ids {
+		if renamed, ok := r.operationIDs[id]; ok {
+			id = renamed
+		}
+		if m.operationIDs[id] {
+			return fmt.Errorf("operation ID %q is already used", id)
+		}
+		m.operationIDs[id] = true
+	}
+
+	return nil
+}
+
+// renameComponents prefixes the service's components whose names are taken
+// by different definitions. Renaming a component changes the definitions
+// referencing it, so this repeats until no more components clash.
+func (m *merger) renameComponents(service string, components map[string]any, r renames) {
+	for changed := true; changed; {
+		changed = false
+
+		for _, kind := range sortedKeys(components) {
+			byName, _ := components[kind].(map[string]any)
+			existing, _ := m.components[kind].(map[string]any)
+
+			for _, name := range sortedKeys(byName) {
+				ref := componentRef(kind, name)
+				if _, renamed := r.refs[ref]; renamed {
+					continue
+				}
+
+				current, exists := existing[name]
+				if exists && !reflect.DeepEqual(current, r.rewrite(byName[name])) {
+					r.refs[ref] = componentRef(kind, service+"_"+name)
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// addComponents adds the service's components under their merged names
+func (m *merger) addComponents(components map[string]any, r renames) error {
+	for _, kind := range sortedKeys(components) {
+		byName, _ := components[kind].(map[string]any)
+		existing, _ := m.components[kind].(map[string]any)
+		if existing == nil {
+			existing = map[string]any{}
+			m.components[kind] = existing
+		}
+
+		for _, name := range sortedKeys(byName) {
+			target := name
+			if renamed, ok := r.refs[componentRef(kind, name)]; ok {
+				target = strings.TrimPrefix(renamed, componentRef(kind, ""))
+			}
+
+			definition := r.rewrite(byName[name])
+			if current, exists := existing[target]; exists && !reflect.DeepEqual(current, definition) {
+				return fmt.Errorf("component %s is already defined", componentRef(kind, target))
+			}
+			existing[target] = definition
+		}
+	}
+
+	return nil
+}
+
+// addPath adds a path item, merging it with one of another service at the
+// same path as long as they don't define the same operation
+func (m *merger) addPath(path string, item any) error {
+	pathItem, _ := item.(map[string]any)
+
+	existing, exists := m.paths[path].(map[string]any)
+	if !exists {
+		m.paths[path] = pathItem
+		return nil
+	}
+
+	for _, key := range sortedKeys(pathItem) {
+		current, defined := existing[key]
+		if !defined {
+			existing[key] = pathItem[key]
+			continue
+		}
+
+		if slices.Contains(httpMethods, key) {
+			return fmt.Errorf("operation %s %s is already defined", strings.ToUpper(key), path)
+		}
+		if !reflect.DeepEqual(current, pathItem[key]) {
+			return fmt.Errorf("%s of path %s differs from the one already defined", key, path)
+		}
+	}
+
+	return nil
+}
+
+// rewrite deep copies a value, replacing renamed component references and
+// operation IDs, the latter also referenced by links
+func (r renames) rewrite(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, v := range value {
+			copied[key] = r.rewrite(v)
+
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			if renamed, ok := r.refs[s]; ok && key == "$ref" {
+				copied[key] = renamed
+			}
+			if renamed, ok := r.operationIDs[s]; ok && key == "operationId" {
+				copied[key] = renamed
+			}
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, v := range value {
+			copied[i] = r.rewrite(v)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// componentRef returns the reference to a component
+func componentRef(kind, name string) string {
+	return "#/components/" + kind + "/" + name
+}
+
+// joinPath prefixes a path, keeping a single slash between the two
+func joinPath(prefix, path string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return path
+	}
+	prefix = "/" + prefix
+	if path == "/" {
+		return prefix
+	}
+	return prefix + path
+}
+
+// tagName returns the name of a tag object
+func tagName(tag any) string {
+	object, _ := tag.(map[string]any)
+	name, _ := object["name"].(string)
+	return name
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serviceSpec builds a spec with one operation returning the Item schema
+func serviceSpec(operationID string, item map[string]any) map[string]any {
+	return map[string]any{
+		"openapi": "3.0.3",
+		"servers": []any{map[string]any{"url": "http://localhost:8080"}},
+		"tags":    []any{map[string]any{"name": "Items"}},
+		"paths": map[string]any{
+			"/items": map[string]any{
+				"get": map[string]any{
+					"operationId": operationID,
+					"responses": map[string]any{
+						"200": map[string]any{
+							"description": "ok",
+							"content": map[string]any{
+								"application/json": map[string]any{
+									"schema": map[string]any{"$ref": "#/components/schemas/ItemList"},
+								},
+							},
+							"links": map[string]any{
+								"self": map[string]any{"operationId": operationID},
+							},
+						},
+					},
+				},
+			},
+		},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Item": item,
+				"ItemList": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"$ref": "#/components/schemas/Item"},
+				},
+				"Error": map[string]any{"type": "object"},
+			},
+		},
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	todos := serviceSpec("listItems", map[string]any{"type": "object", "required": []any{"id"}})
+	notes := serviceSpec("listItems", map[string]any{"type": "object"})
+
+	merged, err := Merge(map[string]any{"title": "Gateway", "version": "1.0.0"}, nil, []Service{
+		{Name: "todos", Prefix: "/todos", Spec: todos},
+		{Name: "notes", Prefix: "notes/", Spec: notes},
+	})
+	require.NoError(t, err)
+
+	paths := merged["paths"].(map[string]any)
+	require.Contains(t, paths, "/todos/items")
+	require.Contains(t, paths, "/notes/items")
+
+	// differing components and their dependents are namespaced, identical ones shared
+	schemas := merged["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Equal(t, []string{"Error", "Item", "ItemList", "notes_Item", "notes_ItemList"}, sortedKeys(schemas))
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/notes_Item"}, schemas["notes_ItemList"].(map[string]any)["items"])
+
+	get := paths["/notes/items"].(map[string]any)["get"].(map[string]any)
+	want := map[string]any{
+		"operationId": "notes_listItems",
+		"responses": map[string]any{
+			"200": map[string]any{
+				"description": "ok",
+				"content": map[string]any{
+					"application/json": map[string]any{
+						"schema": map[string]any{"$ref": "#/components/schemas/notes_ItemList"},
+					},
+				},
+				"links": map[string]any{
+					"self": map[string]any{"operationId": "notes_listItems"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, get); diff != "" {
+		t.Errorf("merged operation mismatch (-want +got):\n%s", diff)
+	}
+
+	// the input specs are left untouched
+	assert.Equal(t, "listItems", notes["paths"].(map[string]any)["/items"].(map[string]any)["get"].(map[string]any)["operationId"])
+
+	assert.Equal(t, []any{map[string]any{"name": "Items"}}, merged["tags"])
+	assert.Equal(t, []any{map[string]any{"url": "http://localhost:8080"}}, merged["servers"])
+}
+
+func TestMergeServers(t *testing.T) {
+	t.Parallel()
+
+	merged, err := Merge(map[string]any{"title": "Gateway"}, []string{"https://api.example.com"}, []Service{
+		{Name: "todos", Spec: serviceSpec("listTodos", map[string]any{})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"url": "https://api.example.com"}}, merged["servers"])
+}
+
+func TestMergeConflicts(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		services []Service
+		wantErr  string
+	}{
+		"same operation": {
+			services: []Service{
+				{Name: "a", Spec: serviceSpec("listA", map[string]any{})},
+				{Name: "b", Spec: serviceSpec("listB", map[string]any{})},
+			},
+			wantErr: "merge b: operation GET /items is already defined",
+		},
+		"openapi version": {
+			services: []Service{
+				{Name: "a", Spec: map[string]any{"openapi": "3.0.3"}},
+				{Name: "b", Spec: map[string]any{"openapi": "3.1.0"}},
+			},
+			wantErr: "merge b: openapi version 3.1.0 differs from 3.0.3",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Merge(map[string]any{"title": "Gateway"}, nil, tc.services)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+// HALMediaType is the media type of HAL documents
+const HALMediaType = "application/hal+json"
+
+// HALLink is a HAL link object
+type HALLink struct {
+	Href string `json:"href" doc:"Target of the link" example:"/todos/123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// TodoHALLinks are the links of a todo item in a HAL document
+type TodoHALLinks struct {
+	Self     HALLink `json:"self" doc:"The todo item itself"`
+	Comments HALLink `json:"comments" doc:"Comments left on the todo item"`
+}
+
+// TodoHAL is a todo item as a HAL resource
+type TodoHAL struct {
+	Todo
+	Links TodoHALLinks `json:"_links" doc:"Links of the todo item"`
+}
+
+// TodoListHALLinks are the links of a todo collection in a HAL document
+type TodoListHALLinks struct {
+	Self HALLink `json:"self" doc:"The collection itself"`
+}
+
+// TodoListHALEmbedded holds the resources embedded in a todo collection
+type TodoListHALEmbedded struct {
+	Todos []TodoHAL `json:"todos" doc:"List of todo items"`
+}
+
+// TodoListHAL is a collection of todo items as a HAL resource
+type TodoListHAL struct {
+	Links    TodoListHALLinks    `json:"_links" doc:"Links of the collection"`
+	Embedded TodoListHALEmbedded `json:"_embedded" doc:"Todo items of the collection"`
+}
+
+// NewTodoHAL converts a todo item into a HAL resource
+func NewTodoHAL(todo Todo) TodoHAL {
+	links := NewTodoLinks(todo.ID)
+
+	return TodoHAL{
+		Todo: todo,
+		Links: TodoHALLinks{
+			Self:     HALLink{Href: links.Self},
+			Comments: HALLink{Href: links.Comments},
+		},
+	}
+}
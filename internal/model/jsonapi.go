@@ -0,0 +1,104 @@
+package model
+
+import (
+	"time"
+)
+
+// JSONAPIMediaType is the media type of JSON:API documents
+const JSONAPIMediaType = "application/vnd.api+json"
+
+// TodoResourceType is the JSON:API resource type of todo items
+const TodoResourceType = "todos"
+
+// TodoAttributes are the attributes of a todo item in a JSON:API document
+type TodoAttributes struct {
+	ExternalID  string     `json:"external_id,omitempty" doc:"Client-supplied identifier used to detect duplicate creates" example:"order-1234"`
+	Title       string     `json:"title" doc:"Title of the todo item" example:"Buy groceries"`
+	Description string     `json:"description,omitempty" doc:"Detailed description of the todo item" example:"Need to buy milk, eggs, and bread"`
+	Completed   bool       `json:"completed" doc:"Whether the todo item is completed" example:"false"`
+	DueDate     *time.Time `json:"due_date,omitempty" doc:"When the todo item is due" example:"2023-01-10T17:00:00Z"`
+	RemindAt    *time.Time `json:"remind_at,omitempty" doc:"When to send a reminder" example:"2023-01-10T09:00:00Z"`
+	Recurrence  string     `json:"recurrence,omitempty" doc:"Recurrence rule (subset of RFC 5545 RRULE)" example:"FREQ=WEEKLY;INTERVAL=2"`
+	CreatedAt   time.Time  `json:"created_at" doc:"When the todo item was created" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" doc:"When the todo item was last updated" example:"2023-01-02T12:00:00Z"`
+}
+
+// JSONAPILinks holds the links of a JSON:API resource or relationship
+type JSONAPILinks struct {
+	Self    string `json:"self,omitempty" doc:"Link to the resource itself" example:"/todos/123e4567-e89b-12d3-a456-426614174000"`
+	Related string `json:"related,omitempty" doc:"Link to the related resources" example:"/todos/123e4567-e89b-12d3-a456-426614174000/comments"`
+}
+
+// JSONAPIRelationship is a relationship only described by its links
+type JSONAPIRelationship struct {
+	Links JSONAPILinks `json:"links" doc:"Links to the related resources"`
+}
+
+// TodoRelationships are the relationships of a todo item in a JSON:API document
+type TodoRelationships struct {
+	Comments JSONAPIRelationship `json:"comments" doc:"Comments left on the todo item"`
+}
+
+// TodoResource is a todo item as a JSON:API resource object
+type TodoResource struct {
+	Type          string            `json:"type" doc:"Resource type, always todos" example:"todos"`
+	ID            string            `json:"id" doc:"Unique identifier for the todo item" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Attributes    TodoAttributes    `json:"attributes" doc:"Attributes of the todo item"`
+	Relationships TodoRelationships `json:"relationships" doc:"Relationships of the todo item"`
+	Links         JSONAPILinks      `json:"links" doc:"Links of the todo item"`
+}
+
+// TodoDocument is a JSON:API document with a single todo item
+type TodoDocument struct {
+	Data TodoResource `json:"data" doc:"A todo item"`
+}
+
+// TodoListDocument is a JSON:API document with multiple todo items
+type TodoListDocument struct {
+	Data []TodoResource `json:"data" doc:"List of todo items"`
+}
+
+// JSONAPIError is a JSON:API error object
+type JSONAPIError struct {
+	Status string           `json:"status" doc:"HTTP status code of the error" example:"404"`
+	Code   string           `json:"code,omitempty" doc:"Application error code, listed in the error catalog" example:"todo_not_found"`
+	Detail string           `json:"detail" doc:"Error message" example:"todo not found"`
+	Meta   JSONAPIErrorMeta `json:"meta" doc:"Non-standard information about the error"`
+}
+
+// JSONAPIErrorMeta is the meta object of JSON:API error objects
+type JSONAPIErrorMeta struct {
+	RequestID string `json:"request_id,omitempty" doc:"ID of the request, also sent in the X-Request-ID header; quote it when reporting the error" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+}
+
+// JSONAPIErrorDocument is a JSON:API document reporting errors
+type JSONAPIErrorDocument struct {
+	Errors []JSONAPIError `json:"errors" doc:"Errors that occurred"`
+}
+
+// NewTodoResource converts a todo item into a JSON:API resource object
+func NewTodoResource(todo Todo) TodoResource {
+	links := NewTodoLinks(todo.ID)
+
+	return TodoResource{
+		Type: TodoResourceType,
+		ID:   todo.ID,
+		Attributes: TodoAttributes{
+			ExternalID:  todo.ExternalID,
+			Title:       todo.Title,
+			Description: todo.Description,
+			Completed:   todo.Completed,
+			DueDate:     todo.DueDate,
+			RemindAt:    todo.RemindAt,
+			Recurrence:  todo.Recurrence,
+			CreatedAt:   todo.CreatedAt,
+			UpdatedAt:   todo.UpdatedAt,
+		},
+		Relationships: TodoRelationships{
+			Comments: JSONAPIRelationship{
+				Links: JSONAPILinks{Related: links.Comments},
+			},
+		},
+		Links: JSONAPILinks{Self: links.Self},
+	}
+}
@@ -7,25 +7,58 @@ import (
 
 // Todo represents a todo item in the system
 type Todo struct {
-	ID          string    `json:"id" doc:"Unique identifier for the todo item" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Title       string    `json:"title" doc:"Title of the todo item" example:"Buy groceries"`
-	Description string    `json:"description,omitempty" doc:"Detailed description of the todo item" example:"Need to buy milk, eggs, and bread"`
-	Completed   bool      `json:"completed" doc:"Whether the todo item is completed" example:"false"`
-	CreatedAt   time.Time `json:"created_at" doc:"When the todo item was created" example:"2023-01-01T12:00:00Z"`
-	UpdatedAt   time.Time `json:"updated_at" doc:"When the todo item was last updated" example:"2023-01-02T12:00:00Z"`
+	ID          string     `json:"id" doc:"Unique identifier for the todo item" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ExternalID  string     `json:"external_id,omitempty" doc:"Client-supplied identifier used to detect duplicate creates" example:"order-1234"`
+	Title       string     `json:"title" doc:"Title of the todo item" example:"Buy groceries" minLength:"1"`
+	Description string     `json:"description,omitempty" doc:"Detailed description of the todo item" example:"Need to buy milk, eggs, and bread"`
+	Completed   bool       `json:"completed" doc:"Whether the todo item is completed" example:"false"`
+	DueDate     *time.Time `json:"due_date,omitempty" doc:"When the todo item is due" example:"2023-01-10T17:00:00Z"`
+	RemindAt    *time.Time `json:"remind_at,omitempty" doc:"When to send a reminder; must not be after the due date" example:"2023-01-10T09:00:00Z"`
+	Recurrence  string     `json:"recurrence,omitempty" doc:"Recurrence rule (subset of RFC 5545 RRULE); completing the todo moves it to the next occurrence" example:"FREQ=WEEKLY;INTERVAL=2" pattern:"^FREQ=(DAILY|WEEKLY|MONTHLY|YEARLY)(;INTERVAL=[1-9][0-9]*)?$"`
+	CreatedAt   time.Time  `json:"created_at" doc:"When the todo item was created" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" doc:"When the todo item was last updated" example:"2023-01-02T12:00:00Z"`
 }
 
 // CreateTodoRequest is used when creating a new todo item
 type CreateTodoRequest struct {
-	Title       string `json:"title" doc:"Title of the todo item" example:"Buy groceries"`
-	Description string `json:"description,omitempty" doc:"Detailed description of the todo item" example:"Need to buy milk, eggs, and bread"`
+	ExternalID  string     `json:"external_id,omitempty" doc:"Client-supplied identifier; creating a second todo with the same value is rejected" example:"order-1234"`
+	Title       string     `json:"title" doc:"Title of the todo item" example:"Buy groceries" minLength:"1"`
+	Description string     `json:"description,omitempty" doc:"Detailed description of the todo item" example:"Need to buy milk, eggs, and bread"`
+	DueDate     *time.Time `json:"due_date,omitempty" doc:"When the todo item is due" example:"2023-01-10T17:00:00Z"`
+	RemindAt    *time.Time `json:"remind_at,omitempty" doc:"When to send a reminder; must not be after the due date" example:"2023-01-10T09:00:00Z"`
+	Recurrence  string     `json:"recurrence,omitempty" doc:"Recurrence rule (subset of RFC 5545 RRULE); completing the todo moves it to the next occurrence" example:"FREQ=WEEKLY;INTERVAL=2" pattern:"^FREQ=(DAILY|WEEKLY|MONTHLY|YEARLY)(;INTERVAL=[1-9][0-9]*)?$"`
 }
 
 // UpdateTodoRequest is used when updating an existing todo item
 type UpdateTodoRequest struct {
-	Title       string `json:"title,omitempty" doc:"Title of the todo item" example:"Buy groceries"`
-	Description string `json:"description,omitempty" doc:"Detailed description of the todo item" example:"Need to buy milk, eggs, and bread"`
-	Completed   bool   `json:"completed,omitempty" doc:"Whether the todo item is completed" example:"true"`
+	Title       string     `json:"title,omitempty" doc:"Title of the todo item" example:"Buy groceries"`
+	Description string     `json:"description,omitempty" doc:"Detailed description of the todo item" example:"Need to buy milk, eggs, and bread"`
+	Completed   bool       `json:"completed,omitempty" doc:"Whether the todo item is completed" example:"true"`
+	DueDate     *time.Time `json:"due_date,omitempty" doc:"When the todo item is due" example:"2023-01-10T17:00:00Z"`
+	RemindAt    *time.Time `json:"remind_at,omitempty" doc:"When to send a reminder; must not be after the due date" example:"2023-01-10T09:00:00Z"`
+	Recurrence  string     `json:"recurrence,omitempty" doc:"Recurrence rule (subset of RFC 5545 RRULE); completing the todo moves it to the next occurrence" example:"FREQ=WEEKLY;INTERVAL=2" pattern:"^FREQ=(DAILY|WEEKLY|MONTHLY|YEARLY)(;INTERVAL=[1-9][0-9]*)?$"`
+}
+
+// TodoLinks are the paths related to a todo item, shared by the hypermedia
+// representations
+type TodoLinks struct {
+	Self     string // The todo item itself
+	Comments string // Comments left on the todo item
+}
+
+// NewTodoLinks returns the paths related to the todo item with the given ID
+func NewTodoLinks(id string) TodoLinks {
+	self := "/todos/" + id
+	return TodoLinks{
+		Self:     self,
+		Comments: self + "/comments",
+	}
+}
+
+// TodoFilter narrows down the todos returned by a listing
+type TodoFilter struct {
+	DueBefore *time.Time // Only include todos due strictly before this time
+	DueAfter  *time.Time // Only include todos due strictly after this time
 }
 
 // TodoResponse is used for responses with a single todo item
@@ -40,5 +73,93 @@ type TodoListResponse struct {
 
 // ErrorResponse represents an error returned by the API
 type ErrorResponse struct {
-	Error string `json:"error" doc:"Error message" example:"Invalid todo ID"`
+	Error     string `json:"error" doc:"Error message" example:"Invalid todo ID"`
+	Code      string `json:"code,omitempty" doc:"Application error code, listed in the error catalog" example:"todo_not_found"`
+	DocsURL   string `json:"docs_url,omitempty" doc:"Page documenting the error code"`
+	RequestID string `json:"request_id,omitempty" doc:"ID of the request, also sent in the X-Request-ID header; quote it when reporting the error" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+}
+
+// ConflictResponse is returned when a create would duplicate an existing todo item
+type ConflictResponse struct {
+	Error      string `json:"error" doc:"Error message" example:"todo with external id order-1234 already exists"`
+	Code       string `json:"code" doc:"Application error code, listed in the error catalog" example:"todo_exists"`
+	RequestID  string `json:"request_id,omitempty" doc:"ID of the request, also sent in the X-Request-ID header; quote it when reporting the error" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	ExistingID string `json:"existing_id" doc:"Identifier of the existing todo item" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Location   string `json:"location" doc:"Path of the existing todo item" example:"/todos/123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// Attachment describes a file attached to a todo item
+type Attachment struct {
+	ID          string    `json:"id" doc:"Unique identifier for the attachment" example:"att-1700000000000000000"`
+	TodoID      string    `json:"todo_id" doc:"Identifier of the todo item the file is attached to" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Filename    string    `json:"filename" doc:"Original name of the uploaded file" example:"receipt.pdf"`
+	ContentType string    `json:"content_type" doc:"Media type of the file" example:"application/pdf"`
+	Size        int64     `json:"size" doc:"Size of the file in bytes" example:"48213"`
+	CreatedAt   time.Time `json:"created_at" doc:"When the file was uploaded" example:"2023-01-01T12:00:00Z"`
+}
+
+// AttachmentUpload documents the multipart form used to upload an attachment
+type AttachmentUpload struct {
+	File string `json:"file" doc:"Contents of the file" format:"binary"`
+}
+
+// AttachmentResponse is used for responses with a single attachment
+type AttachmentResponse struct {
+	Attachment Attachment `json:"attachment" doc:"An attachment"`
+}
+
+// Comment is a note left on a todo item
+type Comment struct {
+	ID        string    `json:"id" doc:"Unique identifier for the comment" example:"comment-42"`
+	TodoID    string    `json:"todo_id" doc:"Identifier of the todo item the comment belongs to" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Body      string    `json:"body" doc:"Text of the comment" example:"Remember the oat milk"`
+	CreatedAt time.Time `json:"created_at" doc:"When the comment was created" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" doc:"When the comment was last updated" example:"2023-01-02T12:00:00Z"`
+}
+
+// CommentRequest is used when creating or updating a comment
+type CommentRequest struct {
+	Body string `json:"body" doc:"Text of the comment" example:"Remember the oat milk"`
+}
+
+// CommentResponse is used for responses with a single comment
+type CommentResponse struct {
+	Comment Comment `json:"comment" doc:"A comment"`
+}
+
+// CommentPage is a page of comments in creation order
+type CommentPage struct {
+	Comments   []Comment `json:"comments" doc:"Comments of the page"`
+	NextCursor string    `json:"next_cursor,omitempty" doc:"Cursor of the next page; absent on the last page" example:"Y29tbWVudC00Mg"`
+}
+
+// Groupings supported by todo statistics
+const (
+	StatsGroupStatus = "status" // open vs. completed
+	StatsGroupDay    = "day"    // creation day
+	StatsGroupWeek   = "week"   // creation week, starting on Monday
+	StatsGroupMonth  = "month"  // creation month
+)
+
+// StatsGroups lists the supported statistics groupings
+var StatsGroups = []string{StatsGroupStatus, StatsGroupDay, StatsGroupWeek, StatsGroupMonth}
+
+// TodoStatsQuery selects how todo statistics are grouped and which todos they cover
+type TodoStatsQuery struct {
+	GroupBy       string     // One of StatsGroups
+	CreatedAfter  *time.Time // Only count todos created at or after this time
+	CreatedBefore *time.Time // Only count todos created before this time
+}
+
+// TodoStatsBucket is the number of todos sharing a group value
+type TodoStatsBucket struct {
+	Key   string `json:"key" doc:"Group value: open or completed for status, otherwise the start of the time bucket" example:"2023-01-02"`
+	Count int    `json:"count" doc:"Number of todos in the group" example:"3"`
+}
+
+// TodoStatsResponse is used for todo statistics responses
+type TodoStatsResponse struct {
+	GroupBy string            `json:"group_by" doc:"Grouping of the buckets" example:"week"`
+	Total   int               `json:"total" doc:"Number of todos counted across all buckets" example:"5"`
+	Buckets []TodoStatsBucket `json:"buckets" doc:"Counts per group, ordered by key"`
 }
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+)
+
+// AttachmentRepository defines the interface for attachment metadata access
+type AttachmentRepository interface {
+	// FindByID returns a specific attachment of a todo
+	FindByID(ctx context.Context, todoID, id string) (model.Attachment, error)
+
+	// Create adds a new attachment
+	Create(ctx context.Context, attachment model.Attachment) (model.Attachment, error)
+
+	// Delete removes an attachment of a todo
+	Delete(ctx context.Context, todoID, id string) error
+}
+
+// InMemoryAttachmentRepository implements AttachmentRepository with an in-memory map
+type InMemoryAttachmentRepository struct {
+	attachments map[string]model.Attachment
+	mutex       sync.RWMutex
+}
+
+// NewInMemoryAttachmentRepository creates a new, empty in-memory attachment repository
+func NewInMemoryAttachmentRepository() *InMemoryAttachmentRepository {
+	return &InMemoryAttachmentRepository{
+		attachments: make(map[string]model.Attachment),
+	}
+}
+
+// FindByID returns a specific attachment of a todo
+func (r *InMemoryAttachmentRepository) FindByID(ctx context.Context, todoID, id string) (model.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Attachment{}, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	attachment, exists := r.attachments[id]
+	if !exists || attachment.TodoID != todoID {
+		return model.Attachment{}, ErrAttachmentNotFound{ID: id}
+	}
+
+	return attachment, nil
+}
+
+// Create adds a new attachment
+func (r *InMemoryAttachmentRepository) Create(ctx context.Context, attachment model.Attachment) (model.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Attachment{}, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.attachments[attachment.ID] = attachment
+	return attachment, nil
+}
+
+// Delete removes an attachment of a todo
+func (r *InMemoryAttachmentRepository) Delete(ctx context.Context, todoID, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	attachment, exists := r.attachments[id]
+	if !exists || attachment.TodoID != todoID {
+		return ErrAttachmentNotFound{ID: id}
+	}
+
+	delete(r.attachments, id)
+	return nil
+}
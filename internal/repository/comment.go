@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+)
+
+// CommentRepository defines the interface for comment data access
+type CommentRepository interface {
+	// List returns the comments of a todo in creation order, starting after
+	// the comment with the given ID (or from the start when empty)
+	List(ctx context.Context, todoID, afterID string, limit int) ([]model.Comment, error)
+
+	// FindByID returns a specific comment of a todo
+	FindByID(ctx context.Context, todoID, id string) (model.Comment, error)
+
+	// Create adds a new comment, assigning its ID
+	Create(ctx context.Context, comment model.Comment) (model.Comment, error)
+
+	// Update modifies an existing comment
+	Update(ctx context.Context, comment model.Comment) (model.Comment, error)
+
+	// Delete removes a comment of a todo
+	Delete(ctx context.Context, todoID, id string) error
+}
+
+// InMemoryCommentRepository implements CommentRepository with an in-memory
+// slice kept in creation order
+type InMemoryCommentRepository struct {
+	comments []model.Comment
+	sequence int
+	mutex    sync.RWMutex
+}
+
+// NewInMemoryCommentRepository creates a new, empty in-memory comment repository
+func NewInMemoryCommentRepository() *InMemoryCommentRepository {
+	return &InMemoryCommentRepository{}
+}
+
+// List returns the comments of a todo in creation order, starting after
+// the comment with the given ID (or from the start when empty)
+func (r *InMemoryCommentRepository) List(ctx context.Context, todoID, afterID string, limit int) ([]model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	start := 0
+	if afterID != "" {
+		index := r.indexOf(todoID, afterID)
+		if index < 0 {
+			return nil, ErrCommentNotFound{ID: afterID}
+		}
+		start = index + 1
+	}
+
+	comments := []model.Comment{}
+	for _, comment := range r.comments[start:] {
+		if len(comments) == limit {
+			break
+		}
+		if comment.TodoID == todoID {
+			comments = append(comments, comment)
+		}
+	}
+
+	return comments, nil
+}
+
+// FindByID returns a specific comment of a todo
+func (r *InMemoryCommentRepository) FindByID(ctx context.Context, todoID, id string) (model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Comment{}, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index := r.indexOf(todoID, id)
+	if index < 0 {
+		return model.Comment{}, ErrCommentNotFound{ID: id}
+	}
+
+	return r.comments[index], nil
+}
+
+// Create adds a new comment, assigning its ID
+func (r *InMemoryCommentRepository) Create(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Comment{}, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sequence++
+	comment.ID = fmt.Sprintf("comment-%d", r.sequence)
+	r.comments = append(r.comments, comment)
+
+	return comment, nil
+}
+
+// Update modifies an existing comment
+func (r *InMemoryCommentRepository) Update(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Comment{}, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index := r.indexOf(comment.TodoID, comment.ID)
+	if index < 0 {
+		return model.Comment{}, ErrCommentNotFound{ID: comment.ID}
+	}
+
+	r.comments[index] = comment
+	return comment, nil
+}
+
+// Delete removes a comment of a todo
+func (r *InMemoryCommentRepository) Delete(ctx context.Context, todoID, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index := r.indexOf(todoID, id)
+	if index < 0 {
+		return ErrCommentNotFound{ID: id}
+	}
+
+	r.comments = append(r.comments[:index], r.comments[index+1:]...)
+	return nil
+}
+
+// indexOf finds a comment of a todo; callers must hold the mutex
+func (r *InMemoryCommentRepository) indexOf(todoID, id string) int {
+	for i, comment := range r.comments {
+		if comment.ID == id && comment.TodoID == todoID {
+			return i
+		}
+	}
+	return -1
+}
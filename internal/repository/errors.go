@@ -14,3 +14,34 @@ type ErrTodoNotFound struct {
 func (e ErrTodoNotFound) Error() string {
 	return fmt.Sprintf("todo with id %s not found", e.ID)
 }
+
+// ErrTodoExists is returned when a todo with the same external ID already exists
+type ErrTodoExists struct {
+	ID         string
+	ExternalID string
+}
+
+// Error implements the error interface
+func (e ErrTodoExists) Error() string {
+	return fmt.Sprintf("todo with external id %s already exists", e.ExternalID)
+}
+
+// ErrAttachmentNotFound is returned when an attachment with the specified ID does not exist
+type ErrAttachmentNotFound struct {
+	ID string
+}
+
+// Error implements the error interface
+func (e ErrAttachmentNotFound) Error() string {
+	return fmt.Sprintf("attachment with id %s not found", e.ID)
+}
+
+// ErrCommentNotFound is returned when a comment with the specified ID does not exist
+type ErrCommentNotFound struct {
+	ID string
+}
+
+// Error implements the error interface
+func (e ErrCommentNotFound) Error() string {
+	return fmt.Sprintf("comment with id %s not found", e.ID)
+}
@@ -3,6 +3,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,6 +19,9 @@ type TodoRepository interface {
 	// FindByID returns a specific todo by ID
 	FindByID(ctx context.Context, id string) (model.Todo, error)
 
+	// FindByExternalID returns a specific todo by its client-supplied external ID
+	FindByExternalID(ctx context.Context, externalID string) (model.Todo, error)
+
 	// Create adds a new todo
 	Create(ctx context.Context, todo model.Todo) (model.Todo, error)
 
@@ -25,6 +30,9 @@ type TodoRepository interface {
 
 	// Delete removes a todo
 	Delete(ctx context.Context, id string) error
+
+	// Stats counts todos per group, ordered by group key
+	Stats(ctx context.Context, query model.TodoStatsQuery) ([]model.TodoStatsBucket, error)
 }
 
 // InMemoryTodoRepository implements TodoRepository with an in-memory map
@@ -56,6 +64,10 @@ func NewInMemoryTodoRepository() *InMemoryTodoRepository {
 
 // FindAll returns all todos
 func (r *InMemoryTodoRepository) FindAll(ctx context.Context) ([]model.Todo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
@@ -69,6 +81,10 @@ func (r *InMemoryTodoRepository) FindAll(ctx context.Context) ([]model.Todo, err
 
 // FindByID returns a specific todo by ID
 func (r *InMemoryTodoRepository) FindByID(ctx context.Context, id string) (model.Todo, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Todo{}, err
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
@@ -80,17 +96,59 @@ func (r *InMemoryTodoRepository) FindByID(ctx context.Context, id string) (model
 	return todo, nil
 }
 
+// FindByExternalID returns a specific todo by its client-supplied external ID
+func (r *InMemoryTodoRepository) FindByExternalID(ctx context.Context, externalID string) (model.Todo, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Todo{}, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if todo, exists := r.findByExternalID(externalID); exists {
+		return todo, nil
+	}
+
+	return model.Todo{}, ErrTodoNotFound{ID: externalID}
+}
+
+// findByExternalID looks up a todo by external ID; callers must hold the mutex
+func (r *InMemoryTodoRepository) findByExternalID(externalID string) (model.Todo, bool) {
+	for _, todo := range r.todos {
+		if todo.ExternalID != "" && todo.ExternalID == externalID {
+			return todo, true
+		}
+	}
+
+	return model.Todo{}, false
+}
+
 // Create adds a new todo
 func (r *InMemoryTodoRepository) Create(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Todo{}, err
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	// guard against concurrent creates racing past the service-level check
+	if todo.ExternalID != "" {
+		if existing, exists := r.findByExternalID(todo.ExternalID); exists {
+			return model.Todo{}, ErrTodoExists{ID: existing.ID, ExternalID: todo.ExternalID}
+		}
+	}
+
 	r.todos[todo.ID] = todo
 	return todo, nil
 }
 
 // Update modifies an existing todo
 func (r *InMemoryTodoRepository) Update(ctx context.Context, id string, todo model.Todo) (model.Todo, error) {
+	if err := ctx.Err(); err != nil {
+		return model.Todo{}, err
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
@@ -108,6 +166,10 @@ func (r *InMemoryTodoRepository) Update(ctx context.Context, id string, todo mod
 
 // Delete removes a todo
 func (r *InMemoryTodoRepository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
@@ -119,3 +181,63 @@ func (r *InMemoryTodoRepository) Delete(ctx context.Context, id string) error {
 	delete(r.todos, id)
 	return nil
 }
+
+// Stats counts todos per group, ordered by group key. Storage backed by SQL
+// would push this down as a GROUP BY; here the todos are grouped in memory.
+func (r *InMemoryTodoRepository) Stats(ctx context.Context, query model.TodoStatsQuery) ([]model.TodoStatsBucket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	counts := map[string]int{}
+	for _, todo := range r.todos {
+		if query.CreatedAfter != nil && todo.CreatedAt.Before(*query.CreatedAfter) {
+			continue
+		}
+		if query.CreatedBefore != nil && !todo.CreatedAt.Before(*query.CreatedBefore) {
+			continue
+		}
+
+		key, err := statsKey(todo, query.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		counts[key]++
+	}
+
+	buckets := make([]model.TodoStatsBucket, 0, len(counts))
+	for key, count := range counts {
+		buckets = append(buckets, model.TodoStatsBucket{Key: key, Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Key < buckets[j].Key
+	})
+
+	return buckets, nil
+}
+
+// statsKey returns the group a todo is counted in
+func statsKey(todo model.Todo, groupBy string) (string, error) {
+	created := todo.CreatedAt.UTC()
+
+	switch groupBy {
+	case model.StatsGroupStatus:
+		if todo.Completed {
+			return "completed", nil
+		}
+		return "open", nil
+	case model.StatsGroupDay:
+		return created.Format("2006-01-02"), nil
+	case model.StatsGroupWeek:
+		// weeks start on Monday, as in ISO 8601
+		offset := (int(created.Weekday()) + 6) % 7
+		return created.AddDate(0, 0, -offset).Format("2006-01-02"), nil
+	case model.StatsGroupMonth:
+		return created.Format("2006-01"), nil
+	default:
+		return "", fmt.Errorf("unsupported grouping '%s'", groupBy)
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/repository"
+	"github.com/cirocosta/openapi-router-go/internal/storage"
+)
+
+// AttachmentLimits restricts the files that can be attached to todos
+type AttachmentLimits struct {
+	MaxSize      int64    // Maximum file size in bytes
+	ContentTypes []string // Accepted media types
+}
+
+// DefaultAttachmentLimits accepts images, PDFs and plain text up to 10 MiB
+var DefaultAttachmentLimits = AttachmentLimits{
+	MaxSize:      10 << 20,
+	ContentTypes: []string{"image/png", "image/jpeg", "image/gif", "application/pdf", "text/plain"},
+}
+
+// AttachmentService handles business logic for todo attachments
+type AttachmentService struct {
+	todos       repository.TodoRepository
+	attachments repository.AttachmentRepository
+	storage     storage.Storage
+	limits      AttachmentLimits
+}
+
+// NewAttachmentService creates a new attachment service storing file
+// contents in the given storage backend
+func NewAttachmentService(todos repository.TodoRepository, attachments repository.AttachmentRepository, store storage.Storage, limits AttachmentLimits) *AttachmentService {
+	return &AttachmentService{
+		todos:       todos,
+		attachments: attachments,
+		storage:     store,
+		limits:      limits,
+	}
+}
+
+// Limits returns the limits enforced on uploads
+func (s *AttachmentService) Limits() AttachmentLimits {
+	return s.limits
+}
+
+// Upload stores a file and attaches it to a todo
+func (s *AttachmentService) Upload(ctx context.Context, todoID, filename, contentType string, size int64, r io.Reader) (model.Attachment, error) {
+	if _, err := s.todos.FindByID(ctx, todoID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	// media type parameters such as charset don't affect the check
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if !slices.Contains(s.limits.ContentTypes, strings.TrimSpace(mediaType)) {
+		return model.Attachment{}, ErrInvalidAttachment{Reason: fmt.Sprintf("content type must be one of: %s", strings.Join(s.limits.ContentTypes, ", "))}
+	}
+
+	if size > s.limits.MaxSize {
+		return model.Attachment{}, ErrInvalidAttachment{Reason: fmt.Sprintf("file must not be larger than %d bytes", s.limits.MaxSize), TooLarge: true}
+	}
+
+	now := time.Now()
+	attachment := model.Attachment{
+		ID:          fmt.Sprintf("att-%d", now.UnixNano()),
+		TodoID:      todoID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   now,
+	}
+
+	if err := s.storage.Put(ctx, attachmentKey(attachment), r, size, contentType); err != nil {
+		return model.Attachment{}, fmt.Errorf("store attachment: %w", err)
+	}
+
+	return s.attachments.Create(ctx, attachment)
+}
+
+// Open returns an attachment together with a reader of its contents, which
+// callers must close
+func (s *AttachmentService) Open(ctx context.Context, todoID, id string) (model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachments.FindByID(ctx, todoID, id)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	contents, err := s.storage.Get(ctx, attachmentKey(attachment))
+	if err != nil {
+		return model.Attachment{}, nil, fmt.Errorf("open attachment: %w", err)
+	}
+
+	return attachment, contents, nil
+}
+
+// Delete removes an attachment and its contents
+func (s *AttachmentService) Delete(ctx context.Context, todoID, id string) error {
+	attachment, err := s.attachments.FindByID(ctx, todoID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachments.Delete(ctx, todoID, id); err != nil {
+		return err
+	}
+
+	if err := s.storage.Delete(ctx, attachmentKey(attachment)); err != nil {
+		return fmt.Errorf("delete attachment contents: %w", err)
+	}
+
+	return nil
+}
+
+// attachmentKey is the storage key of an attachment's contents
+func attachmentKey(attachment model.Attachment) string {
+	return "todos/" + attachment.TodoID + "/attachments/" + attachment.ID
+}
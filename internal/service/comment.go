@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cirocosta/openapi-router-go/internal/model"
+	"github.com/cirocosta/openapi-router-go/internal/repository"
+)
+
+// CommentService handles business logic for comments on todos
+type CommentService struct {
+	todos    repository.TodoRepository
+	comments repository.CommentRepository
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(todos repository.TodoRepository, comments repository.CommentRepository) *CommentService {
+	return &CommentService{
+		todos:    todos,
+		comments: comments,
+	}
+}
+
+// ListComments returns a page of at most limit comments of a todo, starting
+// after the position encoded in cursor (or from the start when empty)
+func (s *CommentService) ListComments(ctx context.Context, todoID, cursor string, limit int) (model.CommentPage, error) {
+	if _, err := s.todos.FindByID(ctx, todoID); err != nil {
+		return model.CommentPage{}, err
+	}
+
+	afterID := ""
+	if cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return model.CommentPage{}, ErrInvalidCursor{}
+		}
+		afterID = string(decoded)
+	}
+
+	// fetch one extra comment to know whether there is a next page
+	comments, err := s.comments.List(ctx, todoID, afterID, limit+1)
+	if err != nil {
+		var notFoundErr repository.ErrCommentNotFound
+		if errors.As(err, &notFoundErr) {
+			return model.CommentPage{}, ErrInvalidCursor{}
+		}
+		return model.CommentPage{}, err
+	}
+
+	page := model.CommentPage{Comments: comments}
+	if len(comments) > limit {
+		page.Comments = comments[:limit]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(comments[limit-1].ID))
+	}
+
+	return page, nil
+}
+
+// GetComment returns a comment of a todo
+func (s *CommentService) GetComment(ctx context.Context, todoID, id string) (model.Comment, error) {
+	return s.comments.FindByID(ctx, todoID, id)
+}
+
+// CreateComment adds a comment to a todo
+func (s *CommentService) CreateComment(ctx context.Context, todoID string, req model.CommentRequest) (model.Comment, error) {
+	if _, err := s.todos.FindByID(ctx, todoID); err != nil {
+		return model.Comment{}, err
+	}
+
+	if strings.TrimSpace(req.Body) == "" {
+		return model.Comment{}, ErrInvalidComment{Reason: "body is required"}
+	}
+
+	now := time.Now()
+	return s.comments.Create(ctx, model.Comment{
+		TodoID:    todoID,
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+// UpdateComment replaces the body of a comment
+func (s *CommentService) UpdateComment(ctx context.Context, todoID, id string, req model.CommentRequest) (model.Comment, error) {
+	if strings.TrimSpace(req.Body) == "" {
+		return model.Comment{}, ErrInvalidComment{Reason: "body is required"}
+	}
+
+	comment, err := s.comments.FindByID(ctx, todoID, id)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	comment.Body = req.Body
+	comment.UpdatedAt = time.Now()
+
+	return s.comments.Update(ctx, comment)
+}
+
+// DeleteComment removes a comment of a todo
+func (s *CommentService) DeleteComment(ctx context.Context, todoID, id string) error {
+	return s.comments.Delete(ctx, todoID, id)
+}
@@ -0,0 +1,41 @@
+package service
+
+// ErrInvalidTodo is returned when a todo fails business validation
+type ErrInvalidTodo struct {
+	Reason string
+}
+
+// Error implements the error interface
+func (e ErrInvalidTodo) Error() string {
+	return e.Reason
+}
+
+// ErrInvalidAttachment is returned when an uploaded file violates the attachment limits
+type ErrInvalidAttachment struct {
+	Reason   string
+	TooLarge bool
+}
+
+// Error implements the error interface
+func (e ErrInvalidAttachment) Error() string {
+	return e.Reason
+}
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or no
+// longer points at an existing item
+type ErrInvalidCursor struct{}
+
+// Error implements the error interface
+func (e ErrInvalidCursor) Error() string {
+	return "invalid cursor"
+}
+
+// ErrInvalidComment is returned when a comment fails validation
+type ErrInvalidComment struct {
+	Reason string
+}
+
+// Error implements the error interface
+func (e ErrInvalidComment) Error() string {
+	return e.Reason
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrencePattern matches the supported subset of RFC 5545 recurrence
+// rules; it is mirrored in the pattern tag of the model's recurrence fields
+var recurrencePattern = regexp.MustCompile(`^FREQ=(DAILY|WEEKLY|MONTHLY|YEARLY)(;INTERVAL=[1-9][0-9]*)?$`)
+
+// NextOccurrence returns the first occurrence of a recurrence rule after the
+// given occurrence, e.g. FREQ=WEEKLY;INTERVAL=2 adds two weeks
+func NextOccurrence(rule string, occurrence time.Time) (time.Time, error) {
+	if !recurrencePattern.MatchString(rule) {
+		return time.Time{}, fmt.Errorf("unsupported recurrence rule '%s'", rule)
+	}
+
+	freq, interval := "", 1
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("parse interval: %w", err)
+			}
+			interval = n
+		}
+	}
+
+	switch freq {
+	case "DAILY":
+		return occurrence.AddDate(0, 0, interval), nil
+	case "WEEKLY":
+		return occurrence.AddDate(0, 0, 7*interval), nil
+	case "MONTHLY":
+		return occurrence.AddDate(0, interval, 0), nil
+	default:
+		return occurrence.AddDate(interval, 0, 0), nil
+	}
+}
+
+// validateSchedule checks the due date, reminder and recurrence of a todo
+func validateSchedule(dueDate, remindAt *time.Time, recurrence string) error {
+	if recurrence != "" {
+		if !recurrencePattern.MatchString(recurrence) {
+			return ErrInvalidTodo{Reason: "recurrence must match " + recurrencePattern.String()}
+		}
+		if dueDate == nil {
+			return ErrInvalidTodo{Reason: "recurrence requires a due date"}
+		}
+	}
+
+	if remindAt != nil && dueDate != nil && remindAt.After(*dueDate) {
+		return ErrInvalidTodo{Reason: "remind_at must not be after due_date"}
+	}
+
+	return nil
+}
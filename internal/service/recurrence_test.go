@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		rule    string
+		want    time.Time
+		wantErr string
+	}{
+		"daily": {
+			rule: "FREQ=DAILY",
+			want: time.Date(2023, time.February, 1, 9, 0, 0, 0, time.UTC),
+		},
+		"every two weeks": {
+			rule: "FREQ=WEEKLY;INTERVAL=2",
+			want: time.Date(2023, time.February, 14, 9, 0, 0, 0, time.UTC),
+		},
+		"monthly": {
+			rule: "FREQ=MONTHLY",
+			want: time.Date(2023, time.March, 3, 9, 0, 0, 0, time.UTC),
+		},
+		"yearly": {
+			rule: "FREQ=YEARLY;INTERVAL=3",
+			want: time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC),
+		},
+		"unsupported": {
+			rule:    "FREQ=HOURLY",
+			wantErr: "unsupported recurrence rule 'FREQ=HOURLY'",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NextOccurrence(tc.rule, start)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	t.Parallel()
+
+	due := time.Date(2023, time.January, 10, 17, 0, 0, 0, time.UTC)
+	before, after := due.Add(-time.Hour), due.Add(time.Hour)
+
+	assert.NoError(t, validateSchedule(&due, &before, "FREQ=DAILY"))
+	assert.EqualError(t, validateSchedule(&due, &after, ""), "remind_at must not be after due_date")
+	assert.EqualError(t, validateSchedule(nil, nil, "FREQ=DAILY"), "recurrence requires a due date")
+	assert.ErrorAs(t, validateSchedule(&due, nil, "RRULE:FREQ=DAILY"), &ErrInvalidTodo{})
+}
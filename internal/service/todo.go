@@ -3,7 +3,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/cirocosta/openapi-router-go/internal/model"
@@ -22,9 +25,59 @@ func NewTodoService(repo repository.TodoRepository) *TodoService {
 	}
 }
 
-// ListTodos returns all todos
-func (s *TodoService) ListTodos(ctx context.Context) ([]model.Todo, error) {
-	return s.repo.FindAll(ctx)
+// ListTodos returns the todos matching the filter
+func (s *TodoService) ListTodos(ctx context.Context, filter model.TodoFilter) ([]model.Todo, error) {
+	todos, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.DueBefore == nil && filter.DueAfter == nil {
+		return todos, nil
+	}
+
+	filtered := make([]model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if todo.DueDate == nil {
+			continue
+		}
+		if filter.DueBefore != nil && !todo.DueDate.Before(*filter.DueBefore) {
+			continue
+		}
+		if filter.DueAfter != nil && !todo.DueDate.After(*filter.DueAfter) {
+			continue
+		}
+		filtered = append(filtered, todo)
+	}
+
+	return filtered, nil
+}
+
+// GetStats counts todos per group, grouping by status when no grouping is given
+func (s *TodoService) GetStats(ctx context.Context, query model.TodoStatsQuery) (model.TodoStatsResponse, error) {
+	if query.GroupBy == "" {
+		query.GroupBy = model.StatsGroupStatus
+	}
+
+	if !slices.Contains(model.StatsGroups, query.GroupBy) {
+		return model.TodoStatsResponse{}, ErrInvalidTodo{Reason: fmt.Sprintf("group_by must be one of: %s", strings.Join(model.StatsGroups, ", "))}
+	}
+
+	buckets, err := s.repo.Stats(ctx, query)
+	if err != nil {
+		return model.TodoStatsResponse{}, fmt.Errorf("compute stats: %w", err)
+	}
+
+	total := 0
+	for _, bucket := range buckets {
+		total += bucket.Count
+	}
+
+	return model.TodoStatsResponse{
+		GroupBy: query.GroupBy,
+		Total:   total,
+		Buckets: buckets,
+	}, nil
 }
 
 // GetTodo returns a todo by ID
@@ -40,16 +93,37 @@ func (s *TodoService) GetTodo(ctx context.Context, id string) (model.Todo, error
 func (s *TodoService) CreateTodo(ctx context.Context, req model.CreateTodoRequest) (model.Todo, error) {
 	// validate input
 	if req.Title == "" {
-		return model.Todo{}, fmt.Errorf("title is required")
+		return model.Todo{}, ErrInvalidTodo{Reason: "title is required"}
+	}
+
+	if err := validateSchedule(req.DueDate, req.RemindAt, req.Recurrence); err != nil {
+		return model.Todo{}, err
+	}
+
+	// reject duplicates when the client supplied a natural key
+	if req.ExternalID != "" {
+		existing, err := s.repo.FindByExternalID(ctx, req.ExternalID)
+		if err == nil {
+			return model.Todo{}, repository.ErrTodoExists{ID: existing.ID, ExternalID: req.ExternalID}
+		}
+
+		var notFoundErr repository.ErrTodoNotFound
+		if !errors.As(err, &notFoundErr) {
+			return model.Todo{}, fmt.Errorf("find todo by external id: %w", err)
+		}
 	}
 
 	// create the todo
 	now := time.Now()
 	todo := model.Todo{
 		ID:          fmt.Sprintf("todo-%d", now.UnixNano()),
+		ExternalID:  req.ExternalID,
 		Title:       req.Title,
 		Description: req.Description,
 		Completed:   false,
+		DueDate:     req.DueDate,
+		RemindAt:    req.RemindAt,
+		Recurrence:  req.Recurrence,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -77,7 +151,37 @@ func (s *TodoService) UpdateTodo(ctx context.Context, id string, req model.Updat
 		existingTodo.Description = req.Description
 	}
 
+	if req.DueDate != nil {
+		existingTodo.DueDate = req.DueDate
+	}
+	if req.RemindAt != nil {
+		existingTodo.RemindAt = req.RemindAt
+	}
+	if req.Recurrence != "" {
+		existingTodo.Recurrence = req.Recurrence
+	}
+
+	if err := validateSchedule(existingTodo.DueDate, existingTodo.RemindAt, existingTodo.Recurrence); err != nil {
+		return model.Todo{}, err
+	}
+
 	existingTodo.Completed = req.Completed
+
+	// completing a recurring todo moves it to its next occurrence instead
+	if existingTodo.Completed && existingTodo.Recurrence != "" {
+		next, err := NextOccurrence(existingTodo.Recurrence, *existingTodo.DueDate)
+		if err != nil {
+			return model.Todo{}, fmt.Errorf("compute next occurrence: %w", err)
+		}
+
+		if existingTodo.RemindAt != nil {
+			remindAt := existingTodo.RemindAt.Add(next.Sub(*existingTodo.DueDate))
+			existingTodo.RemindAt = &remindAt
+		}
+		existingTodo.DueDate = &next
+		existingTodo.Completed = false
+	}
+
 	existingTodo.UpdatedAt = time.Now()
 
 	return s.repo.Update(ctx, id, existingTodo)
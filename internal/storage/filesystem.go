@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FilesystemStorage stores objects as files below a root directory
+type FilesystemStorage struct {
+	root string
+}
+
+// NewFilesystemStorage creates a filesystem storage rooted at dir, creating
+// the directory if needed
+func NewFilesystemStorage(dir string) (*FilesystemStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage directory '%s': %w", dir, err)
+	}
+
+	return &FilesystemStorage{root: dir}, nil
+}
+
+// Put writes the object to a temporary file and moves it into place, so that
+// readers never observe partial objects
+func (s *FilesystemStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create directory for '%s': %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file for '%s': %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write '%s': %w", key, err)
+	}
+
+	if written != size {
+		return fmt.Errorf("write '%s': expected %d bytes, got %d", key, size, written)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("move '%s' into place: %w", key, err)
+	}
+
+	return nil
+}
+
+// Get opens the file backing the object
+func (s *FilesystemStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("open '%s': %w", key, err)
+	}
+
+	return f, nil
+}
+
+// Delete removes the file backing the object
+func (s *FilesystemStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("remove '%s': %w", key, err)
+	}
+
+	return nil
+}
+
+// path maps a key to a file below the root, rejecting keys that escape it
+func (s *FilesystemStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid object key '%s'", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unsignedPayload lets uploads stream without hashing the body up front
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config configures an S3-compatible storage backend
+type S3Config struct {
+	Endpoint        string // Base URL of the service (e.g. https://s3.eu-west-1.amazonaws.com)
+	Region          string // Region used for request signing
+	Bucket          string // Bucket holding the objects
+	AccessKeyID     string // Access key used for request signing
+	SecretAccessKey string // Secret key used for request signing
+}
+
+// S3Storage stores objects in an S3-compatible bucket using path-style
+// requests signed with AWS Signature Version 4
+type S3Storage struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Storage creates an S3-compatible storage backend
+func NewS3Storage(config S3Config) *S3Storage {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+
+	return &S3Storage{
+		config: config,
+		client: http.DefaultClient,
+		now:    time.Now,
+	}
+}
+
+// Put uploads the object with a single PUT request
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("put '%s': %w", key, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Get streams the object from the bucket
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get '%s': %w", key, err)
+	}
+
+	return resp.Body, nil
+}
+
+// Delete removes the object from the bucket
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("delete '%s': %w", key, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// newRequest creates a request for an object of the bucket
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	rawURL := s.config.Endpoint + "/" + escapePath(s.config.Bucket+"/"+key)
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request for '%s': %w", key, err)
+	}
+
+	return req, nil
+}
+
+// do signs and sends a request, turning error statuses into errors
+func (s *S3Storage) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers to a request
+func (s *S3Storage) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": unsignedPayload,
+		"x-amz-date":           amzDate,
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// escapePath percent-encodes everything but unreserved characters and
+// slashes, as SigV4 canonical URIs require
+func escapePath(path string) string {
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/':
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// hmacSHA256 computes an HMAC-SHA256 of data
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// hexSHA256 computes the hex-encoded SHA-256 of data
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,23 @@
+// package storage provides object storage backends for binary content
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores binary objects under string keys
+type Storage interface {
+	// Put stores size bytes read from r under key
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the object stored under key; callers must close it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key
+	Delete(ctx context.Context, key string) error
+}
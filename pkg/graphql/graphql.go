@@ -0,0 +1,463 @@
+// package graphql exposes the documented routes of a router as a GraphQL
+// endpoint, for teams experimenting with GraphQL on top of the same handlers
+// and service layer as the REST API. It lives apart from the router so
+// services not using it don't carry it.
+//
+// The schema is generated from the routes' documentation: GET routes become
+// query fields and routes of other methods mutation fields, named after the
+// routes (listTodos for "List Todos"). Path and query parameters become
+// arguments, and the request type an input argument. Fields resolve by
+// serving a request through the router, so validation, middleware and errors
+// are those of the REST API.
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+// maxQuerySize is the largest request document accepted, in bytes
+const maxQuerySize = 1 << 20
+
+// Handler serves GraphQL requests over HTTP, resolving them through the
+// routes of a router
+type Handler struct {
+	router http.Handler
+	schema *schema
+}
+
+// New creates a handler for the routes registered on the router so far;
+// routes registered afterwards aren't exposed
+func New(dr *router.DocRouter) *Handler {
+	return &Handler{router: dr, schema: newSchema(dr.GetRoutes())}
+}
+
+// Schema returns the schema of the endpoint in the GraphQL schema definition
+// language, e.g. for client code generators
+func (h *Handler) Schema() string {
+	return h.schema.SDL()
+}
+
+// Request is a GraphQL request, sent as the JSON body of POST requests or
+// as the query parameters of GET requests
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the result of a GraphQL request
+type Response struct {
+	Data   any     `json:"data,omitempty"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Error is an error resolving a GraphQL request
+type Error struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// ServeHTTP executes a GraphQL request. Queries may be sent with GET or
+// POST, mutations only with POST.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeResponse(w, http.StatusBadRequest, Response{Errors: []Error{{Message: "invalid variables: " + err.Error()}}})
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxQuerySize)).Decode(&req); err != nil {
+			writeResponse(w, http.StatusBadRequest, Response{Errors: []Error{{Message: "invalid request body: " + err.Error()}}})
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeResponse(w, http.StatusMethodNotAllowed, Response{Errors: []Error{{Message: "method not allowed"}}})
+		return
+	}
+
+	op, err := h.operation(req)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, Response{Errors: []Error{{Message: err.Error()}}})
+		return
+	}
+	if op.kind == "mutation" && r.Method != http.MethodPost {
+		writeResponse(w, http.StatusMethodNotAllowed, Response{Errors: []Error{{Message: "mutations must be sent with POST"}}})
+		return
+	}
+
+	writeResponse(w, http.StatusOK, h.execute(r, op, req.Variables))
+}
+
+// writeResponse writes a GraphQL response as JSON
+func writeResponse(w http.ResponseWriter, status int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// operation parses the request's document and picks the operation to run
+func (h *Handler) operation(req Request) (*operation, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, fmt.Errorf("missing query")
+	}
+	doc, err := parse(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("syntax error: %w", err)
+	}
+
+	if req.OperationName == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required for documents with several operations")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == req.OperationName {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", req.OperationName)
+}
+
+// execute resolves the operation's fields one after the other, in order
+func (h *Handler) execute(r *http.Request, op *operation, variables map[string]any) Response {
+	fields := h.schema.queries
+	if op.kind == "mutation" {
+		fields = h.schema.mutations
+	}
+
+	data := orderedObject{}
+	var errs []Error
+	for _, sel := range op.selections {
+		if sel.name == "__typename" {
+			data = append(data, member{key: sel.key(), value: rootTypeName(op.kind)})
+			continue
+		}
+
+		var field *rootField
+		for _, f := range fields {
+			if f.name == sel.name {
+				field = f
+			}
+		}
+		if field == nil {
+			errs = append(errs, Error{
+				Message: fmt.Sprintf("Cannot query field %q on type %q", sel.name, rootTypeName(op.kind)),
+				Path:    []any{sel.key()},
+			})
+			data = append(data, member{key: sel.key(), value: nil})
+			continue
+		}
+
+		result, err := h.resolve(r, field, sel, op, variables)
+		if err != nil {
+			err.Path = append([]any{sel.key()}, err.Path...)
+			errs = append(errs, *err)
+		}
+		data = append(data, member{key: sel.key(), value: result})
+	}
+
+	return Response{Data: data, Errors: errs}
+}
+
+// rootTypeName returns the name of an operation's root type
+func rootTypeName(kind string) string {
+	if kind == "mutation" {
+		return "Mutation"
+	}
+	return "Query"
+}
+
+// resolve serves the field's route with the selection's arguments and
+// projects the response on the selection
+func (h *Handler) resolve(r *http.Request, field *rootField, sel *selection, op *operation, variables map[string]any) (any, *Error) {
+	path := field.route.Path
+	query := url.Values{}
+	var body io.Reader
+
+	for name := range sel.arguments {
+		if !hasArgument(field, name) {
+			return nil, &Error{Message: fmt.Sprintf("Unknown argument %q on field %q", name, field.name)}
+		}
+	}
+
+	for _, arg := range field.arguments {
+		v, set, err := argumentValue(sel.arguments, arg.name, op, variables)
+		if err != nil {
+			return nil, &Error{Message: err.Error()}
+		}
+		if !set || v == nil {
+			if arg.typ.nonNull {
+				return nil, &Error{Message: fmt.Sprintf("Argument %q of field %q is required", arg.name, field.name)}
+			}
+			continue
+		}
+
+		switch arg.in {
+		case "path":
+			path = strings.NewReplacer("{"+arg.param+"}", url.PathEscape(fmt.Sprint(v)), "{"+arg.param+"...}", fmt.Sprint(v)).Replace(path)
+		case "query":
+			if items, ok := v.([]any); ok {
+				for _, item := range items {
+					query.Add(arg.param, fmt.Sprint(item))
+				}
+			} else {
+				query.Set(arg.param, fmt.Sprint(v))
+			}
+		case "body":
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, &Error{Message: fmt.Sprintf("invalid input: %v", err)}
+			}
+			body = bytes.NewReader(encoded)
+		}
+	}
+
+	target := path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(r.Context(), field.route.Method, target, body)
+	if err != nil {
+		return nil, &Error{Message: err.Error()}
+	}
+	// the route sees the caller's credentials, language and the like
+	for name, values := range r.Header {
+		if name == "Content-Type" || name == "Content-Length" || name == "Accept-Encoding" {
+			continue
+		}
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.RemoteAddr = r.RemoteAddr
+	req.Host = r.Host
+	req.TLS = r.TLS
+
+	rec := &recorder{header: http.Header{}}
+	h.router.ServeHTTP(rec, req)
+
+	var decoded any
+	if rec.body.Len() > 0 {
+		if err := json.Unmarshal(rec.body.Bytes(), &decoded); err != nil {
+			decoded = rec.body.String()
+		}
+	}
+
+	if rec.statusCode() >= 400 {
+		return nil, &Error{
+			Message:    fmt.Sprintf("%s %s responded %d %s", field.route.Method, path, rec.statusCode(), http.StatusText(rec.statusCode())),
+			Extensions: map[string]any{"status": rec.statusCode(), "response": decoded},
+		}
+	}
+
+	return h.project(decoded, field.typ, sel.selections, nil)
+}
+
+// hasArgument reports whether the field takes the argument
+func hasArgument(field *rootField, name string) bool {
+	for _, arg := range field.arguments {
+		if arg.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// argumentValue returns the value of an argument with its variables
+// substituted, and whether it was given
+func argumentValue(arguments map[string]value, name string, op *operation, variables map[string]any) (any, bool, error) {
+	v, set := arguments[name]
+	if !set {
+		return nil, false, nil
+	}
+	if ref, ok := v.(variable); ok {
+		if _, declared := op.variables[string(ref)]; !declared {
+			return nil, false, fmt.Errorf("Variable \"$%s\" is not defined", ref)
+		}
+		if _, given := variables[string(ref)]; !given && op.variables[string(ref)] == nil {
+			return nil, false, nil
+		}
+	}
+	resolved, err := resolveValue(v, op, variables)
+	return resolved, true, err
+}
+
+// resolveValue substitutes the variables of a value, turning it into what
+// encoding/json decodes
+func resolveValue(v value, op *operation, variables map[string]any) (any, error) {
+	switch v := v.(type) {
+	case variable:
+		if given, ok := variables[string(v)]; ok {
+			return given, nil
+		}
+		defaultValue, declared := op.variables[string(v)]
+		if !declared {
+			return nil, fmt.Errorf("Variable \"$%s\" is not defined", v)
+		}
+		return resolveValue(defaultValue, op, variables)
+	case enumValue:
+		return string(v), nil
+	case []value:
+		list := make([]any, len(v))
+		for i, item := range v {
+			resolved, err := resolveValue(item, op, variables)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = resolved
+		}
+		return list, nil
+	case map[string]value:
+		object := make(map[string]any, len(v))
+		for name, member := range v {
+			resolved, err := resolveValue(member, op, variables)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = resolved
+		}
+		return object, nil
+	}
+	return v, nil
+}
+
+// project keeps the selected members of a decoded response, in the order
+// they were selected, checking them against the field's type
+func (h *Handler) project(v any, typ *typeRef, selections []*selection, path []any) (any, *Error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if typ.elem != nil {
+		items, ok := v.([]any)
+		if !ok {
+			return nil, &Error{Message: "expected a list", Path: path}
+		}
+		projected := make([]any, len(items))
+		for i, item := range items {
+			p, err := h.project(item, typ.elem, selections, append(slices.Clip(path), i))
+			if err != nil {
+				return nil, err
+			}
+			projected[i] = p
+		}
+		return projected, nil
+	}
+
+	object := h.schema.objects[typ.name]
+	if object == nil {
+		if len(selections) > 0 && typ.name != scalarJSON {
+			return nil, &Error{Message: fmt.Sprintf("Field of type %q must not have a selection of subfields", typ.name), Path: path}
+		}
+		return v, nil
+	}
+	if len(selections) == 0 {
+		return nil, &Error{Message: fmt.Sprintf("Field of type %q must have a selection of subfields", typ.name), Path: path}
+	}
+
+	members, _ := v.(map[string]any)
+	projected := orderedObject{}
+	for _, sel := range selections {
+		if sel.name == "__typename" {
+			projected = append(projected, member{key: sel.key(), value: typ.name})
+			continue
+		}
+
+		f, ok := object.field(sel.name)
+		if !ok {
+			return nil, &Error{Message: fmt.Sprintf("Cannot query field %q on type %q", sel.name, typ.name), Path: path}
+		}
+		p, err := h.project(members[sel.name], f.typ, sel.selections, append(slices.Clip(path), sel.key()))
+		if err != nil {
+			return nil, err
+		}
+		projected = append(projected, member{key: sel.key(), value: p})
+	}
+	return projected, nil
+}
+
+// orderedObject is a JSON object keeping its members in order, as GraphQL
+// responses list fields in the order they were selected
+type orderedObject []member
+
+// member is a member of an ordered object
+type member struct {
+	key   string
+	value any
+}
+
+// MarshalJSON encodes the members in order
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// recorder captures the response of a route
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the response headers
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+// WriteHeader records the first status code written
+func (r *recorder) WriteHeader(statusCode int) {
+	if r.status == 0 {
+		r.status = statusCode
+	}
+}
+
+// Write records the body
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// statusCode returns the recorded status, defaulting to 200 like net/http
+func (r *recorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
@@ -0,0 +1,241 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+type todo struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+	Owner owner  `json:"owner"`
+}
+
+type owner struct {
+	Name string `json:"name"`
+}
+
+type createTodoRequest struct {
+	Title string `json:"title"`
+}
+
+// todoRouter serves a small todo API over an in-memory list
+func todoRouter() *router.DocRouter {
+	todos := []todo{
+		{ID: "1", Title: "write docs", Owner: owner{Name: "ana"}},
+		{ID: "2", Title: "ship it", Done: true, Owner: owner{Name: "bo"}},
+	}
+
+	r := router.NewDocRouter()
+	r.Route(http.MethodGet, "/todos", func(w http.ResponseWriter, r *http.Request) {
+		matching := []todo{}
+		for _, t := range todos {
+			if done := r.URL.Query().Get("done"); done == "" || done == "true" == t.Done {
+				matching = append(matching, t)
+			}
+		}
+		json.NewEncoder(w).Encode(matching)
+	}).WithName("List Todos").WithQueryParam("done", "Only todos in this state", false).WithResponse([]todo{}).Register()
+
+	r.Route(http.MethodGet, "/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		for _, t := range todos {
+			if t.ID == r.PathValue("id") {
+				json.NewEncoder(w).Encode(t)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"message": "todo not found"})
+	}).WithName("Get Todo").WithResponse(todo{}).Register()
+
+	r.Route(http.MethodPost, "/todos", func(w http.ResponseWriter, r *http.Request) {
+		var req createTodoRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		created := todo{ID: "3", Title: req.Title, Owner: owner{Name: r.Header.Get("X-User")}}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+	}).WithName("Create Todo").WithRequest(createTodoRequest{}).WithResponse(todo{}).Register()
+
+	return r
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `"Values without a documented structure, as JSON"
+scalar JSON
+
+type Query {
+  "List Todos"
+  listTodos(done: Boolean): [todo]
+  "Get Todo"
+  getTodo(id: String!): todo
+}
+
+type Mutation {
+  "Create Todo"
+  createTodo(input: createTodoRequestInput): todo
+}
+
+input createTodoRequestInput {
+  title: String
+}
+
+type owner {
+  name: String
+}
+
+type todo {
+  id: String
+  title: String
+  done: Boolean
+  owner: owner
+}
+`, New(todoRouter()).Schema())
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	h := New(todoRouter())
+
+	for name, tc := range map[string]struct {
+		method     string
+		request    Request
+		wantStatus int
+		want       string
+	}{
+		"query": {
+			request:    Request{Query: `{ listTodos(done: false) { title owner { name } } }`},
+			wantStatus: http.StatusOK,
+			want:       `{"data":{"listTodos":[{"title":"write docs","owner":{"name":"ana"}}]}}`,
+		},
+		"aliases and variables": {
+			request: Request{
+				Query:     `query Pair($first: String!, $second: String! = "9") { a: getTodo(id: $first) { id } b: getTodo(id: $second) { __typename id } }`,
+				Variables: map[string]any{"first": "2", "second": "1"},
+			},
+			wantStatus: http.StatusOK,
+			want:       `{"data":{"a":{"id":"2"},"b":{"__typename":"todo","id":"1"}}}`,
+		},
+		"query over GET": {
+			method:     http.MethodGet,
+			request:    Request{Query: `{ getTodo(id: "1") { title } }`},
+			wantStatus: http.StatusOK,
+			want:       `{"data":{"getTodo":{"title":"write docs"}}}`,
+		},
+		"mutation": {
+			request:    Request{Query: `mutation { createTodo(input: {title: "review"}) { id title owner { name } } }`},
+			wantStatus: http.StatusOK,
+			want:       `{"data":{"createTodo":{"id":"3","title":"review","owner":{"name":"cy"}}}}`,
+		},
+		"route error": {
+			request:    Request{Query: `{ getTodo(id: "9") { id } }`},
+			wantStatus: http.StatusOK,
+			want: `{"data":{"getTodo":null},"errors":[{"message":"GET /todos/9 responded 404 Not Found",` +
+				`"path":["getTodo"],"extensions":{"response":{"message":"todo not found"},"status":404}}]}`,
+		},
+		"missing argument": {
+			request:    Request{Query: `{ getTodo { id } }`},
+			wantStatus: http.StatusOK,
+			want:       `{"data":{"getTodo":null},"errors":[{"message":"Argument \"id\" of field \"getTodo\" is required","path":["getTodo"]}]}`,
+		},
+		"unknown field": {
+			request:    Request{Query: `{ getTodo(id: "1") { priority } }`},
+			wantStatus: http.StatusOK,
+			want:       `{"data":{"getTodo":null},"errors":[{"message":"Cannot query field \"priority\" on type \"todo\"","path":["getTodo"]}]}`,
+		},
+		"mutation over GET": {
+			method:     http.MethodGet,
+			request:    Request{Query: `mutation { createTodo(input: {title: "review"}) { id } }`},
+			wantStatus: http.StatusMethodNotAllowed,
+			want:       `{"errors":[{"message":"mutations must be sent with POST"}]}`,
+		},
+		"syntax error": {
+			request:    Request{Query: `{ listTodos { title }`},
+			wantStatus: http.StatusBadRequest,
+			want:       `{"errors":[{"message":"syntax error: expected a name, found end of document"}]}`,
+		},
+		"fragments": {
+			request:    Request{Query: `{ listTodos { ...fields } }`},
+			wantStatus: http.StatusBadRequest,
+			want:       `{"errors":[{"message":"syntax error: fragments are not supported"}]}`,
+		},
+	} {
+		var req *http.Request
+		if tc.method == http.MethodGet {
+			req = httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(tc.request.Query), nil)
+		} else {
+			body, err := json.Marshal(tc.request)
+			require.NoError(t, err)
+			req = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+		}
+		req.Header.Set("X-User", "cy")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, tc.wantStatus, rec.Code, name)
+		assert.JSONEq(t, tc.want, rec.Body.String(), name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	doc, err := parse(`
+		# list the first todos
+		query Todos($limit: Int = 10, $tags: [String!]) {
+			todos: listTodos(limit: $limit, tags: $tags, sort: TITLE, filter: {title: "a\"b", ratio: -1.5e2}) {
+				id
+			}
+		}
+		mutation { createTodo(input: null) { id } }
+	`)
+	require.NoError(t, err)
+	require.Len(t, doc.operations, 2)
+
+	query := doc.operations[0]
+	assert.Equal(t, "query", query.kind)
+	assert.Equal(t, "Todos", query.name)
+	assert.Equal(t, map[string]value{"limit": int64(10), "tags": nil}, query.variables)
+	assert.Equal(t, []*selection{{
+		alias: "todos",
+		name:  "listTodos",
+		arguments: map[string]value{
+			"limit":  variable("limit"),
+			"tags":   variable("tags"),
+			"sort":   enumValue("TITLE"),
+			"filter": map[string]value{"title": `a"b`, "ratio": -150.0},
+		},
+		selections: []*selection{{name: "id"}},
+	}}, query.selections)
+	assert.Equal(t, "mutation", doc.operations[1].kind)
+
+	for src, want := range map[string]string{
+		``:                            "document has no operations",
+		`{ }`:                         "empty selection set at position 2",
+		`subscription { a }`:          "subscriptions are not supported",
+		`{ a(b: $c) @skip }`:          "directives are not supported",
+		`query ($a: Int = $b) { a }`:  `expected a value, found "$" at position 17`,
+		`{ a(b: "unterminated) }`:     "unterminated string at position 7",
+		`{ a(b: 1.2.3) }`:             `unexpected character '.' at position 10`,
+		`{ a(b: "\q") }`:              `invalid escape \q at position 8`,
+		`{ a % }`:                     `unexpected character '%' at position 4`,
+		`fragment f on Todo { id }`:   "fragments are not supported",
+		`query Named [ { a } ]`:       `expected "{", found "[" at position 12`,
+		`{ a(b: {c: [1, 2}) { id } }`: `expected a value, found "}" at position 16`,
+	} {
+		_, err := parse(src)
+		assert.EqualError(t, err, want, src)
+	}
+}
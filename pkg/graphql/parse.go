@@ -0,0 +1,549 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is a parsed GraphQL request document
+type document struct {
+	operations []*operation
+}
+
+// operation is a query or mutation of a document
+type operation struct {
+	kind       string // "query" or "mutation"
+	name       string
+	variables  map[string]value // Default values of the declared variables, nil when they have none
+	selections []*selection
+}
+
+// selection is a field selected in a selection set
+type selection struct {
+	alias      string
+	name       string
+	arguments  map[string]value
+	selections []*selection
+}
+
+// key returns the name the field's value is returned under
+func (s *selection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+// value is an argument value, possibly referencing variables
+type value interface{}
+
+// variable references a variable of the operation
+type variable string
+
+// enumValue is an enum literal, passed to routes as its name
+type enumValue string
+
+// tokenKind classifies the tokens of a document
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a lexical token of a document
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// lexer splits a document into tokens
+type lexer struct {
+	src string
+	pos int
+}
+
+// next returns the next token, skipping whitespace, commas and comments
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return l.scan()
+		}
+	}
+	return token{kind: tokenEOF, pos: l.pos}, nil
+}
+
+// scan reads the token starting at the current position
+func (l *lexer) scan() (token, error) {
+	start := l.pos
+	c := l.src[l.pos]
+
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunctuator, value: "...", pos: start}, nil
+	case strings.ContainsRune("!$&()/:=@[]{}|", rune(c)):
+		l.pos++
+		return token{kind: tokenPunctuator, value: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.scanNumber()
+	case c == '"':
+		return l.scanString()
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return token{}, fmt.Errorf("unexpected character %q at position %d", r, start)
+}
+
+// scanNumber reads an integer or float literal
+func (l *lexer) scanNumber() (token, error) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	digits := func() {
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+	}
+
+	digits()
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		digits()
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		digits()
+	}
+
+	literal := l.src[start:l.pos]
+	if _, err := strconv.ParseFloat(literal, 64); err != nil {
+		return token{}, fmt.Errorf("invalid number %q at position %d", literal, start)
+	}
+	return token{kind: kind, value: literal, pos: start}, nil
+}
+
+// scanString reads a string or block string literal
+func (l *lexer) scanString() (token, error) {
+	start := l.pos
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		end := strings.Index(l.src[l.pos+3:], `"""`)
+		if end < 0 {
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+		}
+		l.pos += 3 + end + 3
+		return token{kind: tokenString, value: l.src[start+3 : l.pos-3], pos: start}, nil
+	}
+
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokenString, value: b.String(), pos: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+		case c == '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, fmt.Errorf("unterminated string at position %d", start)
+			}
+			escape := l.src[l.pos+1]
+			l.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				b.WriteByte(escape)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, fmt.Errorf("invalid unicode escape at position %d", l.pos-2)
+				}
+				code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("invalid unicode escape at position %d", l.pos-2)
+				}
+				b.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, fmt.Errorf("invalid escape \\%c at position %d", escape, l.pos-2)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+// isLetter reports whether c is an ASCII letter
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser builds a document from tokens
+type parser struct {
+	lexer lexer
+	tok   token
+}
+
+// parse parses a request document. Fragments and directives aren't
+// supported.
+func parse(src string) (*document, error) {
+	p := &parser{lexer: lexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{}
+	for p.tok.kind != tokenEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.operations = append(doc.operations, op)
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document has no operations")
+	}
+	return doc, nil
+}
+
+// advance reads the next token
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// peek reports whether the current token is the punctuator
+func (p *parser) peek(punctuator string) bool {
+	return p.tok.kind == tokenPunctuator && p.tok.value == punctuator
+}
+
+// expect consumes the punctuator
+func (p *parser) expect(punctuator string) error {
+	if !p.peek(punctuator) {
+		return p.unexpected(fmt.Sprintf("%q", punctuator))
+	}
+	return p.advance()
+}
+
+// name consumes a name
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected("a name")
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+// unexpected reports the current token where something else was expected
+func (p *parser) unexpected(expected string) error {
+	if p.tok.kind == tokenEOF {
+		return fmt.Errorf("expected %s, found end of document", expected)
+	}
+	return fmt.Errorf("expected %s, found %q at position %d", expected, p.tok.value, p.tok.pos)
+}
+
+// parseOperation parses an operation, or a shorthand query
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: "query"}
+	if p.peek("{") {
+		selections, err := p.parseSelectionSet()
+		op.selections = selections
+		return op, err
+	}
+
+	if p.tok.kind != tokenName {
+		return nil, p.unexpected("an operation")
+	}
+	switch p.tok.value {
+	case "query", "mutation":
+		op.kind = p.tok.value
+	case "fragment":
+		return nil, fmt.Errorf("fragments are not supported")
+	case "subscription":
+		return nil, fmt.Errorf("subscriptions are not supported")
+	default:
+		return nil, p.unexpected("an operation")
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("(") {
+		variables, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		op.variables = variables
+	}
+	if p.peek("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	selections, err := p.parseSelectionSet()
+	op.selections = selections
+	return op, err
+}
+
+// parseVariableDefinitions parses the variables of an operation and their
+// default values; their types aren't checked, the routes validate the values
+func (p *parser) parseVariableDefinitions() (map[string]value, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	defaults := map[string]value{}
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if err := p.skipType(); err != nil {
+			return nil, err
+		}
+
+		defaults[name] = nil
+		if p.peek("=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if defaults[name], err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return defaults, p.advance()
+}
+
+// skipType consumes a type reference, e.g. [ID!]!
+func (p *parser) skipType() error {
+	if p.peek("[") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.name(); err != nil {
+		return err
+	}
+
+	if p.peek("!") {
+		return p.advance()
+	}
+	return nil
+}
+
+// parseSelectionSet parses the fields between braces
+func (p *parser) parseSelectionSet() ([]*selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []*selection
+	for !p.peek("}") {
+		if p.peek("...") {
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+
+		s, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, s)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("empty selection set at position %d", p.tok.pos)
+	}
+	return selections, p.advance()
+}
+
+// parseField parses a field with its alias, arguments and selections
+func (p *parser) parseField() (*selection, error) {
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &selection{name: name}
+	if p.peek(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		s.alias = name
+		if s.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		s.arguments = map[string]value{}
+		for !p.peek(")") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if s.arguments[name], err = p.parseValue(false); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+	if p.peek("{") {
+		if s.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parseValue parses a value; constant values can't reference variables
+func (p *parser) parseValue(constant bool) (value, error) {
+	tok := p.tok
+	switch {
+	case p.peek("$") && !constant:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		return variable(name), err
+
+	case p.peek("["):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []value{}
+		for !p.peek("]") {
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, p.advance()
+
+	case p.peek("{"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		object := map[string]value{}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if object[name], err = p.parseValue(constant); err != nil {
+				return nil, err
+			}
+		}
+		return object, p.advance()
+
+	case tok.kind == tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q at position %d", tok.value, tok.pos)
+		}
+		return n, p.advance()
+
+	case tok.kind == tokenFloat:
+		f, _ := strconv.ParseFloat(tok.value, 64)
+		return f, p.advance()
+
+	case tok.kind == tokenString:
+		return tok.value, p.advance()
+
+	case tok.kind == tokenName:
+		var v value
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = enumValue(tok.value)
+		}
+		return v, p.advance()
+	}
+
+	return nil, p.unexpected("a value")
+}
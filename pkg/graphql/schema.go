@@ -0,0 +1,405 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cirocosta/openapi-router-go/pkg/router"
+)
+
+// Built-in scalars, and JSON for values without a documented structure
+const (
+	scalarString  = "String"
+	scalarInt     = "Int"
+	scalarFloat   = "Float"
+	scalarBoolean = "Boolean"
+	scalarJSON    = "JSON"
+)
+
+// typeRef is the type of a field or argument: a named scalar or object type,
+// or a list of another type
+type typeRef struct {
+	name    string   // Name of a scalar or object type, empty for lists
+	elem    *typeRef // Type of the items of lists
+	nonNull bool
+}
+
+// String formats the reference in SDL, e.g. [Todo]!
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// objectType is an object or input object type reflected from a struct
+type objectType struct {
+	name   string
+	input  bool
+	fields []objectField
+}
+
+// field returns the field with the name, if any
+func (o *objectType) field(name string) (objectField, bool) {
+	for _, f := range o.fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return objectField{}, false
+}
+
+// objectField is a field of an object type, named after its JSON member
+type objectField struct {
+	name string
+	typ  *typeRef
+}
+
+// argument is an argument of a root field, bound to a path or query
+// parameter or to the request body
+type argument struct {
+	name  string
+	param string // Name of the parameter the argument is sent as
+	in    string // "path", "query" or "body"
+	typ   *typeRef
+}
+
+// rootField is a query or mutation field resolved by a route
+type rootField struct {
+	name        string
+	description string
+	route       router.RouteInfo
+	arguments   []argument
+	typ         *typeRef
+}
+
+// schema is the GraphQL schema of a router's routes
+type schema struct {
+	queries   []*rootField
+	mutations []*rootField
+	objects   map[string]*objectType
+	names     map[typeKey]string // Names of the object types reflected from Go types
+}
+
+// typeKey identifies the object or input object type reflected from a Go type
+type typeKey struct {
+	t     reflect.Type
+	input bool
+}
+
+// namePattern matches valid GraphQL names
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// pathParamPattern matches the parameters of route paths, e.g. {id} or
+// {path...}
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// newSchema builds the schema of the routes: GET routes become queries,
+// routes of other methods become mutations, HEAD and OPTIONS routes are left
+// out
+func newSchema(routes []router.RouteInfo) *schema {
+	s := &schema{objects: map[string]*objectType{}, names: map[typeKey]string{}}
+	used := map[string]bool{}
+
+	for _, route := range routes {
+		method := strings.ToUpper(route.Method)
+		if method == http.MethodHead || method == http.MethodOptions {
+			continue
+		}
+
+		f := &rootField{
+			name:        uniqueName(fieldName(route), used),
+			description: route.Description,
+			route:       route,
+			typ:         s.outputType(route.ResponseType),
+		}
+		if f.description == "" {
+			f.description = route.Name
+		}
+
+		for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+			param := strings.TrimSuffix(match[1], "...")
+			f.arguments = append(f.arguments, argument{
+				name:  argumentName(param),
+				param: param,
+				in:    "path",
+				typ:   &typeRef{name: scalarString, nonNull: true},
+			})
+		}
+		for _, param := range route.Parameters {
+			if param.In != "query" {
+				continue
+			}
+			typ := scalarRef(reflect.TypeOf(param.Schema))
+			typ.nonNull = param.Required
+			f.arguments = append(f.arguments, argument{
+				name:  argumentName(param.Name),
+				param: param.Name,
+				in:    "query",
+				typ:   typ,
+			})
+		}
+		if route.RequestType != nil && method != http.MethodGet {
+			f.arguments = append(f.arguments, argument{
+				name: "input",
+				in:   "body",
+				typ:  s.reflectType(reflect.TypeOf(route.RequestType), true),
+			})
+		}
+
+		if method == http.MethodGet {
+			s.queries = append(s.queries, f)
+		} else {
+			s.mutations = append(s.mutations, f)
+		}
+	}
+
+	return s
+}
+
+// fieldName names the root field of a route after its name, e.g. listTodos
+// for "List Todos", or after its method and path without one
+func fieldName(route router.RouteInfo) string {
+	words := splitWords(route.Name)
+	if len(words) == 0 {
+		words = append(splitWords(strings.ToLower(route.Method)), splitWords(pathParamPattern.ReplaceAllString(route.Path, "by $1"))...)
+	}
+	if len(words) == 0 {
+		return "root"
+	}
+
+	var b strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			b.WriteString(strings.ToLower(word[:1]) + word[1:])
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	name := b.String()
+	if !namePattern.MatchString(name) {
+		name = "_" + name
+	}
+	return name
+}
+
+// splitWords splits text on anything but ASCII letters and digits
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+}
+
+// uniqueName suffixes names already used with a number
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// argumentName turns a parameter name into a valid argument name, e.g.
+// page_size stays as is and filter[status] becomes filter_status_
+func argumentName(param string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, param)
+	if !namePattern.MatchString(name) {
+		name = "_" + name
+	}
+	return name
+}
+
+// scalarRef returns the scalar type of a Go type, or a list of them
+func scalarRef(t reflect.Type) *typeRef {
+	if t == nil {
+		return &typeRef{name: scalarString}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &typeRef{name: scalarString}
+	case reflect.Bool:
+		return &typeRef{name: scalarBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &typeRef{name: scalarInt}
+	case reflect.Float32, reflect.Float64:
+		return &typeRef{name: scalarFloat}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &typeRef{name: scalarString}
+		}
+		return &typeRef{elem: scalarRef(t.Elem())}
+	}
+	return &typeRef{name: scalarJSON}
+}
+
+// outputType returns the type of a route's response type
+func (s *schema) outputType(example any) *typeRef {
+	if example == nil {
+		return &typeRef{name: scalarJSON}
+	}
+	return s.reflectType(reflect.TypeOf(example), false)
+}
+
+// reflectType returns the type of a Go type, reflecting the object types of
+// structs; time.Time and json.RawMessage are scalars like in JSON
+func (s *schema) reflectType(t reflect.Type, input bool) *typeRef {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return &typeRef{name: scalarString}
+	case t == reflect.TypeOf(json.RawMessage{}):
+		return &typeRef{name: scalarJSON}
+	case t.Kind() == reflect.Struct:
+		return &typeRef{name: s.objectType(t, input)}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8:
+		return &typeRef{elem: s.reflectType(t.Elem(), input)}
+	}
+	return scalarRef(t)
+}
+
+// objectType reflects the object type of a struct, named after the Go type
+// (with an Input suffix for input types), and returns its name
+func (s *schema) objectType(t reflect.Type, input bool) string {
+	key := typeKey{t: t, input: input}
+	if name, ok := s.names[key]; ok {
+		return name
+	}
+
+	base := t.Name()
+	if base == "" || !namePattern.MatchString(base) {
+		base = "Object"
+	}
+	if input {
+		base += "Input"
+	}
+	name := base
+	for i := 2; s.objects[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	object := &objectType{name: name, input: input}
+	s.objects[name] = object
+	s.names[key] = name
+
+	object.fields = s.structFields(t, input)
+	return name
+}
+
+// structFields reflects the fields of a struct as encoding/json marshals
+// them, embedded structs included
+func (s *schema) structFields(t reflect.Type, input bool) []objectField {
+	var fields []objectField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, s.structFields(embedded, input)...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		if !namePattern.MatchString(name) {
+			continue
+		}
+		fields = append(fields, objectField{name: name, typ: s.reflectType(sf.Type, input)})
+	}
+	return fields
+}
+
+// SDL prints the schema in the GraphQL schema definition language
+func (s *schema) SDL() string {
+	var b strings.Builder
+	b.WriteString("\"Values without a documented structure, as JSON\"\nscalar JSON\n")
+
+	if len(s.queries) > 0 {
+		b.WriteString("\n")
+		writeRootType(&b, "Query", s.queries)
+	}
+	if len(s.mutations) > 0 {
+		b.WriteString("\n")
+		writeRootType(&b, "Mutation", s.mutations)
+	}
+
+	names := make([]string, 0, len(s.objects))
+	for name := range s.objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		object := s.objects[name]
+		keyword := "type"
+		if object.input {
+			keyword = "input"
+		}
+
+		fmt.Fprintf(&b, "\n%s %s {\n", keyword, name)
+		for _, f := range object.fields {
+			fmt.Fprintf(&b, "  %s: %s\n", f.name, f.typ)
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+// writeRootType prints the Query or Mutation type
+func writeRootType(b *strings.Builder, name string, fields []*rootField) {
+	fmt.Fprintf(b, "type %s {\n", name)
+	for _, f := range fields {
+		if f.description != "" {
+			fmt.Fprintf(b, "  %s\n", strconv.Quote(f.description))
+		}
+		b.WriteString("  " + f.name)
+		if len(f.arguments) > 0 {
+			args := make([]string, len(f.arguments))
+			for i, arg := range f.arguments {
+				args[i] = arg.name + ": " + arg.typ.String()
+			}
+			b.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+		fmt.Fprintf(b, ": %s\n", f.typ)
+	}
+	b.WriteString("}\n")
+}
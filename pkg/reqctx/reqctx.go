@@ -0,0 +1,170 @@
+// package reqctx provides accessors for request-scoped values stored in a context
+package reqctx
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is used when the request does not state a language preference
+const DefaultLocale = "en"
+
+// contextKey is the type of the keys used to store values in a context
+type contextKey int
+
+const (
+	localeKey contextKey = iota
+	locationKey
+)
+
+// WithLocale returns a copy of ctx carrying the given locale (e.g. "en-US")
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
+// Locale returns the request locale, or DefaultLocale if none was set
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// WithLocation returns a copy of ctx carrying the given time zone
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationKey, loc)
+}
+
+// Location returns the request time zone, or UTC if none was set
+func Location(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(locationKey).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// PreferredLanguage returns the highest-weighted language tag of an
+// Accept-Language header value, or an empty string if there is none
+func PreferredLanguage(header string) string {
+	if languages := PreferredLanguages(header); len(languages) > 0 {
+		return languages[0]
+	}
+	return ""
+}
+
+// PreferredLanguages returns the language tags of an Accept-Language header
+// value from the highest-weighted to the lowest, skipping the wildcard,
+// tags with a zero weight and those with a malformed one
+func PreferredLanguages(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		if quality > 0 {
+			tags = append(tags, weighted{tag: tag, quality: quality})
+		}
+	}
+
+	// keep header order among equally weighted tags
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	languages := make([]string, len(tags))
+	for i, tag := range tags {
+		languages[i] = tag.tag
+	}
+	return languages
+}
+
+// layouts maps primary language subtags to date-time and date layouts
+var layouts = map[string][2]string{
+	"en": {"Jan 2, 2006 3:04 PM", "Jan 2, 2006"},
+	"de": {"02.01.2006 15:04", "02.01.2006"},
+	"fr": {"02/01/2006 15:04", "02/01/2006"},
+	"es": {"02/01/2006 15:04", "02/01/2006"},
+	"it": {"02/01/2006 15:04", "02/01/2006"},
+	"pt": {"02/01/2006 15:04", "02/01/2006"},
+	"ja": {"2006/01/02 15:04", "2006/01/02"},
+	"zh": {"2006-01-02 15:04", "2006-01-02"},
+}
+
+// localeLayouts returns the layouts for the request locale, falling back to
+// ISO 8601 for locales without a known convention
+func localeLayouts(ctx context.Context) [2]string {
+	language, _, _ := strings.Cut(strings.ToLower(Locale(ctx)), "-")
+	if l, ok := layouts[language]; ok {
+		return l
+	}
+	return [2]string{"2006-01-02 15:04", time.DateOnly}
+}
+
+// FormatTime formats t in the request time zone using the request locale's conventions
+func FormatTime(ctx context.Context, t time.Time) string {
+	return t.In(Location(ctx)).Format(localeLayouts(ctx)[0])
+}
+
+// FormatDate formats the date of t in the request time zone using the request locale's conventions
+func FormatDate(ctx context.Context, t time.Time) string {
+	return t.In(Location(ctx)).Format(localeLayouts(ctx)[1])
+}
+
+// grpcTimeoutUnits maps the unit suffixes of the grpc-timeout header to durations
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// RequestTimeout returns the timeout a client asked for through the
+// X-Request-Timeout header (a Go duration such as "1.5s") or, failing that,
+// the grpc-timeout header (up to 8 digits and a unit, such as "250m").
+// Missing, malformed and non-positive timeouts are reported as absent.
+func RequestTimeout(header http.Header) (time.Duration, bool) {
+	if value := header.Get("X-Request-Timeout"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		return timeout, err == nil && timeout > 0
+	}
+
+	value := header.Get("Grpc-Timeout")
+	if len(value) < 2 || len(value) > 9 {
+		return 0, false
+	}
+
+	unit, ok := grpcTimeoutUnits[value[len(value)-1]]
+	if !ok {
+		return 0, false
+	}
+
+	amount, err := strconv.ParseUint(value[:len(value)-1], 10, 32)
+	if err != nil || amount == 0 {
+		return 0, false
+	}
+
+	return time.Duration(amount) * unit, true
+}
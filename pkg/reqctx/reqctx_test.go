@@ -0,0 +1,115 @@
+package reqctx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferredLanguage(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		header   string
+		expected string
+	}{
+		"empty":           {header: "", expected: ""},
+		"single":          {header: "de-DE", expected: "de-DE"},
+		"weighted":        {header: "en;q=0.8, fr-CH, de;q=0.9", expected: "fr-CH"},
+		"header order":    {header: "pt, es", expected: "pt"},
+		"wildcard only":   {header: "*", expected: ""},
+		"zero quality":    {header: "en;q=0, ja;q=0.1", expected: "ja"},
+		"invalid quality": {header: "en;q=abc, it;q=0.5", expected: "it"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, PreferredLanguage(tc.header))
+		})
+	}
+}
+
+func TestPreferredLanguages(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"fr-CH", "de", "en"}, PreferredLanguages("en;q=0.8, *, fr-CH, es;q=0, de;q=0.9, it;q=high"))
+	assert.Empty(t, PreferredLanguages(""))
+}
+
+func TestFormatting(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	ts := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		ctx      context.Context
+		wantTime string
+		wantDate string
+	}{
+		"defaults": {
+			ctx:      context.Background(),
+			wantTime: "Mar 5, 2024 2:30 PM",
+			wantDate: "Mar 5, 2024",
+		},
+		"german in berlin": {
+			ctx:      WithLocation(WithLocale(context.Background(), "de-DE"), berlin),
+			wantTime: "05.03.2024 15:30",
+			wantDate: "05.03.2024",
+		},
+		"unknown locale": {
+			ctx:      WithLocale(context.Background(), "xx"),
+			wantTime: "2024-03-05 14:30",
+			wantDate: "2024-03-05",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.wantTime, FormatTime(tc.ctx, ts))
+			assert.Equal(t, tc.wantDate, FormatDate(tc.ctx, ts))
+		})
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		header   http.Header
+		expected time.Duration
+		ok       bool
+	}{
+		"none":           {header: http.Header{}},
+		"duration":       {header: http.Header{"X-Request-Timeout": {"1.5s"}}, expected: 1500 * time.Millisecond, ok: true},
+		"negative":       {header: http.Header{"X-Request-Timeout": {"-1s"}}},
+		"malformed":      {header: http.Header{"X-Request-Timeout": {"5"}}},
+		"grpc":           {header: http.Header{"Grpc-Timeout": {"250m"}}, expected: 250 * time.Millisecond, ok: true},
+		"grpc hours":     {header: http.Header{"Grpc-Timeout": {"2H"}}, expected: 2 * time.Hour, ok: true},
+		"grpc no unit":   {header: http.Header{"Grpc-Timeout": {"250"}}},
+		"grpc too long":  {header: http.Header{"Grpc-Timeout": {"123456789S"}}},
+		"header wins":    {header: http.Header{"X-Request-Timeout": {"2s"}, "Grpc-Timeout": {"1S"}}, expected: 2 * time.Second, ok: true},
+		"grpc zero":      {header: http.Header{"Grpc-Timeout": {"0S"}}},
+		"grpc bad digit": {header: http.Header{"Grpc-Timeout": {"1xS"}}},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			timeout, ok := RequestTimeout(tc.header)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.expected, timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package router
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Names the security schemes are registered under by WithBearerAuth,
+// WithAPIKeyAuth, WithBasicAuth, WithOAuth2 and WithOpenIDConnect, for
+// routes to reference in WithSecurity
+const (
+	BearerAuth    = "bearerAuth"
+	APIKeyAuth    = "apiKeyAuth"
+	BasicAuth     = "basicAuth"
+	OAuth2        = "oauth2"
+	OpenIDConnect = "openIdConnect"
+)
+
+// SecurityScheme documents how clients authenticate, under the spec's
+// components.securitySchemes
+type SecurityScheme struct {
+	Type             string      // "http", "apiKey", "oauth2" or "openIdConnect"
+	Scheme           string      // Authorization scheme of http schemes, e.g. "bearer" or "basic"
+	BearerFormat     string      // Format of bearer tokens, e.g. "JWT" (optional)
+	Name             string      // Name of the header, query parameter or cookie of apiKey schemes
+	In               string      // Where apiKey schemes are sent: "header", "query" or "cookie"
+	Flows            *OAuthFlows // Flows of oauth2 schemes
+	OpenIDConnectURL string      // Discovery document of openIdConnect schemes
+	Description      string      // Description of the scheme (optional)
+}
+
+// OAuthFlows documents the OAuth2 flows a scheme supports; nil flows aren't
+// supported
+type OAuthFlows struct {
+	AuthorizationCode *OAuthFlow
+	ClientCredentials *OAuthFlow
+	Implicit          *OAuthFlow
+	Password          *OAuthFlow
+}
+
+// OAuthFlow documents an OAuth2 flow
+type OAuthFlow struct {
+	AuthorizationURL string            // Required by the authorizationCode and implicit flows
+	TokenURL         string            // Required by the authorizationCode, clientCredentials and password flows
+	RefreshURL       string            // URL for refreshing tokens (optional)
+	Scopes           map[string]string // Descriptions of the flow's scopes, by name
+}
+
+// SecurityRequirement maps the names of the security schemes a request must
+// all satisfy to the scopes they require, as in an OpenAPI security
+// requirement; an empty requirement documents authentication as optional
+type SecurityRequirement map[string][]string
+
+// WithSecurityScheme documents a security scheme under the given name, for
+// routes to reference in WithSecurity
+func (dr *DocRouter) WithSecurityScheme(name string, scheme SecurityScheme) *DocRouter {
+	if dr.securitySchemes == nil {
+		dr.securitySchemes = make(map[string]SecurityScheme)
+	}
+
+	dr.securitySchemes[name] = scheme
+	return dr
+}
+
+// WithBearerAuth documents authentication with JWT bearer tokens in the
+// Authorization header, as BearerAuth
+func (dr *DocRouter) WithBearerAuth() *DocRouter {
+	return dr.WithSecurityScheme(BearerAuth, SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"})
+}
+
+// WithBasicAuth documents HTTP basic authentication, as BasicAuth
+func (dr *DocRouter) WithBasicAuth() *DocRouter {
+	return dr.WithSecurityScheme(BasicAuth, SecurityScheme{Type: "http", Scheme: "basic"})
+}
+
+// WithAPIKeyAuth documents authentication with an API key sent in the named
+// header, query parameter or cookie, as APIKeyAuth. It panics if in is
+// neither "header", "query" nor "cookie".
+func (dr *DocRouter) WithAPIKeyAuth(name, in string) *DocRouter {
+	if !slices.Contains([]string{"header", "query", "cookie"}, in) {
+		panic(fmt.Sprintf("router: invalid API key location %q for %q", in, name))
+	}
+
+	return dr.WithSecurityScheme(APIKeyAuth, SecurityScheme{Type: "apiKey", Name: name, In: in})
+}
+
+// WithOAuth2 documents authentication with OAuth2 access tokens obtained
+// through the given flows, as OAuth2. It panics if no flow is given, or a
+// flow lacks one of its required URLs.
+func (dr *DocRouter) WithOAuth2(flows OAuthFlows) *DocRouter {
+	if err := flows.validate(); err != nil {
+		panic(fmt.Sprintf("router: invalid OAuth2 flows: %v", err))
+	}
+
+	return dr.WithSecurityScheme(OAuth2, SecurityScheme{Type: "oauth2", Flows: &flows})
+}
+
+// WithOpenIDConnect documents authentication with OpenID Connect, whose
+// provider is described by the discovery document at url, as OpenIDConnect
+func (dr *DocRouter) WithOpenIDConnect(url string) *DocRouter {
+	return dr.WithSecurityScheme(OpenIDConnect, SecurityScheme{Type: "openIdConnect", OpenIDConnectURL: url})
+}
+
+// WithSecurity documents a security scheme the route accepts, registered on
+// the router, with the scopes it requires of OAuth2 and OpenID Connect
+// tokens, e.g. WithSecurity(OAuth2, "todos:write"); calling it several times
+// documents alternatives, any of which authenticates requests
+func (rc *RouteConfig) WithSecurity(scheme string, scopes ...string) *RouteConfig {
+	rc.security = append(rc.security, SecurityRequirement{scheme: scopes})
+	return rc
+}
+
+// WithSecurityRequirements documents alternative requirements of the route,
+// any of which authenticates requests, each satisfied when all its schemes
+// are, e.g. mTLS and a bearer token, or else an API key:
+//
+//	WithSecurityRequirements(
+//		SecurityRequirement{"mutualTLS": nil, BearerAuth: nil},
+//		SecurityRequirement{APIKeyAuth: nil},
+//	)
+func (rc *RouteConfig) WithSecurityRequirements(requirements ...SecurityRequirement) *RouteConfig {
+	rc.security = append(rc.security, requirements...)
+	return rc
+}
+
+// WithSecurity documents a security scheme every route of the group accepts
+// (see RouteConfig.WithSecurity)
+func (g *RouteGroup) WithSecurity(scheme string, scopes ...string) *RouteGroup {
+	g.security = append(g.security, SecurityRequirement{scheme: scopes})
+	return g
+}
+
+// WithSecurityRequirements documents alternative requirements of every
+// route of the group (see RouteConfig.WithSecurityRequirements)
+func (g *RouteGroup) WithSecurityRequirements(requirements ...SecurityRequirement) *RouteGroup {
+	g.security = append(g.security, requirements...)
+	return g
+}
+
+// validate checks that at least one flow is given, with its required URLs
+func (f OAuthFlows) validate() error {
+	if f.AuthorizationCode == nil && f.ClientCredentials == nil && f.Implicit == nil && f.Password == nil {
+		return fmt.Errorf("no flow given")
+	}
+
+	for _, flow := range []struct {
+		name                 string
+		flow                 *OAuthFlow
+		authorization, token bool
+	}{
+		{"authorizationCode", f.AuthorizationCode, true, true},
+		{"clientCredentials", f.ClientCredentials, false, true},
+		{"implicit", f.Implicit, true, false},
+		{"password", f.Password, false, true},
+	} {
+		if flow.flow == nil {
+			continue
+		}
+		if flow.authorization && flow.flow.AuthorizationURL == "" {
+			return fmt.Errorf("%s flow without an authorization URL", flow.name)
+		}
+		if flow.token && flow.flow.TokenURL == "" {
+			return fmt.Errorf("%s flow without a token URL", flow.name)
+		}
+	}
+
+	return nil
+}
+
+// allSecuritySchemes returns the security schemes of the router and of its
+// mounted routers, whose routes reference them too; the router's own take
+// precedence
+func (dr *DocRouter) allSecuritySchemes() map[string]SecurityScheme {
+	schemes := make(map[string]SecurityScheme)
+	for _, m := range dr.mounts {
+		maps.Copy(schemes, m.router.allSecuritySchemes())
+	}
+	maps.Copy(schemes, dr.securitySchemes)
+
+	return schemes
+}
+
+// securitySchemeObject documents a security scheme
+func securitySchemeObject(scheme SecurityScheme) map[string]any {
+	object := map[string]any{
+		"type": scheme.Type,
+	}
+	for key, value := range map[string]string{
+		"scheme":           scheme.Scheme,
+		"bearerFormat":     scheme.BearerFormat,
+		"name":             scheme.Name,
+		"in":               scheme.In,
+		"openIdConnectUrl": scheme.OpenIDConnectURL,
+		"description":      scheme.Description,
+	} {
+		if value != "" {
+			object[key] = value
+		}
+	}
+	if scheme.Flows != nil {
+		object["flows"] = oauthFlowsObject(*scheme.Flows)
+	}
+
+	return object
+}
+
+// oauthFlowsObject documents the supported OAuth2 flows
+func oauthFlowsObject(flows OAuthFlows) map[string]any {
+	object := map[string]any{}
+	for name, flow := range map[string]*OAuthFlow{
+		"authorizationCode": flows.AuthorizationCode,
+		"clientCredentials": flows.ClientCredentials,
+		"implicit":          flows.Implicit,
+		"password":          flows.Password,
+	} {
+		if flow == nil {
+			continue
+		}
+
+		scopes := flow.Scopes
+		if scopes == nil {
+			scopes = map[string]string{}
+		}
+		flowObject := map[string]any{"scopes": scopes}
+		for key, value := range map[string]string{
+			"authorizationUrl": flow.AuthorizationURL,
+			"tokenUrl":         flow.TokenURL,
+			"refreshUrl":       flow.RefreshURL,
+		} {
+			if value != "" {
+				flowObject[key] = value
+			}
+		}
+		object[name] = flowObject
+	}
+
+	return object
+}
+
+// securityRequirements documents the security requirements of a route, any
+// of which authenticates requests
+func securityRequirements(requirements []SecurityRequirement) []any {
+	result := make([]any, 0, len(requirements))
+	for _, requirement := range requirements {
+		object := make(map[string]any, len(requirement))
+		for scheme, scopes := range requirement {
+			if scopes == nil {
+				scopes = []string{}
+			}
+			object[scheme] = scopes
+		}
+		result = append(result, object)
+	}
+
+	return result
+}
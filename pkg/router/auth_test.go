@@ -0,0 +1,160 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecuritySchemes(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	admin := NewDocRouter().WithBasicAuth()
+	admin.Get("/stats", noop).WithSecurity(BasicAuth).Register()
+
+	r := NewDocRouter().
+		WithBearerAuth().
+		WithAPIKeyAuth("X-API-Key", "header")
+	r.Get("/health", noop).Register()
+	r.Get("/users", noop).WithSecurity(BearerAuth).WithSecurity(APIKeyAuth).Register()
+	r.Group("/todos").WithSecurity(BearerAuth).Get("", noop).Register()
+	r.Mount("/admin", admin)
+
+	spec := r.OpenAPI().Generate()
+
+	want := map[string]any{
+		BearerAuth: map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+		APIKeyAuth: map[string]any{"type": "apiKey", "name": "X-API-Key", "in": "header"},
+		BasicAuth:  map[string]any{"type": "http", "scheme": "basic"},
+	}
+	if diff := cmp.Diff(want, spec["components"].(map[string]any)["securitySchemes"]); diff != "" {
+		t.Errorf("security schemes mismatch (-want +got):\n%s", diff)
+	}
+
+	paths := spec["paths"].(map[string]any)
+	security := func(path string) any {
+		return paths[path].(map[string]any)["get"].(map[string]any)["security"]
+	}
+	assert.Nil(t, security("/health"), "routes without requirements are left to the spec's")
+	assert.Equal(t, []any{
+		map[string]any{BearerAuth: []string{}},
+		map[string]any{APIKeyAuth: []string{}},
+	}, security("/users"), "either scheme authenticates requests")
+	assert.Equal(t, []any{map[string]any{BearerAuth: []string{}}}, security("/todos"))
+	assert.Equal(t, []any{map[string]any{BasicAuth: []string{}}}, security("/admin/stats"))
+
+	assert.PanicsWithValue(t, `router: invalid API key location "body" for "api_key"`, func() {
+		NewDocRouter().WithAPIKeyAuth("api_key", "body")
+	})
+}
+
+func TestOAuth2(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().
+		WithOAuth2(OAuthFlows{
+			AuthorizationCode: &OAuthFlow{
+				AuthorizationURL: "https://auth.example.com/authorize",
+				TokenURL:         "https://auth.example.com/token",
+				Scopes:           map[string]string{"todos:read": "Read todos", "todos:write": "Change todos"},
+			},
+			ClientCredentials: &OAuthFlow{TokenURL: "https://auth.example.com/token"},
+		}).
+		WithOpenIDConnect("https://auth.example.com/.well-known/openid-configuration")
+	r.Post("/todos", noop).WithSecurity(OAuth2, "todos:read", "todos:write").WithSecurity(OpenIDConnect).Register()
+
+	spec := r.OpenAPI().Generate()
+
+	want := map[string]any{
+		OAuth2: map[string]any{
+			"type": "oauth2",
+			"flows": map[string]any{
+				"authorizationCode": map[string]any{
+					"authorizationUrl": "https://auth.example.com/authorize",
+					"tokenUrl":         "https://auth.example.com/token",
+					"scopes":           map[string]string{"todos:read": "Read todos", "todos:write": "Change todos"},
+				},
+				"clientCredentials": map[string]any{
+					"tokenUrl": "https://auth.example.com/token",
+					"scopes":   map[string]string{},
+				},
+			},
+		},
+		OpenIDConnect: map[string]any{
+			"type":             "openIdConnect",
+			"openIdConnectUrl": "https://auth.example.com/.well-known/openid-configuration",
+		},
+	}
+	if diff := cmp.Diff(want, spec["components"].(map[string]any)["securitySchemes"]); diff != "" {
+		t.Errorf("security schemes mismatch (-want +got):\n%s", diff)
+	}
+
+	operation := spec["paths"].(map[string]any)["/todos"].(map[string]any)["post"].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{OAuth2: []string{"todos:read", "todos:write"}},
+		map[string]any{OpenIDConnect: []string{}},
+	}, operation["security"])
+
+	for name, tc := range map[string]struct {
+		flows     OAuthFlows
+		wantPanic string
+	}{
+		"no flow": {
+			wantPanic: "router: invalid OAuth2 flows: no flow given",
+		},
+		"authorization code without token URL": {
+			flows:     OAuthFlows{AuthorizationCode: &OAuthFlow{AuthorizationURL: "https://auth.example.com/authorize"}},
+			wantPanic: "router: invalid OAuth2 flows: authorizationCode flow without a token URL",
+		},
+		"implicit without authorization URL": {
+			flows:     OAuthFlows{Implicit: &OAuthFlow{}},
+			wantPanic: "router: invalid OAuth2 flows: implicit flow without an authorization URL",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.PanicsWithValue(t, tc.wantPanic, func() {
+				NewDocRouter().WithOAuth2(tc.flows)
+			})
+		})
+	}
+}
+
+func TestSecurityRequirements(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().
+		WithBearerAuth().
+		WithAPIKeyAuth("X-API-Key", "header").
+		WithSecurityScheme("mutualTLS", SecurityScheme{Type: "mutualTLS"})
+	r.Group("/payments").
+		WithSecurityRequirements(SecurityRequirement{"mutualTLS": nil, BearerAuth: nil}).
+		Post("", noop).
+		WithSecurity(APIKeyAuth).
+		Register()
+	r.Get("/todos", noop).
+		WithSecurityRequirements(SecurityRequirement{BearerAuth: {"todos:read"}}, SecurityRequirement{}).
+		Register()
+
+	paths := r.OpenAPI().Generate()["paths"].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{"mutualTLS": []string{}, BearerAuth: []string{}},
+		map[string]any{APIKeyAuth: []string{}},
+	}, paths["/payments"].(map[string]any)["post"].(map[string]any)["security"],
+		"mTLS and a bearer token, or else an API key")
+	assert.Equal(t, []any{
+		map[string]any{BearerAuth: []string{"todos:read"}},
+		map[string]any{},
+	}, paths["/todos"].(map[string]any)["get"].(map[string]any)["security"],
+		"an empty requirement makes authentication optional")
+}
@@ -0,0 +1,261 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bindingTags are the struct tags binding a field to a request parameter,
+// by the parameter's location
+var bindingTags = []string{"path", "query", "header"}
+
+// BindError is returned by Bind when request values don't convert to the
+// types of their fields
+type BindError struct {
+	Errors []ValidationError
+}
+
+// Error lists the values that failed to bind
+func (e *BindError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = fmt.Sprintf("%s parameter %s %s", err.In, err.Field, err.Message)
+	}
+	return "invalid request parameters: " + strings.Join(messages, "; ")
+}
+
+// Bind fills the struct pointed to by v from the request: fields tagged
+// `path:"name"`, `query:"name"` or `header:"Name"` from the parameter of that
+// name, the other fields from the JSON body of methods that carry one.
+// Parameters are bound to strings, booleans, numbers, time.Time (RFC 3339),
+// pointers to those for optional values, and slices of those for repeated
+// query parameters; missing parameters leave their field untouched.
+//
+// Routes documenting the struct with WithRequest document the bound fields as
+// parameters rather than as part of the body.
+func Bind(r *http.Request, v any) error {
+	return bind(r, v, methodTakesBody(r.Method))
+}
+
+// bind fills the struct pointed to by v, decoding the JSON body if asked to;
+// parameters take precedence over body fields
+func bind(r *http.Request, v any, decodeBody bool) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: %T is not a pointer to a struct", v)
+	}
+
+	if decodeBody && r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+			return errors.New("invalid request format")
+		}
+	}
+
+	var errs []ValidationError
+	elem := value.Elem()
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Type().Field(i)
+		in, name := bindingTag(field)
+		if in == "" || field.PkgPath != "" {
+			continue
+		}
+
+		var values []string
+		switch in {
+		case "path":
+			if value := r.PathValue(name); value != "" {
+				values = []string{value}
+			}
+		case "query":
+			values = r.URL.Query()[name]
+		case "header":
+			values = r.Header.Values(name)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := setField(elem.Field(i), values); err != nil {
+			errs = append(errs, invalidValue{field: name, in: in, msg: errorMessage(err)}.validationError(nil))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &BindError{Errors: errs}
+	}
+	return nil
+}
+
+// bindingTag returns the location and name of the parameter a field is bound to
+func bindingTag(field reflect.StructField) (in, name string) {
+	for _, tag := range bindingTags {
+		if name := field.Tag.Get(tag); name != "" {
+			return tag, name
+		}
+	}
+	return "", ""
+}
+
+// setField converts request values to the type of a field
+func setField(field reflect.Value, values []string) error {
+	switch field.Kind() {
+	case reflect.Ptr:
+		target := reflect.New(field.Type().Elem())
+		if err := setField(target.Elem(), values); err != nil {
+			return err
+		}
+		field.Set(target)
+		return nil
+	case reflect.Slice:
+		items := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setField(items.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		field.Set(items)
+		return nil
+	}
+
+	value := values[0]
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return newMessage("must be an RFC 3339 date-time")
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return newMessage("must be a boolean")
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return newMessage("must be an integer")
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return newMessage("must be a non-negative integer")
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return newMessage("must be a number")
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("can't be bound to %s", field.Type())
+	}
+
+	return nil
+}
+
+// bindingParameters documents the fields of a request type bound to
+// parameters. Path parameters are always required, others when their field
+// is validated as required.
+func bindingParameters(requestType any) []Parameter {
+	typ := requestStruct(requestType)
+	if typ == nil {
+		return nil
+	}
+
+	var params []Parameter
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		in, name := bindingTag(field)
+		if in == "" || field.PkgPath != "" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		param := Parameter{
+			Name:        name,
+			In:          in,
+			Description: field.Tag.Get("doc"),
+			Required:    in == "path" || validateRequired(field),
+			Schema:      reflect.Zero(fieldType).Interface(),
+			Pattern:     field.Tag.Get("pattern"),
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			param.Enum = strings.Split(enum, ",")
+		}
+		if minimum, err := strconv.ParseFloat(field.Tag.Get("minimum"), 64); err == nil {
+			param.Minimum = &minimum
+		}
+		if maximum, err := strconv.ParseFloat(field.Tag.Get("maximum"), 64); err == nil {
+			param.Maximum = &maximum
+		}
+
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// hasBodyFields reports whether a request type has fields decoded from the
+// body, i.e. it isn't a struct whose fields are all bound to parameters
+func hasBodyFields(requestType any) bool {
+	typ := requestStruct(requestType)
+	if typ == nil {
+		return true
+	}
+
+	bound := false
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if in, _ := bindingTag(field); in != "" {
+			bound = true
+			continue
+		}
+		if field.Tag.Get("json") != "-" && (field.PkgPath == "" || field.Anonymous) {
+			return true
+		}
+	}
+	return !bound
+}
+
+// addBindingParameters adds the parameters bound by a request type to the
+// declared ones, unless declared already
+func addBindingParameters(params []Parameter, requestType any) []Parameter {
+	for _, bound := range bindingParameters(requestType) {
+		declared := slices.ContainsFunc(params, func(param Parameter) bool {
+			return param.In == bound.In && param.Name == bound.Name
+		})
+		if !declared {
+			params = append(params, bound)
+		}
+	}
+	return params
+}
+
+// requestStruct returns the struct type of a request type, or nil if it
+// isn't a struct or pointer to one
+func requestStruct(requestType any) reflect.Type {
+	if requestType == nil {
+		return nil
+	}
+	return structType(reflect.TypeOf(requestType))
+}
@@ -0,0 +1,168 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listTodosRequest binds parameters from every location
+type listTodosRequest struct {
+	ProjectID string     `path:"projectId"`
+	Limit     int        `query:"limit" doc:"Page size" minimum:"1" maximum:"100"`
+	Status    *string    `query:"status" enum:"open,done"`
+	Tags      []string   `query:"tag"`
+	Since     time.Time  `query:"since"`
+	Done      bool       `query:"done"`
+	RequestID string     `header:"X-Request-ID" validate:"required"`
+	Filter    todoFilter `json:"filter"`
+}
+
+// todoFilter is decoded from the body
+type todoFilter struct {
+	Text string `json:"text"`
+}
+
+func TestBind(t *testing.T) {
+	t.Parallel()
+
+	status := "open"
+
+	for name, tc := range map[string]struct {
+		method  string
+		target  string
+		header  http.Header
+		body    string
+		want    listTodosRequest
+		wantErr string
+	}{
+		"every location": {
+			method: http.MethodPost,
+			target: "/projects/p1/todos?limit=10&status=open&tag=a&tag=b&since=2024-01-02T03:04:05Z&done=true",
+			header: http.Header{"X-Request-Id": {"req-1"}},
+			body:   `{"filter":{"text":"milk"}}`,
+			want: listTodosRequest{
+				ProjectID: "p1",
+				Limit:     10,
+				Status:    &status,
+				Tags:      []string{"a", "b"},
+				Since:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Done:      true,
+				RequestID: "req-1",
+				Filter:    todoFilter{Text: "milk"},
+			},
+		},
+		"missing parameters are left alone": {
+			method: http.MethodGet,
+			target: "/projects/p1/todos",
+			want:   listTodosRequest{ProjectID: "p1"},
+		},
+		"body ignored for GET": {
+			method: http.MethodGet,
+			target: "/projects/p1/todos",
+			body:   `{"filter":{"text":"milk"}}`,
+			want:   listTodosRequest{ProjectID: "p1"},
+		},
+		"empty body": {
+			method: http.MethodPost,
+			target: "/projects/p1/todos?limit=5",
+			want:   listTodosRequest{ProjectID: "p1", Limit: 5},
+		},
+		"invalid values": {
+			method:  http.MethodGet,
+			target:  "/projects/p1/todos?limit=ten&done=maybe",
+			wantErr: "invalid request parameters: query parameter limit must be an integer; query parameter done must be a boolean",
+		},
+		"invalid time": {
+			method:  http.MethodGet,
+			target:  "/projects/p1/todos?since=yesterday",
+			wantErr: "invalid request parameters: query parameter since must be an RFC 3339 date-time",
+		},
+		"invalid body": {
+			method:  http.MethodPost,
+			target:  "/projects/p1/todos",
+			body:    `{"filter":`,
+			wantErr: "invalid request format",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got listTodosRequest
+			var err error
+			mux := http.NewServeMux()
+			mux.HandleFunc("/projects/{projectId}/todos", func(w http.ResponseWriter, r *http.Request) {
+				err = Bind(r, &got)
+			})
+
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("not a struct pointer", func(t *testing.T) {
+		t.Parallel()
+
+		var got listTodosRequest
+		err := Bind(httptest.NewRequest(http.MethodGet, "/", nil), got)
+		require.EqualError(t, err, "bind: router.listTodosRequest is not a pointer to a struct")
+	})
+}
+
+func TestBindingDocumentation(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Post("/projects/{projectId}/todos", func(w http.ResponseWriter, r *http.Request) {}).
+		WithRequest(listTodosRequest{}).
+		WithParameter(Parameter{Name: "limit", In: "query", Description: "Declared limit", Schema: 0}).
+		Register()
+	r.Get("/projects/{projectId}", func(w http.ResponseWriter, r *http.Request) {}).
+		WithRequest(getUserRequest{}).
+		Register()
+
+	spec := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes()).Generate()
+	paths := spec["paths"].(map[string]any)
+
+	post := paths["/projects/{projectId}/todos"].(map[string]any)["post"].(map[string]any)
+	var params []string
+	var limit map[string]any
+	for _, param := range post["parameters"].([]any) {
+		param := param.(map[string]any)
+		params = append(params, param["in"].(string)+" "+param["name"].(string))
+		if param["name"] == "limit" {
+			limit = param
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"query limit", "path projectId", "query status", "query tag", "query since", "query done", "header X-Request-ID",
+	}, params)
+
+	assert.Equal(t, "Declared limit", limit["description"], "declared parameters take precedence")
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	body := schemas["listTodosRequest"].(map[string]any)
+	if diff := cmp.Diff([]string{"filter"}, keys(body["properties"])); diff != "" {
+		t.Errorf("body properties mismatch (-want +got):\n%s", diff)
+	}
+
+	get := paths["/projects/{projectId}"].(map[string]any)["get"].(map[string]any)
+	assert.NotContains(t, get, "requestBody", "requests binding every field have no body")
+}
@@ -0,0 +1,217 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// validateBody wraps a handler so that JSON request bodies are checked
+// against the schema of the request type before the handler runs. Malformed
+// JSON is rejected with a 400, bodies not matching the schema with a 422.
+// The body is restored for the handler to decode.
+func validateBody(requestType any, next http.Handler) http.Handler {
+	schema := jsonSchema(requestType)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request body",
+				[]invalidValue{{in: "body", msg: textMessage(err.Error())}})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var body any
+		if err := decoder.Decode(&body); err != nil {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request body",
+				[]invalidValue{{in: "body", msg: textMessage(err.Error())}})
+			return
+		}
+
+		if errs := validateJSON("", body, schema); len(errs) > 0 {
+			writeValidationError(w, r, http.StatusUnprocessableEntity, "invalid request body", errs)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateJSON checks a decoded JSON value against a schema, returning a
+// validation error for every violation found. The field is the path of the
+// value within the body, e.g. "items[0].name".
+func validateJSON(field string, value any, schema map[string]any) []invalidValue {
+	if schema == nil {
+		return nil
+	}
+	invalid := func(msg *message) []invalidValue {
+		return []invalidValue{{field: field, in: "body", msg: msg}}
+	}
+
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || schema["type"] == nil {
+			return nil
+		}
+		return invalid(newMessage("must not be null"))
+	}
+
+	var errs []invalidValue
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, s := range allOf {
+			sub, _ := s.(map[string]any)
+			errs = append(errs, validateJSON(field, value, sub)...)
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return invalid(newMessage("must be an object"))
+		}
+		errs = append(errs, validateJSONObject(field, object, schema)...)
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return invalid(newMessage("must be an array"))
+		}
+		if msg := checkCount(len(items), "items", schema["minItems"], schema["maxItems"]); msg != nil {
+			return invalid(msg)
+		}
+		itemSchema, _ := schema["items"].(map[string]any)
+		for i, item := range items {
+			errs = append(errs, validateJSON(fmt.Sprintf("%s[%d]", field, i), item, itemSchema)...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return invalid(newMessage("must be a string"))
+		}
+		if msg := validateString(s, schema); msg != nil {
+			return invalid(msg)
+		}
+	case "integer", "number", "boolean":
+		if msg := validateScalar(value, schema); msg != nil {
+			return invalid(msg)
+		}
+	}
+
+	return errs
+}
+
+// validateJSONObject checks the required and declared properties of an
+// object, and its other properties against additionalProperties
+func validateJSONObject(field string, object map[string]any, schema map[string]any) []invalidValue {
+	prefix := field
+	if prefix != "" {
+		prefix += "."
+	}
+
+	var errs []invalidValue
+	required, _ := schema["required"].([]string)
+	for _, name := range required {
+		if _, exists := object[name]; !exists {
+			errs = append(errs, invalidValue{field: prefix + name, in: "body", msg: newMessage("is required")})
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	additional, _ := schema["additionalProperties"].(map[string]any)
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		propertySchema, declared := properties[key].(map[string]any)
+		if !declared {
+			// undeclared properties are ignored when decoding structs
+			propertySchema = additional
+		}
+		errs = append(errs, validateJSON(prefix+key, object[key], propertySchema)...)
+	}
+
+	return errs
+}
+
+// validateString checks a string against the schema's enum, length, pattern
+// and format
+func validateString(s string, schema map[string]any) *message {
+	length := utf8.RuneCountInString(s)
+	if msg := checkCount(length, "characters", schema["minLength"], schema["maxLength"]); msg != nil {
+		return msg
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		if values := enumStrings(enum); !slices.Contains(values, s) {
+			return newMessage("must be one of: %s", strings.Join(values, ", "))
+		}
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := compilePattern(pattern); err == nil && !re.MatchString(s) {
+			return newMessage("must match pattern %s", pattern)
+		}
+	}
+
+	format, _ := schema["format"].(string)
+	if !conformsToFormat(reflect.StructField{}, format, s) {
+		return newMessage("must be a valid %s", format)
+	}
+
+	return nil
+}
+
+// checkCount verifies a length against the schema's bounds for it, counting
+// units such as "items"
+func checkCount(n int, unit string, minimum, maximum any) *message {
+	if minimum, ok := minimum.(int); ok && n < minimum {
+		return newMessage("must have at least %d "+unit, minimum)
+	}
+	if maximum, ok := maximum.(int); ok && n > maximum {
+		return newMessage("must have at most %d "+unit, maximum)
+	}
+	return nil
+}
+
+// validateScalar checks a number or boolean against the schema, reusing the
+// checks of query parameters on its textual form
+func validateScalar(value any, schema map[string]any) *message {
+	switch value.(type) {
+	case json.Number:
+		if schema["type"] == "boolean" {
+			return typeMessage(schema["type"])
+		}
+	case bool:
+		if schema["type"] != "boolean" {
+			return typeMessage(schema["type"])
+		}
+	default:
+		return typeMessage(schema["type"])
+	}
+
+	return validateValue(fmt.Sprint(value), schema)
+}
+
+// typeMessage returns the message of values not of a scalar schema type
+func typeMessage(schemaType any) *message {
+	switch schemaType {
+	case "integer":
+		return newMessage("must be an integer")
+	case "boolean":
+		return newMessage("must be a boolean")
+	}
+	return newMessage("must be a number")
+}
@@ -0,0 +1,155 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bodyValidationItem is the request type of the body validation tests
+type bodyValidationItem struct {
+	Name     string            `json:"name" pattern:"^[a-z]+$" maxLength:"12"`
+	Status   string            `json:"status,omitempty" enum:"open,done"`
+	Priority int               `json:"priority,omitempty"`
+	Due      *time.Time        `json:"due,omitempty"`
+	Tags     []string          `json:"tags,omitempty" maxItems:"2"`
+	Labels   map[string]int    `json:"labels,omitempty"`
+	Owner    *bodyValidationID `json:"owner,omitempty"`
+}
+
+// bodyValidationID is a nested object of the body validation tests
+type bodyValidationID struct {
+	ID    string `json:"id"`
+	Email string `json:"email,omitempty" format:"email"`
+}
+
+func TestRequestValidation(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		body       string
+		wantStatus int
+		wantErrors []ValidationError
+	}{
+		"valid": {
+			body:       `{"name": "groceries", "status": "open", "priority": 2, "due": "2023-01-10T17:00:00Z", "tags": ["home"], "extra": true}`,
+			wantStatus: http.StatusOK,
+		},
+		"malformed": {
+			body:       `{"name": `,
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{{In: "body", Code: CodeInvalid, Message: "unexpected EOF"}},
+		},
+		"missing required": {
+			body:       `{"priority": 1}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationError{{Field: "name", In: "body", Code: CodeRequired, Message: "is required"}},
+		},
+		"wrong types": {
+			body:       `{"name": 1, "priority": 1.5, "tags": "home", "labels": {"a": true}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationError{
+				{Field: "labels.a", In: "body", Code: CodeType, Message: "must be an integer"},
+				{Field: "name", In: "body", Code: CodeType, Message: "must be a string"},
+				{Field: "priority", In: "body", Code: CodeType, Message: "must be an integer"},
+				{Field: "tags", In: "body", Code: CodeType, Message: "must be an array"},
+			},
+		},
+		"constraints": {
+			body:       `{"name": "Groceries", "status": "archived", "due": "tomorrow", "tags": [null]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationError{
+				{Field: "due", In: "body", Code: CodeFormat, Message: "must be a valid date-time"},
+				{Field: "name", In: "body", Code: CodePattern, Message: "must match pattern ^[a-z]+$"},
+				{Field: "status", In: "body", Code: CodeEnum, Message: "must be one of: open, done"},
+				{Field: "tags[0]", In: "body", Code: CodeNotNull, Message: "must not be null"},
+			},
+		},
+		"bounds": {
+			body:       `{"name": "groceriesandmore", "tags": ["a", "b", "c"]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationError{
+				{Field: "name", In: "body", Code: CodeMaxLength, Message: "must have at most 12 characters"},
+				{Field: "tags", In: "body", Code: CodeMaxItems, Message: "must have at most 2 items"},
+			},
+		},
+		"nested object": {
+			body:       `{"name": "groceries", "owner": {"email": "nobody"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationError{
+				{Field: "owner.id", In: "body", Code: CodeRequired, Message: "is required"},
+				{Field: "owner.email", In: "body", Code: CodeFormat, Message: "must be a valid email"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			r.Route("POST", "/items", func(w http.ResponseWriter, r *http.Request) {
+				// the handler still reads the body
+				data, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tc.body, string(data))
+			}).
+				WithRequest(bodyValidationItem{}).
+				WithRequestValidation().
+				Register()
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantErrors == nil {
+				return
+			}
+
+			var resp ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, "invalid request body", resp.Error)
+
+			if diff := cmp.Diff(tc.wantErrors, resp.Errors); diff != "" {
+				t.Errorf("validation errors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRequestValidationDocumentation(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("POST", "/items", noop).
+		WithRequest(bodyValidationItem{}).
+		WithErrorResponse("400", "Bad Request", nil).
+		WithRequestValidation().
+		Register()
+	r.Route("POST", "/uploads", noop).
+		WithRequest(bodyValidationItem{}).
+		WithRequestContentType("multipart/form-data").
+		WithRequestValidation().
+		Register()
+
+	paths := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	responses := func(path string) map[string]any {
+		return paths[path].(map[string]any)["post"].(map[string]any)["responses"].(map[string]any)
+	}
+
+	// the route's own 400 is kept
+	assert.Equal(t, "Bad Request", responses("/items")["400"].(map[string]any)["description"])
+	assert.Equal(t, "request body not matching its schema", responses("/items")["422"].(map[string]any)["description"])
+
+	// only JSON bodies are validated
+	assert.NotContains(t, responses("/uploads"), "422")
+}
@@ -0,0 +1,134 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// cacheDirectivePattern matches a Cache-Control directive, e.g. "public",
+// "max-age=60" or `no-cache="Set-Cookie"`
+var cacheDirectivePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*(=([0-9A-Za-z-]+|"[^"]*"))?$`)
+
+// WithCacheControl declares the caching policy of the route's responses, e.g.
+// "public, max-age=60". The Cache-Control header is set on its successful
+// and 304 responses unless the handler sets one itself, and documented on
+// them and in the operation's description. It panics on malformed
+// directives.
+func (rc *RouteConfig) WithCacheControl(directives string) *RouteConfig {
+	if err := validateCacheControl(directives); err != nil {
+		panic(fmt.Sprintf("router: invalid cache control %q: %v", directives, err))
+	}
+
+	rc.cacheControl = directives
+	return rc
+}
+
+// validateCacheControl checks the syntax of comma-separated directives
+func validateCacheControl(directives string) error {
+	if strings.TrimSpace(directives) == "" {
+		return fmt.Errorf("no directives")
+	}
+
+	for _, directive := range strings.Split(directives, ",") {
+		directive = strings.TrimSpace(directive)
+		if !cacheDirectivePattern.MatchString(directive) {
+			return fmt.Errorf("malformed directive %q", directive)
+		}
+	}
+	return nil
+}
+
+// cachesStatus reports whether the policy applies to responses with the
+// status code
+func cachesStatus(statusCode int) bool {
+	return statusCode/100 == 2 || statusCode == http.StatusNotModified
+}
+
+// cacheControlMiddleware sets the route's Cache-Control header on the
+// responses it applies to
+func cacheControlMiddleware(directives string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&cacheControlWriter{ResponseWriter: w, directives: directives}, r)
+	})
+}
+
+// cacheControlWriter sets Cache-Control when the status code is written
+type cacheControlWriter struct {
+	http.ResponseWriter
+	directives  string
+	wroteHeader bool
+}
+
+// WriteHeader sets the header for cacheable status codes the handler didn't
+// set it for
+func (w *cacheControlWriter) WriteHeader(statusCode int) {
+	// informational responses precede the final one
+	if !w.wroteHeader && statusCode >= 200 {
+		w.wroteHeader = true
+		if cachesStatus(statusCode) && w.Header().Get("Cache-Control") == "" {
+			w.Header().Set("Cache-Control", w.directives)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write writes the implicit 200 status first
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// cacheControlDescription appends the caching policy, if any, to an
+// operation's description
+func cacheControlDescription(description, directives string) string {
+	if directives == "" {
+		return description
+	}
+
+	policy := fmt.Sprintf("Successful responses carry `Cache-Control: %s`.", directives)
+	if description == "" {
+		return policy
+	}
+	return description + "\n\n" + policy
+}
+
+// addCacheControl documents the Cache-Control header of the inline responses
+// the policy applies to, unless the route documents the header itself
+func addCacheControl(responses map[string]any, directives string) {
+	if directives == "" {
+		return
+	}
+
+	for statusCode, response := range responses {
+		response, ok := response.(map[string]any)
+		if !ok || response["$ref"] != nil || (statusCode[0] != '2' && statusCode != "304") {
+			continue
+		}
+
+		headers, ok := response["headers"].(map[string]any)
+		if !ok {
+			headers = map[string]any{}
+			response["headers"] = headers
+		}
+		if _, exists := headers["Cache-Control"]; exists {
+			continue
+		}
+
+		headers["Cache-Control"] = map[string]any{
+			"description": "Caching policy of the response",
+			"schema": map[string]any{
+				"type":    "string",
+				"example": directives,
+			},
+		}
+	}
+}
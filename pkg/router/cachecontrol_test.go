@@ -0,0 +1,71 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheControl(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Get("/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("id") {
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "stale":
+			w.WriteHeader(http.StatusNotModified)
+		case "private":
+			w.Header().Set("Cache-Control", "private")
+			w.Write([]byte("{}"))
+		default:
+			w.Write([]byte("{}"))
+		}
+	}).
+		WithDescription("Returns a todo").
+		WithResponse(map[string]any{}).
+		WithErrorResponse("404", "Not found", nil).
+		WithCacheControl("public, max-age=60").
+		Register()
+
+	for id, want := range map[string]string{
+		"1":       "public, max-age=60",
+		"stale":   "public, max-age=60",
+		"missing": "",
+		"private": "private",
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/todos/"+id, nil))
+		assert.Equal(t, want, rec.Header().Get("Cache-Control"), id)
+	}
+
+	operation := r.OpenAPI().Generate()["paths"].(map[string]any)["/todos/{id}"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "Returns a todo\n\nSuccessful responses carry `Cache-Control: public, max-age=60`.", operation["description"])
+
+	responses := operation["responses"].(map[string]any)
+	headers, ok := responses["200"].(map[string]any)["headers"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{
+		"description": "Caching policy of the response",
+		"schema":      map[string]any{"type": "string", "example": "public, max-age=60"},
+	}, headers["Cache-Control"])
+	assert.NotContains(t, responses["404"], "headers")
+
+	for name, directives := range map[string]string{
+		"empty":             " ",
+		"empty directive":   "public,,max-age=60",
+		"malformed value":   "max-age=6 0",
+		"unbalanced quotes": `no-cache="Set-Cookie`,
+	} {
+		assert.Panics(t, func() {
+			NewDocRouter().Get("/", nil).WithCacheControl(directives)
+		}, name)
+	}
+	assert.NotPanics(t, func() {
+		NewDocRouter().Get("/", nil).WithCacheControl(`private, no-cache="Set-Cookie", max-age=0`)
+	})
+}
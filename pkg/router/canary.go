@@ -0,0 +1,171 @@
+package router
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync/atomic"
+)
+
+// DefaultCanaryHeader is the request header that pins a request to a variant
+const DefaultCanaryHeader = "X-Canary"
+
+// Canary variants, also accepted as values of the canary header
+const (
+	VariantStable = "stable"
+	VariantCanary = "canary"
+)
+
+// CanaryStatus reports the traffic split of a route and the requests served
+// by each variant
+type CanaryStatus struct {
+	Route          string `json:"route"`           // Method and path of the route, e.g. "GET /todos"
+	Percent        int    `json:"percent"`         // Share of requests sent to the canary
+	StableRequests int64  `json:"stable_requests"` // Requests served by the route's handler
+	StableErrors   int64  `json:"stable_errors"`   // Of which answered with a 5xx status
+	CanaryRequests int64  `json:"canary_requests"` // Requests served by the canary handler
+	CanaryErrors   int64  `json:"canary_errors"`   // Of which answered with a 5xx status
+}
+
+// variantStats counts the requests served by one variant
+type variantStats struct {
+	requests atomic.Int64
+	errors   atomic.Int64
+}
+
+// canary splits a route's traffic between its handler and a canary handler
+type canary struct {
+	route   string
+	percent atomic.Int32
+	stable  variantStats
+	canary  variantStats
+}
+
+// WithCanary sends the given percentage of the route's requests to another
+// handler, e.g. a rewrite of the route's own handler. Requests can be pinned
+// to a variant with the router's canary header, and the split can be changed
+// at runtime through SetCanaryPercent.
+func (rc *RouteConfig) WithCanary(handler http.HandlerFunc, percent int) *RouteConfig {
+	rc.canary = handler
+	rc.canaryPercent = percent
+	return rc
+}
+
+// WithCanaryHeader changes the request header that pins requests to a variant
+func (dr *DocRouter) WithCanaryHeader(name string) *DocRouter {
+	dr.canaryHeader = name
+	return dr
+}
+
+// Canaries returns the status of every route with a canary, ordered by route
+func (dr *DocRouter) Canaries() []CanaryStatus {
+	dr.canaryMutex.RLock()
+	defer dr.canaryMutex.RUnlock()
+
+	statuses := make([]CanaryStatus, 0, len(dr.canaries))
+	for _, c := range dr.canaries {
+		statuses = append(statuses, CanaryStatus{
+			Route:          c.route,
+			Percent:        int(c.percent.Load()),
+			StableRequests: c.stable.requests.Load(),
+			StableErrors:   c.stable.errors.Load(),
+			CanaryRequests: c.canary.requests.Load(),
+			CanaryErrors:   c.canary.errors.Load(),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Route < statuses[j].Route
+	})
+
+	return statuses
+}
+
+// SetCanaryPercent changes the share of requests a route's canary receives
+func (dr *DocRouter) SetCanaryPercent(route string, percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("percent must be between 0 and 100, got %d", percent)
+	}
+
+	dr.canaryMutex.RLock()
+	c, exists := dr.canaries[route]
+	dr.canaryMutex.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("no canary registered for route '%s'", route)
+	}
+
+	c.percent.Store(int32(percent))
+	return nil
+}
+
+// canaryMiddleware registers a canary for the route and dispatches each
+// request to one of the variants
+func canaryMiddleware(dr *DocRouter, route string, percent int, canaryHandler, next http.Handler) http.Handler {
+	if percent < 0 || percent > 100 {
+		panic(fmt.Sprintf("router: canary percent of %s must be between 0 and 100, got %d", route, percent))
+	}
+
+	c := &canary{route: route}
+	c.percent.Store(int32(percent))
+
+	dr.canaryMutex.Lock()
+	if dr.canaries == nil {
+		dr.canaries = make(map[string]*canary)
+	}
+	if _, exists := dr.canaries[route]; exists {
+		panic(fmt.Sprintf("router: multiple canaries for %s", route))
+	}
+	dr.canaries[route] = c
+	dr.canaryMutex.Unlock()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, stats := next, &c.stable
+
+		switch r.Header.Get(dr.canaryHeader) {
+		case VariantCanary:
+			handler, stats = canaryHandler, &c.canary
+		case VariantStable:
+		default:
+			if rand.IntN(100) < int(c.percent.Load()) {
+				handler, stats = canaryHandler, &c.canary
+			}
+		}
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		handler.ServeHTTP(sw, r)
+
+		stats.requests.Add(1)
+		if sw.status >= http.StatusInternalServerError {
+			stats.errors.Add(1)
+		}
+	})
+}
+
+// statusWriter records the status code written through it
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before writing it
+func (w *statusWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.status = statusCode
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write marks the header as written, with the default status if none was set
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
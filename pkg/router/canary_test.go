@@ -0,0 +1,85 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanary(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Route("GET", "/things", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("stable"))
+	}).
+		WithCanary(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}, 0).
+		Register()
+
+	get := func(pin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/things", nil)
+		if pin != "" {
+			req.Header.Set(DefaultCanaryHeader, pin)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// no traffic goes to the canary at 0%, unless pinned
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "stable", get("").Body.String())
+	}
+	assert.Equal(t, http.StatusInternalServerError, get(VariantCanary).Code)
+
+	// all traffic goes to the canary at 100%, unless pinned
+	require.NoError(t, r.SetCanaryPercent("GET /things", 100))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusInternalServerError, get("").Code)
+	}
+	assert.Equal(t, "stable", get(VariantStable).Body.String())
+
+	assert.Equal(t, []CanaryStatus{{
+		Route:          "GET /things",
+		Percent:        100,
+		StableRequests: 11,
+		CanaryRequests: 11,
+		CanaryErrors:   11,
+	}}, r.Canaries())
+}
+
+func TestSetCanaryPercent(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Route("GET", "/things", func(w http.ResponseWriter, r *http.Request) {}).
+		WithCanary(func(w http.ResponseWriter, r *http.Request) {}, 10).
+		Register()
+
+	for name, tc := range map[string]struct {
+		route   string
+		percent int
+		wantErr string
+	}{
+		"valid":         {route: "GET /things", percent: 50},
+		"unknown route": {route: "GET /other", percent: 50, wantErr: "no canary registered for route 'GET /other'"},
+		"out of range":  {route: "GET /things", percent: 101, wantErr: "percent must be between 0 and 100, got 101"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := r.SetCanaryPercent(tc.route, tc.percent)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
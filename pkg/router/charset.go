@@ -0,0 +1,54 @@
+package router
+
+import "strings"
+
+// addCharset appends a charset parameter to the JSON and text media types
+// documented under the value, e.g. "application/json; charset=utf-8".
+// Schemas and examples are left untouched, as are media types that already
+// carry parameters.
+func addCharset(value any, charset string) {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, v := range value {
+			switch key {
+			case "schema", "example", "examples":
+				continue
+			case "content":
+				if content, ok := v.(map[string]any); ok {
+					charsetContent(content, charset)
+				}
+			}
+			addCharset(v, charset)
+		}
+	case map[string]map[string]any:
+		for _, v := range value {
+			addCharset(v, charset)
+		}
+	case []any:
+		for _, v := range value {
+			addCharset(v, charset)
+		}
+	}
+}
+
+// charsetContent renames the textual media types of a content object
+func charsetContent(content map[string]any, charset string) {
+	for mediaType, object := range content {
+		if !textMediaType(mediaType) {
+			continue
+		}
+
+		delete(content, mediaType)
+		content[mediaType+"; charset="+charset] = object
+	}
+}
+
+// textMediaType reports whether a media type without parameters is text,
+// i.e. JSON (including +json types such as application/vnd.api+json) or text/*
+func textMediaType(mediaType string) bool {
+	if strings.Contains(mediaType, ";") {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		strings.HasPrefix(mediaType, "text/")
+}
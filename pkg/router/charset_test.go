@@ -0,0 +1,50 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharset(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Post("/users", noop).
+		WithRequest(UserRequest{}).
+		WithResponse(UserResponse{}).
+		WithAlternateContent("200", "application/vnd.api+json", UserResponse{}).
+		WithContentResponse("201", "Avatar", "image/png", nil).
+		WithErrorResponse("400", "Bad Request", ValidationErrorResponse{}).
+		Register()
+
+	generator := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes())
+	generator.Charset = "utf-8"
+	generator.RegisterResponse("NotFound", map[string]any{
+		"description": "Not Found",
+		"content": map[string]any{
+			"text/plain; charset=us-ascii": map[string]any{},
+			"text/html":                    map[string]any{},
+		},
+	})
+
+	spec := generator.Generate()
+	operation := spec["paths"].(map[string]any)["/users"].(map[string]any)["post"].(map[string]any)
+	contentTypes := func(object any) []string {
+		return keys(object.(map[string]any)["content"])
+	}
+
+	assert.ElementsMatch(t, []string{"application/json; charset=utf-8"}, contentTypes(operation["requestBody"]))
+
+	responses := operation["responses"].(map[string]any)
+	assert.ElementsMatch(t, []string{"application/json; charset=utf-8", "application/vnd.api+json; charset=utf-8"},
+		contentTypes(responses["200"]))
+	assert.ElementsMatch(t, []string{"image/png"}, contentTypes(responses["201"]), "binary media types are left alone")
+	assert.ElementsMatch(t, []string{"application/json; charset=utf-8"}, contentTypes(responses["400"]))
+
+	notFound := spec["components"].(map[string]any)["responses"].(map[string]map[string]any)["NotFound"]
+	assert.ElementsMatch(t, []string{"text/plain; charset=us-ascii", "text/html; charset=utf-8"}, contentTypes(notFound))
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// WithTrustedProxies sets the proxies allowed to report the client address
+// through the Forwarded or X-Forwarded-For headers, such as a load balancer
+func (dr *DocRouter) WithTrustedProxies(proxies ...netip.Prefix) *DocRouter {
+	dr.trustedProxies = proxies
+	return dr
+}
+
+// ClientIP returns the address of the client that made the request. Forwarding
+// headers are only read when the peer is a trusted proxy, walking the chain
+// of forwarded addresses from the peer back to the first untrusted one.
+// Forwarded takes precedence over X-Forwarded-For.
+func (dr *DocRouter) ClientIP(r *http.Request) (netip.Addr, bool) {
+	addr, ok := remoteAddr(r)
+	if !ok || !dr.trustedProxy(addr) {
+		return addr, ok
+	}
+
+	hops := forwardedFor(r.Header.Values("Forwarded"))
+	if hops == nil {
+		hops = xForwardedFor(r.Header.Values("X-Forwarded-For"))
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		next, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			// an obfuscated or unknown hop ends the chain at the proxy reporting it
+			return addr, true
+		}
+
+		addr = next.Unmap()
+		if !dr.trustedProxy(addr) {
+			break
+		}
+	}
+
+	return addr, true
+}
+
+// trustedProxy reports whether the address belongs to a trusted proxy
+func (dr *DocRouter) trustedProxy(addr netip.Addr) bool {
+	return slices.ContainsFunc(dr.trustedProxies, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// forwardedFor returns the for= addresses of Forwarded header values (RFC
+// 7239), in the order the proxies added them
+func forwardedFor(values []string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, node, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if !found || !strings.EqualFold(key, "for") {
+					continue
+				}
+				hops = append(hops, forwardedNode(node))
+			}
+		}
+	}
+
+	return hops
+}
+
+// forwardedNode strips the quotes, brackets and port from a Forwarded node,
+// e.g. "[2001:db8::17]:4711" becomes 2001:db8::17
+func forwardedNode(node string) string {
+	node = strings.Trim(node, `"`)
+	if host, _, found := strings.Cut(strings.TrimPrefix(node, "["), "]"); found {
+		return host
+	}
+	if host, _, found := strings.Cut(node, ":"); found && strings.Count(node, ":") == 1 {
+		return host
+	}
+	return node
+}
+
+// xForwardedFor returns the addresses of X-Forwarded-For header values, in
+// the order the proxies added them
+func xForwardedFor(values []string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, hop := range strings.Split(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	return hops
+}
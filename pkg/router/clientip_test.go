@@ -0,0 +1,99 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter().WithTrustedProxies(
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	)
+
+	for name, tc := range map[string]struct {
+		remoteAddr string
+		header     http.Header
+		want       string
+	}{
+		"no headers": {
+			remoteAddr: "203.0.113.5:1234",
+			want:       "203.0.113.5",
+		},
+		"untrusted peer": {
+			remoteAddr: "203.0.113.5:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.7"}},
+			want:       "203.0.113.5",
+		},
+		"trusted peer": {
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"198.51.100.7"}},
+			want:       "198.51.100.7",
+		},
+		"spoofed entries before the client": {
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"1.2.3.4, 198.51.100.7", "10.0.0.2"}},
+			want:       "198.51.100.7",
+		},
+		"only proxies": {
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}},
+			want:       "10.0.0.3",
+		},
+		"forwarded": {
+			remoteAddr: "[2001:db8::1]:1234",
+			header: http.Header{
+				"Forwarded":       {`for=198.51.100.7;proto=https, for="[2001:db8:cafe::17]:4711"`},
+				"X-Forwarded-For": {"1.2.3.4"},
+			},
+			want: "198.51.100.7",
+		},
+		"forwarded with port": {
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"Forwarded": {`For="198.51.100.7:8080"`}},
+			want:       "198.51.100.7",
+		},
+		"obfuscated hop": {
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"Forwarded": {"for=198.51.100.7, for=_hidden"}},
+			want:       "10.0.0.1",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header = tc.header
+
+			addr, ok := r.ClientIP(req)
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, addr.String())
+		})
+	}
+}
+
+func TestIPFilterTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	filter, err := ParseIPFilter(nil, []string{"198.51.100.0/24"})
+	assert.NoError(t, err)
+
+	r := NewDocRouter().WithIPFilter(filter).WithTrustedProxies(netip.MustParsePrefix("10.0.0.0/8"))
+	r.Route("GET", "/things", func(w http.ResponseWriter, r *http.Request) {}).Register()
+
+	req := httptest.NewRequest(http.MethodGet, "/things", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
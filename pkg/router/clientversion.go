@@ -0,0 +1,244 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ClientVersionHeader is the request header clients send their version in
+const ClientVersionHeader = "X-Client-Version"
+
+// maxClientVersions bounds the distinct versions counted by a router;
+// requests of further versions are counted under OtherClientVersions
+const maxClientVersions = 100
+
+// Versions counted under a shared entry of ClientVersions
+const (
+	UnknownClientVersion = "unknown" // Requests without a version
+	OtherClientVersions  = "other"   // Requests of versions beyond the ones counted individually
+)
+
+// UpgradeRequiredResponse is written with a 426 to clients older than the
+// minimum version of a route
+type UpgradeRequiredResponse struct {
+	Error         string `json:"error" doc:"Error message" example:"client upgrade required"`
+	ClientVersion string `json:"client_version" doc:"Version the client sent in X-Client-Version" example:"2.3.1"`
+	MinVersion    string `json:"min_version" doc:"Oldest client version the operation serves" example:"2.4.0"`
+	UpgradeURL    string `json:"upgrade_url,omitempty" doc:"Where to get a newer client" example:"https://example.com/download"`
+}
+
+// ClientVersionStats counts the requests of a client version to the
+// router's version-gated routes
+type ClientVersionStats struct {
+	Version  string `json:"version"`  // Version sent by clients, UnknownClientVersion or OtherClientVersions
+	Requests int64  `json:"requests"` // Requests of the version
+	Rejected int64  `json:"rejected"` // Of which rejected with 426 Upgrade Required
+}
+
+// clientGate is the minimum client version of a route
+type clientGate struct {
+	minVersion string
+	upgradeURL string
+}
+
+// WithMinClientVersion rejects requests from clients older than the version,
+// e.g. "2.4.0", according to ClientVersionHeader, with a documented 426
+// Upgrade Required pointing to upgradeURL (optional). Requests without the
+// header are served; combine with WithRequiredHeader to reject them too. The
+// versions of the requests are counted in ClientVersions.
+func (rc *RouteConfig) WithMinClientVersion(version, upgradeURL string) *RouteConfig {
+	rc.clientGate = newClientGate(version, upgradeURL)
+	return rc
+}
+
+// WithMinClientVersion sets the minimum client version of every route of the
+// group, like RouteConfig.WithMinClientVersion
+func (g *RouteGroup) WithMinClientVersion(version, upgradeURL string) *RouteGroup {
+	g.clientGate = newClientGate(version, upgradeURL)
+	return g
+}
+
+// newClientGate creates the gate, checking the minimum version
+func newClientGate(version, upgradeURL string) *clientGate {
+	if _, err := parseClientVersion(version); err != nil {
+		panic(fmt.Sprintf("router: invalid minimum client version %q: %v", version, err))
+	}
+	return &clientGate{minVersion: version, upgradeURL: upgradeURL}
+}
+
+// parseClientVersion parses a dotted numeric version such as "2.4" or
+// "v2.4.1", ignoring pre-release and build suffixes like "-beta.1"
+func parseClientVersion(version string) ([]int, error) {
+	core, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), "-")
+	core, _, _ = strings.Cut(core, "+")
+
+	parts := strings.Split(core, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%q is not a dotted numeric version", version)
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+// compareClientVersions compares versions component by component, missing
+// components counting as zero so 2.4 and 2.4.0 are the same
+func compareClientVersions(a, b []int) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// clientVersionCounter counts the requests of each client version
+type clientVersionCounter struct {
+	mu       sync.Mutex
+	versions map[string]*ClientVersionStats
+}
+
+// count records a request of the version
+func (c *clientVersionCounter) count(version string, rejected bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.versions == nil {
+		c.versions = make(map[string]*ClientVersionStats)
+	}
+	stats, exists := c.versions[version]
+	if !exists && len(c.versions) >= maxClientVersions {
+		version = OtherClientVersions
+		stats, exists = c.versions[version]
+	}
+	if !exists {
+		stats = &ClientVersionStats{Version: version}
+		c.versions[version] = stats
+	}
+
+	stats.Requests++
+	if rejected {
+		stats.Rejected++
+	}
+}
+
+// ClientVersions returns the requests to the router's version-gated routes
+// by client version, newest first, followed by the unknown and other ones
+func (dr *DocRouter) ClientVersions() []ClientVersionStats {
+	dr.clientVersions.mu.Lock()
+	stats := make([]ClientVersionStats, 0, len(dr.clientVersions.versions))
+	for _, s := range dr.clientVersions.versions {
+		stats = append(stats, *s)
+	}
+	dr.clientVersions.mu.Unlock()
+
+	slices.SortFunc(stats, func(a, b ClientVersionStats) int {
+		x, errA := parseClientVersion(a.Version)
+		y, errB := parseClientVersion(b.Version)
+		switch {
+		case errA == nil && errB == nil:
+			if c := compareClientVersions(y, x); c != 0 {
+				return c
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		}
+		return strings.Compare(a.Version, b.Version)
+	})
+	return stats
+}
+
+// clientVersionMiddleware rejects requests from clients older than the
+// gate's minimum version and counts the requests of each version
+func clientVersionMiddleware(dr *DocRouter, gate *clientGate, next http.Handler) http.Handler {
+	if gate == nil {
+		return next
+	}
+	minimum, _ := parseClientVersion(gate.minVersion)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := strings.TrimSpace(r.Header.Get(ClientVersionHeader))
+		if header == "" {
+			dr.clientVersions.count(UnknownClientVersion, false)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		version, err := parseClientVersion(header)
+		if err != nil {
+			dr.clientVersions.count(UnknownClientVersion, false)
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request parameters", []invalidValue{{
+				field: ClientVersionHeader,
+				in:    "header",
+				msg:   newMessage("must be a version such as 1.4.2"),
+			}})
+			return
+		}
+
+		if compareClientVersions(version, minimum) < 0 {
+			dr.clientVersions.count(header, true)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUpgradeRequired)
+			json.NewEncoder(w).Encode(UpgradeRequiredResponse{
+				Error:         "client upgrade required",
+				ClientVersion: header,
+				MinVersion:    gate.minVersion,
+				UpgradeURL:    gate.upgradeURL,
+			})
+			return
+		}
+
+		dr.clientVersions.count(header, false)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// addClientVersion documents the client version header and the 426 of a
+// version-gated route, unless the route documents them itself
+func (g *OpenAPIGenerator) addClientVersion(operation map[string]any, route RouteInfo) {
+	operation["x-min-client-version"] = route.MinClientVersion
+
+	parameters, _ := operation["parameters"].([]any)
+	if !slices.ContainsFunc(parameters, func(value any) bool {
+		parameter, _ := value.(map[string]any)
+		name, _ := parameter["name"].(string)
+		return parameter["in"] == "header" && strings.EqualFold(name, ClientVersionHeader)
+	}) {
+		operation["parameters"] = append(parameters, map[string]any{
+			"name":        ClientVersionHeader,
+			"in":          "header",
+			"description": fmt.Sprintf("Version of the calling client; clients older than %s must upgrade", route.MinClientVersion),
+			"schema":      map[string]any{"type": "string"},
+		})
+	}
+
+	responses := operation["responses"].(map[string]any)
+	if _, exists := responses["426"]; !exists {
+		responses["426"] = map[string]any{
+			"description": fmt.Sprintf("client older than %s, which must be upgraded", route.MinClientVersion),
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": g.schemaRef(UpgradeRequiredResponse{}),
+				},
+			},
+		}
+	}
+	g.addValidationResponse(responses)
+}
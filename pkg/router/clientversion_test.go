@@ -0,0 +1,114 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinClientVersion(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	mobile := r.Group("/mobile").WithMinClientVersion("2.4", "https://example.com/download")
+	mobile.Get("/feed", noop).Register()
+	mobile.Get("/legacy", noop).WithMinClientVersion("1.0.0", "").Register()
+	r.Get("/web", noop).Register()
+
+	for name, tc := range map[string]struct {
+		path       string
+		version    string
+		wantStatus int
+		wantBody   any
+	}{
+		"current":           {path: "/mobile/feed", version: "2.4.0", wantStatus: http.StatusOK},
+		"newer":             {path: "/mobile/feed", version: "v2.10.1-beta.2", wantStatus: http.StatusOK},
+		"without a version": {path: "/mobile/feed", wantStatus: http.StatusOK},
+		"route minimum":     {path: "/mobile/legacy", version: "1.9", wantStatus: http.StatusOK},
+		"not gated":         {path: "/web", version: "0.1", wantStatus: http.StatusOK},
+		"stale": {
+			path:       "/mobile/feed",
+			version:    "2.3.9",
+			wantStatus: http.StatusUpgradeRequired,
+			wantBody: &UpgradeRequiredResponse{
+				Error:         "client upgrade required",
+				ClientVersion: "2.3.9",
+				MinVersion:    "2.4",
+				UpgradeURL:    "https://example.com/download",
+			},
+		},
+		"malformed": {
+			path:       "/mobile/feed",
+			version:    "latest",
+			wantStatus: http.StatusBadRequest,
+			wantBody: &ValidationErrorResponse{
+				Error: "invalid request parameters",
+				Errors: []ValidationError{
+					{Field: ClientVersionHeader, In: "header", Code: CodeFormat, Message: "must be a version such as 1.4.2"},
+				},
+			},
+		},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.version != "" {
+			req.Header.Set(ClientVersionHeader, tc.version)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		require.Equal(t, tc.wantStatus, rec.Code, name)
+		switch want := tc.wantBody.(type) {
+		case *UpgradeRequiredResponse:
+			var got UpgradeRequiredResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, *want, got, name)
+		case *ValidationErrorResponse:
+			var got ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, want.Errors, got.Errors, name)
+		}
+	}
+
+	assert.Equal(t, []ClientVersionStats{
+		{Version: "v2.10.1-beta.2", Requests: 1},
+		{Version: "2.4.0", Requests: 1},
+		{Version: "2.3.9", Requests: 1, Rejected: 1},
+		{Version: "1.9", Requests: 1},
+		{Version: UnknownClientVersion, Requests: 2},
+	}, r.ClientVersions(), "requests to routes without a minimum aren't counted")
+
+	operation := r.OpenAPI().Generate()["paths"].(map[string]any)["/mobile/feed"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "2.4", operation["x-min-client-version"])
+	assert.Equal(t, []any{map[string]any{
+		"name":        ClientVersionHeader,
+		"in":          "header",
+		"description": "Version of the calling client; clients older than 2.4 must upgrade",
+		"schema":      map[string]any{"type": "string"},
+	}}, operation["parameters"])
+	responses := operation["responses"].(map[string]any)
+	assert.Equal(t, "client older than 2.4, which must be upgraded", responses["426"].(map[string]any)["description"])
+	assert.Contains(t, responses, "400")
+
+	assert.PanicsWithValue(t, `router: invalid minimum client version "next": "next" is not a dotted numeric version`, func() {
+		NewDocRouter().Get("/", noop).WithMinClientVersion("next", "")
+	})
+}
+
+func TestClientVersionsBound(t *testing.T) {
+	t.Parallel()
+
+	var counter clientVersionCounter
+	for i := 0; i < maxClientVersions+5; i++ {
+		counter.count(fmt.Sprintf("1.%d", i), false)
+	}
+
+	assert.Len(t, counter.versions, maxClientVersions+1)
+	assert.Equal(t, int64(5), counter.versions[OtherClientVersions].Requests)
+}
@@ -0,0 +1,82 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeSamples(t *testing.T) {
+	t.Parallel()
+
+	routes := []RouteInfo{
+		{
+			Method:      "POST",
+			Path:        "/users",
+			Name:        "Create User",
+			RequestType: SimpleType{},
+			Parameters: []Parameter{
+				{Name: "X-Request-Id", In: "header", Schema: "", Required: true},
+			},
+		},
+		{
+			Method: "GET",
+			Path:   "/users/{id}",
+			Name:   "Get User",
+		},
+		{
+			Method: "HEAD",
+			Path:   "/users/{id}",
+			Name:   "Check User",
+		},
+	}
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", routes)
+	require.NoError(t, generator.RegisterCodeSamples("https://api.example.com/"))
+	spec := generator.Generate()
+
+	paths := spec["paths"].(map[string]any)
+
+	postSamples := paths["/users"].(map[string]any)["post"].(map[string]any)["x-codeSamples"]
+	expected := []any{
+		map[string]any{
+			"lang":  "Shell",
+			"label": "curl",
+			"source": "curl -X POST 'https://api.example.com/users' \\\n" +
+				"  -H 'X-Request-Id: <X-Request-Id>' \\\n" +
+				"  -H 'Content-Type: application/json' \\\n" +
+				`  -d '{"name":"","age":0}'`,
+		},
+		map[string]any{
+			"lang":  "Go",
+			"label": "Go",
+			"source": "body := strings.NewReader(`{\"name\":\"\",\"age\":0}`)\n" +
+				"req, err := http.NewRequest(\"POST\", \"https://api.example.com/users\", body)\n" +
+				"if err != nil {\n\treturn err\n}\n" +
+				"req.Header.Set(\"X-Request-Id\", \"<X-Request-Id>\")\n" +
+				"req.Header.Set(\"Content-Type\", \"application/json\")\n" +
+				"\nresp, err := http.DefaultClient.Do(req)\n" +
+				"if err != nil {\n\treturn err\n}\n" +
+				"defer resp.Body.Close()",
+		},
+	}
+	if diff := cmp.Diff(expected, postSamples); diff != "" {
+		t.Errorf("code samples mismatch (-want +got):\n%s", diff)
+	}
+
+	getSamples := paths["/users/{id}"].(map[string]any)["get"].(map[string]any)["x-codeSamples"].([]any)
+	require.Len(t, getSamples, 2)
+	assert.Equal(t, "curl -X GET 'https://api.example.com/users/{id}'", getSamples[0].(map[string]any)["source"])
+
+	headSamples := paths["/users/{id}"].(map[string]any)["head"].(map[string]any)["x-codeSamples"].([]any)
+	assert.Equal(t, "curl -I 'https://api.example.com/users/{id}'", headSamples[0].(map[string]any)["source"])
+}
+
+func TestCodeSamplesUnsupportedLanguage(t *testing.T) {
+	t.Parallel()
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", nil)
+	assert.EqualError(t, generator.RegisterCodeSamples("http://localhost", "cobol"), "unsupported code sample language 'cobol'")
+}
@@ -0,0 +1,177 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// Query parameters of paginated routes
+const (
+	LimitParam  = "limit"
+	CursorParam = "cursor"
+)
+
+// CollectionPolicy bounds the listings of the router's paginated routes, so
+// page sizes and filters are configured in one place instead of per handler
+type CollectionPolicy struct {
+	DefaultPageSize int // Items per page when requests don't set a limit
+	MaxPageSize     int // Largest limit requests can set
+	MaxFilterTerms  int // Most values of each array query parameter, e.g. ?status=open,done (zero for no bound)
+}
+
+// DefaultCollectionPolicy applies to routers without a policy of their own
+var DefaultCollectionPolicy = CollectionPolicy{DefaultPageSize: 20, MaxPageSize: 100}
+
+// Page is the page of a collection a request asks for
+type Page struct {
+	Cursor string // Opaque cursor returned with the previous page, empty for the first one
+	Limit  int    // Items to return at most, within the router's collection policy
+}
+
+// WithCollectionPolicy sets the page sizes and filter bounds of the router's
+// paginated routes, replacing DefaultCollectionPolicy
+func (dr *DocRouter) WithCollectionPolicy(policy CollectionPolicy) *DocRouter {
+	if err := policy.validate(); err != nil {
+		panic(fmt.Sprintf("router: invalid collection policy: %v", err))
+	}
+
+	dr.collection = &policy
+	return dr
+}
+
+// WithPagination marks the route as listing a collection one page at a time.
+// Its cursor and limit query parameters are documented and checked against
+// the router's collection policy, which also bounds the values of its array
+// query parameters, before the handler runs, responding with a structured 400
+// otherwise. The handler reads the requested page with Pagination.
+func (rc *RouteConfig) WithPagination() *RouteConfig {
+	rc.paginated = true
+	return rc
+}
+
+// Pagination returns the page requested from a paginated route, with the
+// limit defaulting to the router's default page size
+func Pagination(ctx context.Context) (Page, bool) {
+	page, ok := ctx.Value(pageKey{}).(Page)
+	return page, ok
+}
+
+// pageKey is the context key under which the requested page is stored
+type pageKey struct{}
+
+// collectionPolicy returns the policy of the router's paginated routes
+func (dr *DocRouter) collectionPolicy() CollectionPolicy {
+	if dr.collection == nil {
+		return DefaultCollectionPolicy
+	}
+	return *dr.collection
+}
+
+// validate checks that the policy can be satisfied
+func (p CollectionPolicy) validate() error {
+	if p.DefaultPageSize < 1 {
+		return fmt.Errorf("default page size must be at least 1, got %d", p.DefaultPageSize)
+	}
+	if p.MaxPageSize < p.DefaultPageSize {
+		return fmt.Errorf("max page size %d is below the default page size %d", p.MaxPageSize, p.DefaultPageSize)
+	}
+	if p.MaxFilterTerms < 0 {
+		return fmt.Errorf("max filter terms can't be negative, got %d", p.MaxFilterTerms)
+	}
+	return nil
+}
+
+// limitParameter documents the limit of paginated routes
+func (p CollectionPolicy) limitParameter() Parameter {
+	minimum, maximum := 1.0, float64(p.MaxPageSize)
+	return Parameter{
+		Name:        LimitParam,
+		In:          "query",
+		Description: fmt.Sprintf("Maximum number of items per page (default %d)", p.DefaultPageSize),
+		Schema:      0,
+		Minimum:     &minimum,
+		Maximum:     &maximum,
+	}
+}
+
+// parameters returns the parameters of a paginated route: its own, with
+// array query parameters bounded by the max filter terms, followed by the
+// cursor and limit unless the route documents them itself
+func (p CollectionPolicy) parameters(params []Parameter) []Parameter {
+	params = slices.Clone(params)
+	for i, param := range params {
+		if p.MaxFilterTerms > 0 && isArrayQueryParam(param) && param.MaxItems == 0 {
+			params[i].MaxItems = p.MaxFilterTerms
+		}
+	}
+
+	for _, param := range []Parameter{
+		{
+			Name:        CursorParam,
+			In:          "query",
+			Description: "Opaque cursor returned with the previous page, omitted for the first page",
+			Schema:      "",
+		},
+		p.limitParameter(),
+	} {
+		if !slices.ContainsFunc(params, func(declared Parameter) bool {
+			return declared.In == "query" && declared.Name == param.Name
+		}) {
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+// isArrayQueryParam reports whether the parameter is a query parameter
+// taking several values
+func isArrayQueryParam(param Parameter) bool {
+	return param.In == "query" && jsonSchema(param.Schema)["type"] == "array"
+}
+
+// paginationMiddleware checks the limit and filters of requests to a
+// paginated route against the router's collection policy, at request time
+// so the policy may be set after the route is registered, and stores the
+// requested page for Pagination
+func paginationMiddleware(dr *DocRouter, params []Parameter, next http.Handler) http.Handler {
+	filters := slices.DeleteFunc(slices.Clone(params), func(param Parameter) bool {
+		return !isArrayQueryParam(param)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy := dr.collectionPolicy()
+		query := r.URL.Query()
+		page := Page{Cursor: query.Get(CursorParam), Limit: policy.DefaultPageSize}
+
+		var errs []invalidValue
+		if values, ok := query[LimitParam]; ok {
+			msg := validateValue(values[0], parameterSchema(policy.limitParameter()))
+			if len(values) > 1 {
+				msg = newMessage("must not be repeated")
+			}
+			if msg == nil {
+				page.Limit, _ = strconv.Atoi(values[0])
+			} else {
+				errs = append(errs, invalidValue{field: LimitParam, in: "query", msg: msg})
+			}
+		}
+		if policy.MaxFilterTerms > 0 {
+			for _, param := range filters {
+				if msg := checkCount(len(QueryArray(r, param)), "items", nil, policy.MaxFilterTerms); msg != nil {
+					errs = append(errs, invalidValue{field: param.Name, in: "query", msg: msg})
+				}
+			}
+		}
+
+		if len(errs) > 0 {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request parameters", errs)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), pageKey{}, page)))
+	})
+}
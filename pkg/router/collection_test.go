@@ -0,0 +1,95 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagination(t *testing.T) {
+	t.Parallel()
+
+	var got Page
+	r := NewDocRouter()
+	r.Get("/todos", func(w http.ResponseWriter, r *http.Request) {
+		got, _ = Pagination(r.Context())
+	}).WithParameter(Parameter{Name: "status", In: "query", Schema: []string{}}).WithPagination().Register()
+	r.WithCollectionPolicy(CollectionPolicy{DefaultPageSize: 10, MaxPageSize: 50, MaxFilterTerms: 2})
+
+	operation := r.OpenAPI().Generate()["paths"].(map[string]any)["/todos"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{
+			"name":   "status",
+			"in":     "query",
+			"schema": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "maxItems": 2},
+		},
+		map[string]any{
+			"name":        "cursor",
+			"in":          "query",
+			"description": "Opaque cursor returned with the previous page, omitted for the first page",
+			"schema":      map[string]any{"type": "string"},
+		},
+		map[string]any{
+			"name":        "limit",
+			"in":          "query",
+			"description": "Maximum number of items per page (default 10)",
+			"schema":      map[string]any{"type": "integer", "minimum": 1.0, "maximum": 50.0},
+		},
+	}, operation["parameters"], "the policy set after registering the route applies")
+	assert.Contains(t, operation["responses"], "400")
+
+	for name, tc := range map[string]struct {
+		query      string
+		wantPage   Page
+		wantErrors []ValidationError
+	}{
+		"defaults": {
+			wantPage: Page{Limit: 10},
+		},
+		"next page": {
+			query:    "?cursor=abc&limit=50&status=open&status=done",
+			wantPage: Page{Cursor: "abc", Limit: 50},
+		},
+		"limit above max": {
+			query:      "?limit=51",
+			wantErrors: []ValidationError{{Field: "limit", In: "query", Code: CodeMaximum, Message: "must be less than or equal to 50"}},
+		},
+		"invalid limit": {
+			query:      "?limit=0&limit=1",
+			wantErrors: []ValidationError{{Field: "limit", In: "query", Code: CodeNotRepeated, Message: "must not be repeated"}},
+		},
+		"too many filter terms": {
+			query:      "?status=open&status=done&status=archived",
+			wantErrors: []ValidationError{{Field: "status", In: "query", Code: CodeMaxItems, Message: "must have at most 2 items"}},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			got = Page{}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/todos"+tc.query, nil))
+
+			if tc.wantErrors == nil {
+				require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+				assert.Equal(t, tc.wantPage, got)
+				return
+			}
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			var resp ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tc.wantErrors, resp.Errors)
+		})
+	}
+
+	_, ok := Pagination(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	assert.False(t, ok)
+
+	assert.PanicsWithValue(t, "router: invalid collection policy: max page size 5 is below the default page size 10", func() {
+		NewDocRouter().WithCollectionPolicy(CollectionPolicy{DefaultPageSize: 10, MaxPageSize: 5})
+	})
+}
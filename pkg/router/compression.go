@@ -0,0 +1,238 @@
+package router
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EncodingGzip is the content encoding the router compresses responses with
+const EncodingGzip = "gzip"
+
+// DefaultCompressionMinSize is the smallest response body, in bytes,
+// compressed by policies without a MinSize; smaller bodies grow once framed
+const DefaultCompressionMinSize = 1024
+
+// CompressionPolicy describes how the router compresses responses for
+// clients accepting it through Accept-Encoding
+type CompressionPolicy struct {
+	MinSize int // Smallest response body compressed, in bytes (zero for DefaultCompressionMinSize)
+
+	// Document advertises the supported encodings of every operation in
+	// x-content-encodings and documents the Accept-Encoding parameter, which
+	// some client generators configure transparent decompression from
+	Document bool
+}
+
+// WithCompression gzips the router's responses to clients accepting it, when
+// they are large enough and of a compressible media type such as JSON or
+// text. Responses already carrying a Content-Encoding are left alone. Like
+// CORS, it must be set before the router starts serving.
+func (dr *DocRouter) WithCompression(policy CompressionPolicy) *DocRouter {
+	if policy.MinSize == 0 {
+		policy.MinSize = DefaultCompressionMinSize
+	}
+
+	dr.compression = &policy
+	return dr
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header accepts the
+// encoding, explicitly or through "*", with a non-zero quality
+func acceptsEncoding(header, encoding string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != encoding && coding != "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			quality, _ = strconv.ParseFloat(q, 64)
+		}
+		// an explicit entry overrides the wildcard
+		if coding == encoding {
+			return quality > 0
+		}
+		accepted = quality > 0
+	}
+	return accepted
+}
+
+// compressible reports whether responses of a media type shrink when
+// compressed; images, archives and other binary formats usually don't
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return slices.Contains([]string{
+		"application/json",
+		"application/x-ndjson",
+		"application/xml",
+		"application/javascript",
+		"image/svg+xml",
+	}, mediaType)
+}
+
+// compressionMiddleware gzips responses to requests accepting it
+func compressionMiddleware(policy CompressionPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || !acceptsEncoding(r.Header.Get("Accept-Encoding"), EncodingGzip) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, minSize: policy.MinSize}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// compressWriter buffers the start of a response until it is known whether
+// it's worth compressing, then writes it through gzip or as is
+type compressWriter struct {
+	http.ResponseWriter
+	minSize int
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+// WriteHeader defers the status code until the body decides the encoding
+func (w *compressWriter) WriteHeader(statusCode int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	// informational responses precede the final one
+	if statusCode < 200 {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+// Write buffers the body until it reaches the minimum size
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush decides the encoding with what's buffered and flushes it
+func (w *compressWriter) Flush() {
+	if !w.decided && w.status != 0 {
+		w.start(len(w.buf) >= w.minSize)
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// start writes the status and the buffered body, compressing them when
+// worthwhile and the response isn't already encoded
+func (w *compressWriter) start(worthwhile bool) error {
+	w.decided = true
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if worthwhile && header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) &&
+		w.status != http.StatusNoContent && w.status != http.StatusNotModified {
+		header.Set("Content-Encoding", EncodingGzip)
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if w.gz != nil {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// close writes what's still buffered and terminates the gzip stream
+func (w *compressWriter) close() {
+	if !w.decided && w.status != 0 {
+		w.start(false)
+	}
+	if w.gz != nil {
+		w.gz.Close()
+	}
+}
+
+// addCompression advertises the supported encodings and documents the
+// Accept-Encoding parameter of every operation returning content
+func addCompression(paths map[string]any) {
+	for _, item := range paths {
+		for method, operation := range item.(map[string]any) {
+			if method == "head" {
+				continue
+			}
+
+			operation := operation.(map[string]any)
+			operation["x-content-encodings"] = []string{EncodingGzip}
+
+			parameters, _ := operation["parameters"].([]any)
+			if slices.ContainsFunc(parameters, func(value any) bool {
+				parameter, _ := value.(map[string]any)
+				name, _ := parameter["name"].(string)
+				return parameter["in"] == "header" && strings.EqualFold(name, "Accept-Encoding")
+			}) {
+				continue
+			}
+			operation["parameters"] = append(parameters, map[string]any{
+				"name":        "Accept-Encoding",
+				"in":          "header",
+				"description": "Content encodings the client accepts; responses are compressed with gzip when it's accepted",
+				"schema": map[string]any{
+					"type": "string",
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package router
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	t.Parallel()
+
+	large := `{"items": "` + strings.Repeat("a", 2048) + `"}`
+
+	r := NewDocRouter().WithCompression(CompressionPolicy{Document: true})
+	r.Get("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, large[:10])
+		io.WriteString(w, large[10:])
+	}).Register()
+	r.Get("/small", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{}`)
+	}).Register()
+	r.Get("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		io.WriteString(w, large)
+	}).Register()
+	r.Get("/encoded", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "br")
+		io.WriteString(w, large)
+	}).Register()
+	r.Get("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}).Register()
+
+	for name, tc := range map[string]struct {
+		path           string
+		acceptEncoding string
+		wantStatus     int
+		wantEncoding   string
+		wantBody       string
+	}{
+		"compressed": {
+			path:           "/large",
+			acceptEncoding: "br, gzip",
+			wantStatus:     http.StatusCreated,
+			wantEncoding:   EncodingGzip,
+			wantBody:       large,
+		},
+		"not accepted": {
+			path:           "/large",
+			acceptEncoding: "gzip;q=0, *",
+			wantStatus:     http.StatusCreated,
+			wantBody:       large,
+		},
+		"wildcard": {
+			path:           "/large",
+			acceptEncoding: "*;q=0.5",
+			wantStatus:     http.StatusCreated,
+			wantEncoding:   EncodingGzip,
+			wantBody:       large,
+		},
+		"below min size": {
+			path:           "/small",
+			acceptEncoding: "gzip",
+			wantStatus:     http.StatusOK,
+			wantBody:       `{}`,
+		},
+		"incompressible media type": {
+			path:           "/image",
+			acceptEncoding: "gzip",
+			wantStatus:     http.StatusOK,
+			wantBody:       large,
+		},
+		"already encoded": {
+			path:           "/encoded",
+			acceptEncoding: "gzip",
+			wantStatus:     http.StatusOK,
+			wantEncoding:   "br",
+			wantBody:       large,
+		},
+		"no content": {
+			path:           "/empty",
+			acceptEncoding: "gzip",
+			wantStatus:     http.StatusNoContent,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			assert.Equal(t, tc.wantEncoding, rec.Header().Get("Content-Encoding"))
+
+			body := io.Reader(rec.Body)
+			if tc.wantEncoding == EncodingGzip {
+				gz, err := gzip.NewReader(rec.Body)
+				require.NoError(t, err)
+				body = gz
+			}
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantBody, string(data))
+		})
+	}
+
+	paths := r.OpenAPI().Generate()["paths"].(map[string]any)
+	operation := paths["/large"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, []string{EncodingGzip}, operation["x-content-encodings"])
+	assert.Equal(t, []any{map[string]any{
+		"name":        "Accept-Encoding",
+		"in":          "header",
+		"description": "Content encodings the client accepts; responses are compressed with gzip when it's accepted",
+		"schema":      map[string]any{"type": "string"},
+	}}, operation["parameters"])
+
+	undocumented := NewDocRouter().WithCompression(CompressionPolicy{})
+	undocumented.Get("/large", func(w http.ResponseWriter, r *http.Request) {}).Register()
+	operation = undocumented.OpenAPI().Generate()["paths"].(map[string]any)["/large"].(map[string]any)["get"].(map[string]any)
+	assert.NotContains(t, operation, "x-content-encodings")
+}
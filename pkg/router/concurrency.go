@@ -0,0 +1,111 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// GlobalConcurrencyRoute names the router-wide limit in ConcurrencyStatus
+const GlobalConcurrencyRoute = "*"
+
+// ConcurrencyStatus reports the utilization of a concurrency limit
+type ConcurrencyStatus struct {
+	Route    string `json:"route"`     // Route the limit applies to, or "*" for the router-wide limit
+	Limit    int    `json:"limit"`     // Maximum number of requests served at once
+	InFlight int    `json:"in_flight"` // Requests currently being served
+	Rejected int64  `json:"rejected"`  // Requests rejected because the limit was reached
+}
+
+// concurrencyLimiter is a semaphore bounding the requests served at once
+type concurrencyLimiter struct {
+	route    string
+	slots    chan struct{}
+	rejected atomic.Int64
+}
+
+// newConcurrencyLimiter creates a limiter allowing limit concurrent requests
+func newConcurrencyLimiter(route string, limit int) *concurrencyLimiter {
+	if limit <= 0 {
+		panic(fmt.Sprintf("router: concurrency limit of %s must be positive, got %d", route, limit))
+	}
+
+	return &concurrencyLimiter{
+		route: route,
+		slots: make(chan struct{}, limit),
+	}
+}
+
+// acquire takes a slot without waiting, reporting whether one was free
+func (l *concurrencyLimiter) acquire() bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		l.rejected.Add(1)
+		return false
+	}
+}
+
+// release returns a slot taken by acquire
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// status reports the limiter's utilization
+func (l *concurrencyLimiter) status() ConcurrencyStatus {
+	return ConcurrencyStatus{
+		Route:    l.route,
+		Limit:    cap(l.slots),
+		InFlight: len(l.slots),
+		Rejected: l.rejected.Load(),
+	}
+}
+
+// WithConcurrencyLimit bounds the requests served at once across every
+// route; requests beyond the limit are rejected with 503 instead of waiting
+func (dr *DocRouter) WithConcurrencyLimit(limit int) *DocRouter {
+	dr.concurrencyLimiter = newConcurrencyLimiter(GlobalConcurrencyRoute, limit)
+	return dr
+}
+
+// WithConcurrencyLimit bounds the requests the route serves at once, in
+// addition to the router's limit
+func (rc *RouteConfig) WithConcurrencyLimit(limit int) *RouteConfig {
+	rc.concurrencyLimit = limit
+	return rc
+}
+
+// ConcurrencyLimits returns the utilization of the router-wide limit, if
+// set, followed by the route limits in registration order
+func (dr *DocRouter) ConcurrencyLimits() []ConcurrencyStatus {
+	var statuses []ConcurrencyStatus
+	if dr.concurrencyLimiter != nil {
+		statuses = append(statuses, dr.concurrencyLimiter.status())
+	}
+	for _, limiter := range dr.routeLimiters {
+		statuses = append(statuses, limiter.status())
+	}
+
+	return statuses
+}
+
+// concurrencyMiddleware rejects requests once the router's or the route's
+// limit is reached
+func concurrencyMiddleware(dr *DocRouter, limiter *concurrencyLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, l := range []*concurrencyLimiter{dr.concurrencyLimiter, limiter} {
+			if l == nil {
+				continue
+			}
+			if !l.acquire() {
+				w.Header().Set("Retry-After", "1")
+				writeValidationError(w, r, http.StatusServiceUnavailable, "server busy", nil)
+				return
+			}
+			defer l.release()
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
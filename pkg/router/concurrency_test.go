@@ -0,0 +1,84 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+
+	r := NewDocRouter().WithConcurrencyLimit(2)
+	r.Route("GET", "/blocking", func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-unblock
+	}).
+		WithConcurrencyLimit(1).
+		Register()
+	r.Route("GET", "/other", func(w http.ResponseWriter, r *http.Request) {}).Register()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		get("/blocking")
+	}()
+	<-entered
+
+	// the route's own limit is reached, the router's isn't
+	rec := get("/blocking")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error": "server busy", "errors": [], "request_id": "req-1"}`, rec.Body.String())
+	assert.Equal(t, http.StatusOK, get("/other").Code)
+
+	assert.Equal(t, []ConcurrencyStatus{
+		{Route: GlobalConcurrencyRoute, Limit: 2, InFlight: 1},
+		{Route: "GET /blocking", Limit: 1, InFlight: 1, Rejected: 1},
+	}, r.ConcurrencyLimits())
+
+	close(unblock)
+	<-done
+
+	assert.Equal(t, []ConcurrencyStatus{
+		{Route: GlobalConcurrencyRoute, Limit: 2},
+		{Route: "GET /blocking", Limit: 1, Rejected: 1},
+	}, r.ConcurrencyLimits())
+}
+
+func TestConcurrencyLimitDocumentation(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "/limited", noop).WithConcurrencyLimit(5).Register()
+	r.Route("GET", "/unlimited", noop).Register()
+
+	paths := NewOpenAPIGenerator("Test", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	responses := func(path string) map[string]any {
+		return paths[path].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)
+	}
+
+	require.Contains(t, responses("/limited"), "503")
+	assert.NotContains(t, responses("/unlimited"), "503")
+
+	// a router-wide limit applies to every route
+	r.WithConcurrencyLimit(50)
+	paths = NewOpenAPIGenerator("Test", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	assert.Contains(t, responses("/unlimited"), "503")
+}
@@ -0,0 +1,255 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ControllerRoute maps a controller method to a documented route
+type ControllerRoute struct {
+	Method      string          // HTTP method (GET, POST, etc.)
+	Path        string          // URL path
+	Handler     string          // Name of the controller method serving the route
+	Name        string          // Friendly name for the endpoint
+	Description string          // Description of what the endpoint does
+	Responses   []RouteResponse // Additional (error) responses (optional)
+	Tags        []string        // Tags for grouping endpoints
+	Status      int             // Success status code (200, or 204 for methods only returning an error)
+
+	// RequestOnAnyMethod decodes and documents the JSON body even when the
+	// method is GET, HEAD or DELETE (see RouteConfig.WithRequestOnAnyMethod)
+	RequestOnAnyMethod bool
+}
+
+// Controller is implemented by types registered through RegisterController.
+// Each route's Handler names a method with one of the signatures
+//
+//	func(ctx context.Context, req Req) (Resp, error)
+//	func(ctx context.Context) (Resp, error)
+//	func(ctx context.Context, req Req) error
+//	func(ctx context.Context) error
+//
+// where Req is a struct (or pointer to one) bound with Bind: decoded from the
+// JSON body, with fields tagged `path:"name"`, `query:"name"` or
+// `header:"Name"` filled from parameters.
+type Controller interface {
+	Routes() []ControllerRoute
+}
+
+// StatusCoder can be implemented by errors returned from controller methods
+// to choose the HTTP status code of the error response
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// controllerError is the body written when a controller method fails
+type controllerError struct {
+	Error     string `json:"error" doc:"Error message"`
+	Code      string `json:"code,omitempty" doc:"Application error code, for errors of an error catalog"`
+	DocsURL   string `json:"docs_url,omitempty" doc:"Page documenting the error code"`
+	RequestID string `json:"request_id,omitempty" doc:"ID of the request, also sent in the X-Request-ID header, to correlate the error with logs"`
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// RegisterController registers and documents every route of a controller,
+// deriving request and response types from the method signatures
+func (dr *DocRouter) RegisterController(v any) error {
+	controller, ok := v.(Controller)
+	if !ok {
+		return fmt.Errorf("register controller %T: does not implement Controller", v)
+	}
+
+	value := reflect.ValueOf(v)
+	for _, route := range controller.Routes() {
+		method := value.MethodByName(route.Handler)
+		if !method.IsValid() {
+			return fmt.Errorf("register controller %T: method '%s' not found", v, route.Handler)
+		}
+
+		handler, err := newControllerHandler(method)
+		if err != nil {
+			return fmt.Errorf("register controller %T: method '%s': %w", v, route.Handler, err)
+		}
+		handler.decodeBody = route.RequestOnAnyMethod || methodTakesBody(route.Method)
+		handler.status = route.Status
+		if handler.status == 0 {
+			handler.status = http.StatusOK
+			if handler.response == nil {
+				handler.status = http.StatusNoContent
+			}
+		}
+
+		rc := dr.Handle(route.Method, route.Path, handler).
+			WithName(route.Name).
+			WithDescription(route.Description).
+			WithRequest(handler.requestExample()).
+			WithResponse(handler.responseExample()).
+			WithResponseStatus(handler.status).
+			WithTags(route.Tags...)
+
+		if route.RequestOnAnyMethod {
+			rc.WithRequestOnAnyMethod()
+		}
+
+		for _, response := range route.Responses {
+			rc.WithErrorResponse(response.StatusCode, response.Description, response.Schema, response.Examples...)
+		}
+
+		rc.Register()
+	}
+
+	return nil
+}
+
+// controllerHandler adapts a controller method to an http.Handler
+type controllerHandler struct {
+	method     reflect.Value
+	request    reflect.Type // nil when the method takes no request
+	response   reflect.Type // nil when the method only returns an error
+	decodeBody bool         // whether the request is decoded from the JSON body
+	status     int          // status code of successful responses
+}
+
+// newControllerHandler validates a method signature and wraps it
+func newControllerHandler(method reflect.Value) (*controllerHandler, error) {
+	typ := method.Type()
+
+	if typ.NumIn() < 1 || typ.NumIn() > 2 || typ.In(0) != contextType {
+		return nil, errors.New("must accept (context.Context) or (context.Context, request)")
+	}
+	if typ.NumOut() < 1 || typ.NumOut() > 2 || typ.Out(typ.NumOut()-1) != errorType {
+		return nil, errors.New("must return (response, error) or (error)")
+	}
+
+	h := &controllerHandler{method: method}
+
+	if typ.NumIn() == 2 {
+		h.request = typ.In(1)
+
+		structType := h.request
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+		if structType.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("request type %s must be a struct", h.request)
+		}
+	}
+
+	if typ.NumOut() == 2 {
+		h.response = typ.Out(0)
+	}
+
+	return h, nil
+}
+
+// HandlerTypes returns the request and response types of the method
+func (h *controllerHandler) HandlerTypes() (request, response reflect.Type) {
+	return h.request, h.response
+}
+
+// requestExample returns a zero request value for schema generation
+func (h *controllerHandler) requestExample() any {
+	if h.request == nil {
+		return nil
+	}
+	return zeroValue(h.request)
+}
+
+// responseExample returns a zero response value for schema generation
+func (h *controllerHandler) responseExample() any {
+	if h.response == nil {
+		return nil
+	}
+	return zeroValue(h.response)
+}
+
+// zeroValue returns a pointer to a zero value of the (dereferenced) type
+func zeroValue(typ reflect.Type) any {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return reflect.New(typ).Interface()
+}
+
+// ServeHTTP decodes the request, calls the controller method and writes its result
+func (h *controllerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	args := []reflect.Value{reflect.ValueOf(r.Context())}
+
+	if h.request != nil {
+		req, err := h.decodeRequest(r)
+		if err != nil {
+			writeControllerJSON(w, controllerError{Error: err.Error(), RequestID: RequestID(r.Context())}, http.StatusBadRequest)
+			return
+		}
+		args = append(args, req)
+	}
+
+	results := h.method.Call(args)
+
+	if errValue := results[len(results)-1]; !errValue.IsNil() {
+		writeControllerErr(w, r, errValue.Interface().(error))
+		return
+	}
+
+	if h.response == nil {
+		w.WriteHeader(h.status)
+		return
+	}
+
+	writeControllerJSON(w, results[0].Interface(), h.status)
+}
+
+// decodeRequest builds the request argument from path, query and header
+// parameters and, for methods that take one, the JSON body
+func (h *controllerHandler) decodeRequest(r *http.Request) (reflect.Value, error) {
+	req := reflect.New(structType(h.request))
+	if err := bind(r, req.Interface(), h.decodeBody); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if h.request.Kind() == reflect.Ptr {
+		return req, nil
+	}
+	return req.Elem(), nil
+}
+
+// writeControllerErr writes an error returned by a controller method
+func writeControllerErr(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		status = coder.StatusCode()
+	}
+
+	// don't leak internal error details
+	message := err.Error()
+	if status >= http.StatusInternalServerError {
+		message = strings.ToLower(http.StatusText(status))
+	}
+
+	body := controllerError{Error: message, RequestID: RequestID(r.Context())}
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		body.Code = coded.Code
+		body.DocsURL = coded.DocsURL
+	}
+
+	writeControllerJSON(w, body, status)
+}
+
+// writeControllerJSON writes a JSON response with the given status code
+func writeControllerJSON(w http.ResponseWriter, data any, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
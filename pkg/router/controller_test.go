@@ -0,0 +1,193 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notFoundError is a test error carrying its own status code
+type notFoundError struct{}
+
+func (notFoundError) Error() string   { return "user not found" }
+func (notFoundError) StatusCode() int { return http.StatusNotFound }
+
+// getUserRequest binds the user ID from the path
+type getUserRequest struct {
+	ID string `path:"id" json:"-"`
+}
+
+// userController is a test controller exercising each supported signature
+type userController struct{}
+
+func (userController) Routes() []ControllerRoute {
+	return []ControllerRoute{
+		{Method: "GET", Path: "/users", Handler: "ListUsers", Name: "List Users", Tags: []string{"Users"}},
+		{Method: "GET", Path: "/users/{id}", Handler: "GetUser", Name: "Get User"},
+		{Method: "POST", Path: "/users", Handler: "CreateUser", Name: "Create User", Status: http.StatusCreated},
+		{Method: "DELETE", Path: "/users/{id}", Handler: "DeleteUser", Name: "Delete User"},
+		{Method: "GET", Path: "/users/search", Handler: "SearchUsers", Name: "Search Users", RequestOnAnyMethod: true},
+	}
+}
+
+func (userController) ListUsers(ctx context.Context) (UserList, error) {
+	return UserList{Total: 1, Users: []UserResponse{{ID: "1", Name: "Ada"}}}, nil
+}
+
+func (userController) GetUser(ctx context.Context, req getUserRequest) (*UserResponse, error) {
+	if req.ID != "1" {
+		return nil, notFoundError{}
+	}
+	return &UserResponse{ID: req.ID, Name: "Ada"}, nil
+}
+
+func (userController) CreateUser(ctx context.Context, req *UserRequest) (UserResponse, error) {
+	if req.Name == "" {
+		return UserResponse{}, errors.New("database exploded")
+	}
+	return UserResponse{ID: "2", Name: req.Name, Email: req.Email}, nil
+}
+
+func (userController) DeleteUser(ctx context.Context, req getUserRequest) error {
+	return nil
+}
+
+func (userController) SearchUsers(ctx context.Context, req UserRequest) (UserList, error) {
+	if req.Name != "Ada" {
+		return UserList{}, nil
+	}
+	return UserList{Total: 1, Users: []UserResponse{{ID: "1", Name: "Ada"}}}, nil
+}
+
+// badController declares a method with an unsupported signature
+type badController struct{}
+
+func (badController) Routes() []ControllerRoute {
+	return []ControllerRoute{{Method: "GET", Path: "/bad", Handler: "Bad"}}
+}
+
+func (badController) Bad(id string) string { return id }
+
+func TestRegisterController(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	require.NoError(t, r.RegisterController(userController{}))
+
+	t.Run("documentation", func(t *testing.T) {
+		t.Parallel()
+
+		routes := r.GetRoutes()
+		require.Len(t, routes, 5)
+
+		assert.Equal(t, "List Users", routes[0].Name)
+		assert.Equal(t, []string{"Users"}, routes[0].Tags)
+		assert.Nil(t, routes[0].RequestType)
+		assert.IsType(t, &UserList{}, routes[0].ResponseType)
+
+		assert.IsType(t, &UserRequest{}, routes[2].RequestType)
+		assert.IsType(t, &UserResponse{}, routes[2].ResponseType)
+		assert.Equal(t, http.StatusCreated, routes[2].ResponseStatus)
+
+		assert.Nil(t, routes[3].ResponseType)
+		assert.Equal(t, http.StatusNoContent, routes[3].ResponseStatus)
+	})
+
+	for name, tc := range map[string]struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		"no request": {
+			method:     http.MethodGet,
+			path:       "/users",
+			wantStatus: http.StatusOK,
+			wantBody:   `"total":1`,
+		},
+		"path binding": {
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Ada"`,
+		},
+		"body ignored on get": {
+			method:     http.MethodGet,
+			path:       "/users/1",
+			body:       `{invalid`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Ada"`,
+		},
+		"body decoded on get with opt-in": {
+			method:     http.MethodGet,
+			path:       "/users/search",
+			body:       `{"name": "Ada"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"total":1`,
+		},
+		"status coder error": {
+			method:     http.MethodGet,
+			path:       "/users/9",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"error":"user not found","request_id":"req-1"}`,
+		},
+		"json body": {
+			method:     http.MethodPost,
+			path:       "/users",
+			body:       `{"name": "Grace", "email": "grace@example.com"}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `"email":"grace@example.com"`,
+		},
+		"invalid json": {
+			method:     http.MethodPost,
+			path:       "/users",
+			body:       `{invalid`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"invalid request format","request_id":"req-1"}`,
+		},
+		"internal error is hidden": {
+			method:     http.MethodPost,
+			path:       "/users",
+			body:       `{}`,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":"internal server error","request_id":"req-1"}`,
+		},
+		"error only": {
+			method:     http.MethodDelete,
+			path:       "/users/1",
+			wantStatus: http.StatusNoContent,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set(RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.wantBody)
+		})
+	}
+}
+
+func TestRegisterControllerErrors(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+
+	err := r.RegisterController(struct{}{})
+	assert.ErrorContains(t, err, "does not implement Controller")
+
+	err = r.RegisterController(badController{})
+	assert.ErrorContains(t, err, "must accept (context.Context)")
+}
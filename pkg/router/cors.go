@@ -0,0 +1,225 @@
+package router
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy describes the cross-origin requests the router accepts. It
+// answers preflight requests for every registered path and adds the
+// Access-Control-* headers to responses to allowed origins.
+type CORSPolicy struct {
+	AllowedOrigins   []string      // Origins allowed to call the API, "*" for any
+	AllowedHeaders   []string      // Request headers allowed besides the CORS-safelisted ones
+	ExposedHeaders   []string      // Response headers readable by the caller besides the CORS-safelisted ones
+	AllowCredentials bool          // Allow cookies and authorization headers; "*" then echoes the request's origin
+	MaxAge           time.Duration // How long preflight responses can be cached (zero leaves it to the browser)
+
+	// Document emits the preflight OPTIONS operations and Access-Control-*
+	// response headers in the router's spec
+	Document bool
+}
+
+// WithCORS sets the router's CORS policy. It applies before the router's
+// middleware, so preflight requests don't go through authentication, and
+// must be set before the router starts serving.
+func (dr *DocRouter) WithCORS(policy CORSPolicy) *DocRouter {
+	dr.cors = &policy
+	return dr
+}
+
+// allowedOrigin returns the Access-Control-Allow-Origin value for a request
+// origin, or false if the origin isn't allowed
+func (p CORSPolicy) allowedOrigin(origin string) (string, bool) {
+	if slices.Contains(p.AllowedOrigins, origin) {
+		return origin, true
+	}
+	if slices.Contains(p.AllowedOrigins, "*") {
+		if p.AllowCredentials {
+			return origin, true
+		}
+		return "*", true
+	}
+	return "", false
+}
+
+// corsMiddleware answers preflight requests and adds the CORS headers to
+// responses to allowed origins
+func corsMiddleware(dr *DocRouter, policy CORSPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		allowOrigin, allowed := policy.allowedOrigin(origin)
+
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && requestMethod != "" {
+			// preflight: disallowed origins and methods get no CORS headers,
+			// which the browser reports as a CORS failure
+			allowedMethods := dr.pathMethods(r)
+			if allowed && slices.Contains(allowedMethods, requestMethod) {
+				header.Set("Access-Control-Allow-Origin", allowOrigin)
+				header.Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
+				if len(policy.AllowedHeaders) > 0 {
+					header.Set("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+				}
+				if policy.AllowCredentials {
+					header.Set("Access-Control-Allow-Credentials", "true")
+				}
+				if policy.MaxAge > 0 {
+					header.Set("Access-Control-Max-Age", strconv.FormatInt(int64(policy.MaxAge/time.Second), 10))
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if allowed {
+			header.Set("Access-Control-Allow-Origin", allowOrigin)
+			if len(policy.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+			}
+			if policy.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// pathMethods returns the methods routes are registered for on the request's
+// path, leaving out HEAD when only served through GET, as documented
+func (dr *DocRouter) pathMethods(r *http.Request) []string {
+	var allowed []string
+	for _, method := range methods {
+		if method == http.MethodOptions {
+			continue
+		}
+
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		_, pattern := dr.mux.Handler(probe)
+		if pattern != "" && (method != http.MethodHead || !strings.HasPrefix(pattern, http.MethodGet+" ")) {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// addCORS documents the CORS policy: a preflight operation on every path
+// without its own OPTIONS route and the headers of the other operations'
+// responses
+func (g *OpenAPIGenerator) addCORS(paths map[string]any) {
+	for path, item := range paths {
+		operations := item.(map[string]any)
+		for _, operation := range operations {
+			responses, _ := operation.(map[string]any)["responses"].(map[string]any)
+			addCORSHeaders(*g.CORS, responses)
+		}
+
+		if _, exists := operations["options"]; !exists {
+			operations["options"] = corsOperation(*g.CORS, path, operations)
+		}
+	}
+}
+
+// corsOperation documents the preflight request of a path
+func corsOperation(policy CORSPolicy, path string, operations map[string]any) map[string]any {
+	var allowed []string
+	for _, method := range methods {
+		if _, ok := operations[strings.ToLower(method)]; ok {
+			allowed = append(allowed, method)
+		}
+	}
+
+	headers := map[string]any{
+		"Access-Control-Allow-Origin": corsHeader("Origin allowed to make the request", nil),
+		"Access-Control-Allow-Methods": corsHeader("Methods allowed on the path",
+			[]string{strings.Join(allowed, ", ")}),
+	}
+	if len(policy.AllowedHeaders) > 0 {
+		headers["Access-Control-Allow-Headers"] = corsHeader("Request headers allowed besides the CORS-safelisted ones",
+			[]string{strings.Join(policy.AllowedHeaders, ", ")})
+	}
+	if policy.AllowCredentials {
+		headers["Access-Control-Allow-Credentials"] = corsHeader("Whether credentials are allowed", []string{"true"})
+	}
+	if policy.MaxAge > 0 {
+		headers["Access-Control-Max-Age"] = map[string]any{
+			"description": "Seconds the preflight response can be cached",
+			"schema":      map[string]any{"type": "integer"},
+		}
+	}
+
+	return map[string]any{
+		"summary":     "CORS preflight",
+		"description": "Checks whether a cross-origin request to " + path + " is allowed",
+		"operationId": baseOperationID(http.MethodOptions, path),
+		"parameters": []any{
+			corsParameter("Origin", "Origin of the cross-origin request", true),
+			corsParameter("Access-Control-Request-Method", "Method of the cross-origin request", true),
+			corsParameter("Access-Control-Request-Headers", "Headers of the cross-origin request", false),
+		},
+		"responses": map[string]any{
+			"204": map[string]any{
+				"description": "Preflight answered; CORS headers are omitted for disallowed origins and methods",
+				"headers":     headers,
+			},
+		},
+	}
+}
+
+// addCORSHeaders documents the Access-Control-* headers of responses to
+// allowed origins; referenced responses are left untouched
+func addCORSHeaders(policy CORSPolicy, responses map[string]any) {
+	for _, response := range responses {
+		response, ok := response.(map[string]any)
+		if !ok || response["$ref"] != nil {
+			continue
+		}
+
+		headers, ok := response["headers"].(map[string]any)
+		if !ok {
+			headers = map[string]any{}
+			response["headers"] = headers
+		}
+
+		headers["Access-Control-Allow-Origin"] = corsHeader("Origin allowed to read the response", nil)
+		if len(policy.ExposedHeaders) > 0 {
+			headers["Access-Control-Expose-Headers"] = corsHeader("Response headers readable by the caller",
+				[]string{strings.Join(policy.ExposedHeaders, ", ")})
+		}
+		if policy.AllowCredentials {
+			headers["Access-Control-Allow-Credentials"] = corsHeader("Whether credentials are allowed", []string{"true"})
+		}
+	}
+}
+
+// corsHeader documents a string response header, with its fixed values if any
+func corsHeader(description string, enum []string) map[string]any {
+	schema := map[string]any{"type": "string"}
+	if len(enum) > 0 {
+		schema["enum"] = enum
+	}
+	return map[string]any{"description": description, "schema": schema}
+}
+
+// corsParameter documents a request header of preflight requests
+func corsParameter(name, description string, required bool) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "header",
+		"description": description,
+		"required":    required,
+		"schema":      map[string]any{"type": "string"},
+	}
+}
@@ -0,0 +1,139 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	policy := CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"ETag"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	r := NewDocRouter().WithCORS(policy)
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}).Register()
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {}).Register()
+
+	for name, tc := range map[string]struct {
+		method     string
+		header     map[string]string
+		wantStatus int
+		wantHeader map[string]string
+	}{
+		"preflight": {
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://app.example.com", "Access-Control-Request-Method": "POST"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+				"Vary":                             "Origin",
+			},
+		},
+		"preflight of unknown method": {
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://app.example.com", "Access-Control-Request-Method": "DELETE"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		"preflight from other origin": {
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://evil.example.com", "Access-Control-Request-Method": "GET"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		"cross-origin request": {
+			method:     http.MethodGet,
+			header:     map[string]string{"Origin": "https://app.example.com", "Authorization": "Bearer token"},
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":   "https://app.example.com",
+				"Access-Control-Expose-Headers": "ETag",
+			},
+		},
+		"same-origin request": {
+			method:     http.MethodGet,
+			header:     map[string]string{"Authorization": "Bearer token"},
+			wantStatus: http.StatusOK,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "", "Vary": ""},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tc.method, "/users", nil)
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			for key, value := range tc.wantHeader {
+				assert.Equal(t, value, rec.Header().Get(key), key)
+			}
+		})
+	}
+
+	t.Run("any origin", func(t *testing.T) {
+		t.Parallel()
+
+		origin, ok := CORSPolicy{AllowedOrigins: []string{"*"}}.allowedOrigin("https://app.example.com")
+		assert.True(t, ok)
+		assert.Equal(t, "*", origin)
+
+		origin, ok = CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}.allowedOrigin("https://app.example.com")
+		assert.True(t, ok)
+		assert.Equal(t, "https://app.example.com", origin, "credentials require the origin to be echoed")
+	})
+}
+
+func TestCORSDocumentation(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithCORS(CORSPolicy{AllowedOrigins: []string{"*"}, ExposedHeaders: []string{"ETag"}})
+	r.Get("/users", noop).WithResponse(UserList{}).Register()
+	r.Post("/users", noop).Register()
+
+	paths := r.OpenAPI().Generate()["paths"].(map[string]any)
+	assert.NotContains(t, paths["/users"], "options", "only documented when asked to")
+
+	r.WithCORS(CORSPolicy{AllowedOrigins: []string{"*"}, ExposedHeaders: []string{"ETag"}, Document: true})
+	paths = r.OpenAPI().Generate()["paths"].(map[string]any)
+	item := paths["/users"].(map[string]any)
+
+	preflight, ok := item["options"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "CORS preflight", preflight["summary"])
+	headers := preflight["responses"].(map[string]any)["204"].(map[string]any)["headers"].(map[string]any)
+	assert.Equal(t, []string{"GET, POST"}, headers["Access-Control-Allow-Methods"].(map[string]any)["schema"].(map[string]any)["enum"])
+
+	success := item["get"].(map[string]any)["responses"].(map[string]any)["200"].(map[string]any)
+	assert.ElementsMatch(t, []string{"Access-Control-Allow-Origin", "Access-Control-Expose-Headers"}, keys(success["headers"]))
+}
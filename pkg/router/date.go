@@ -0,0 +1,91 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// DateLayout is the layout of date-only values (RFC 3339 full-date)
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date that marshals to and from JSON as "2006-01-02" and
+// is documented with format: date
+type Date struct {
+	time.Time
+}
+
+// NewDate creates a date in UTC
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// String formats the date using DateLayout
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON encodes the date using DateLayout
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.Format(DateLayout) + `"`), nil
+}
+
+// UnmarshalJSON decodes a date in DateLayout, leaving null as the zero date
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return fmt.Errorf("date must be a string in the form %s", DateLayout)
+	}
+
+	t, err := time.Parse(DateLayout, string(data[1:len(data)-1]))
+	if err != nil {
+		return fmt.Errorf("parse date: %w", err)
+	}
+
+	d.Time = t
+	return nil
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	dateType = reflect.TypeOf(Date{})
+)
+
+// timeSchema documents a time.Time or Date field. A `timeFormat` tag
+// overrides the field's layout (for types with their own JSON encoding, as
+// encoding/json always writes time.Time as RFC 3339), and layouts without a
+// time of day are documented as format: date
+func timeSchema(field reflect.StructField, layout string) map[string]any {
+	if tag := field.Tag.Get("timeFormat"); tag != "" {
+		layout = tag
+	}
+
+	format := "date-time"
+	if isDateOnly(layout) {
+		format = "date"
+	}
+
+	schema := map[string]any{
+		"type":   "string",
+		"format": format,
+	}
+	addFieldMetadata(schema, field)
+
+	return schema
+}
+
+// isDateOnly reports whether a time layout omits the time of day
+func isDateOnly(layout string) bool {
+	reference := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	parsed, err := time.Parse(layout, reference.Format(layout))
+	if err != nil {
+		return false
+	}
+
+	return parsed.Hour() == 0 && parsed.Minute() == 0 && parsed.Second() == 0
+}
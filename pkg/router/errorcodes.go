@@ -0,0 +1,157 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrorCode is an application error code: a stable identifier clients can
+// rely on instead of parsing messages, with the status and message it is
+// reported with
+type ErrorCode struct {
+	Code    string `json:"code"`               // Stable identifier, e.g. "todo_not_found"
+	Status  int    `json:"status"`             // HTTP status code of the error response
+	Message string `json:"message"`            // Message template, formatted with the arguments of New
+	DocsURL string `json:"docs_url,omitempty"` // Page documenting the error (optional)
+}
+
+// New returns an error of the code, formatting the message template with
+// args. Controllers can return it as is: it reports its status code and the
+// controller error handler writes its code.
+func (c ErrorCode) New(args ...any) *CodedError {
+	message := c.Message
+	if len(args) > 0 {
+		message = fmt.Sprintf(c.Message, args...)
+	}
+	return &CodedError{ErrorCode: c, message: message}
+}
+
+// CodedError is an error of a registered error code
+type CodedError struct {
+	ErrorCode
+	message string
+}
+
+// Error returns the formatted message
+func (e *CodedError) Error() string {
+	return e.message
+}
+
+// StatusCode returns the status code of the error code
+func (e *CodedError) StatusCode() int {
+	return e.Status
+}
+
+// ErrorCatalog is the registry of an API's error codes, exported as
+// documentation with Markdown or as JSON
+type ErrorCatalog struct {
+	codes map[string]ErrorCode
+}
+
+// NewErrorCatalog creates an empty error catalog
+func NewErrorCatalog() *ErrorCatalog {
+	return &ErrorCatalog{codes: make(map[string]ErrorCode)}
+}
+
+// Register adds an error code to the catalog and returns it, so codes can be
+// declared as package variables
+func (c *ErrorCatalog) Register(code ErrorCode) ErrorCode {
+	if code.Code == "" {
+		panic("router: error code without a code")
+	}
+	if http.StatusText(code.Status) == "" {
+		panic(fmt.Sprintf("router: error code %q has invalid status %d", code.Code, code.Status))
+	}
+	if _, exists := c.codes[code.Code]; exists {
+		panic(fmt.Sprintf("router: error code %q registered twice", code.Code))
+	}
+
+	c.codes[code.Code] = code
+	return code
+}
+
+// Lookup returns the registered error code with the given code
+func (c *ErrorCatalog) Lookup(code string) (ErrorCode, bool) {
+	errorCode, ok := c.codes[code]
+	return errorCode, ok
+}
+
+// Codes returns the registered error codes, sorted by status and code
+func (c *ErrorCatalog) Codes() []ErrorCode {
+	codes := make([]ErrorCode, 0, len(c.codes))
+	for _, code := range c.codes {
+		codes = append(codes, code)
+	}
+
+	slices.SortFunc(codes, func(a, b ErrorCode) int {
+		if a.Status != b.Status {
+			return a.Status - b.Status
+		}
+		return strings.Compare(a.Code, b.Code)
+	})
+	return codes
+}
+
+// Markdown renders the catalog as a Markdown document with a section per
+// error code, which docs URLs can link to by anchor
+func (c *ErrorCatalog) Markdown(title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", title)
+
+	codes := c.Codes()
+	if len(codes) == 0 {
+		b.WriteString("\nNo error codes.\n")
+		return b.String()
+	}
+
+	b.WriteString("\n| Code | Status | Message |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for _, code := range codes {
+		fmt.Fprintf(&b, "| [`%s`](#%s) | %d | %s |\n", code.Code, code.Code, code.Status, code.Message)
+	}
+
+	for _, code := range codes {
+		fmt.Fprintf(&b, "\n## %s\n\n", code.Code)
+		fmt.Fprintf(&b, "- Status: %d %s\n", code.Status, http.StatusText(code.Status))
+		fmt.Fprintf(&b, "- Message: %s\n", code.Message)
+		if code.DocsURL != "" {
+			fmt.Fprintf(&b, "- Documentation: %s\n", code.DocsURL)
+		}
+	}
+	return b.String()
+}
+
+// WithErrorCodes documents the error codes the route responds with in the
+// x-error-codes extension of the responses of their status codes, adding
+// those responses when not documented otherwise
+func (rc *RouteConfig) WithErrorCodes(codes ...ErrorCode) *RouteConfig {
+	rc.errorCodes = append(rc.errorCodes, codes...)
+	return rc
+}
+
+// addErrorCodes documents the route's error codes on the responses of their
+// status codes; referenced responses are left untouched
+func addErrorCodes(responses map[string]any, codes []ErrorCode) {
+	for _, code := range codes {
+		statusCode := strconv.Itoa(code.Status)
+		if _, exists := responses[statusCode]; !exists {
+			responses[statusCode] = map[string]any{"description": http.StatusText(code.Status)}
+		}
+
+		response, ok := responses[statusCode].(map[string]any)
+		if !ok || response["$ref"] != nil {
+			continue
+		}
+
+		entry := map[string]any{"code": code.Code, "message": code.Message}
+		if code.DocsURL != "" {
+			entry["docsUrl"] = code.DocsURL
+		}
+
+		documented, _ := response["x-error-codes"].([]any)
+		response["x-error-codes"] = append(documented, entry)
+	}
+}
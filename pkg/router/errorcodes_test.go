@@ -0,0 +1,165 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewErrorCatalog()
+	notFound := catalog.Register(ErrorCode{
+		Code:    "user_not_found",
+		Status:  http.StatusNotFound,
+		Message: "user %s not found",
+		DocsURL: "https://example.com/errors#user_not_found",
+	})
+	catalog.Register(ErrorCode{Code: "invalid_email", Status: http.StatusBadRequest, Message: "invalid email"})
+	catalog.Register(ErrorCode{Code: "email_taken", Status: http.StatusConflict, Message: "email already taken"})
+
+	var codes []string
+	for _, code := range catalog.Codes() {
+		codes = append(codes, code.Code)
+	}
+	if diff := cmp.Diff([]string{"invalid_email", "user_not_found", "email_taken"}, codes); diff != "" {
+		t.Errorf("codes mismatch (-want +got):\n%s", diff)
+	}
+
+	got, ok := catalog.Lookup("user_not_found")
+	require.True(t, ok)
+	assert.Equal(t, notFound, got)
+
+	err := notFound.New("u1")
+	assert.EqualError(t, err, "user u1 not found")
+	assert.Equal(t, http.StatusNotFound, err.StatusCode())
+	assert.EqualError(t, catalog.codes["invalid_email"].New(), "invalid email")
+
+	markdown := catalog.Markdown("Errors")
+	assert.True(t, strings.HasPrefix(markdown, "# Errors\n"))
+	assert.Contains(t, markdown, "| [`user_not_found`](#user_not_found) | 404 | user %s not found |")
+	assert.Contains(t, markdown, "## email_taken\n\n- Status: 409 Conflict\n")
+	assert.Contains(t, markdown, "- Documentation: https://example.com/errors#user_not_found\n")
+
+	assert.PanicsWithValue(t, `router: error code "user_not_found" registered twice`, func() {
+		catalog.Register(ErrorCode{Code: "user_not_found", Status: http.StatusNotFound})
+	})
+	assert.PanicsWithValue(t, `router: error code "teapot" has invalid status 0`, func() {
+		catalog.Register(ErrorCode{Code: "teapot"})
+	})
+}
+
+// errorCodeController fails with error codes
+type errorCodeController struct {
+	code ErrorCode
+}
+
+func (c errorCodeController) Routes() []ControllerRoute {
+	return []ControllerRoute{{Method: http.MethodGet, Path: "/users/{id}", Handler: "GetUser"}}
+}
+
+func (c errorCodeController) GetUser(ctx context.Context, req getUserRequest) (UserResponse, error) {
+	return UserResponse{}, c.code.New(req.ID)
+}
+
+func TestErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	notFound := ErrorCode{Code: "user_not_found", Status: http.StatusNotFound, Message: "user %s not found",
+		DocsURL: "https://example.com/errors#user_not_found"}
+	unavailable := ErrorCode{Code: "store_unavailable", Status: http.StatusServiceUnavailable, Message: "store %s is down"}
+	conflict := ErrorCode{Code: "email_taken", Status: http.StatusConflict, Message: "email already taken"}
+
+	t.Run("controller errors", func(t *testing.T) {
+		t.Parallel()
+
+		for name, tc := range map[string]struct {
+			code       ErrorCode
+			wantStatus int
+			wantBody   controllerError
+		}{
+			"client error": {
+				code:       notFound,
+				wantStatus: http.StatusNotFound,
+				wantBody: controllerError{
+					Error:     "user u1 not found",
+					Code:      "user_not_found",
+					DocsURL:   "https://example.com/errors#user_not_found",
+					RequestID: "req-1",
+				},
+			},
+			"server error hides the message": {
+				code:       unavailable,
+				wantStatus: http.StatusServiceUnavailable,
+				wantBody:   controllerError{Error: "service unavailable", Code: "store_unavailable", RequestID: "req-1"},
+			},
+		} {
+			tc := tc
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				r := NewDocRouter()
+				require.NoError(t, r.RegisterController(errorCodeController{code: tc.code}))
+
+				req := httptest.NewRequest(http.MethodGet, "/users/u1", nil)
+				req.Header.Set(RequestIDHeader, "req-1")
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, req)
+				assert.Equal(t, tc.wantStatus, rec.Code)
+
+				var body controllerError
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, tc.wantBody, body)
+			})
+		}
+	})
+
+	t.Run("documentation", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewDocRouter()
+		users := r.Group("/users").WithErrorCodes(notFound)
+		users.Put("/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+			WithResponse(UserResponse{}).
+			WithErrorResponse("404", "Not Found", controllerError{}).
+			WithErrorCodes(conflict).
+			Register()
+		users.Delete("/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+			WithResponseRef("404", "NotFound").
+			Register()
+
+		generator := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes())
+		generator.RegisterResponse("NotFound", map[string]any{"description": "Not Found"})
+		paths := generator.Generate()["paths"].(map[string]any)
+		item := paths["/users/{id}"].(map[string]any)
+
+		put := item["put"].(map[string]any)["responses"].(map[string]any)
+		notFoundResponse := put["404"].(map[string]any)
+		assert.Equal(t, "Not Found", notFoundResponse["description"])
+		assert.Contains(t, notFoundResponse, "content")
+		assert.Equal(t, []any{map[string]any{
+			"code":    "user_not_found",
+			"message": "user %s not found",
+			"docsUrl": "https://example.com/errors#user_not_found",
+		}}, notFoundResponse["x-error-codes"])
+
+		assert.Equal(t, map[string]any{
+			"description": "Conflict",
+			"x-error-codes": []any{map[string]any{
+				"code":    "email_taken",
+				"message": "email already taken",
+			}},
+		}, put["409"], "undocumented statuses are added")
+
+		del := item["delete"].(map[string]any)["responses"].(map[string]any)
+		assert.Equal(t, map[string]any{"$ref": "#/components/responses/NotFound"}, del["404"])
+	})
+}
@@ -0,0 +1,184 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// uuidPattern matches the textual form of a UUID
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ExampleIssue reports an `example` tag whose value doesn't conform to the
+// schema of its field
+type ExampleIssue struct {
+	Field   string // Struct and field holding the tag, e.g. "Todo.Priority"
+	Message string // What is wrong with the example
+}
+
+// String formats the issue for logging
+func (i ExampleIssue) String() string {
+	return i.Field + ": " + i.Message
+}
+
+// ExampleIssues returns the example issues found in the schemas generated so
+// far, ordered by field
+func (g *OpenAPIGenerator) ExampleIssues() []ExampleIssue {
+	issues := slices.Clone(g.exampleIssues)
+	slices.SortStableFunc(issues, func(a, b ExampleIssue) int {
+		if a.Field < b.Field {
+			return -1
+		}
+		if a.Field > b.Field {
+			return 1
+		}
+		return 0
+	})
+
+	return issues
+}
+
+// typedValue converts a tag value to the JSON type of the schema, leaving it
+// as a string when the schema is a string or the value doesn't parse. Values
+// of arrays, objects and untyped schemas are parsed as JSON.
+func typedValue(schemaType any, value string) any {
+	switch schemaType {
+	case "array", "object", nil:
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err == nil {
+			return v
+		}
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
+
+// typedEnum converts enum tag values to the JSON type of the schema. String
+// enums stay a []string; values that don't parse are kept as strings.
+func typedEnum(schemaType any, values []string) any {
+	if schemaType != "integer" && schemaType != "number" && schemaType != "boolean" {
+		return values
+	}
+
+	typed := make([]any, len(values))
+	for i, value := range values {
+		typed[i] = typedValue(schemaType, strings.TrimSpace(value))
+	}
+	return typed
+}
+
+// exampleIssue checks the example of a field's schema against the schema's
+// type, enum, pattern and format, returning an empty string if it conforms
+func exampleIssue(field reflect.StructField, schema map[string]any) string {
+	example, exists := schema["example"]
+	if !exists {
+		return ""
+	}
+
+	value, isString := example.(string)
+	if schemaType, _ := schema["type"].(string); schemaType != "string" && schemaType != "" {
+		if isString {
+			return fmt.Sprintf("example %q is not a valid %s", value, schemaType)
+		}
+		value = fmt.Sprint(example)
+	}
+
+	if enum, ok := schema["enum"]; ok && !slices.Contains(enumStrings(enum), value) {
+		return fmt.Sprintf("example %q is not one of the enum values", value)
+	}
+
+	bounds := checkCount(utf8.RuneCountInString(value), "characters", schema["minLength"], schema["maxLength"])
+	if n, ok := example.(float64); ok {
+		bounds = checkRange(n, schema)
+	} else if n, ok := example.(int64); ok {
+		bounds = checkRange(float64(n), schema)
+	}
+	if bounds != nil {
+		return fmt.Sprintf("example %q %s", value, bounds)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Sprintf("pattern %q does not compile: %v", pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Sprintf("example %q does not match pattern %q", value, pattern)
+		}
+	}
+
+	format, _ := schema["format"].(string)
+	if !conformsToFormat(field, format, value) {
+		return fmt.Sprintf("example %q is not a valid %s", value, format)
+	}
+
+	return ""
+}
+
+// enumStrings returns the textual form of enum values
+func enumStrings(enum any) []string {
+	values := reflect.ValueOf(enum)
+	if values.Kind() != reflect.Slice {
+		return nil
+	}
+
+	texts := make([]string, values.Len())
+	for i := range texts {
+		texts[i] = fmt.Sprint(values.Index(i).Interface())
+	}
+	return texts
+}
+
+// conformsToFormat reports whether a value is valid for a string format;
+// formats without a check are assumed to conform
+func conformsToFormat(field reflect.StructField, format, value string) bool {
+	layout := field.Tag.Get("timeFormat")
+
+	switch format {
+	case "date-time":
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		_, err := time.Parse(layout, value)
+		return err == nil
+	case "date":
+		if layout == "" {
+			layout = DateLayout
+		}
+		_, err := time.Parse(layout, value)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.IsAbs()
+	}
+
+	return true
+}
@@ -0,0 +1,113 @@
+package router
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ExternalDocs links to documentation outside the spec, e.g. a developer
+// portal page
+type ExternalDocs struct {
+	URL         string // Where the documentation lives
+	Description string // What the reader finds there (optional)
+}
+
+// TagInfo documents a tag routes are grouped under, listed in the spec's
+// tags in the order the router documents them
+type TagInfo struct {
+	Name         string        // Tag the routes reference in WithTags
+	Description  string        // Description of the routes under the tag (optional)
+	ExternalDocs *ExternalDocs // Documentation of the tag, if any
+}
+
+// WithExternalDocs links the tag to its documentation
+func (t TagInfo) WithExternalDocs(url, description string) TagInfo {
+	t.ExternalDocs = newExternalDocs(url, description)
+	return t
+}
+
+// WithExternalDocs links the spec to the API's documentation
+func (dr *DocRouter) WithExternalDocs(url, description string) *DocRouter {
+	dr.externalDocs = newExternalDocs(url, description)
+	return dr
+}
+
+// WithTagInfo documents tags in the spec, replacing any documented before
+// under the same name
+func (dr *DocRouter) WithTagInfo(tags ...TagInfo) *DocRouter {
+	for _, tag := range tags {
+		if tag.Name == "" {
+			panic("router: tag info without a name")
+		}
+
+		i := slices.IndexFunc(dr.tags, func(documented TagInfo) bool {
+			return documented.Name == tag.Name
+		})
+		if i >= 0 {
+			dr.tags[i] = tag
+			continue
+		}
+		dr.tags = append(dr.tags, tag)
+	}
+	return dr
+}
+
+// WithExternalDocs links the route's operation to its documentation
+func (rc *RouteConfig) WithExternalDocs(url, description string) *RouteConfig {
+	rc.externalDocs = newExternalDocs(url, description)
+	return rc
+}
+
+// newExternalDocs creates the link, panicking without a URL as OpenAPI
+// requires one
+func newExternalDocs(url, description string) *ExternalDocs {
+	if url == "" {
+		panic(fmt.Sprintf("router: external docs %q without a URL", description))
+	}
+	return &ExternalDocs{URL: url, Description: description}
+}
+
+// allTags returns the tags documented by the router, followed by those of
+// mounted routers it doesn't document itself
+func (dr *DocRouter) allTags() []TagInfo {
+	tags := slices.Clone(dr.tags)
+	for _, m := range dr.mounts {
+		for _, tag := range m.router.allTags() {
+			if !slices.ContainsFunc(tags, func(documented TagInfo) bool { return documented.Name == tag.Name }) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags
+}
+
+// externalDocsObject documents a link to external documentation
+func externalDocsObject(docs ExternalDocs) map[string]any {
+	object := map[string]any{
+		"url": docs.URL,
+	}
+	if docs.Description != "" {
+		object["description"] = docs.Description
+	}
+	return object
+}
+
+// tagObjects documents the tags in order
+func tagObjects(tags []TagInfo) []any {
+	objects := make([]any, 0, len(tags))
+	for _, tag := range tags {
+		object := map[string]any{
+			"name": tag.Name,
+		}
+		if tag.Description != "" {
+			object["description"] = tag.Description
+		}
+		if tag.ExternalDocs != nil {
+			object["externalDocs"] = externalDocsObject(*tag.ExternalDocs)
+		}
+		objects = append(objects, object)
+	}
+
+	return objects
+}
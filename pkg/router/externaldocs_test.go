@@ -0,0 +1,68 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalDocs(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	admin := NewDocRouter().WithTagInfo(
+		TagInfo{Name: "Admin", Description: "Operations"},
+		TagInfo{Name: "Users", Description: "Shadowed by the parent's"},
+	)
+	admin.Get("/stats", noop).WithTags("Admin").Register()
+
+	r := NewDocRouter().
+		WithExternalDocs("https://developer.example.com", "Developer portal").
+		WithTagInfo(
+			TagInfo{Name: "Users"},
+			TagInfo{Name: "Todos", Description: "Todo lists"}.WithExternalDocs("https://developer.example.com/todos", ""),
+		).
+		WithTagInfo(TagInfo{Name: "Users", Description: "User accounts"})
+	r.Get("/users", noop).WithTags("Users").
+		WithExternalDocs("https://developer.example.com/users#list", "Listing users").
+		Register()
+	r.Get("/todos", noop).WithTags("Todos").Register()
+	r.Mount("/admin", admin)
+
+	spec := r.OpenAPI().Generate()
+
+	assert.Equal(t, map[string]any{
+		"url":         "https://developer.example.com",
+		"description": "Developer portal",
+	}, spec["externalDocs"])
+	assert.Equal(t, []any{
+		map[string]any{"name": "Users", "description": "User accounts"},
+		map[string]any{
+			"name":         "Todos",
+			"description":  "Todo lists",
+			"externalDocs": map[string]any{"url": "https://developer.example.com/todos"},
+		},
+		map[string]any{"name": "Admin", "description": "Operations"},
+	}, spec["tags"], "tags keep the order they were documented in")
+
+	paths := spec["paths"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"url":         "https://developer.example.com/users#list",
+		"description": "Listing users",
+	}, paths["/users"].(map[string]any)["get"].(map[string]any)["externalDocs"])
+	assert.NotContains(t, paths["/todos"].(map[string]any)["get"], "externalDocs")
+
+	undocumented := NewDocRouter()
+	undocumented.Get("/health", noop).Register()
+	assert.NotContains(t, undocumented.OpenAPI().Generate(), "externalDocs")
+	assert.NotContains(t, undocumented.OpenAPI().Generate(), "tags")
+
+	assert.PanicsWithValue(t, `router: external docs "Portal" without a URL`, func() {
+		NewDocRouter().WithExternalDocs("", "Portal")
+	})
+	assert.PanicsWithValue(t, "router: tag info without a name", func() {
+		NewDocRouter().WithTagInfo(TagInfo{Description: "Nameless"})
+	})
+}
@@ -0,0 +1,209 @@
+package router
+
+import (
+	"slices"
+	"strings"
+)
+
+// httpMethods lists the path item keys that hold operations
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// FilterByTags returns a copy of a generated spec that only contains the
+// operations tagged with at least one of the given tags, together with the
+// components those operations reference (directly or transitively)
+func FilterByTags(spec map[string]any, tags ...string) map[string]any {
+	filtered := make(map[string]any, len(spec))
+	for key, value := range spec {
+		filtered[key] = value
+	}
+
+	paths := map[string]any{}
+	if specPaths, ok := spec["paths"].(map[string]any); ok {
+		for path, item := range specPaths {
+			pathItem, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			kept := map[string]any{}
+			for key, value := range pathItem {
+				// keep path-level fields (e.g. shared parameters) alongside operations
+				if !slices.Contains(httpMethods, key) {
+					kept[key] = value
+					continue
+				}
+
+				if operationHasTag(value, tags) {
+					kept[key] = value
+				}
+			}
+
+			if hasOperation(kept) {
+				paths[path] = kept
+			}
+		}
+	}
+	filtered["paths"] = paths
+
+	components, ok := spec["components"].(map[string]any)
+	if !ok {
+		return filtered
+	}
+
+	// resolve every component reachable from the remaining operations, and
+	// the security schemes they and the document require by name
+	reachable := map[string]bool{}
+	pending := collectRefs(paths)
+	pending = append(pending, securitySchemeRefs(spec["security"])...)
+	for _, item := range paths {
+		for key, operation := range item.(map[string]any) {
+			if op, ok := operation.(map[string]any); ok && slices.Contains(httpMethods, key) {
+				pending = append(pending, securitySchemeRefs(op["security"])...)
+			}
+		}
+	}
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+
+		if reachable[ref] {
+			continue
+		}
+		reachable[ref] = true
+
+		if component := resolveComponent(components, ref); component != nil {
+			pending = append(pending, collectRefs(component)...)
+		}
+	}
+
+	filteredComponents := map[string]any{}
+	for section, value := range components {
+		entries, ok := asMap(value)
+		if !ok {
+			filteredComponents[section] = value
+			continue
+		}
+
+		kept := map[string]any{}
+		for name, entry := range entries {
+			if reachable["#/components/"+section+"/"+name] {
+				kept[name] = entry
+			}
+		}
+
+		// the schemas section is always present in generated specs
+		if len(kept) > 0 || section == "schemas" {
+			filteredComponents[section] = kept
+		}
+	}
+	filtered["components"] = filteredComponents
+
+	return filtered
+}
+
+// operationHasTag reports whether an operation object carries any of the tags
+func operationHasTag(operation any, tags []string) bool {
+	op, ok := operation.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	var opTags []string
+	switch value := op["tags"].(type) {
+	case []string:
+		opTags = value
+	case []any:
+		for _, tag := range value {
+			if s, ok := tag.(string); ok {
+				opTags = append(opTags, s)
+			}
+		}
+	}
+
+	for _, tag := range opTags {
+		if slices.Contains(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOperation reports whether a path item contains at least one operation
+func hasOperation(pathItem map[string]any) bool {
+	for key := range pathItem {
+		if slices.Contains(httpMethods, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRefs returns every local $ref found in a spec fragment
+func collectRefs(node any) []string {
+	var refs []string
+
+	switch value := node.(type) {
+	case map[string]any:
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" && strings.HasPrefix(ref, "#/") {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, collectRefs(child)...)
+		}
+	case map[string]map[string]any:
+		for _, child := range value {
+			refs = append(refs, collectRefs(child)...)
+		}
+	case []any:
+		for _, child := range value {
+			refs = append(refs, collectRefs(child)...)
+		}
+	}
+
+	return refs
+}
+
+// securitySchemeRefs returns references to the security schemes named by
+// security requirements, which name their schemes instead of using $ref
+func securitySchemeRefs(security any) []string {
+	var refs []string
+	requirements, _ := security.([]any)
+	for _, requirement := range requirements {
+		schemes, _ := requirement.(map[string]any)
+		for name := range schemes {
+			refs = append(refs, "#/components/securitySchemes/"+name)
+		}
+	}
+	return refs
+}
+
+// resolveComponent looks up a "#/components/<section>/<name>" reference
+func resolveComponent(components map[string]any, ref string) any {
+	section, name, ok := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+	if !ok {
+		return nil
+	}
+
+	entries, ok := asMap(components[section])
+	if !ok {
+		return nil
+	}
+
+	return entries[name]
+}
+
+// asMap normalizes the component section representations used by the generator
+func asMap(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case map[string]map[string]any:
+		m := make(map[string]any, len(v))
+		for key, entry := range v {
+			m[key] = entry
+		}
+		return m, true
+	}
+	return nil, false
+}
@@ -0,0 +1,94 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterByTags(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "/users", noop).WithResponse(UserList{}).WithTags("Users").Register()
+	r.Route("POST", "/users", noop).WithRequest(UserRequest{}).WithResponse(UserResponse{}).WithTags("Users", "Admin").Register()
+	r.Route("GET", "/items", noop).WithResponse(NestedType{}).WithTags("Items").Register()
+	r.Route("GET", "/health", noop).Register()
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes())
+	generator.RegisterResponse("NotFound", map[string]any{"description": "not found"})
+	generator.RegisterResponse("Unused", map[string]any{"description": "unused"})
+	generator.RegisterRouteResponse("/users", "GET", "404", "NotFound")
+
+	spec := generator.Generate()
+
+	t.Run("single tag", func(t *testing.T) {
+		t.Parallel()
+
+		filtered := FilterByTags(spec, "Users")
+
+		paths := filtered["paths"].(map[string]any)
+		assert.Len(t, paths, 1)
+		require.Contains(t, paths, "/users")
+		assert.Len(t, paths["/users"], 2, "both tagged operations should be kept")
+
+		components := filtered["components"].(map[string]any)
+		schemas := components["schemas"].(map[string]any)
+		assert.Contains(t, schemas, "UserList")
+		assert.Contains(t, schemas, "UserListUsersItem", "transitively referenced schemas should be kept")
+		assert.Contains(t, schemas, "UserRequest")
+		assert.Contains(t, schemas, "UserResponse")
+		assert.NotContains(t, schemas, "NestedType")
+		assert.NotContains(t, schemas, "NestedTypeProperties")
+
+		responses := components["responses"].(map[string]any)
+		assert.Contains(t, responses, "NotFound")
+		assert.NotContains(t, responses, "Unused")
+	})
+
+	t.Run("operation subset", func(t *testing.T) {
+		t.Parallel()
+
+		filtered := FilterByTags(spec, "Admin")
+
+		usersPath := filtered["paths"].(map[string]any)["/users"].(map[string]any)
+		assert.Contains(t, usersPath, "post")
+		assert.NotContains(t, usersPath, "get")
+
+		components := filtered["components"].(map[string]any)
+		assert.NotContains(t, components, "responses", "unreferenced response sections should be dropped")
+	})
+
+	t.Run("original untouched", func(t *testing.T) {
+		t.Parallel()
+
+		FilterByTags(spec, "Items")
+		assert.Len(t, spec["paths"], 3)
+	})
+}
+
+func TestFilterByTagsSecurity(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().
+		WithBearerAuth().
+		WithSecurityScheme("apiKey", SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"})
+	r.Route("GET", "/users", noop).WithSecurity(BearerAuth).WithTags("Users").Register()
+	r.Route("GET", "/items", noop).WithSecurity("apiKey").WithTags("Items").Register()
+
+	filtered := FilterByTags(r.OpenAPI().Generate(), "Users")
+
+	// the schemes of the kept operations are defined, the others dropped
+	schemes := filtered["components"].(map[string]any)["securitySchemes"].(map[string]any)
+	assert.Contains(t, schemes, BearerAuth)
+	assert.NotContains(t, schemes, "apiKey")
+
+	operation := filtered["paths"].(map[string]any)["/users"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, []any{map[string]any{BearerAuth: []string{}}}, operation["security"])
+}
@@ -0,0 +1,176 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecConflict reports a value of a spec fragment that differs from the one
+// generated at the same location; the generated value is kept
+type SpecConflict struct {
+	Pointer   string // JSON pointer of the value, e.g. "/paths/~1todos/get/summary"
+	Generated any    // Value in the generated spec
+	Fragment  any    // Value in the fragment
+}
+
+// String formats the conflict for logging
+func (c SpecConflict) String() string {
+	generated, _ := json.Marshal(c.Generated)
+	fragment, _ := json.Marshal(c.Fragment)
+	return fmt.Sprintf("%s: fragment value %s differs from generated %s", c.Pointer, fragment, generated)
+}
+
+// MergeSpec deep-merges a hand-written fragment of an OpenAPI document, such
+// as extra paths, custom schemas or webhooks, into the router's generated
+// spec. Objects are merged member by member and arrays gain the items they
+// don't contain yet; other values the generated spec defines differently are
+// reported by OpenAPIGenerator.FragmentConflicts and left as generated.
+// Fragments are merged in the order added, before spec transforms run. It
+// panics on fragments that aren't JSON values.
+func (dr *DocRouter) MergeSpec(fragment map[string]any) *DocRouter {
+	normalized, err := normalizeJSON(fragment)
+	if err != nil {
+		panic(fmt.Sprintf("router: invalid spec fragment: %v", err))
+	}
+
+	dr.fragments = append(dr.fragments, normalized.(map[string]any))
+	return dr
+}
+
+// MergeSpecFile merges the fragment in a JSON or, with a .yaml or .yml
+// extension, YAML file into the router's spec, like MergeSpec
+func (dr *DocRouter) MergeSpecFile(path string) error {
+	fragment, err := ReadSpecFragment(path)
+	if err != nil {
+		return err
+	}
+
+	dr.fragments = append(dr.fragments, fragment)
+	return nil
+}
+
+// ReadSpecFragment reads a fragment of an OpenAPI document from a JSON or,
+// with a .yaml or .yml extension, YAML file
+func ReadSpecFragment(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec fragment from '%s': %w", path, err)
+	}
+
+	var fragment map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &fragment)
+	default:
+		err = json.Unmarshal(data, &fragment)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse spec fragment from '%s': %w", path, err)
+	}
+
+	// YAML decodes to other types than JSON, which the generated spec is
+	// compared with
+	normalized, err := normalizeJSON(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec fragment from '%s': %w", path, err)
+	}
+	fragment, _ = normalized.(map[string]any)
+	return fragment, nil
+}
+
+// FragmentConflicts returns the values of the fragments that differ from
+// the generated ones, found by the last call to Generate
+func (g *OpenAPIGenerator) FragmentConflicts() []SpecConflict {
+	return g.fragmentConflicts
+}
+
+// normalizeJSON returns a copy of a value with the types decoding its JSON
+// encoding yields, e.g. []any instead of []string
+func normalizeJSON(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// mergeFragments deep-merges the fragments into the spec, in order
+func (g *OpenAPIGenerator) mergeFragments(spec map[string]any) {
+	g.fragmentConflicts = nil
+	for _, fragment := range g.Fragments {
+		// the fragment is copied so the spec doesn't share values with it
+		normalized, err := normalizeJSON(fragment)
+		if err != nil {
+			g.fragmentConflicts = append(g.fragmentConflicts, SpecConflict{Pointer: "", Fragment: fragment})
+			continue
+		}
+		g.mergeValue("", spec, normalized)
+	}
+}
+
+// mergeValue merges a normalized fragment value into the generated one at
+// the pointer, returning the merged value
+func (g *OpenAPIGenerator) mergeValue(pointer string, generated, fragment any) any {
+	switch fragment := fragment.(type) {
+	case map[string]any:
+		object, ok := generated.(map[string]any)
+		if !ok {
+			// e.g. a map of another type, merged as its JSON object
+			normalized, _ := normalizeJSON(generated)
+			object, ok = normalized.(map[string]any)
+		}
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(fragment))
+		for key := range fragment {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if existing, exists := object[key]; exists {
+				object[key] = g.mergeValue(pointer+"/"+escapePointer(key), existing, fragment[key])
+			} else {
+				object[key] = fragment[key]
+			}
+		}
+		return object
+
+	case []any:
+		normalized, _ := normalizeJSON(generated)
+		items, ok := normalized.([]any)
+		if !ok {
+			break
+		}
+		for _, item := range fragment {
+			if !slices.ContainsFunc(items, func(existing any) bool { return reflect.DeepEqual(existing, item) }) {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+
+	if normalized, _ := normalizeJSON(generated); !reflect.DeepEqual(normalized, fragment) {
+		g.fragmentConflicts = append(g.fragmentConflicts, SpecConflict{Pointer: pointer, Generated: generated, Fragment: fragment})
+	}
+	return generated
+}
+
+// escapePointer escapes a member name as a JSON pointer token
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,107 @@
+package router
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSpec(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithInfo("Test API", "", "1.0.0").
+		MergeSpec(map[string]any{
+			"x-webhooks": map[string]any{
+				"todoCreated": map[string]any{"post": map[string]any{"summary": "A todo was created"}},
+			},
+			"paths": map[string]any{
+				"/legacy": map[string]any{"get": map[string]any{"summary": "Served by the old stack"}},
+				"/users": map[string]any{
+					"get": map[string]any{
+						"summary": "Enumerate Users",
+						"tags":    []string{"Users", "Admin"},
+					},
+				},
+			},
+			"components": map[string]any{
+				"schemas": map[string]any{"Money": map[string]any{"type": "string", "pattern": `^\d+\.\d{2}$`}},
+			},
+		})
+	r.Get("/users", noop).WithName("List Users").WithTags("Users").Register()
+
+	generator := r.OpenAPI()
+	spec := generator.Generate()
+	assert.Contains(t, spec, "x-webhooks")
+	assert.Contains(t, spec["components"].(map[string]any)["schemas"], "Money")
+
+	paths := spec["paths"].(map[string]any)
+	assert.Equal(t, map[string]any{"get": map[string]any{"summary": "Served by the old stack"}}, paths["/legacy"])
+
+	operation := paths["/users"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "List Users", operation["summary"], "the generated value is kept")
+	assert.Equal(t, []any{"Users", "Admin"}, operation["tags"])
+	assert.Equal(t, []SpecConflict{{
+		Pointer:   "/paths/~1users/get/summary",
+		Generated: "List Users",
+		Fragment:  "Enumerate Users",
+	}}, generator.FragmentConflicts())
+	assert.Equal(t, `/paths/~1users/get/summary: fragment value "Enumerate Users" differs from generated "List Users"`,
+		generator.FragmentConflicts()[0].String())
+
+	// fragments aren't shared with the specs they're merged into
+	paths["/legacy"].(map[string]any)["get"].(map[string]any)["summary"] = "changed"
+	assert.Equal(t, "Served by the old stack",
+		r.OpenAPI().Generate()["paths"].(map[string]any)["/legacy"].(map[string]any)["get"].(map[string]any)["summary"])
+
+	assert.PanicsWithValue(t, "router: invalid spec fragment: json: unsupported type: func()", func() {
+		NewDocRouter().MergeSpec(map[string]any{"x-handler": func() {}})
+	})
+}
+
+func TestMergeSpecFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"fragment.json": `{"x-audience": "partners", "tags": [{"name": "Billing"}]}`,
+		"fragment.yaml": "x-audience: partners\ntags:\n  - name: Billing\n",
+		"broken.yml":    "x-audience: [partners\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	for name, tc := range map[string]struct {
+		file    string
+		wantErr string
+	}{
+		"json":      {file: "fragment.json"},
+		"yaml":      {file: "fragment.yaml"},
+		"malformed": {file: "broken.yml", wantErr: "parse spec fragment from"},
+		"missing":   {file: "missing.json", wantErr: "read spec fragment from"},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			err := r.MergeSpecFile(filepath.Join(dir, tc.file))
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			generator := r.OpenAPI()
+			spec := generator.Generate()
+			assert.Equal(t, "partners", spec["x-audience"])
+			assert.Equal(t, []any{map[string]any{"name": "Billing"}}, spec["tags"])
+			assert.Empty(t, generator.FragmentConflicts())
+		})
+	}
+}
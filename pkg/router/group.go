@@ -0,0 +1,189 @@
+package router
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RouteGroup starts routes sharing a path prefix, middleware and
+// documentation, such as the tags and error responses of a resource
+type RouteGroup struct {
+	router       *DocRouter
+	prefix       string
+	middleware   []func(http.Handler) http.Handler
+	tags         []string
+	responses    map[string]RouteResponse
+	responseRefs map[string]string
+	parameters   []Parameter
+	errorCodes   []ErrorCode
+	security     []SecurityRequirement
+	servers      []Server
+	tenantScoped bool
+
+	requiredHeaders []string
+	clientGate      *clientGate
+
+	contentNegotiation bool
+}
+
+// Group starts a group of routes whose paths start with prefix
+func (dr *DocRouter) Group(prefix string) *RouteGroup {
+	return &RouteGroup{
+		router:    dr,
+		prefix:    prefix,
+		responses: make(map[string]RouteResponse),
+	}
+}
+
+// Group starts a nested group whose paths start with the group's prefix
+// followed by prefix; it inherits the group's settings
+func (g *RouteGroup) Group(prefix string) *RouteGroup {
+	return &RouteGroup{
+		router:       g.router,
+		prefix:       joinPath(g.prefix, prefix),
+		middleware:   slices.Clone(g.middleware),
+		tags:         slices.Clone(g.tags),
+		responses:    maps.Clone(g.responses),
+		responseRefs: maps.Clone(g.responseRefs),
+		parameters:   slices.Clone(g.parameters),
+		errorCodes:   slices.Clone(g.errorCodes),
+		security:     slices.Clone(g.security),
+		servers:      slices.Clone(g.servers),
+		tenantScoped: g.tenantScoped,
+
+		requiredHeaders: slices.Clone(g.requiredHeaders),
+		clientGate:      g.clientGate,
+
+		contentNegotiation: g.contentNegotiation,
+	}
+}
+
+// Use adds middleware wrapping the handlers of the group's routes, in the
+// order given. It runs after the route is matched, so MatchedRoute is
+// available to it, and only applies to routes started after the call.
+func (g *RouteGroup) Use(middleware ...func(http.Handler) http.Handler) *RouteGroup {
+	g.middleware = append(g.middleware, middleware...)
+	return g
+}
+
+// WithTags adds tags to every route of the group
+func (g *RouteGroup) WithTags(tags ...string) *RouteGroup {
+	g.tags = append(g.tags, tags...)
+	return g
+}
+
+// WithErrorResponse documents an error response of every route of the
+// group, e.g. the 401 of routes behind authentication. Routes can replace it
+// by documenting the same status code.
+func (g *RouteGroup) WithErrorResponse(statusCode, description string, schema any, examples ...Example) *RouteGroup {
+	g.responses[statusCode] = RouteResponse{
+		StatusCode:  statusCode,
+		Description: description,
+		Schema:      schema,
+		Examples:    examples,
+	}
+	return g
+}
+
+// WithResponseRef documents a response of every route of the group with a
+// component registered on the generator (see RouteConfig.WithResponseRef)
+func (g *RouteGroup) WithResponseRef(statusCode, responseName string) *RouteGroup {
+	if g.responseRefs == nil {
+		g.responseRefs = make(map[string]string)
+	}
+
+	g.responseRefs[statusCode] = responseName
+	return g
+}
+
+// WithParameter documents a parameter of every route of the group, such as
+// a path parameter of the prefix
+func (g *RouteGroup) WithParameter(param Parameter) *RouteGroup {
+	g.parameters = append(g.parameters, param)
+	return g
+}
+
+// WithErrorCodes documents error codes every route of the group responds
+// with, e.g. the not found code of the resource in the prefix
+func (g *RouteGroup) WithErrorCodes(codes ...ErrorCode) *RouteGroup {
+	g.errorCodes = append(g.errorCodes, codes...)
+	return g
+}
+
+// WithTenantScope places every route of the group under TenantPathPrefix
+// (see RouteConfig.WithTenantScope)
+func (g *RouteGroup) WithTenantScope() *RouteGroup {
+	g.tenantScoped = true
+	return g
+}
+
+// Route starts a route configuration chain for a path relative to the
+// group's prefix
+func (g *RouteGroup) Route(method, path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Handle(method, path, handler)
+}
+
+// Get starts a route configuration chain for a GET route of the group
+func (g *RouteGroup) Get(path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Route(http.MethodGet, path, handler)
+}
+
+// Post starts a route configuration chain for a POST route of the group
+func (g *RouteGroup) Post(path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Route(http.MethodPost, path, handler)
+}
+
+// Put starts a route configuration chain for a PUT route of the group
+func (g *RouteGroup) Put(path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Route(http.MethodPut, path, handler)
+}
+
+// Patch starts a route configuration chain for a PATCH route of the group
+func (g *RouteGroup) Patch(path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Route(http.MethodPatch, path, handler)
+}
+
+// Delete starts a route configuration chain for a DELETE route of the group
+func (g *RouteGroup) Delete(path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Route(http.MethodDelete, path, handler)
+}
+
+// Head starts a route configuration chain for a HEAD route of the group
+func (g *RouteGroup) Head(path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Route(http.MethodHead, path, handler)
+}
+
+// Options starts a route configuration chain for an OPTIONS route of the group
+func (g *RouteGroup) Options(path string, handler http.HandlerFunc) *RouteConfig {
+	return g.Route(http.MethodOptions, path, handler)
+}
+
+// Handle starts a route configuration chain for any http.Handler, with a
+// path relative to the group's prefix and the group's settings applied
+func (g *RouteGroup) Handle(method, path string, handler http.Handler) *RouteConfig {
+	rc := g.router.Handle(method, joinPath(g.prefix, path), handler)
+	rc.middleware = slices.Clone(g.middleware)
+	rc.tags = slices.Clone(g.tags)
+	maps.Copy(rc.responses, g.responses)
+	rc.responseRefs = maps.Clone(g.responseRefs)
+	rc.parameters = slices.Clone(g.parameters)
+	rc.errorCodes = slices.Clone(g.errorCodes)
+	rc.security = slices.Clone(g.security)
+	rc.servers = slices.Clone(g.servers)
+	rc.tenantScoped = g.tenantScoped
+	rc.requiredHeaders = slices.Clone(g.requiredHeaders)
+	rc.clientGate = g.clientGate
+	rc.contentNegotiation = g.contentNegotiation
+	return rc
+}
+
+// joinPath appends path to prefix with a single slash between them; an
+// empty path is the prefix itself
+func joinPath(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
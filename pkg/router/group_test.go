@@ -0,0 +1,65 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	var seen []string
+	record := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				route, _ := MatchedRoute(r.Context())
+				seen = append(seen, name+" "+route.Key())
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	todos := r.Group("/v1/todos/").
+		Use(record("todos")).
+		WithTags("Todos").
+		WithErrorResponse("401", "Unauthorized", nil)
+	todos.Get("", noop).Register()
+	todos.Delete("{id}", noop).
+		WithTags("Todos", "Admin").
+		WithErrorResponse("401", "Login required", nil).
+		Register()
+
+	comments := todos.Group("/{id}/comments").Use(record("comments"))
+	comments.Post("/", noop).Register()
+	r.Get("/health", noop).Register()
+
+	for _, req := range []string{"GET /v1/todos", "DELETE /v1/todos/1", "POST /v1/todos/1/comments", "GET /health"} {
+		method, path, _ := strings.Cut(req, " ")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, req)
+	}
+	assert.Equal(t, []string{
+		"todos GET /v1/todos",
+		"todos DELETE /v1/todos/{id}",
+		"todos POST /v1/todos/{id}/comments",
+		"comments POST /v1/todos/{id}/comments",
+	}, seen)
+
+	routes := r.GetRoutes()
+	require.Len(t, routes, 4)
+	assert.Equal(t, []string{"Todos"}, routes[0].Tags)
+	assert.Equal(t, "Unauthorized", routes[0].Responses["401"].Description)
+	assert.Equal(t, []string{"Todos", "Admin"}, routes[1].Tags)
+	assert.Equal(t, "Login required", routes[1].Responses["401"].Description)
+	assert.Equal(t, []string{"Todos"}, routes[2].Tags)
+	assert.Empty(t, routes[3].Tags)
+	assert.Empty(t, routes[3].Responses)
+}
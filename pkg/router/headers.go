@@ -0,0 +1,84 @@
+package router
+
+// ResponseHeader documents a header of a response, either inline or as a
+// reference to a header component registered on the generator
+type ResponseHeader struct {
+	Description string // Description of the header (optional)
+	Schema      any    // Example value of the header's type, e.g. "" or 0 (defaults to a string)
+	Ref         string // Name of a header component registered with RegisterHeader, replacing the above
+}
+
+// WithResponseHeader documents a header of the response with the given
+// status code, e.g. the Location of a 201 or the ETag of a 200
+func (rc *RouteConfig) WithResponseHeader(statusCode, name string, schema any, description string) *RouteConfig {
+	return rc.withResponseHeader(statusCode, name, ResponseHeader{Description: description, Schema: schema})
+}
+
+// WithResponseHeaderRef documents a header of the response with the given
+// status code with a component registered on the generator through
+// RegisterHeader, for headers shared by many routes such as rate limits
+func (rc *RouteConfig) WithResponseHeaderRef(statusCode, name, headerName string) *RouteConfig {
+	return rc.withResponseHeader(statusCode, name, ResponseHeader{Ref: headerName})
+}
+
+// withResponseHeader adds a header to the response with the given status code
+func (rc *RouteConfig) withResponseHeader(statusCode, name string, header ResponseHeader) *RouteConfig {
+	if rc.responseHeaders == nil {
+		rc.responseHeaders = make(map[string]map[string]ResponseHeader)
+	}
+	if rc.responseHeaders[statusCode] == nil {
+		rc.responseHeaders[statusCode] = make(map[string]ResponseHeader)
+	}
+
+	rc.responseHeaders[statusCode][name] = header
+	return rc
+}
+
+// RegisterHeader adds a reusable header to the components section, which
+// routes reference through WithResponseHeaderRef
+func (g *OpenAPIGenerator) RegisterHeader(name string, schema any, description string) {
+	if g.customHeaders == nil {
+		g.customHeaders = make(map[string]any)
+	}
+
+	g.customHeaders[name] = headerObject(ResponseHeader{Description: description, Schema: schema})
+}
+
+// addResponseHeaders attaches the route's headers to its documented
+// responses; referenced responses are left untouched
+func addResponseHeaders(responses map[string]any, headers map[string]map[string]ResponseHeader) {
+	for statusCode, named := range headers {
+		response, ok := responses[statusCode].(map[string]any)
+		if !ok || response["$ref"] != nil {
+			continue
+		}
+
+		result, ok := response["headers"].(map[string]any)
+		if !ok {
+			result = map[string]any{}
+			response["headers"] = result
+		}
+
+		for name, header := range named {
+			result[name] = headerObject(header)
+		}
+	}
+}
+
+// headerObject documents a header, or references its component
+func headerObject(header ResponseHeader) map[string]any {
+	if header.Ref != "" {
+		return map[string]any{"$ref": "#/components/headers/" + header.Ref}
+	}
+
+	schema := jsonSchema(header.Schema)
+	if schema == nil {
+		schema = map[string]any{"type": "string"}
+	}
+
+	object := map[string]any{"schema": schema}
+	if header.Description != "" {
+		object["description"] = header.Description
+	}
+	return object
+}
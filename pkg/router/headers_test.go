@@ -0,0 +1,58 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Post("/users", noop).
+		WithResponse(UserResponse{}).
+		WithResponseStatus(http.StatusCreated).
+		WithResponseHeader("201", "Location", "", "URL of the created user").
+		WithResponseHeader("201", "Last-Modified", time.Time{}, "").
+		WithResponseHeaderRef("201", "X-RateLimit-Remaining", "RateLimitRemaining").
+		WithResponseRef("429", "TooManyRequests").
+		WithResponseHeaderRef("429", "Retry-After", "RetryAfter").
+		Register()
+
+	generator := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes())
+	generator.RegisterHeader("RateLimitRemaining", 0, "Requests left in the current window")
+	generator.RegisterResponse("TooManyRequests", map[string]any{"description": "Too Many Requests"})
+
+	spec := generator.Generate()
+	responses := spec["paths"].(map[string]any)["/users"].(map[string]any)["post"].(map[string]any)["responses"].(map[string]any)
+
+	want := map[string]any{
+		"Location": map[string]any{
+			"description": "URL of the created user",
+			"schema":      map[string]any{"type": "string"},
+		},
+		"Last-Modified": map[string]any{
+			"schema": map[string]any{"type": "string", "format": "date-time"},
+		},
+		"X-RateLimit-Remaining": map[string]any{"$ref": "#/components/headers/RateLimitRemaining"},
+	}
+	if diff := cmp.Diff(want, responses["201"].(map[string]any)["headers"]); diff != "" {
+		t.Errorf("headers mismatch (-want +got):\n%s", diff)
+	}
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/TooManyRequests"}, responses["429"],
+		"referenced responses are left untouched")
+
+	headers := spec["components"].(map[string]any)["headers"]
+	assert.Equal(t, map[string]any{
+		"RateLimitRemaining": map[string]any{
+			"description": "Requests left in the current window",
+			"schema":      map[string]any{"type": "integer"},
+		},
+	}, headers)
+}
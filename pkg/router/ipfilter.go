@@ -0,0 +1,136 @@
+package router
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// IPFilter restricts requests to client addresses within CIDR ranges. Denied
+// ranges take precedence over allowed ones, and an empty allow list allows
+// every address that isn't denied.
+type IPFilter struct {
+	Allow []netip.Prefix
+	Deny  []netip.Prefix
+}
+
+// ParseIPFilter builds an IPFilter from CIDR ranges, see ParsePrefixes
+func ParseIPFilter(allow, deny []string) (IPFilter, error) {
+	var filter IPFilter
+	var err error
+
+	if filter.Allow, err = ParsePrefixes(allow); err != nil {
+		return IPFilter{}, fmt.Errorf("parse allowed ranges: %w", err)
+	}
+	if filter.Deny, err = ParsePrefixes(deny); err != nil {
+		return IPFilter{}, fmt.Errorf("parse denied ranges: %w", err)
+	}
+
+	return filter, nil
+}
+
+// ParsePrefixes parses CIDR ranges such as "10.0.0.0/8", accepting single
+// addresses as ranges of one address and skipping empty values
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address '%s': %w", value, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range '%s': %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// Allows reports whether the filter lets the address through
+func (f IPFilter) Allows(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	contains := func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	}
+
+	if slices.ContainsFunc(f.Deny, contains) {
+		return false
+	}
+	return len(f.Allow) == 0 || slices.ContainsFunc(f.Allow, contains)
+}
+
+// Middleware rejects requests from peers the filter doesn't allow, for
+// handlers outside a DocRouter such as an admin API. Forwarding headers are
+// ignored.
+func (f IPFilter) Middleware(next http.Handler) http.Handler {
+	return ipFilterMiddleware(nil, &f, next)
+}
+
+// WithIPFilter restricts every route that doesn't set its own filter
+func (dr *DocRouter) WithIPFilter(filter IPFilter) *DocRouter {
+	dr.ipFilter = &filter
+	return dr
+}
+
+// WithIPFilter restricts the route to the filter's ranges, replacing the
+// router's filter
+func (rc *RouteConfig) WithIPFilter(filter IPFilter) *RouteConfig {
+	rc.ipFilter = &filter
+	return rc
+}
+
+// ipFilterMiddleware rejects requests the route's filter doesn't allow,
+// falling back to the router's filter at request time. Client addresses are
+// resolved through the router's trusted proxies.
+func ipFilterMiddleware(dr *DocRouter, filter *IPFilter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := filter
+		if f == nil && dr != nil {
+			f = dr.ipFilter
+		}
+		if f == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		addr, ok := remoteAddr(r)
+		if dr != nil {
+			addr, ok = dr.ClientIP(r)
+		}
+		if !ok || !f.Allows(addr) {
+			writeValidationError(w, r, http.StatusForbidden, "client address not allowed", nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// remoteAddr returns the address of the peer the request came from
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
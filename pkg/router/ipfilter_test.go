@@ -0,0 +1,96 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIPFilter(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		allow   []string
+		deny    []string
+		want    IPFilter
+		wantErr string
+	}{
+		"ranges and addresses": {
+			allow: []string{"10.0.0.0/8", " 192.168.1.7 "},
+			deny:  []string{"10.1.2.3/16", ""},
+			want: IPFilter{
+				Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.7/32")},
+				Deny:  []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")},
+			},
+		},
+		"invalid range": {
+			allow:   []string{"10.0.0.0/33"},
+			wantErr: `parse allowed ranges: invalid range '10.0.0.0/33': netip.ParsePrefix("10.0.0.0/33"): prefix length out of range`,
+		},
+		"invalid address": {
+			deny:    []string{"localhost"},
+			wantErr: `parse denied ranges: invalid address 'localhost': ParseAddr("localhost"): unable to parse IP`,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := ParseIPFilter(tc.allow, tc.deny)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, filter)
+		})
+	}
+}
+
+func TestIPFilter(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	internal, err := ParseIPFilter([]string{"10.0.0.0/8", "::1"}, []string{"10.0.99.0/24"})
+	require.NoError(t, err)
+
+	r := NewDocRouter()
+	r.Route("GET", "/public", noop).Register()
+	r.Route("GET", "/internal", noop).WithIPFilter(internal).Register()
+
+	for name, tc := range map[string]struct {
+		path       string
+		remoteAddr string
+		wantStatus int
+	}{
+		"unrestricted route":   {path: "/public", remoteAddr: "203.0.113.5:1234", wantStatus: http.StatusOK},
+		"allowed":              {path: "/internal", remoteAddr: "10.1.2.3:1234", wantStatus: http.StatusOK},
+		"allowed ipv6":         {path: "/internal", remoteAddr: "[::1]:1234", wantStatus: http.StatusOK},
+		"ipv4 mapped":          {path: "/internal", remoteAddr: "[::ffff:10.1.2.3]:1234", wantStatus: http.StatusOK},
+		"outside allowed":      {path: "/internal", remoteAddr: "203.0.113.5:1234", wantStatus: http.StatusForbidden},
+		"denied within allow":  {path: "/internal", remoteAddr: "10.0.99.4:1234", wantStatus: http.StatusForbidden},
+		"unparseable address":  {path: "/internal", remoteAddr: "pipe", wantStatus: http.StatusForbidden},
+		"address without port": {path: "/internal", remoteAddr: "10.1.2.3", wantStatus: http.StatusOK},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set(RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantStatus == http.StatusForbidden {
+				assert.JSONEq(t, `{"error": "client address not allowed", "errors": [], "request_id": "req-1"}`, rec.Body.String())
+			}
+		})
+	}
+}
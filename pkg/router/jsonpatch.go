@@ -0,0 +1,476 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchContentType is the media type of JSON Patch documents
+const JSONPatchContentType = "application/json-patch+json"
+
+// patchOps are the operations of JSON Patch documents
+var patchOps = []string{"add", "remove", "replace", "move", "copy", "test"}
+
+// PatchOperation is an operation of a JSON Patch document (RFC 6902)
+type PatchOperation struct {
+	Op    string          `json:"op"`              // One of add, remove, replace, move, copy and test
+	Path  string          `json:"path"`            // JSON Pointer (RFC 6901) to the target location
+	From  string          `json:"from,omitempty"`  // JSON Pointer to the source location of move and copy
+	Value json.RawMessage `json:"value,omitempty"` // Value to add, replace or test against
+}
+
+// JSONPatch is a JSON Patch document (RFC 6902), the operations to apply to a
+// JSON document in order
+type JSONPatch []PatchOperation
+
+// jsonPatchType is documented with jsonPatchSchema instead of being reflected,
+// as operation values can be of any type
+var jsonPatchType = reflect.TypeOf(JSONPatch{})
+
+// WithJSONPatch documents the request body as a JSON Patch document of media
+// type application/json-patch+json, typically on PATCH routes, and validates
+// its operations before the handler runs, responding with a structured 400
+// for malformed JSON and 422 for invalid operations. Handlers decode the
+// body into a JSONPatch and apply it to the resource with Apply.
+func (rc *RouteConfig) WithJSONPatch() *RouteConfig {
+	rc.requestType = JSONPatch{}
+	rc.requestContentType = JSONPatchContentType
+	rc.bodyValidation = true
+	return rc
+}
+
+// jsonPatchSchema documents JSON Patch documents
+func jsonPatchSchema() map[string]any {
+	return map[string]any{
+		"type":        "array",
+		"description": "JSON Patch document (RFC 6902), the operations to apply in order",
+		"items": map[string]any{
+			"type":     "object",
+			"required": []string{"op", "path"},
+			"properties": map[string]any{
+				"op": map[string]any{
+					"type":        "string",
+					"enum":        slices.Clone(patchOps),
+					"description": "Operation to perform",
+				},
+				"path": map[string]any{
+					"type":        "string",
+					"description": "JSON Pointer (RFC 6901) to the target location",
+					"example":     "/title",
+				},
+				"from": map[string]any{
+					"type":        "string",
+					"description": "JSON Pointer to the source location, required by move and copy",
+				},
+				"value": map[string]any{
+					"description": "Value to add, replace or test against, required by add, replace and test",
+				},
+			},
+		},
+	}
+}
+
+// validatePatch wraps a handler so that JSON Patch documents are checked
+// before the handler runs, like validateBody. The body is restored for the
+// handler to decode.
+func validatePatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request body",
+				[]invalidValue{{in: "body", msg: textMessage(err.Error())}})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+
+		var operations []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &operations); err != nil {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request body",
+				[]invalidValue{{in: "body", msg: textMessage(err.Error())}})
+			return
+		}
+
+		if errs := validatePatchOperations(operations); len(errs) > 0 {
+			writeValidationError(w, r, http.StatusUnprocessableEntity, "invalid request body", errs)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validatePatchOperations checks that every operation has the members its op
+// requires, with valid JSON Pointers
+func validatePatchOperations(operations []map[string]json.RawMessage) []invalidValue {
+	var errs []invalidValue
+	for i, operation := range operations {
+		invalid := func(member string, msg *message) {
+			errs = append(errs, invalidValue{
+				field: fmt.Sprintf("[%d].%s", i, member),
+				in:    "body",
+				msg:   msg,
+			})
+		}
+
+		op, ok, err := patchMember(operation, "op")
+		switch {
+		case err != nil:
+			invalid("op", errorMessage(err))
+			continue
+		case !ok:
+			invalid("op", newMessage("is required"))
+			continue
+		case !slices.Contains(patchOps, op):
+			invalid("op", newMessage("must be one of: %s", strings.Join(patchOps, ", ")))
+			continue
+		}
+
+		pointers := []string{"path"}
+		if op == "move" || op == "copy" {
+			pointers = append(pointers, "from")
+		}
+		for _, member := range pointers {
+			pointer, ok, err := patchMember(operation, member)
+			switch {
+			case err != nil:
+				invalid(member, errorMessage(err))
+			case !ok:
+				invalid(member, newMessage("is required by %s", op))
+			default:
+				if _, err := parsePointer(pointer); err != nil {
+					invalid(member, errorMessage(err))
+				}
+			}
+		}
+
+		if _, ok := operation["value"]; !ok && (op == "add" || op == "replace" || op == "test") {
+			invalid("value", newMessage("is required by %s", op))
+		}
+	}
+
+	return errs
+}
+
+// patchMember returns a string member of an operation, and whether it is set
+func patchMember(operation map[string]json.RawMessage, name string) (string, bool, error) {
+	raw, ok := operation[name]
+	if !ok {
+		return "", false, nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", true, newMessage("must be a string")
+	}
+	return value, true, nil
+}
+
+// Apply applies the patch to a JSON document, returning the patched
+// document. Operations are applied in order and the patch fails as a whole
+// on the first one that can't be applied, e.g. whose path doesn't exist or
+// whose test doesn't match; the document passed in is never modified.
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	target, err := decodePatchValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	for i, op := range p {
+		target, err = op.apply(target)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+// apply applies the operation to the decoded document
+func (op PatchOperation) apply(doc any) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("value is required")
+		}
+		if value, err = decodePatchValue(op.Value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	var from []string
+	if op.Op == "move" || op.Op == "copy" {
+		if from, err = parsePointer(op.From); err != nil {
+			return nil, err
+		}
+		if value, err = pointerGet(doc, from); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return pointerAdd(doc, path, value)
+	case "remove":
+		return pointerRemove(doc, path)
+	case "replace":
+		if _, err := pointerGet(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		return pointerUpdate(doc, path, func(container any, token string) (any, error) {
+			return pointerSet(container, token, value)
+		})
+	case "move":
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("can't move %s into one of its children", op.From)
+		}
+		if doc, err = pointerRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+	case "copy":
+		return pointerAdd(doc, path, copyPatchValue(value))
+	case "test":
+		current, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !patchValuesEqual(current, value) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens;
+// the empty pointer refers to the whole document
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, newMessage("pointer %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, newMessage("pointer %q has an invalid escape, ~ must be followed by 0 or 1", pointer)
+			}
+		}
+		// ~1 is unescaped first, so ~01 is "~1" and not "/"
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// pointerGet returns the value the tokens refer to
+func pointerGet(doc any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			doc = value
+		case []any:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+		}
+	}
+
+	return doc, nil
+}
+
+// pointerAdd adds the value at the tokens, inserting it into arrays
+func pointerAdd(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return pointerUpdate(doc, tokens, func(container any, token string) (any, error) {
+		array, ok := container.([]any)
+		if !ok {
+			return pointerSet(container, token, value)
+		}
+
+		if token == "-" {
+			return append(array, value), nil
+		}
+		i, err := arrayIndex(token, len(array))
+		if err != nil {
+			return nil, err
+		}
+		return slices.Insert(array, i, value), nil
+	})
+}
+
+// pointerRemove removes the value at the tokens
+func pointerRemove(doc any, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("can't remove the whole document")
+	}
+
+	return pointerUpdate(doc, tokens, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			if _, ok := node[token]; !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			delete(node, token)
+			return node, nil
+		case []any:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(node, i, i+1), nil
+		default:
+			return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+		}
+	})
+}
+
+// pointerSet replaces the member or item of the container referred to by
+// the token, adding members that don't exist
+func pointerSet(container any, token string, value any) (any, error) {
+	switch node := container.(type) {
+	case map[string]any:
+		node[token] = value
+		return node, nil
+	case []any:
+		i, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+		return node, nil
+	default:
+		return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+	}
+}
+
+// pointerUpdate replaces the container of the value the tokens refer to by
+// the result of fn, given the container and the last token; arrays may grow
+// or shrink, so each container is stored back into its parent
+func pointerUpdate(doc any, tokens []string, fn func(container any, token string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+
+	child, err := pointerGet(doc, tokens[:1])
+	if err != nil {
+		return nil, err
+	}
+	updated, err := pointerUpdate(child, tokens[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return pointerSet(doc, tokens[0], updated)
+}
+
+// arrayIndex parses an array index token, which must be at most last
+func arrayIndex(token string, last int) (int, error) {
+	// leading zeros are not allowed, nor signs
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.ContainsAny(token, "+-") {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > last {
+		return 0, fmt.Errorf("array index %d out of bounds", i)
+	}
+	return i, nil
+}
+
+// decodePatchValue decodes a JSON value, keeping numbers as written
+func decodePatchValue(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// copyPatchValue deep copies a decoded JSON value, so copies don't share
+// objects and arrays with their source
+func copyPatchValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, v := range value {
+			copied[key] = copyPatchValue(v)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, v := range value {
+			copied[i] = copyPatchValue(v)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// patchValuesEqual reports whether decoded JSON values are equal, comparing
+// numbers by value so that 1 and 1.0 are equal
+func patchValuesEqual(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, v := range a {
+			w, ok := b[key]
+			if !ok || !patchValuesEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		return ok && slices.EqualFunc(a, b, patchValuesEqual)
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,179 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPatchApply(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		doc     string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		"add member": {
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  `{"baz": "qux", "foo": "bar"}`,
+		},
+		"add array item": {
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:  `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		"append array item": {
+			doc:   `{"foo": {"items": [1]}}`,
+			patch: `[{"op": "add", "path": "/foo/items/-", "value": {"n": 2}}]`,
+			want:  `{"foo": {"items": [1, {"n": 2}]}}`,
+		},
+		"add null": {
+			doc:   `{}`,
+			patch: `[{"op": "add", "path": "/foo", "value": null}]`,
+			want:  `{"foo": null}`,
+		},
+		"remove array item": {
+			doc:   `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`,
+			want:  `{"foo": ["bar", "baz"]}`,
+		},
+		"replace whole document": {
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "", "value": [1.50]}]`,
+			want:  `[1.50]`,
+		},
+		"move": {
+			doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		"copy is independent": {
+			doc: `{"a": {"b": 1}}`,
+			patch: `[{"op": "copy", "from": "/a", "path": "/c"},
+				{"op": "replace", "path": "/c/b", "value": 2}]`,
+			want: `{"a": {"b": 1}, "c": {"b": 2}}`,
+		},
+		"escaped pointer": {
+			doc:   `{"a/b": {"m~n": 1}}`,
+			patch: `[{"op": "test", "path": "/a~1b/m~0n", "value": 1.0}, {"op": "remove", "path": "/a~1b/m~0n"}]`,
+			want:  `{"a/b": {}}`,
+		},
+		"test failed": {
+			doc:     `{"baz": "qux"}`,
+			patch:   `[{"op": "replace", "path": "/baz", "value": "boo"}, {"op": "test", "path": "/baz", "value": "qux"}]`,
+			wantErr: "operation 1 (test /baz): test failed",
+		},
+		"missing member": {
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "replace", "path": "/baz", "value": "qux"}]`,
+			wantErr: `operation 0 (replace /baz): member "baz" not found`,
+		},
+		"index out of bounds": {
+			doc:     `{"foo": ["bar"]}`,
+			patch:   `[{"op": "add", "path": "/foo/2", "value": "qux"}]`,
+			wantErr: "operation 0 (add /foo/2): array index 2 out of bounds",
+		},
+		"move into child": {
+			doc:     `{"a": {"b": 1}}`,
+			patch:   `[{"op": "move", "from": "/a", "path": "/a/c"}]`,
+			wantErr: "operation 0 (move /a/c): can't move /a into one of its children",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var patch JSONPatch
+			require.NoError(t, json.Unmarshal([]byte(tc.patch), &patch))
+
+			got, err := patch.Apply([]byte(tc.doc))
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestWithJSONPatch(t *testing.T) {
+	t.Parallel()
+
+	var received string
+	r := NewDocRouter()
+	r.Patch("/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}).WithName("Patch Todo").WithJSONPatch().Register()
+
+	spec := r.OpenAPI().Generate()
+	operation := spec["paths"].(map[string]any)["/todos/{id}"].(map[string]any)["patch"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		JSONPatchContentType: map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/JSONPatch"}},
+	}, operation["requestBody"].(map[string]any)["content"])
+	assert.Contains(t, operation["responses"], "422")
+
+	schema := spec["components"].(map[string]any)["schemas"].(map[string]any)["JSONPatch"].(map[string]any)
+	assert.Equal(t, "array", schema["type"])
+	value := schema["items"].(map[string]any)["properties"].(map[string]any)["value"]
+	assert.NotContains(t, value, "type", "values can be of any type")
+
+	for name, tc := range map[string]struct {
+		body       string
+		wantStatus int
+		wantErrors []ValidationError
+	}{
+		"valid": {
+			body:       `[{"op": "replace", "path": "/title", "value": null}, {"op": "move", "from": "/a", "path": "/b"}]`,
+			wantStatus: http.StatusOK,
+		},
+		"malformed": {
+			body:       `{"op": "remove", "path": "/title"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		"invalid operations": {
+			body: `[{"path": "/title"}, {"op": "delete", "path": "/title"}, {"op": "add", "path": "title"},
+				{"op": "copy", "path": "/b"}, {"op": "test", "path": 1, "value": 1}, {"op": "remove", "path": "/a~2"}]`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantErrors: []ValidationError{
+				{Field: "[0].op", In: "body", Code: CodeRequired, Message: "is required"},
+				{Field: "[1].op", In: "body", Code: CodeEnum, Message: "must be one of: add, remove, replace, move, copy, test"},
+				{Field: "[2].path", In: "body", Code: CodePointer, Message: `pointer "title" must start with /`},
+				{Field: "[2].value", In: "body", Code: CodeRequired, Message: "is required by add"},
+				{Field: "[3].from", In: "body", Code: CodeRequired, Message: "is required by copy"},
+				{Field: "[4].path", In: "body", Code: CodeType, Message: "must be a string"},
+				{Field: "[5].path", In: "body", Code: CodePointer, Message: `pointer "/a~2" has an invalid escape, ~ must be followed by 0 or 1`},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/todos/1", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", JSONPatchContentType)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, tc.body, received, "the body is restored for the handler")
+				return
+			}
+
+			var resp ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			if tc.wantErrors != nil {
+				assert.Equal(t, tc.wantErrors, resp.Errors)
+			}
+		})
+	}
+}
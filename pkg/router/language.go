@@ -0,0 +1,38 @@
+package router
+
+// WithContentLanguage documents the Content-Language header of the route's
+// responses, listing the languages their content may be in, e.g. for
+// handlers localizing messages from the Accept-Language header
+func (rc *RouteConfig) WithContentLanguage(languages ...string) *RouteConfig {
+	rc.contentLanguages = append(rc.contentLanguages, languages...)
+	return rc
+}
+
+// addContentLanguage documents the Content-Language header on the responses
+// with content; referenced responses are left untouched
+func addContentLanguage(responses map[string]any, languages []string) {
+	if len(languages) == 0 {
+		return
+	}
+
+	for _, response := range responses {
+		response, ok := response.(map[string]any)
+		if !ok || response["$ref"] != nil || response["content"] == nil {
+			continue
+		}
+
+		headers, ok := response["headers"].(map[string]any)
+		if !ok {
+			headers = map[string]any{}
+			response["headers"] = headers
+		}
+
+		headers["Content-Language"] = map[string]any{
+			"description": "Language of the response content",
+			"schema": map[string]any{
+				"type": "string",
+				"enum": languages,
+			},
+		}
+	}
+}
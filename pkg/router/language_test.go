@@ -0,0 +1,37 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentLanguage(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Get("/greeting", noop).
+		WithResponse(UserResponse{}).
+		WithErrorResponse("404", "Not Found", UserResponse{}).
+		WithResponseRef("500", "Error").
+		WithContentResponse("204", "No greeting", "", nil).
+		WithContentLanguage("en", "de").
+		Register()
+
+	generator := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes())
+	responses := generator.Generate()["paths"].(map[string]any)["/greeting"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)
+
+	header := map[string]any{
+		"description": "Language of the response content",
+		"schema":      map[string]any{"type": "string", "enum": []string{"de", "en"}},
+	}
+	for _, status := range []string{"200", "404"} {
+		headers := responses[status].(map[string]any)["headers"].(map[string]any)
+		assert.Equal(t, header, headers["Content-Language"], status)
+	}
+	assert.NotContains(t, responses["500"], "headers", "referenced responses are left alone")
+	assert.NotContains(t, responses["204"], "headers", "responses without content have no language")
+}
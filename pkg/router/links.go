@@ -0,0 +1,50 @@
+package router
+
+// Link documents how values of a response can be used to call another
+// operation, identified by its method and path
+type Link struct {
+	Method      string            // Method of the target operation
+	Path        string            // Path of the target operation
+	Parameters  map[string]string // Target parameter names mapped to runtime expressions (e.g. $response.body#/todo/id)
+	Description string            // Description of the link (optional)
+}
+
+// WithLink documents a link from the response with the given status code to
+// another operation, e.g. from a parent resource to its children
+func (rc *RouteConfig) WithLink(statusCode, name string, link Link) *RouteConfig {
+	if rc.links == nil {
+		rc.links = make(map[string]map[string]Link)
+	}
+	if rc.links[statusCode] == nil {
+		rc.links[statusCode] = make(map[string]Link)
+	}
+
+	rc.links[statusCode][name] = link
+	return rc
+}
+
+// addLinks attaches the route's links to its documented responses
+func (g *OpenAPIGenerator) addLinks(responses map[string]any, links map[string]map[string]Link) {
+	for statusCode, named := range links {
+		response, ok := responses[statusCode].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		result := map[string]any{}
+		for name, link := range named {
+			entry := map[string]any{
+				"operationId": g.operationID(link.Method, link.Path),
+			}
+			if len(link.Parameters) > 0 {
+				entry["parameters"] = link.Parameters
+			}
+			if link.Description != "" {
+				entry["description"] = link.Description
+			}
+			result[name] = entry
+		}
+
+		response["links"] = result
+	}
+}
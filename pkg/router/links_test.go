@@ -0,0 +1,50 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLinks(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Route("GET", "/users/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+		WithResponse(UserResponse{}).
+		WithLink("200", "posts", Link{
+			Method:      "GET",
+			Path:        "/users/{id}/posts",
+			Parameters:  map[string]string{"id": "$response.body#/id"},
+			Description: "Posts written by the user",
+		}).
+		WithLink("404", "ignored", Link{Method: "GET", Path: "/users"}).
+		Register()
+	r.Route("GET", "/users/{id}/posts", func(w http.ResponseWriter, r *http.Request) {}).
+		Register()
+
+	spec := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes()).Generate()
+	paths := spec["paths"].(map[string]any)
+
+	responses := paths["/users/{id}"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)
+	expected := map[string]any{
+		"posts": map[string]any{
+			"operationId": "get__users_{id}_posts",
+			"parameters":  map[string]string{"id": "$response.body#/id"},
+			"description": "Posts written by the user",
+		},
+	}
+	if diff := cmp.Diff(expected, responses["200"].(map[string]any)["links"]); diff != "" {
+		t.Errorf("links mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, documented := responses["404"]; documented {
+		t.Errorf("links should not document responses that don't exist")
+	}
+
+	target := paths["/users/{id}/posts"].(map[string]any)["get"].(map[string]any)
+	if target["operationId"] != "get__users_{id}_posts" {
+		t.Errorf("link should reference the target's operationId, got %v", target["operationId"])
+	}
+}
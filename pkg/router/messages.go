@@ -0,0 +1,266 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cirocosta/openapi-router-go/pkg/reqctx"
+)
+
+// Codes of validation messages, stable across languages so clients can
+// branch on them whatever language the messages are in
+const (
+	CodeRequired         = "required"
+	CodeNotRepeated      = "not_repeated"
+	CodeNotNull          = "not_null"
+	CodeType             = "type"
+	CodeFormat           = "format"
+	CodeEnum             = "enum"
+	CodePattern          = "pattern"
+	CodeMinLength        = "min_length"
+	CodeMaxLength        = "max_length"
+	CodeMinItems         = "min_items"
+	CodeMaxItems         = "max_items"
+	CodeMinimum          = "minimum"
+	CodeExclusiveMinimum = "exclusive_minimum"
+	CodeMaximum          = "maximum"
+	CodeExclusiveMaximum = "exclusive_maximum"
+	CodePointer          = "pointer"
+	CodeNotAllowed       = "not_allowed"
+	CodeInvalid          = "invalid" // Messages without a template, e.g. from decoders or tenant validators
+)
+
+// ValidationMessage is the English template of a validation message, in the
+// fmt syntax it's formatted with, and its code
+type ValidationMessage struct {
+	Code     string // Code of the message, e.g. CodeMaximum
+	Template string // English template, e.g. "must be less than or equal to %v"
+}
+
+// validationMessages are the templates of the router's validation messages
+var validationMessages = []ValidationMessage{
+	{CodeRequired, "is required by %s"},
+	{CodeRequired, "is required"},
+	{CodeNotRepeated, "must not be repeated"},
+	{CodeNotNull, "must not be null"},
+	{CodeType, "must be a boolean"},
+	{CodeType, "must be an integer"},
+	{CodeType, "must be a non-negative integer"},
+	{CodeType, "must be a number"},
+	{CodeType, "must be a string"},
+	{CodeType, "must be an object"},
+	{CodeType, "must be an array"},
+	{CodeFormat, "must be an RFC 3339 date-time"},
+	{CodeFormat, "must be a date (YYYY-MM-DD)"},
+	{CodeFormat, "must be a valid %s"},
+	{CodeFormat, "must be 1-64 letters, digits, '-' or '_'"},
+	{CodeFormat, "must be a version such as 1.4.2"},
+	{CodeEnum, "must be one of: %s"},
+	{CodeEnum, "must accept one of: %s"},
+	{CodePattern, "must match pattern %s"},
+	{CodeMinLength, "must have at least %d characters"},
+	{CodeMaxLength, "must have at most %d characters"},
+	{CodeMinItems, "must have at least %d items"},
+	{CodeMaxItems, "must have at most %d items"},
+	{CodeMinimum, "must be greater than or equal to %v"},
+	{CodeExclusiveMinimum, "must be greater than %v"},
+	{CodeMaximum, "must be less than or equal to %v"},
+	{CodeExclusiveMaximum, "must be less than %v"},
+	{CodePointer, "pointer %q must start with /"},
+	{CodePointer, "pointer %q has an invalid escape, ~ must be followed by 0 or 1"},
+	{CodeNotAllowed, "property '%s' is not allowed"},
+}
+
+// messageCodes are the codes of validationMessages by template
+var messageCodes = func() map[string]string {
+	codes := make(map[string]string, len(validationMessages))
+	for _, message := range validationMessages {
+		codes[message.Template] = message.Code
+	}
+	return codes
+}()
+
+// messageContexts are the templates giving the context of another message,
+// e.g. the array item it's about, which catalogs may translate too. Their
+// second argument is the message, whose code they keep.
+var messageContexts = []string{
+	"item '%s' %s",
+	"property '%s' %s",
+}
+
+// validationSummaries are the messages summarizing validation error
+// responses, which catalogs may translate too
+var validationSummaries = []string{
+	"invalid request parameters",
+	"invalid request body",
+	"invalid tenant",
+	"unsupported version",
+	"https required",
+	"client certificate required",
+	"client address not allowed",
+	"server busy",
+	"unsupported media type",
+	"not acceptable",
+}
+
+// verbPattern matches the verbs of message templates, with their optional
+// explicit argument index, e.g. %s or %[2]d
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[sdvq]`)
+
+// ValidationMessages returns the templates of the router's validation
+// messages, which message catalogs translate
+func ValidationMessages() []ValidationMessage {
+	return slices.Clone(validationMessages)
+}
+
+// MessageCatalog translates validation messages into a language, by their
+// English template (see ValidationMessages), including the templates giving
+// the context of messages about array items and object properties,
+// "item '%s' %s" and "property '%s' %s", or, for the summaries of
+// validation error responses, their English text. Translations take the
+// template's arguments in the same order, or reorder them with explicit
+// argument indexes, e.g. "muss kleiner oder gleich %[1]v sein".
+type MessageCatalog map[string]string
+
+// WithMessageCatalog returns validation errors in the language to requests
+// preferring it through Accept-Language, e.g. "de" for "de-CH, en;q=0.5".
+// Messages the catalog doesn't translate stay in English; their codes are
+// the same in every language. It panics on translations of unknown
+// messages or with a different number of arguments.
+func (dr *DocRouter) WithMessageCatalog(language string, catalog MessageCatalog) *DocRouter {
+	for template, translation := range catalog {
+		if err := validateTranslation(template, translation); err != nil {
+			panic(fmt.Sprintf("router: invalid %s message catalog: %v", language, err))
+		}
+	}
+
+	if dr.messageCatalogs == nil {
+		dr.messageCatalogs = make(map[string]MessageCatalog)
+	}
+	dr.messageCatalogs[strings.ToLower(language)] = catalog
+	return dr
+}
+
+// validateTranslation checks that the template is a known message and that
+// the translation takes as many arguments
+func validateTranslation(template, translation string) error {
+	if slices.Contains(validationSummaries, template) {
+		return nil
+	}
+	if _, ok := messageCodes[template]; !ok && !slices.Contains(messageContexts, template) {
+		return fmt.Errorf("unknown message %q", template)
+	}
+
+	want := len(verbPattern.FindAllString(template, -1))
+	if got := len(verbPattern.FindAllString(translation, -1)); got != want {
+		return fmt.Errorf("translation %q of %q takes %d arguments instead of %d", translation, template, got, want)
+	}
+	return nil
+}
+
+// messageCatalogsKey is the context key under which the router's message
+// catalogs are stored
+type messageCatalogsKey struct{}
+
+// messageCatalogMiddleware makes the router's message catalogs available to
+// the validation errors written while serving requests
+func messageCatalogMiddleware(dr *DocRouter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(dr.messageCatalogs) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), messageCatalogsKey{}, dr.messageCatalogs)))
+	})
+}
+
+// requestCatalog returns the catalog of the language the request prefers
+// among those of the router, matching tags such as de-CH to de
+func requestCatalog(r *http.Request) (string, MessageCatalog) {
+	catalogs, _ := r.Context().Value(messageCatalogsKey{}).(map[string]MessageCatalog)
+	if len(catalogs) == 0 {
+		return "", nil
+	}
+
+	for _, language := range reqctx.PreferredLanguages(r.Header.Get("Accept-Language")) {
+		language = strings.ToLower(language)
+		if catalog, ok := catalogs[language]; ok {
+			return language, catalog
+		}
+		primary, _, _ := strings.Cut(language, "-")
+		if catalog, ok := catalogs[primary]; ok {
+			return primary, catalog
+		}
+	}
+	return "", nil
+}
+
+// message is a validation message: its code, the template it's formatted
+// from and the arguments it's formatted with, so that it can be translated
+// once the language of the response is known. Arguments may be messages
+// themselves, such as the message of an array item.
+type message struct {
+	code     string
+	template string
+	args     []any
+}
+
+// newMessage returns a message of one of validationMessages' templates. It
+// panics on unknown templates, which catalogs couldn't translate.
+func newMessage(template string, args ...any) *message {
+	code, ok := messageCodes[template]
+	if !ok {
+		panic(fmt.Sprintf("router: unknown validation message %q", template))
+	}
+	return &message{code: code, template: template, args: args}
+}
+
+// contextMessage returns a message giving the context of another, with one
+// of messageContexts' templates and the code of the message
+func contextMessage(template, context string, msg *message) *message {
+	return &message{code: msg.code, template: template, args: []any{context, msg}}
+}
+
+// textMessage returns a message without a template, such as a decoder's
+// error, which catalogs don't translate as they can't have a bare %s
+func textMessage(text string) *message {
+	return &message{code: CodeInvalid, template: "%s", args: []any{text}}
+}
+
+// errorMessage returns the message of an error, which is the error itself
+// for validation messages returned as errors
+func errorMessage(err error) *message {
+	var msg *message
+	if errors.As(err, &msg) {
+		return msg
+	}
+	return textMessage(err.Error())
+}
+
+// format formats the message, translated with the catalog when it has the
+// template; messages it wraps are formatted with the catalog too
+func (m *message) format(catalog MessageCatalog) string {
+	template := m.template
+	if translation, ok := catalog[template]; ok {
+		template = translation
+	}
+
+	args := make([]any, len(m.args))
+	for i, arg := range m.args {
+		if msg, ok := arg.(*message); ok {
+			arg = msg.format(catalog)
+		}
+		args[i] = arg
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// Error formats the message in English, so it can be returned as an error
+func (m *message) Error() string {
+	return m.format(nil)
+}
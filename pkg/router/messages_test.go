@@ -0,0 +1,142 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageCatalog(t *testing.T) {
+	t.Parallel()
+
+	minimum, maximum := 1.0, 100.0
+	r := NewDocRouter().
+		WithMessageCatalog("de", MessageCatalog{
+			"invalid request parameters":       "ungültige Anfrageparameter",
+			"is required":                      "ist erforderlich",
+			"must be less than or equal to %v": "darf höchstens %[1]v sein",
+			"must be one of: %s":               "muss einer der Werte %s sein",
+			"must be an integer":               "muss eine ganze Zahl sein",
+			"item '%s' %s":                     "Element '%s' %s",
+		}).
+		WithMessageCatalog("fr", MessageCatalog{"is required": "est obligatoire"})
+	r.Get("/todos", func(w http.ResponseWriter, r *http.Request) {}).
+		WithParameter(Parameter{Name: "limit", In: "query", Schema: 0, Minimum: &minimum, Maximum: &maximum}).
+		WithParameter(Parameter{Name: "status", In: "query", Schema: "", Enum: []string{"open", "done"}}).
+		WithParameter(Parameter{Name: "since", In: "query", Schema: 0}).
+		WithParameter(Parameter{Name: "owner", In: "query", Schema: "", Required: true}).
+		WithParameter(Parameter{Name: "ids", In: "query", Schema: []int{}}).
+		WithQueryValidation().
+		Register()
+
+	for name, tc := range map[string]struct {
+		acceptLanguage string
+		wantLanguage   string
+		wantError      string
+		wantErrors     []ValidationError
+	}{
+		"english by default": {
+			wantError: "invalid request parameters",
+			wantErrors: []ValidationError{
+				{Field: "limit", In: "query", Code: CodeMaximum, Message: "must be less than or equal to 100"},
+				{Field: "status", In: "query", Code: CodeEnum, Message: "must be one of: open, done"},
+				{Field: "since", In: "query", Code: CodeType, Message: "must be an integer"},
+				{Field: "owner", In: "query", Code: CodeRequired, Message: "is required"},
+				{Field: "ids", In: "query", Code: CodeType, Message: "item 'a' must be an integer"},
+			},
+		},
+		"regional variant": {
+			acceptLanguage: "de-CH, en;q=0.5",
+			wantLanguage:   "de",
+			wantError:      "ungültige Anfrageparameter",
+			wantErrors: []ValidationError{
+				{Field: "limit", In: "query", Code: CodeMaximum, Message: "darf höchstens 100 sein"},
+				{Field: "status", In: "query", Code: CodeEnum, Message: "muss einer der Werte open, done sein"},
+				{Field: "since", In: "query", Code: CodeType, Message: "muss eine ganze Zahl sein"},
+				{Field: "owner", In: "query", Code: CodeRequired, Message: "ist erforderlich"},
+				{Field: "ids", In: "query", Code: CodeType, Message: "Element 'a' muss eine ganze Zahl sein"},
+			},
+		},
+		"by quality": {
+			acceptLanguage: "es, de;q=0.2, fr;q=0.8",
+			wantLanguage:   "fr",
+			wantError:      "invalid request parameters",
+			wantErrors: []ValidationError{
+				{Field: "limit", In: "query", Code: CodeMaximum, Message: "must be less than or equal to 100"},
+				{Field: "status", In: "query", Code: CodeEnum, Message: "must be one of: open, done"},
+				{Field: "since", In: "query", Code: CodeType, Message: "must be an integer"},
+				{Field: "owner", In: "query", Code: CodeRequired, Message: "est obligatoire"},
+				{Field: "ids", In: "query", Code: CodeType, Message: "item 'a' must be an integer"},
+			},
+		},
+		"wildcard and malformed weights": {
+			acceptLanguage: "*, de;q=high, fr;q=0.8",
+			wantLanguage:   "fr",
+			wantError:      "invalid request parameters",
+			wantErrors: []ValidationError{
+				{Field: "limit", In: "query", Code: CodeMaximum, Message: "must be less than or equal to 100"},
+				{Field: "status", In: "query", Code: CodeEnum, Message: "must be one of: open, done"},
+				{Field: "since", In: "query", Code: CodeType, Message: "must be an integer"},
+				{Field: "owner", In: "query", Code: CodeRequired, Message: "est obligatoire"},
+				{Field: "ids", In: "query", Code: CodeType, Message: "item 'a' must be an integer"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/todos?limit=500&status=late&since=yesterday&ids=1&ids=a", nil)
+			req.Header.Set("Accept-Language", tc.acceptLanguage)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, tc.wantLanguage, rec.Header().Get("Content-Language"))
+
+			var resp ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tc.wantError, resp.Error)
+			assert.ElementsMatch(t, tc.wantErrors, resp.Errors)
+		})
+	}
+
+	assert.PanicsWithValue(t, `router: invalid de message catalog: unknown message "must be great"`, func() {
+		NewDocRouter().WithMessageCatalog("de", MessageCatalog{"must be great": "muss toll sein"})
+	})
+	assert.PanicsWithValue(t, `router: invalid de message catalog: translation "muss kleiner sein" of "must be less than %v" takes 0 arguments instead of 1`, func() {
+		NewDocRouter().WithMessageCatalog("de", MessageCatalog{"must be less than %v": "muss kleiner sein"})
+	})
+	assert.PanicsWithValue(t, `router: invalid de message catalog: translation "Element %s" of "item '%s' %s" takes 1 arguments instead of 2`, func() {
+		NewDocRouter().WithMessageCatalog("de", MessageCatalog{"item '%s' %s": "Element %s"})
+	})
+}
+
+func TestValidationMessageCodes(t *testing.T) {
+	t.Parallel()
+
+	field, _ := reflect.TypeOf(ValidationError{}).FieldByName("Code")
+	documented := strings.Split(field.Tag.Get("enum"), ",")
+
+	for _, message := range ValidationMessages() {
+		assert.Contains(t, documented, message.Code, message.Template)
+		assert.Equal(t, message.Code, newMessage(message.Template).code, message.Template)
+	}
+	assert.Contains(t, documented, CodeInvalid)
+
+	// messages in context keep the code of the message
+	msg := contextMessage("property '%s' %s", "archived", newMessage("must be a boolean"))
+	assert.Equal(t, CodeType, msg.code)
+	assert.Equal(t, "property 'archived' must be a boolean", msg.Error())
+
+	assert.Equal(t, CodeInvalid, textMessage("unexpected EOF").code)
+	assert.PanicsWithValue(t, `router: unknown validation message "must be great"`, func() {
+		newMessage("must be great")
+	})
+}
@@ -0,0 +1,148 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// MiddlewareHandler names the route handler's entry in MiddlewareTiming
+const MiddlewareHandler = "handler"
+
+// MiddlewareTiming reports the time spent in a middleware layer, or in a
+// route's handler, over the requests served so far
+type MiddlewareTiming struct {
+	Route string        `json:"route,omitempty"` // Route of the middleware or handler, empty for the router's middleware
+	Name  string        `json:"name"`            // Function name of the middleware, or "handler"
+	Calls int64         `json:"calls"`           // Requests that went through the layer
+	Total time.Duration `json:"total_ns"`        // Time spent in the layer itself, without the layers it wraps
+}
+
+// middlewareLayer accumulates the timing of a middleware layer
+type middlewareLayer struct {
+	route string
+	name  string
+	calls atomic.Int64
+	total atomic.Int64
+}
+
+// WithMiddlewareTiming measures the time spent in each middleware layer of
+// the router and its routes, and in the routes' handlers, reported by
+// MiddlewareTimings. It adds a little overhead to every layer, so it is off
+// by default.
+func (dr *DocRouter) WithMiddlewareTiming() *DocRouter {
+	dr.middlewareTiming = true
+	return dr
+}
+
+// MiddlewareTimings returns the time spent in the router's middleware, in
+// the order it runs, followed by the middleware and handler of each route in
+// registration order; it is empty unless WithMiddlewareTiming is set
+func (dr *DocRouter) MiddlewareTimings() []MiddlewareTiming {
+	if !dr.middlewareTiming {
+		return nil
+	}
+
+	layers := append(slices.Clip(dr.routerLayers), dr.routeLayers...)
+
+	timings := make([]MiddlewareTiming, 0, len(layers))
+	for _, layer := range layers {
+		timings = append(timings, MiddlewareTiming{
+			Route: layer.route,
+			Name:  layer.name,
+			Calls: layer.calls.Load(),
+			Total: time.Duration(layer.total.Load()),
+		})
+	}
+
+	return timings
+}
+
+// timedRouterMiddleware applies the router's middleware to next, timing
+// each layer
+func (dr *DocRouter) timedRouterMiddleware(next http.Handler) http.Handler {
+	dr.routerLayers = make([]*middlewareLayer, len(dr.middleware))
+	for i, middleware := range dr.middleware {
+		dr.routerLayers[i] = &middlewareLayer{name: funcName(middleware)}
+	}
+
+	handler := next
+	for i := len(dr.middleware) - 1; i >= 0; i-- {
+		handler = timedMiddleware(dr, dr.routerLayers[i], dr.middleware[i], handler)
+	}
+	return handler
+}
+
+// newRouteLayers creates the timings of the route's middleware, followed by
+// its handler as the last layer; they are measured once WithMiddlewareTiming
+// is set, even if it is set after the route is registered
+func (rc *RouteConfig) newRouteLayers(route string) []*middlewareLayer {
+	layers := make([]*middlewareLayer, 0, len(rc.middleware)+1)
+	for _, middleware := range rc.middleware {
+		layers = append(layers, &middlewareLayer{route: route, name: funcName(middleware)})
+	}
+	layers = append(layers, &middlewareLayer{route: route, name: MiddlewareHandler})
+
+	rc.router.routeLayers = append(rc.router.routeLayers, layers...)
+	return layers
+}
+
+// record adds a request's time in the layer
+func (l *middlewareLayer) record(elapsed time.Duration) {
+	l.calls.Add(1)
+	l.total.Add(int64(elapsed))
+}
+
+// middlewareLayerKey is the context key under which a layer stores the time
+// its request spent in the handlers it wraps
+type middlewareLayerKey struct {
+	layer *middlewareLayer
+}
+
+// timedMiddleware applies the middleware to next, recording the time spent
+// in the middleware itself, the time it took to serve requests minus the
+// time next took, when the router times its middleware
+func timedMiddleware(dr *DocRouter, layer *middlewareLayer, middleware func(http.Handler) http.Handler, next http.Handler) http.Handler {
+	key := middlewareLayerKey{layer: layer}
+	wrapped := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !dr.middlewareTiming {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		if inNext, ok := r.Context().Value(key).(*time.Duration); ok {
+			*inNext += time.Since(start)
+		}
+	}))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !dr.middlewareTiming {
+			wrapped.ServeHTTP(w, r)
+			return
+		}
+
+		var inNext time.Duration
+		start := time.Now()
+		wrapped.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), key, &inNext)))
+		layer.record(time.Since(start) - inNext)
+	})
+}
+
+// timedHandler records the time spent in a route's handler when the router
+// times its middleware
+func timedHandler(dr *DocRouter, layer *middlewareLayer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !dr.middlewareTiming {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		layer.record(time.Since(start))
+	})
+}
@@ -0,0 +1,66 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepingMiddleware spends d before calling the next handler
+func sleepingMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(d)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMiddlewareTiming(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	r := NewDocRouter()
+	r.Use(sleepingMiddleware(time.Millisecond))
+	r.Group("/users").Use(sleepingMiddleware(2*time.Millisecond)).Get("", handler).Register()
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {}).Register()
+	r.WithMiddlewareTiming() // routes registered before are timed too
+
+	for i := 0; i < 2; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	timings := r.MiddlewareTimings()
+	require.Len(t, timings, 4)
+
+	routerMiddleware, routeMiddleware, usersHandler, healthHandler := timings[0], timings[1], timings[2], timings[3]
+	assert.Equal(t, "", routerMiddleware.Route)
+	assert.Contains(t, routerMiddleware.Name, "sleepingMiddleware")
+	assert.Equal(t, int64(3), routerMiddleware.Calls)
+	assert.GreaterOrEqual(t, routerMiddleware.Total, 3*time.Millisecond)
+	assert.Less(t, routerMiddleware.Total, 40*time.Millisecond, "time in the layers it wraps is left out")
+
+	assert.Equal(t, "GET /users", routeMiddleware.Route)
+	assert.Equal(t, int64(2), routeMiddleware.Calls)
+	assert.GreaterOrEqual(t, routeMiddleware.Total, 4*time.Millisecond)
+	assert.Less(t, routeMiddleware.Total, 40*time.Millisecond, "time in the handler is left out")
+
+	assert.Equal(t, MiddlewareTiming{Route: "GET /users", Name: MiddlewareHandler, Calls: 2, Total: usersHandler.Total}, usersHandler)
+	assert.GreaterOrEqual(t, usersHandler.Total, 40*time.Millisecond)
+	assert.Equal(t, "GET /health", healthHandler.Route)
+	assert.Equal(t, int64(1), healthHandler.Calls)
+
+	untimed := NewDocRouter()
+	untimed.Use(sleepingMiddleware(0))
+	untimed.Get("/health", func(w http.ResponseWriter, r *http.Request) {}).Register()
+	untimed.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Empty(t, untimed.MiddlewareTimings())
+}
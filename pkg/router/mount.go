@@ -0,0 +1,56 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// mount is a router serving the requests under a path prefix
+type mount struct {
+	prefix string
+	router *DocRouter
+}
+
+// Mount serves the requests under prefix with another router, such as the
+// router of another team's domain, which sees paths without the prefix. Its
+// routes are documented along with the router's own, with the prefix
+// applied, including routes registered after mounting. Requests go through
+// the router's middleware, then the mounted router's. The prefix can't
+// contain wildcards; it panics otherwise.
+func (dr *DocRouter) Mount(prefix string, router *DocRouter) *DocRouter {
+	path, err := normalizePath(prefix)
+	if err == nil && path == "/" {
+		err = fmt.Errorf("can't mount at the root")
+	}
+	if err == nil && strings.ContainsAny(path, "{}") {
+		err = fmt.Errorf("wildcards are not supported")
+	}
+	if err != nil {
+		panic(fmt.Sprintf("router: invalid mount prefix %q: %v", prefix, err))
+	}
+
+	var handler http.Handler = http.StripPrefix(path, router)
+	handler = concurrencyMiddleware(dr, nil, handler)
+	handler = ipFilterMiddleware(dr, nil, handler)
+	handler = transportMiddleware(dr, nil, handler)
+	handler = securityHeadersMiddleware(dr, nil, handler)
+
+	dr.mux.Handle(path+"/", handler)
+	dr.mounts = append(dr.mounts, mount{prefix: path, router: router})
+	return dr
+}
+
+// mountedRoutes returns the documented routes of the mounted routers, with
+// their prefix applied
+func (dr *DocRouter) mountedRoutes() []RouteInfo {
+	var routes []RouteInfo
+	for _, m := range dr.mounts {
+		for _, route := range m.router.GetRoutes() {
+			route.Path = m.prefix + route.Path
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
+}
@@ -0,0 +1,108 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMount(t *testing.T) {
+	t.Parallel()
+
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path + " " + r.PathValue("id")))
+	}
+
+	admin := NewDocRouter()
+	admin.Get("/users/{id}", echo).WithName("Get User").WithTags("Admin").Register()
+
+	r := NewDocRouter().WithParameter(Parameter{Name: "X-Request-Id", In: "header", Schema: ""})
+	r.Get("/health", echo).Register()
+	r.Mount("/admin/", admin)
+
+	// routes registered after mounting are served and documented too
+	admin.Delete("/users/{id}", echo).WithName("Delete User").Register()
+
+	for name, tc := range map[string]struct {
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		"mounted route": {
+			method:     http.MethodGet,
+			path:       "/admin/users/1",
+			wantStatus: http.StatusOK,
+			wantBody:   "/users/1 1",
+		},
+		"registered after mounting": {
+			method:     http.MethodDelete,
+			path:       "/admin/users/2",
+			wantStatus: http.StatusOK,
+			wantBody:   "/users/2 2",
+		},
+		"own route": {
+			method:     http.MethodGet,
+			path:       "/health",
+			wantStatus: http.StatusOK,
+		},
+		"unknown mounted path": {
+			method:     http.MethodGet,
+			path:       "/admin/groups",
+			wantStatus: http.StatusNotFound,
+		},
+		"unprefixed path": {
+			method:     http.MethodGet,
+			path:       "/users/1",
+			wantStatus: http.StatusNotFound,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+
+	routes := r.GetRoutes()
+	require.Len(t, routes, 3)
+	assert.Equal(t, "GET /admin/users/{id}", routes[1].Key())
+	assert.Equal(t, []string{"Admin"}, routes[1].Tags)
+	assert.Equal(t, "DELETE /admin/users/{id}", routes[2].Key())
+	assert.Equal(t, "X-Request-Id", routes[2].Parameters[0].Name)
+
+	paths := NewOpenAPIGenerator("Test", "", "1.0.0", routes).Generate()["paths"].(map[string]any)
+	assert.Contains(t, paths, "/admin/users/{id}")
+
+	admin.Get("/forgotten", echo)
+	assert.Equal(t, []VerificationIssue{{
+		Route:   "GET /admin/forgotten",
+		Message: "route is configured but Register was never called",
+	}}, r.Verify())
+}
+
+func TestMountInvalidPrefix(t *testing.T) {
+	t.Parallel()
+
+	for name, prefix := range map[string]string{
+		"root":     "/",
+		"wildcard": "/tenants/{id}",
+		"space":    "/ad min",
+	} {
+		prefix := prefix
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Panics(t, func() { NewDocRouter().Mount(prefix, NewDocRouter()) })
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package router
+
+import "strings"
+
+// namespaceComponents returns a copy of the spec whose components are named
+// "<namespace>_<name>", with every reference to them rewritten. Security
+// schemes keep their names, which requirements reference without $ref.
+func namespaceComponents(spec map[string]any, namespace string) map[string]any {
+	namespaced := namespaceRefs(spec, namespace).(map[string]any)
+
+	components, _ := namespaced["components"].(map[string]any)
+	for kind, section := range components {
+		byName, ok := section.(map[string]any)
+		if !ok || kind == "securitySchemes" {
+			continue
+		}
+
+		renamed := make(map[string]any, len(byName))
+		for name, component := range byName {
+			renamed[namespace+"_"+name] = component
+		}
+		components[kind] = renamed
+	}
+
+	return namespaced
+}
+
+// namespaceRefs deep copies a value, prefixing the component name of every
+// "#/components/<kind>/<name>" reference with the namespace
+func namespaceRefs(value any, namespace string) any {
+	switch value := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, v := range value {
+			copied[key] = namespaceRefs(v, namespace)
+		}
+
+		ref, _ := value["$ref"].(string)
+		if component, ok := strings.CutPrefix(ref, "#/components/"); ok {
+			if kind, name, ok := strings.Cut(component, "/"); ok {
+				copied["$ref"] = "#/components/" + kind + "/" + namespace + "_" + name
+			}
+		}
+		return copied
+	case map[string]map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, v := range value {
+			copied[key] = namespaceRefs(v, namespace)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, v := range value {
+			copied[i] = namespaceRefs(v, namespace)
+		}
+		return copied
+	default:
+		return value
+	}
+}
@@ -0,0 +1,89 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentNamespace(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}).
+		WithResponse(UserList{}).
+		WithErrorResponse("400", "Bad Request", nil, Example{Ref: "BadRequest"}).
+		Register()
+
+	generator := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes())
+	generator.ComponentNamespace = "UserService"
+	generator.SecuritySchemes = map[string]SecurityScheme{BearerAuth: {Type: "http", Scheme: "bearer"}}
+	generator.RegisterExample("BadRequest", map[string]any{"error": "bad request"})
+	generator.RegisterResponse("NotFound", map[string]any{
+		"description": "Not Found",
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/UserList"},
+			},
+		},
+	})
+	generator.RegisterRouteResponse("/users", "GET", "404", "NotFound")
+
+	spec := generator.Generate()
+
+	components := spec["components"].(map[string]any)
+	assert.ElementsMatch(t, []string{"UserService_UserList", "UserService_UserListUsersItem"}, keys(components["schemas"]))
+	assert.ElementsMatch(t, []string{"UserService_BadRequest"}, keys(components["examples"]))
+	assert.ElementsMatch(t, []string{"UserService_NotFound"}, keys(components["responses"]))
+	assert.ElementsMatch(t, []string{BearerAuth}, keys(components["securitySchemes"]),
+		"security requirements reference schemes by name")
+
+	userList := components["schemas"].(map[string]any)["UserService_UserList"].(map[string]any)
+	assert.Equal(t, "#/components/schemas/UserService_UserListUsersItem",
+		userList["properties"].(map[string]any)["users"].(map[string]any)["items"].(map[string]any)["$ref"])
+	notFound := components["responses"].(map[string]any)["UserService_NotFound"].(map[string]any)
+	assert.Equal(t, "#/components/schemas/UserService_UserList",
+		notFound["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)["$ref"])
+
+	responses := spec["paths"].(map[string]any)["/users"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)
+	want := map[string]any{
+		"200": map[string]any{
+			"description": "successful operation",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/UserService_UserList"},
+				},
+			},
+		},
+		"400": map[string]any{
+			"description": "Bad Request",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"examples": map[string]any{
+						"BadRequest": map[string]any{"$ref": "#/components/examples/UserService_BadRequest"},
+					},
+				},
+			},
+		},
+		"404": map[string]any{"$ref": "#/components/responses/UserService_NotFound"},
+	}
+	if diff := cmp.Diff(want, responses); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+
+	// generating again doesn't prefix twice
+	again := generator.Generate()["components"].(map[string]any)
+	require.Contains(t, again["schemas"], "UserService_UserList")
+}
+
+// keys returns the keys of a component section
+func keys(section any) []string {
+	var names []string
+	for name := range section.(map[string]any) {
+		names = append(names, name)
+	}
+	return names
+}
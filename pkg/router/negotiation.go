@@ -0,0 +1,249 @@
+package router
+
+import (
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WithContentNegotiation enforces the media types the route documents:
+// requests with a body in another type than the documented request body's
+// (WithRequestContentType, application/json by default) get a 415
+// Unsupported Media Type, and requests whose Accept header accepts none of
+// the types of its documented success responses, alternate content
+// included, get a 406 Not Acceptable, both before the handler runs and both
+// documented. Success responses referencing components are not known to the
+// route, so their routes aren't checked for 406.
+func (rc *RouteConfig) WithContentNegotiation() *RouteConfig {
+	rc.contentNegotiation = true
+	return rc
+}
+
+// WithContentNegotiation enforces the documented media types of every route
+// of the group, like RouteConfig.WithContentNegotiation
+func (g *RouteGroup) WithContentNegotiation() *RouteGroup {
+	g.contentNegotiation = true
+	return g
+}
+
+// consumedMediaTypes returns the media types of the route's request body, or
+// none when it doesn't document one
+func (rc *RouteConfig) consumedMediaTypes() []string {
+	if !hasRequestBody(RouteInfo{
+		Method:             rc.method,
+		RequestType:        rc.requestType,
+		RequestRef:         rc.requestRef,
+		RequestOnAnyMethod: rc.requestOnAnyMethod,
+	}) {
+		return nil
+	}
+
+	if rc.requestContentType == "" {
+		return []string{"application/json"}
+	}
+	return []string{rc.requestContentType}
+}
+
+// producedMediaTypes returns the media types of the route's success
+// responses, sorted, or none when they have no content or reference
+// components whose content isn't known
+func (rc *RouteConfig) producedMediaTypes() []string {
+	var types []string
+	add := func(contentType string) {
+		if !slices.Contains(types, contentType) {
+			types = append(types, contentType)
+		}
+	}
+
+	for statusCode := range rc.responseRefs {
+		if strings.HasPrefix(statusCode, "2") {
+			return nil
+		}
+	}
+
+	documented := map[string]bool{}
+	for statusCode, response := range rc.responses {
+		if !strings.HasPrefix(statusCode, "2") {
+			continue
+		}
+		documented[statusCode] = true
+
+		switch {
+		case response.ContentType != "":
+			add(response.ContentType)
+		case response.Schema != nil || len(response.Examples) > 0:
+			add("application/json")
+		}
+	}
+
+	route := RouteInfo{ResponseType: rc.responseType, ResponseStatus: rc.responseStatus, Responses: rc.responses}
+	if status := route.successStatus(); !documented[status] && !documentsSuccess(route) {
+		documented[status] = true
+		if rc.responseType != nil && status != "204" {
+			add("application/json")
+		}
+	}
+
+	for statusCode, byType := range rc.alternateContent {
+		if !documented[statusCode] {
+			continue
+		}
+		for contentType := range byType {
+			add(contentType)
+		}
+	}
+
+	slices.Sort(types)
+	return types
+}
+
+// contentNegotiationMiddleware rejects requests whose body isn't of a
+// consumed media type with a 415, and those accepting none of the produced
+// media types with a 406
+func contentNegotiationMiddleware(consumes, produces []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// requests of unknown length, such as chunked ones, have a body
+		if len(consumes) > 0 && r.ContentLength != 0 && !consumable(r.Header.Get("Content-Type"), consumes) {
+			writeValidationError(w, r, http.StatusUnsupportedMediaType, "unsupported media type", []invalidValue{{
+				field: "Content-Type",
+				in:    "header",
+				msg:   newMessage("must be one of: %s", strings.Join(consumes, ", ")),
+			}})
+			return
+		}
+
+		if len(produces) > 0 && !acceptable(r.Header.Values("Accept"), produces) {
+			writeValidationError(w, r, http.StatusNotAcceptable, "not acceptable", []invalidValue{{
+				field: "Accept",
+				in:    "header",
+				msg:   newMessage("must accept one of: %s", strings.Join(produces, ", ")),
+			}})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// consumable reports whether a Content-Type header is one of the media
+// types, which may be ranges such as image/*
+func consumable(contentType string, consumes []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(consumes, func(consumed string) bool {
+		return mediaRangeMatches(baseMediaType(consumed), mediaType)
+	})
+}
+
+// acceptable reports whether Accept headers accept any of the media types;
+// requests without one accept anything
+func acceptable(accept []string, produces []string) bool {
+	type mediaRange struct {
+		value   string
+		quality float64
+	}
+	var ranges []mediaRange
+	for _, header := range accept {
+		for _, part := range strings.Split(header, ",") {
+			value, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value == "" {
+				continue
+			}
+
+			quality := 1.0
+			for _, param := range strings.Split(params, ";") {
+				if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+					quality, _ = strconv.ParseFloat(q, 64)
+				}
+			}
+			ranges = append(ranges, mediaRange{value: value, quality: quality})
+		}
+	}
+	if len(ranges) == 0 {
+		return true
+	}
+
+	for _, produced := range produces {
+		produced = baseMediaType(produced)
+
+		// the most specific matching range decides, so "*/*, text/csv;q=0"
+		// rejects text/csv
+		best, quality := -1, 0.0
+		for _, r := range ranges {
+			if !mediaRangeMatches(r.value, produced) && !mediaRangeMatches(produced, r.value) {
+				continue
+			}
+			if specificity := mediaRangeSpecificity(r.value); specificity > best {
+				best, quality = specificity, r.quality
+			}
+		}
+		if quality > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// baseMediaType returns a media type without its parameters, lowercased
+func baseMediaType(mediaType string) string {
+	base, _, _ := strings.Cut(mediaType, ";")
+	return strings.ToLower(strings.TrimSpace(base))
+}
+
+// mediaRangeMatches reports whether a media range, such as */*, text/* or
+// text/csv, includes the media type
+func mediaRangeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(mediaRange, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+// mediaRangeSpecificity ranks */* below text/* below text/csv
+func mediaRangeSpecificity(mediaRange string) int {
+	switch {
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		return 1
+	}
+	return 2
+}
+
+// addNegotiationResponses documents the 415 and 406 of routes negotiating
+// their content, unless the route documents its own
+func (g *OpenAPIGenerator) addNegotiationResponses(responses map[string]any, route RouteInfo) {
+	for statusCode, description := range map[string]string{
+		"415": describeMediaTypes("request body not in a supported media type", route.Consumes),
+		"406": describeMediaTypes("none of the response media types accepted", route.Produces),
+	} {
+		if _, exists := responses[statusCode]; exists || description == "" {
+			continue
+		}
+
+		responses[statusCode] = map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": g.schemaRef(ValidationErrorResponse{}),
+				},
+			},
+		}
+	}
+}
+
+// describeMediaTypes appends the media types to a description, or returns
+// an empty one without media types
+func describeMediaTypes(description string, mediaTypes []string) string {
+	if len(mediaTypes) == 0 {
+		return ""
+	}
+	return description + ": " + strings.Join(mediaTypes, ", ")
+}
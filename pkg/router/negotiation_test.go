@@ -0,0 +1,113 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	t.Parallel()
+
+	type Todo struct {
+		Title string `json:"title"`
+	}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	todos := r.Group("/todos").WithContentNegotiation()
+	todos.Post("", noop).WithRequest(Todo{}).WithResponse(Todo{}).Register()
+	todos.Get("/export", noop).
+		WithContentResponse("200", "exported todos", "text/csv", nil).
+		WithAlternateContent("200", "application/json", []Todo{}).
+		Register()
+	todos.Put("/{id}/attachment", noop).WithRequest(Todo{}).WithRequestContentType("image/*").Register()
+	r.Post("/loose", noop).WithRequest(Todo{}).WithResponse(Todo{}).Register()
+
+	for name, tc := range map[string]struct {
+		method      string
+		path        string
+		contentType string
+		accept      string
+		wantStatus  int
+		wantErrors  []ValidationError
+	}{
+		"json":              {method: http.MethodPost, path: "/todos", contentType: "application/json; charset=utf-8", wantStatus: http.StatusOK},
+		"accepting json":    {method: http.MethodPost, path: "/todos", contentType: "application/json", accept: "application/*;q=0.5", wantStatus: http.StatusOK},
+		"media range":       {method: http.MethodPut, path: "/todos/1/attachment", contentType: "image/png", wantStatus: http.StatusOK},
+		"alternate content": {method: http.MethodGet, path: "/todos/export", accept: "application/json", wantStatus: http.StatusOK},
+		"any":               {method: http.MethodGet, path: "/todos/export", accept: "*/*", wantStatus: http.StatusOK},
+		"not negotiating":   {method: http.MethodPost, path: "/loose", contentType: "text/plain", accept: "text/html", wantStatus: http.StatusOK},
+		"unsupported": {
+			method:      http.MethodPost,
+			path:        "/todos",
+			contentType: "application/xml",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantErrors:  []ValidationError{{Field: "Content-Type", In: "header", Code: CodeEnum, Message: "must be one of: application/json"}},
+		},
+		"without content type": {
+			method:     http.MethodPost,
+			path:       "/todos",
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantErrors: []ValidationError{{Field: "Content-Type", In: "header", Code: CodeEnum, Message: "must be one of: application/json"}},
+		},
+		"not acceptable": {
+			method:      http.MethodPost,
+			path:        "/todos",
+			contentType: "application/json",
+			accept:      "text/html, application/xml",
+			wantStatus:  http.StatusNotAcceptable,
+			wantErrors:  []ValidationError{{Field: "Accept", In: "header", Code: CodeEnum, Message: "must accept one of: application/json"}},
+		},
+		"excluded": {
+			method:     http.MethodGet,
+			path:       "/todos/export",
+			accept:     "*/*, text/csv;q=0, application/json;q=0",
+			wantStatus: http.StatusNotAcceptable,
+			wantErrors: []ValidationError{{Field: "Accept", In: "header", Code: CodeEnum, Message: "must accept one of: application/json, text/csv"}},
+		},
+	} {
+		body := ""
+		if tc.method != http.MethodGet {
+			body = `{"title":"write docs"}`
+		}
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(body))
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		if tc.accept != "" {
+			req.Header.Set("Accept", tc.accept)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		require.Equal(t, tc.wantStatus, rec.Code, name)
+		if tc.wantErrors != nil {
+			var got ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tc.wantErrors, got.Errors, name)
+		}
+	}
+
+	paths := r.OpenAPI().Generate()["paths"].(map[string]any)
+	responses := paths["/todos"].(map[string]any)["post"].(map[string]any)["responses"].(map[string]any)
+	assert.Equal(t, "request body not in a supported media type: application/json", responses["415"].(map[string]any)["description"])
+	assert.Equal(t, "none of the response media types accepted: application/json", responses["406"].(map[string]any)["description"])
+
+	responses = paths["/todos/export"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)
+	assert.NotContains(t, responses, "415", "routes without a request body accept any")
+	assert.Equal(t, "none of the response media types accepted: application/json, text/csv", responses["406"].(map[string]any)["description"])
+
+	responses = paths["/todos/{id}/attachment"].(map[string]any)["put"].(map[string]any)["responses"].(map[string]any)
+	assert.Contains(t, responses, "415")
+	assert.NotContains(t, responses, "406", "responses without content are acceptable to any client")
+
+	responses = paths["/loose"].(map[string]any)["post"].(map[string]any)["responses"].(map[string]any)
+	assert.NotContains(t, responses, "415")
+	assert.NotContains(t, responses, "406")
+}
@@ -2,18 +2,22 @@ package router
 
 import (
 	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
 	"strings"
 )
 
 // schemaRegistry tracks schema definitions to enable reuse
 type schemaRegistry struct {
-	schemas map[string]map[string]any
+	schemas map[string]any
 }
 
 // newSchemaRegistry creates a new schema registry
 func newSchemaRegistry() *schemaRegistry {
 	return &schemaRegistry{
-		schemas: make(map[string]map[string]any),
+		schemas: make(map[string]any),
 	}
 }
 
@@ -22,24 +26,110 @@ func (r *schemaRegistry) register(typeName string, schema map[string]any) {
 	r.schemas[typeName] = schema
 }
 
-// getSchemas returns all registered schemas
+// getSchemas returns all registered schemas, in a map of their own so the
+// spec's components can change without changing the registry
 func (r *schemaRegistry) getSchemas() map[string]any {
-	result := make(map[string]any)
-	for name, schema := range r.schemas {
-		result[name] = schema
-	}
-	return result
+	return maps.Clone(r.schemas)
 }
 
 // OpenAPIGenerator generates OpenAPI specs from route info
 type OpenAPIGenerator struct {
-	Title           string
-	Description     string
-	Version         string
-	Routes          []RouteInfo
-	schemaRegistry  *schemaRegistry
-	customResponses map[string]map[string]any
-	routeResponses  map[string]map[string]string // Maps routeID -> statusCode -> responseName
+	Title         string
+	Description   string
+	Version       string
+	Routes        []RouteInfo
+	SchemaOptions SchemaOptions // How Go types map to schemas; set before calling Generate
+
+	// ComponentNamespace prefixes every component name, e.g. "TodoService"
+	// for TodoService_Todo, so specs of several services merge without
+	// collisions; set before calling Generate
+	ComponentNamespace string
+
+	// Charset is appended to JSON and text media types, e.g. "utf-8" for
+	// "application/json; charset=utf-8", for consumers requiring it to be
+	// explicit; set before calling Generate
+	Charset string
+
+	// CORS documents the preflight operations and Access-Control-* response
+	// headers of the policy; set by DocRouter.OpenAPI for policies with
+	// Document set
+	CORS *CORSPolicy
+
+	// Compression advertises the content encodings of every operation; set
+	// by DocRouter.OpenAPI for policies with Document set
+	Compression *CompressionPolicy
+
+	// SecuritySchemes documents how clients authenticate, by the names
+	// routes reference in their security requirements; set by
+	// DocRouter.OpenAPI
+	SecuritySchemes map[string]SecurityScheme
+
+	// ExternalDocs links the spec to the API's documentation, and Tags
+	// document the tags routes are grouped under; set by DocRouter.OpenAPI
+	ExternalDocs *ExternalDocs
+	Tags         []TagInfo
+
+	// Servers documents where the API is served from; set by
+	// DocRouter.OpenAPI
+	Servers []Server
+
+	// RecordedExamples are payloads promoted from real traffic by an
+	// ExampleRecorder, added as examples of the JSON request bodies and
+	// responses their routes document; set before calling Generate
+	RecordedExamples []RecordedExample
+
+	// OperationIDFunc names the operations of routes without an operation
+	// ID of their own, falling back to the ID derived from the method and
+	// path when it returns an empty string; set by DocRouter.OpenAPI
+	OperationIDFunc func(RouteInfo) string
+
+	// Fragments are hand-written parts of the document deep-merged into the
+	// spec after it's generated, in order; set by DocRouter.OpenAPI
+	Fragments []map[string]any
+
+	// Transforms rewrite the spec after it's generated and merged with
+	// Fragments, in order; set by DocRouter.OpenAPI
+	Transforms []SpecTransform
+
+	// BuildInfo overrides Version with the released version and documents
+	// the build in info.x-build; set before calling Generate
+	BuildInfo BuildInfo
+
+	// Contact, License and TermsOfService complete the spec's info for
+	// publication, e.g. to API catalogs; set by DocRouter.OpenAPI
+	Contact        *Contact
+	License        *License
+	TermsOfService string
+
+	// DeclarationOrder keeps required, enum and tags arrays in the order
+	// fields, values and tags were declared in instead of sorting them,
+	// which keeps spec diffs small when declarations are reordered; set
+	// before calling Generate
+	DeclarationOrder bool
+
+	// Parallelism is the number of goroutines reflecting the distinct types
+	// of the routes before generating the spec, which is the same as with
+	// the default sequential generation; set before calling Generate
+	Parallelism int
+
+	schemaRegistry      *schemaRegistry
+	inlineSchemas       map[reflect.Type]map[string]any  // Inline schemas of unnamed types, e.g. slices, by type
+	reflected           map[reflect.Type]reflectedSchema // Schemas reflected in parallel, until generateSchema takes them
+	customResponses     map[string]map[string]any
+	customExamples      map[string]map[string]any
+	customHeaders       map[string]any
+	customRequestBodies map[string]any                     // Example values of the registered request bodies' types, by name
+	routeResponses      map[operationKey]map[string]string // Maps route -> statusCode -> responseName
+
+	codeSampleServer string
+	codeSampleLangs  []string
+
+	exampleIssues []ExampleIssue
+
+	operationIDs          map[operationKey]string
+	operationIDCollisions []OperationIDCollision
+
+	fragmentConflicts []SpecConflict
 }
 
 // NewOpenAPIGenerator creates a new OpenAPI generator
@@ -50,8 +140,10 @@ func NewOpenAPIGenerator(title, description, version string, routes []RouteInfo)
 		Version:         version,
 		Routes:          routes,
 		schemaRegistry:  newSchemaRegistry(),
+		inlineSchemas:   make(map[reflect.Type]map[string]any),
 		customResponses: make(map[string]map[string]any),
-		routeResponses:  make(map[string]map[string]string),
+		customExamples:  make(map[string]map[string]any),
+		routeResponses:  make(map[operationKey]map[string]string),
 	}
 }
 
@@ -60,35 +152,167 @@ func (g *OpenAPIGenerator) RegisterResponse(name string, response map[string]any
 	g.customResponses[name] = response
 }
 
-// RegisterRouteResponse associates a named response with a specific route and status code
+// RegisterExample adds a named example to the components section so that
+// routes can reference it through Example.Ref instead of repeating it
+func (g *OpenAPIGenerator) RegisterExample(name string, value any) {
+	g.customExamples[name] = map[string]any{
+		"value": value,
+	}
+}
+
+// RegisterRouteResponse associates a named response with a specific route and
+// status code. The path is matched in the normalized form routes are
+// registered in, so "/users/" and "/users" are the same route; prefer
+// RouteConfig.WithResponseRef, which can't miss its route.
 func (g *OpenAPIGenerator) RegisterRouteResponse(routePath, method, statusCode, responseName string) {
-	routeID := fmt.Sprintf("%s:%s", strings.ToLower(method), routePath)
+	key := operationKey{path: routeKeyPath(routePath), method: strings.ToLower(method)}
 
 	// Initialize the map for this route if it doesn't exist
-	if _, exists := g.routeResponses[routeID]; !exists {
-		g.routeResponses[routeID] = make(map[string]string)
+	if _, exists := g.routeResponses[key]; !exists {
+		g.routeResponses[key] = make(map[string]string)
 	}
 
 	// Associate the response name with the status code for this route
-	g.routeResponses[routeID][statusCode] = responseName
+	g.routeResponses[key][statusCode] = responseName
+}
+
+// RegisterRouteRefResponse associates a named response with a registered
+// route and status code, like RegisterRouteResponse
+func (g *OpenAPIGenerator) RegisterRouteRefResponse(route *RouteRef, statusCode, responseName string) {
+	g.RegisterRouteResponse(route.Path(), route.Method(), statusCode, responseName)
 }
 
 // Generate creates and returns an OpenAPI specification
 func (g *OpenAPIGenerator) Generate() map[string]any {
+	info := map[string]any{
+		"title":       g.Title,
+		"description": g.Description,
+		"version":     g.Version,
+	}
+	if g.BuildInfo.Version != "" {
+		info["version"] = g.BuildInfo.Version
+	}
+	if build := g.BuildInfo.extension(); build != nil {
+		info["x-build"] = build
+	}
+	if g.Contact != nil {
+		info["contact"] = g.Contact.object()
+	}
+	if g.License != nil {
+		info["license"] = g.License.object()
+	}
+	if g.TermsOfService != "" {
+		info["termsOfService"] = g.TermsOfService
+	}
+
+	if g.Parallelism > 1 {
+		g.reflectRootTypes()
+	}
+
+	paths := g.generatePaths()
+	if len(g.RecordedExamples) > 0 {
+		g.addRecordedExamples(paths)
+	}
+
 	spec := map[string]any{
-		"openapi": "3.0.0",
-		"info": map[string]any{
-			"title":       g.Title,
-			"description": g.Description,
-			"version":     g.Version,
-		},
-		"paths":      g.generatePaths(),
+		"openapi":    "3.0.0",
+		"info":       info,
+		"paths":      paths,
 		"components": g.generateComponents(),
 	}
+	g.reflected = nil
+	if g.ExternalDocs != nil {
+		spec["externalDocs"] = externalDocsObject(*g.ExternalDocs)
+	}
+	if len(g.Tags) > 0 {
+		spec["tags"] = tagObjects(g.Tags)
+	}
+	if len(g.Servers) > 0 {
+		spec["servers"] = serverObjects(g.Servers)
+	}
+
+	if g.Charset != "" {
+		components, _ := spec["components"].(map[string]any)
+		addCharset(spec["paths"], g.Charset)
+		addCharset(components["responses"], g.Charset)
+		addCharset(components["requestBodies"], g.Charset)
+	}
+
+	if !g.DeclarationOrder {
+		sortArrays(spec)
+	}
+
+	if g.ComponentNamespace != "" {
+		spec = namespaceComponents(spec, g.ComponentNamespace)
+	}
+
+	// schemas are shared with the registry, by the operations documenting
+	// the same inline type and with the specs of previous calls, so
+	// fragments and transforms, which change the spec in place, get a copy
+	if len(g.Fragments) > 0 || len(g.Transforms) > 0 {
+		spec = copySpecValue(spec).(map[string]any)
+	}
+
+	g.mergeFragments(spec)
+
+	for _, transform := range g.Transforms {
+		spec = transform(spec)
+	}
 
 	return spec
 }
 
+// copySpecValue deep copies the maps and slices of a spec value
+func copySpecValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, v := range value {
+			copied[key] = copySpecValue(v)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, v := range value {
+			copied[i] = copySpecValue(v)
+		}
+		return copied
+	}
+
+	// typed maps and slices, e.g. map[string]map[string]any or []string
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			copied.SetMapIndex(iter.Key(), copyTypedValue(iter.Value(), v.Type().Elem()))
+		}
+		return copied.Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(copyTypedValue(v.Index(i), v.Type().Elem()))
+		}
+		return copied.Interface()
+	}
+	return value
+}
+
+// copyTypedValue deep copies an element of a typed map or slice
+func copyTypedValue(v reflect.Value, typ reflect.Type) reflect.Value {
+	copied := copySpecValue(v.Interface())
+	if copied == nil {
+		return reflect.Zero(typ)
+	}
+	return reflect.ValueOf(copied).Convert(typ)
+}
+
 // extractPathParams gets path parameters from a URL path
 func extractPathParams(path string) []string {
 	var params []string
@@ -105,70 +329,327 @@ func extractPathParams(path string) []string {
 	return params
 }
 
-// generatePathParameters creates parameter objects for path parameters
-func generatePathParameters(params []string) []any {
+// generatePathParameters creates parameter objects for the parameters of a
+// path, documented by the matching declared path parameter where there is
+// one. It returns the declared parameters that aren't path parameters, as
+// shared path parameters only apply to the paths using them.
+func generatePathParameters(names []string, declared []Parameter) ([]any, []Parameter) {
 	var parameters []any
+	for _, name := range names {
+		index := slices.IndexFunc(declared, func(param Parameter) bool {
+			return param.In == "path" && param.Name == name
+		})
+		if index >= 0 {
+			parameters = append(parameters, generateParameters(declared[index:index+1])...)
+			continue
+		}
 
-	for _, param := range params {
 		parameters = append(parameters, map[string]any{
-			"name":     param,
+			"name":     name,
 			"in":       "path",
 			"required": true,
 			"schema": map[string]any{
 				"type": "string",
 			},
-			"description": fmt.Sprintf("%s parameter", param),
 		})
 	}
 
+	rest := slices.DeleteFunc(slices.Clone(declared), func(param Parameter) bool {
+		return param.In == "path"
+	})
+
+	return parameters, rest
+}
+
+// hasPathPattern reports whether any of the generated parameters is a path
+// parameter with a pattern, whose values are validated
+func hasPathPattern(parameters []any) bool {
+	return slices.ContainsFunc(parameters, func(value any) bool {
+		parameter, _ := value.(map[string]any)
+		schema, _ := parameter["schema"].(map[string]any)
+		return parameter["in"] == "path" && schema["pattern"] != nil
+	})
+}
+
+// generateParameters creates parameter objects for the route's declared parameters
+func generateParameters(params []Parameter) []any {
+	var parameters []any
+
+	for _, param := range params {
+		parameter := map[string]any{
+			"name":   param.Name,
+			"in":     param.In,
+			"schema": parameterSchema(param),
+		}
+
+		if param.Description != "" {
+			parameter["description"] = param.Description
+		}
+
+		if param.Style != "" {
+			parameter["style"] = param.Style
+		}
+		if param.Explode != nil {
+			parameter["explode"] = *param.Explode
+		}
+
+		// path parameters are always required in OpenAPI
+		if param.Required || param.In == "path" {
+			parameter["required"] = true
+		}
+
+		parameters = append(parameters, parameter)
+	}
+
 	return parameters
 }
 
+// operationKey identifies an operation by path and lowercase method
+type operationKey struct {
+	path   string
+	method string
+}
+
 // generatePaths creates the paths section of the OpenAPI spec
 func (g *OpenAPIGenerator) generatePaths() map[string]any {
 	paths := map[string]any{}
 
+	// group routes sharing a path and method (versioned variants of one operation)
+	var keys []operationKey
+	groups := map[operationKey][]RouteInfo{}
+
 	for _, route := range g.Routes {
 		// skip if the path contains regex patterns (not easily mappable to OpenAPI)
 		if strings.Contains(route.Path, "^") || strings.Contains(route.Path, "(") {
 			continue
 		}
 
-		// convert Chi path params from {param} to OpenAPI {param}
-		path := route.Path
+		key := operationKey{path: route.Path, method: strings.ToLower(route.Method)}
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], route)
+	}
+
+	g.assignOperationIDs(keys, groups)
 
+	for _, key := range keys {
 		// add the path if it doesn't exist
-		if _, exists := paths[path]; !exists {
-			paths[path] = map[string]any{}
+		if _, exists := paths[key.path]; !exists {
+			paths[key.path] = map[string]any{}
 		}
 
-		pathItem := paths[path].(map[string]any)
-		method := strings.ToLower(route.Method)
+		operation := g.generateVersionedOperation(groups[key])
+		if key.method == "head" {
+			dropResponseContent(operation)
+		}
 
-		// Extract path parameters
-		pathParams := extractPathParams(path)
+		pathItem := paths[key.path].(map[string]any)
+		pathItem[key.method] = operation
+	}
 
-		operation := map[string]any{
-			"summary":     route.Name,
-			"description": route.Description,
-			"operationId": fmt.Sprintf("%s_%s", method, strings.ReplaceAll(route.Path, "/", "_")),
-			"responses":   g.generateResponses(route),
+	// preflight operations aren't compressed, so they are added after
+	if g.Compression != nil {
+		addCompression(paths)
+	}
+	if g.CORS != nil {
+		g.addCORS(paths)
+	}
+
+	return paths
+}
+
+// generateOperation creates the operation object for a single route
+func (g *OpenAPIGenerator) generateOperation(route RouteInfo) map[string]any {
+	method := strings.ToLower(route.Method)
+
+	// Extract path parameters
+	pathParams := extractPathParams(route.Path)
+
+	operation := map[string]any{
+		"summary":     route.Name,
+		"description": cacheControlDescription(route.Description, route.CacheControl),
+		"operationId": g.operationID(method, route.Path),
+		"responses":   g.generateResponses(route),
+	}
+
+	if len(route.Tags) > 0 {
+		operation["tags"] = route.Tags
+	}
+
+	if len(route.Security) > 0 {
+		operation["security"] = securityRequirements(route.Security)
+	}
+
+	if route.ExternalDocs != nil {
+		operation["externalDocs"] = externalDocsObject(*route.ExternalDocs)
+	}
+
+	if len(route.Servers) > 0 {
+		operation["servers"] = serverObjects(route.Servers)
+	}
+
+	// tenant-scoped routes reference the shared tenant parameter component
+	if route.TenantScoped {
+		pathParams = slices.DeleteFunc(pathParams, func(param string) bool {
+			return param == TenantParam
+		})
+	}
+
+	// Add path and declared parameters if any exist
+	parameters, declared := generatePathParameters(pathParams, route.Parameters)
+	parameters = append(parameters, generateParameters(declared)...)
+	if route.TenantScoped {
+		parameters = append([]any{map[string]any{
+			"$ref": "#/components/parameters/" + tenantComponent,
+		}}, parameters...)
+		g.addValidationResponse(operation["responses"].(map[string]any))
+	}
+	if hasPathPattern(parameters) {
+		g.addValidationResponse(operation["responses"].(map[string]any))
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if hasRequestBody(route) {
+		operation["requestBody"] = g.generateRequestBody(route)
+	}
+
+	if route.MinClientVersion != "" {
+		g.addClientVersion(operation, route)
+	}
+
+	if route.ConcurrencyLimit > 0 {
+		g.addBusyResponse(operation["responses"].(map[string]any))
+	}
+
+	if route.Transport != nil {
+		operation["x-transport"] = transportExtension(*route.Transport)
+	}
+
+	if route.TimeoutHint > 0 {
+		operation["x-timeout"] = timeoutExtension(route.TimeoutHint)
+	}
+	if route.RetryPolicy != nil {
+		operation["x-retry"] = retryExtension(*route.RetryPolicy)
+	}
+
+	if len(g.codeSampleLangs) > 0 {
+		operation["x-codeSamples"] = g.generateCodeSamples(route)
+	}
+
+	return operation
+}
+
+// hasRequestBody reports whether the route's request type or request body
+// component should be documented; TRACE requests never carry one, nor do
+// requests whose fields are all bound to parameters
+func hasRequestBody(route RouteInfo) bool {
+	if strings.EqualFold(route.Method, http.MethodTrace) {
+		return false
+	}
+	if route.RequestRef == "" && (route.RequestType == nil || !hasBodyFields(route.RequestType)) {
+		return false
+	}
+	return route.RequestOnAnyMethod || methodTakesBody(route.Method)
+}
+
+// methodTakesBody reports whether requests of the method carry a body by
+// default: all but GET, HEAD, DELETE and TRACE, whose request content has no
+// defined semantics
+func methodTakesBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodTrace:
+		return false
+	}
+	return true
+}
+
+// dropResponseContent removes the content of an operation's responses, and
+// of its versions', for HEAD operations whose responses carry no body
+func dropResponseContent(operation map[string]any) {
+	drop := func(responses any) {
+		byStatus, _ := responses.(map[string]any)
+		for _, response := range byStatus {
+			if response, ok := response.(map[string]any); ok {
+				delete(response, "content")
+			}
 		}
+	}
 
-		// Add path parameters if any exist
-		if len(pathParams) > 0 {
-			operation["parameters"] = generatePathParameters(pathParams)
+	drop(operation["responses"])
+	versions, _ := operation["x-versions"].(map[string]any)
+	for _, version := range versions {
+		if version, ok := version.(map[string]any); ok {
+			drop(version["responses"])
 		}
+	}
+}
 
-		// add request body for POST, PUT, PATCH
-		if route.RequestType != nil && (method == "post" || method == "put" || method == "patch") {
-			operation["requestBody"] = g.generateRequestBody(route)
+// generateVersionedOperation merges the routes registered for one path and
+// method under different versions into a single operation. The unversioned
+// route (or else the latest version) provides the base operation, the version
+// header is documented as a parameter and each version's behavior is listed
+// under the x-versions extension.
+func (g *OpenAPIGenerator) generateVersionedOperation(routes []RouteInfo) map[string]any {
+	primary := routes[len(routes)-1]
+	hasDefault := false
+
+	var versions []string
+	for _, route := range routes {
+		if route.Version == "" {
+			primary = route
+			hasDefault = true
+			continue
 		}
+		versions = append(versions, route.Version)
+	}
 
-		pathItem[method] = operation
+	operation := g.generateOperation(primary)
+	if len(versions) == 0 {
+		return operation
 	}
 
-	return paths
+	perVersion := map[string]any{}
+	for _, route := range routes {
+		if route.Version == "" {
+			continue
+		}
+
+		version := map[string]any{
+			"summary":     route.Name,
+			"description": cacheControlDescription(route.Description, route.CacheControl),
+			"responses":   g.generateResponses(route),
+		}
+		if hasRequestBody(route) {
+			version["requestBody"] = g.generateRequestBody(route)
+		}
+
+		perVersion[route.Version] = version
+	}
+	operation["x-versions"] = perVersion
+
+	// unknown versions are rejected by the dispatcher
+	g.addValidationResponse(operation["responses"].(map[string]any))
+
+	description := fmt.Sprintf("API version to use; omitting it selects version %s", versions[len(versions)-1])
+	if hasDefault {
+		description = "API version to use; omitting it selects the unversioned behavior"
+	}
+
+	parameters, _ := operation["parameters"].([]any)
+	operation["parameters"] = append(parameters, map[string]any{
+		"name":        primary.VersionHeader,
+		"in":          "header",
+		"description": description,
+		"schema": map[string]any{
+			"type": "string",
+			"enum": versions,
+		},
+	})
+
+	return operation
 }
 
 // generateResponses creates response documentation
@@ -180,23 +661,22 @@ func (g *OpenAPIGenerator) generateResponses(route RouteInfo) map[string]any {
 		for statusCode, routeResponse := range route.Responses {
 			responseContent := map[string]any{}
 
+			contentType := routeResponse.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+
 			// Add schema if available
 			if routeResponse.Schema != nil {
 				schema := g.schemaRef(routeResponse.Schema)
 				responseContent["schema"] = schema
+			} else if contentType != "application/json" {
+				responseContent["schema"] = binarySchema()
 			}
 
 			// Add examples if available
 			if len(routeResponse.Examples) > 0 {
-				examples := map[string]any{}
-				for _, example := range routeResponse.Examples {
-					examples[example.ContentType] = map[string]any{
-						"value": example.Value,
-					}
-				}
-				if len(examples) > 0 {
-					responseContent["examples"] = examples
-				}
+				responseContent["examples"] = generateExamples(routeResponse.Examples)
 			}
 
 			// Create response object
@@ -207,7 +687,7 @@ func (g *OpenAPIGenerator) generateResponses(route RouteInfo) map[string]any {
 			// Add content if we have schema or examples
 			if len(responseContent) > 0 {
 				response["content"] = map[string]any{
-					"application/json": responseContent,
+					contentType: responseContent,
 				}
 			}
 
@@ -215,12 +695,28 @@ func (g *OpenAPIGenerator) generateResponses(route RouteInfo) map[string]any {
 		}
 	}
 
+	// Reference the named responses the route documents itself
+	for statusCode, responseName := range route.ResponseRefs {
+		responses[statusCode] = map[string]any{
+			"$ref": "#/components/responses/" + responseName,
+		}
+	}
+
+	if route.QueryValidation || route.Paginated || len(route.RequiredHeaders) > 0 {
+		g.addValidationResponse(responses)
+	}
+	if route.BodyValidation {
+		g.addBodyValidationResponses(responses)
+	}
+	g.addNegotiationResponses(responses, route)
+
 	// Add success response if it wasn't overridden by a custom response
-	if _, exists := responses["200"]; !exists {
-		if route.ResponseType != nil {
+	successStatus := route.successStatus()
+	if _, exists := responses[successStatus]; !exists && !documentsSuccess(route) {
+		if route.ResponseType != nil && successStatus != "204" {
 			schema := g.schemaRef(route.ResponseType)
 
-			responses["200"] = map[string]any{
+			responses[successStatus] = map[string]any{
 				"description": "successful operation",
 				"content": map[string]any{
 					"application/json": map[string]any{
@@ -230,15 +726,15 @@ func (g *OpenAPIGenerator) generateResponses(route RouteInfo) map[string]any {
 			}
 		} else {
 			// generic success response if no type provided
-			responses["200"] = map[string]any{
+			responses[successStatus] = map[string]any{
 				"description": "successful operation",
 			}
 		}
 	}
 
 	// Add custom responses for this route if any exist in the global registry
-	routeID := fmt.Sprintf("%s:%s", strings.ToLower(route.Method), route.Path)
-	if routeResps, exists := g.routeResponses[routeID]; exists {
+	key := operationKey{path: route.Path, method: strings.ToLower(route.Method)}
+	if routeResps, exists := g.routeResponses[key]; exists {
 		for statusCode, responseName := range routeResps {
 			// Skip if this status code is already defined in the route's responses
 			if _, exists := responses[statusCode]; exists {
@@ -252,34 +748,227 @@ func (g *OpenAPIGenerator) generateResponses(route RouteInfo) map[string]any {
 		}
 	}
 
+	g.addAlternateContent(responses, route.AlternateContent)
+	g.addLinks(responses, route.Links)
+	addContentLanguage(responses, route.ContentLanguages)
+	addResponseHeaders(responses, route.ResponseHeaders)
+	addCacheControl(responses, route.CacheControl)
+	addErrorCodes(responses, route.ErrorCodes)
+
 	return responses
 }
 
+// documentsSuccess reports whether a route without a response type documents
+// its 2xx responses itself, e.g. through WithResponseFor
+func documentsSuccess(route RouteInfo) bool {
+	if route.ResponseType != nil {
+		return false
+	}
+
+	for statusCode := range route.Responses {
+		if strings.HasPrefix(statusCode, "2") {
+			return true
+		}
+	}
+	for statusCode := range route.ResponseRefs {
+		if strings.HasPrefix(statusCode, "2") {
+			return true
+		}
+	}
+	return false
+}
+
+// addAlternateContent adds the route's alternative media types to its
+// documented responses; referenced responses are left untouched
+func (g *OpenAPIGenerator) addAlternateContent(responses map[string]any, alternates map[string]map[string]any) {
+	for statusCode, byType := range alternates {
+		response, ok := responses[statusCode].(map[string]any)
+		if !ok || response["$ref"] != nil {
+			continue
+		}
+
+		content, ok := response["content"].(map[string]any)
+		if !ok {
+			content = map[string]any{}
+			response["content"] = content
+		}
+
+		for contentType, schema := range byType {
+			content[contentType] = map[string]any{
+				"schema": g.schemaRef(schema),
+			}
+		}
+	}
+}
+
+// addValidationResponse documents validation failures unless the responses
+// already describe a 400
+func (g *OpenAPIGenerator) addValidationResponse(responses map[string]any) {
+	if _, exists := responses["400"]; exists {
+		return
+	}
+
+	responses["400"] = map[string]any{
+		"description": "invalid request parameters",
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": g.schemaRef(ValidationErrorResponse{}),
+			},
+		},
+	}
+}
+
+// addBodyValidationResponses documents the 400 and 422 returned by routes
+// validating their request body, unless the route documents its own
+func (g *OpenAPIGenerator) addBodyValidationResponses(responses map[string]any) {
+	for statusCode, description := range map[string]string{
+		"400": "malformed request body",
+		"422": "request body not matching its schema",
+	} {
+		if _, exists := responses[statusCode]; exists {
+			continue
+		}
+
+		responses[statusCode] = map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": g.schemaRef(ValidationErrorResponse{}),
+				},
+			},
+		}
+	}
+}
+
+// addBusyResponse documents the 503 returned by routes whose concurrency
+// limit is reached, unless the route documents its own
+func (g *OpenAPIGenerator) addBusyResponse(responses map[string]any) {
+	if _, exists := responses["503"]; exists {
+		return
+	}
+
+	responses["503"] = map[string]any{
+		"description": "server busy, retry after the number of seconds in Retry-After",
+		"headers": map[string]any{
+			"Retry-After": map[string]any{
+				"description": "Seconds to wait before retrying",
+				"schema":      map[string]any{"type": "integer"},
+			},
+		},
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": g.schemaRef(ValidationErrorResponse{}),
+			},
+		},
+	}
+}
+
 // generateRequestBody creates request body documentation
 func (g *OpenAPIGenerator) generateRequestBody(route RouteInfo) map[string]any {
+	if route.RequestRef != "" {
+		return map[string]any{
+			"$ref": "#/components/requestBodies/" + route.RequestRef,
+		}
+	}
+
 	schema := g.schemaRef(route.RequestType)
 
+	mediaType := map[string]any{
+		"schema": schema,
+	}
+
+	if len(route.RequestExamples) > 0 {
+		mediaType["examples"] = generateExamples(route.RequestExamples)
+	}
+
+	contentType := route.RequestContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
 	return map[string]any{
 		"description": fmt.Sprintf("request body for %s", route.Name),
 		"required":    true,
 		"content": map[string]any{
-			"application/json": map[string]any{
-				"schema": schema,
-			},
+			contentType: mediaType,
 		},
 	}
 }
 
+// binarySchema documents raw binary content
+func binarySchema() map[string]any {
+	return map[string]any{
+		"type":   "string",
+		"format": "binary",
+	}
+}
+
+// generateExamples creates the examples map of a media type object, using
+// references for examples registered as components
+func generateExamples(examples []Example) map[string]any {
+	result := map[string]any{}
+
+	for _, example := range examples {
+		if example.Ref != "" {
+			result[example.Ref] = map[string]any{
+				"$ref": fmt.Sprintf("#/components/examples/%s", example.Ref),
+			}
+			continue
+		}
+
+		result[example.ContentType] = map[string]any{
+			"value": example.Value,
+		}
+	}
+
+	return result
+}
+
 // generateComponents creates reusable components
 func (g *OpenAPIGenerator) generateComponents() map[string]any {
-	components := map[string]any{
-		"schemas": g.schemaRegistry.getSchemas(),
-	}
+	components := map[string]any{}
 
 	// Add custom responses section only when we have responses defined
 	if len(g.customResponses) > 0 {
 		components["responses"] = g.customResponses
 	}
 
+	// Add examples section only when we have examples defined
+	if len(g.customExamples) > 0 {
+		components["examples"] = g.customExamples
+	}
+
+	// Add request bodies section only when we have request bodies defined
+	if len(g.customRequestBodies) > 0 {
+		components["requestBodies"] = g.generateRequestBodies()
+	}
+
+	// Add headers section only when we have headers defined
+	if len(g.customHeaders) > 0 {
+		components["headers"] = g.customHeaders
+	}
+
+	// Add security schemes section only when we have schemes defined
+	if len(g.SecuritySchemes) > 0 {
+		schemes := make(map[string]any, len(g.SecuritySchemes))
+		for name, scheme := range g.SecuritySchemes {
+			schemes[name] = securitySchemeObject(scheme)
+		}
+		components["securitySchemes"] = schemes
+	}
+
+	// Add the shared tenant parameter when any route is tenant-scoped
+	for _, route := range g.Routes {
+		if route.TenantScoped {
+			components["parameters"] = map[string]any{
+				tenantComponent: tenantParameterComponent(),
+			}
+			break
+		}
+	}
+
+	// schemas last, as request bodies register the schemas of their types
+	components["schemas"] = g.schemaRegistry.getSchemas()
+
 	return components
 }
@@ -0,0 +1,142 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// benchOrder and the types below model a deep payload of a large API
+type benchOrder struct {
+	ID        string               `json:"id" doc:"Order ID" example:"order-1"`
+	Status    string               `json:"status" enum:"pending,paid,shipped,delivered"`
+	Customer  benchCustomer        `json:"customer"`
+	Items     []benchLineItem      `json:"items" minItems:"1"`
+	Shipping  *benchAddress        `json:"shipping,omitempty"`
+	Metadata  map[string]benchAttr `json:"metadata"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type benchCustomer struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name" minLength:"1" maxLength:"200"`
+	Email   string        `json:"email" format:"email"`
+	Address benchAddress  `json:"address"`
+	Tiers   []benchTier   `json:"tiers"`
+	Contact *benchContact `json:"contact"`
+}
+
+type benchAddress struct {
+	Street  string   `json:"street"`
+	City    string   `json:"city"`
+	Country string   `json:"country" pattern:"^[A-Z]{2}$"`
+	Geo     benchGeo `json:"geo"`
+}
+
+type benchGeo struct {
+	Lat float64 `json:"lat" minimum:"-90" maximum:"90"`
+	Lng float64 `json:"lng" minimum:"-180" maximum:"180"`
+}
+
+type benchLineItem struct {
+	Product  benchProduct `json:"product"`
+	Quantity int          `json:"quantity" minimum:"1"`
+	Price    float64      `json:"price"`
+}
+
+type benchProduct struct {
+	SKU      string        `json:"sku"`
+	Name     string        `json:"name"`
+	Category benchCategory `json:"category"`
+	Tags     []string      `json:"tags" enum:"new,sale,clearance"`
+}
+
+type benchCategory struct {
+	Name   string    `json:"name"`
+	Parent *benchTag `json:"parent"`
+}
+
+type benchTag struct {
+	Name string `json:"name"`
+}
+
+type benchTier struct {
+	Level int    `json:"level"`
+	Name  string `json:"name"`
+}
+
+type benchContact struct {
+	Phone string `json:"phone"`
+}
+
+type benchAttr struct {
+	Value string `json:"value"`
+	Kind  string `json:"kind" enum:"string,number"`
+}
+
+// benchRouter registers 500 routes: 100 resources with 5 operations each
+func benchRouter() *DocRouter {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithInfo("Bench API", "", "1.0.0")
+	for i := 0; i < 100; i++ {
+		prefix := fmt.Sprintf("/resources%d", i)
+		tag := fmt.Sprintf("Resource %d", i%10)
+
+		r.Get(prefix, noop).WithName("List").WithTags(tag).
+			WithParameter(Parameter{Name: "limit", In: "query", Schema: 0}).
+			WithQueryValidation().
+			WithResponse([]benchOrder{}).Register()
+		r.Post(prefix, noop).WithName("Create").WithTags(tag).
+			WithRequest(benchOrder{}).WithResponse(benchOrder{}).
+			WithErrorResponse("409", "Conflict", ValidationErrorResponse{}).Register()
+		r.Get(prefix+"/{id}", noop).WithName("Get").WithTags(tag).
+			WithResponse(benchOrder{}).
+			WithErrorResponse("404", "Not Found", ValidationErrorResponse{}).Register()
+		r.Put(prefix+"/{id}", noop).WithName("Update").WithTags(tag).
+			WithRequest(benchOrder{}).WithResponse(benchOrder{}).Register()
+		r.Delete(prefix+"/{id}", noop).WithName("Delete").WithTags(tag).
+			WithResponseStatus(http.StatusNoContent).Register()
+	}
+	return r
+}
+
+// benchDistinctRouter registers 200 routes responding with distinct deep
+// types, built at runtime as a service would declare them one by one
+func benchDistinctRouter() *DocRouter {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithInfo("Bench API", "", "1.0.0")
+	for i := 0; i < 200; i++ {
+		response := reflect.StructOf([]reflect.StructField{
+			{Name: "Order", Type: reflect.TypeOf(benchOrder{}), Tag: `json:"order"`},
+			{Name: fmt.Sprintf("Field%d", i), Type: reflect.TypeOf(""), Tag: reflect.StructTag(fmt.Sprintf(`json:"field%d"`, i))},
+		})
+		r.Get(fmt.Sprintf("/resources%d", i), noop).WithResponse(reflect.New(response).Elem().Interface()).Register()
+	}
+	return r
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for _, bench := range []struct {
+		name   string
+		router *DocRouter
+	}{
+		{"shared types", benchRouter()},
+		{"distinct types", benchDistinctRouter()},
+	} {
+		r := bench.router
+		for _, parallelism := range []int{0, 4} {
+			b.Run(fmt.Sprintf("%s/parallelism=%d", bench.name, parallelism), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					generator := r.OpenAPI()
+					generator.Parallelism = parallelism
+					generator.Generate()
+				}
+			})
+		}
+	}
+}
@@ -2,6 +2,8 @@ package router
 
 import (
 	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -111,6 +113,19 @@ func TestSchemaRef(t *testing.T) {
 		_, exists = schemas["NestedTypeProperties"]
 		assert.True(t, exists, "NestedTypeProperties schema should be registered")
 	})
+
+	// Test inline schemas of unnamed types generated once
+	t.Run("inline type", func(t *testing.T) {
+		t.Parallel()
+
+		generator := NewOpenAPIGenerator("Test API", "Test Description", "1.0.0", routes)
+		first := generator.schemaRef([]UserResponse{})
+		second := generator.schemaRef([]UserResponse{})
+
+		assert.Equal(t, "array", first["type"])
+		assert.Equal(t, first, second)
+		assert.Len(t, generator.inlineSchemas, 1, "[]UserResponse should be generated once")
+	})
 }
 
 func TestCustomResponses(t *testing.T) {
@@ -441,3 +456,526 @@ func TestPathParameters(t *testing.T) {
 		assert.Contains(t, paramNames, "postId", "Parameters should include 'postId'")
 	})
 }
+
+func TestPathParamDescriptions(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithPathParam("id", "Identifier of the user")
+	r.Route("GET", "/users", noop).Register()
+	r.Route("GET", "/users/{id}", noop).Register()
+	r.Route("GET", "/users/{id}/posts/{postId}", noop).
+		WithPathParam("id", "Identifier of the post's author").
+		Register()
+
+	paths := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	parameters := func(path string) any {
+		return paths[path].(map[string]any)["get"].(map[string]any)["parameters"]
+	}
+
+	assert.Nil(t, parameters("/users"), "shared path parameters only apply to paths using them")
+
+	expected := []any{
+		map[string]any{
+			"name":        "id",
+			"in":          "path",
+			"required":    true,
+			"description": "Identifier of the user",
+			"schema":      map[string]any{"type": "string"},
+		},
+	}
+	if diff := cmp.Diff(expected, parameters("/users/{id}")); diff != "" {
+		t.Errorf("parameters mismatch (-want +got):\n%s", diff)
+	}
+
+	expected = []any{
+		map[string]any{
+			"name":        "id",
+			"in":          "path",
+			"required":    true,
+			"description": "Identifier of the post's author",
+			"schema":      map[string]any{"type": "string"},
+		},
+		map[string]any{
+			"name":     "postId",
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		},
+	}
+	if diff := cmp.Diff(expected, parameters("/users/{id}/posts/{postId}")); diff != "" {
+		t.Errorf("parameters mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQueryParameters(t *testing.T) {
+	t.Parallel()
+
+	route := RouteInfo{
+		Method:       "GET",
+		Path:         "/users/{id}",
+		Name:         "Get User",
+		ResponseType: UserResponse{},
+		Parameters: []Parameter{
+			{Name: "fields", In: "query", Description: "Fields to include", Schema: []string{}},
+			{Name: "verbose", In: "query", Required: true, Schema: true},
+		},
+	}
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", []RouteInfo{route})
+	spec := generator.Generate()
+
+	getOp := spec["paths"].(map[string]any)["/users/{id}"].(map[string]any)["get"].(map[string]any)
+	params, ok := getOp["parameters"].([]any)
+	require.True(t, ok, "Parameters should exist")
+
+	expected := []any{
+		map[string]any{
+			"name":     "id",
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		},
+		map[string]any{
+			"name":        "fields",
+			"in":          "query",
+			"description": "Fields to include",
+			"schema": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string"},
+			},
+		},
+		map[string]any{
+			"name":     "verbose",
+			"in":       "query",
+			"required": true,
+			"schema":   map[string]any{"type": "boolean"},
+		},
+	}
+
+	if diff := cmp.Diff(expected, params); diff != "" {
+		t.Errorf("parameters mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVersionedOperations(t *testing.T) {
+	t.Parallel()
+
+	routes := []RouteInfo{
+		{
+			Method:        "GET",
+			Path:          "/users",
+			Name:          "List Users",
+			ResponseType:  UserList{},
+			VersionHeader: DefaultVersionHeader,
+		},
+		{
+			Method:        "GET",
+			Path:          "/users",
+			Name:          "List Users (v2)",
+			Description:   "Returns users with pagination",
+			ResponseType:  UserResponse{},
+			Version:       "2",
+			VersionHeader: DefaultVersionHeader,
+		},
+	}
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", routes)
+	spec := generator.Generate()
+
+	getOp := spec["paths"].(map[string]any)["/users"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "List Users", getOp["summary"], "unversioned route should provide the base operation")
+
+	expectedParams := []any{
+		map[string]any{
+			"name":        "Accept-Version",
+			"in":          "header",
+			"description": "API version to use; omitting it selects the unversioned behavior",
+			"schema": map[string]any{
+				"type": "string",
+				"enum": []string{"2"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedParams, getOp["parameters"]); diff != "" {
+		t.Errorf("parameters mismatch (-want +got):\n%s", diff)
+	}
+
+	versions, ok := getOp["x-versions"].(map[string]any)
+	require.True(t, ok, "x-versions should be present")
+
+	v2, ok := versions["2"].(map[string]any)
+	require.True(t, ok, "version 2 should be documented")
+	assert.Equal(t, "List Users (v2)", v2["summary"])
+	assert.Equal(t, "Returns users with pagination", v2["description"])
+
+	responses := getOp["responses"].(map[string]any)
+	assert.Contains(t, responses, "400", "unsupported versions should be documented")
+}
+
+func TestExampleComponents(t *testing.T) {
+	t.Parallel()
+
+	routes := []RouteInfo{
+		{
+			Method:          "POST",
+			Path:            "/users",
+			Name:            "Create User",
+			RequestType:     UserRequest{},
+			RequestExamples: []Example{{Ref: "NewUser"}},
+			Responses: map[string]RouteResponse{
+				"201": {
+					Description: "User created",
+					Schema:      UserResponse{},
+					Examples:    []Example{{Ref: "User"}},
+				},
+			},
+		},
+		{
+			Method:       "GET",
+			Path:         "/users/{id}",
+			Name:         "Get User",
+			ResponseType: UserResponse{},
+			Responses: map[string]RouteResponse{
+				"200": {
+					Description: "User found",
+					Schema:      UserResponse{},
+					Examples:    []Example{{Ref: "User"}},
+				},
+			},
+		},
+	}
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", routes)
+	generator.RegisterExample("User", map[string]any{"id": "1", "name": "Jane"})
+	generator.RegisterExample("NewUser", map[string]any{"name": "Jane"})
+	spec := generator.Generate()
+
+	components := spec["components"].(map[string]any)
+	expectedExamples := map[string]map[string]any{
+		"User":    {"value": map[string]any{"id": "1", "name": "Jane"}},
+		"NewUser": {"value": map[string]any{"name": "Jane"}},
+	}
+	if diff := cmp.Diff(expectedExamples, components["examples"]); diff != "" {
+		t.Errorf("example components mismatch (-want +got):\n%s", diff)
+	}
+
+	paths := spec["paths"].(map[string]any)
+	postOp := paths["/users"].(map[string]any)["post"].(map[string]any)
+	getOp := paths["/users/{id}"].(map[string]any)["get"].(map[string]any)
+
+	userRef := map[string]any{
+		"User": map[string]any{"$ref": "#/components/examples/User"},
+	}
+	for name, content := range map[string]any{
+		"request body": postOp["requestBody"].(map[string]any)["content"],
+		"201 response": postOp["responses"].(map[string]any)["201"].(map[string]any)["content"],
+		"200 response": getOp["responses"].(map[string]any)["200"].(map[string]any)["content"],
+	} {
+		examples := content.(map[string]any)["application/json"].(map[string]any)["examples"]
+
+		want := userRef
+		if name == "request body" {
+			want = map[string]any{
+				"NewUser": map[string]any{"$ref": "#/components/examples/NewUser"},
+			}
+		}
+
+		if diff := cmp.Diff(want, examples); diff != "" {
+			t.Errorf("%s examples mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+}
+
+func TestContentTypes(t *testing.T) {
+	t.Parallel()
+
+	type upload struct {
+		File string `json:"file" format:"binary"`
+	}
+
+	r := NewDocRouter()
+	r.Route("POST", "/files", func(w http.ResponseWriter, r *http.Request) {}).
+		WithRequest(upload{}).
+		WithRequestContentType("multipart/form-data").
+		Register()
+	r.Route("GET", "/files/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+		WithContentResponse("200", "File contents", "application/octet-stream", nil).
+		Register()
+
+	spec := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes()).Generate()
+	paths := spec["paths"].(map[string]any)
+
+	requestContent := paths["/files"].(map[string]any)["post"].(map[string]any)["requestBody"].(map[string]any)["content"].(map[string]any)
+	assert.Contains(t, requestContent, "multipart/form-data")
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	fileSchema := schemas["upload"].(map[string]any)["properties"].(map[string]any)["file"]
+	if diff := cmp.Diff(map[string]any{"type": "string", "format": "binary"}, fileSchema); diff != "" {
+		t.Errorf("file schema mismatch (-want +got):\n%s", diff)
+	}
+
+	response := paths["/files/{id}"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)["200"]
+	expected := map[string]any{
+		"description": "File contents",
+		"content": map[string]any{
+			"application/octet-stream": map[string]any{
+				"schema": map[string]any{"type": "string", "format": "binary"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, response); diff != "" {
+		t.Errorf("binary response mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAlternateContent(t *testing.T) {
+	t.Parallel()
+
+	type userDocument struct {
+		Data UserResponse `json:"data"`
+	}
+
+	r := NewDocRouter()
+	r.Route("GET", "/users/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+		WithResponse(UserResponse{}).
+		WithErrorResponse("404", "Not Found", nil).
+		WithAlternateContent("200", "application/vnd.api+json", userDocument{}).
+		WithAlternateContent("404", "application/vnd.api+json", userDocument{}).
+		WithAlternateContent("500", "application/vnd.api+json", userDocument{}).
+		Register()
+
+	spec := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes()).Generate()
+	responses := spec["paths"].(map[string]any)["/users/{id}"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)
+
+	expected := map[string]any{
+		"description": "successful operation",
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/UserResponse"},
+			},
+			"application/vnd.api+json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/userDocument"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, responses["200"]); diff != "" {
+		t.Errorf("response mismatch (-want +got):\n%s", diff)
+	}
+
+	// responses without content of their own gain the alternative only
+	assert.Equal(t, map[string]any{
+		"application/vnd.api+json": map[string]any{
+			"schema": map[string]any{"$ref": "#/components/schemas/userDocument"},
+		},
+	}, responses["404"].(map[string]any)["content"])
+
+	assert.NotContains(t, responses, "500")
+}
+
+func TestRequestOnAnyMethod(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		method   string
+		optIn    bool
+		wantBody bool
+	}{
+		"post":               {method: "POST", wantBody: true},
+		"get":                {method: "GET", wantBody: false},
+		"get with opt-in":    {method: "GET", optIn: true, wantBody: true},
+		"delete with opt-in": {method: "DELETE", optIn: true, wantBody: true},
+		"options":            {method: "OPTIONS", wantBody: true},
+		"head":               {method: "HEAD", wantBody: false},
+		"trace with opt-in":  {method: "TRACE", optIn: true, wantBody: false},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			rc := r.Route(tc.method, "/search", func(w http.ResponseWriter, r *http.Request) {}).
+				WithRequest(UserRequest{})
+			if tc.optIn {
+				rc.WithRequestOnAnyMethod()
+			}
+			rc.Register()
+
+			spec := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes()).Generate()
+			operation := spec["paths"].(map[string]any)["/search"].(map[string]any)[strings.ToLower(tc.method)].(map[string]any)
+
+			_, hasBody := operation["requestBody"]
+			assert.Equal(t, tc.wantBody, hasBody)
+		})
+	}
+}
+
+func TestHeadOperations(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "/users/{id}", noop).WithResponse(UserResponse{}).Register()
+	r.Route("HEAD", "/users/{id}", noop).
+		WithResponse(UserResponse{}).
+		WithErrorResponse("404", "Not Found", ValidationErrorResponse{}).
+		Register()
+	r.Route("OPTIONS", "/users/{id}", noop).Register()
+
+	spec := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes()).Generate()
+	pathItem := spec["paths"].(map[string]any)["/users/{id}"].(map[string]any)
+	require.Contains(t, pathItem, "get")
+	require.Contains(t, pathItem, "head")
+	require.Contains(t, pathItem, "options")
+
+	// HEAD responses carry no body
+	assert.Equal(t, map[string]any{
+		"200": map[string]any{"description": "successful operation"},
+		"404": map[string]any{"description": "Not Found"},
+	}, pathItem["head"].(map[string]any)["responses"])
+	assert.Contains(t, pathItem["get"].(map[string]any)["responses"].(map[string]any)["200"], "content")
+}
+
+func TestResponseRefs(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Get("/users/{id:[0-9]+}", noop).
+		WithErrorResponse("404", "Not Found", nil).
+		WithResponseRef("404", "NotFound").
+		Register()
+	r.Group("/admin").WithResponseRef("403", "Forbidden").Post("/users", noop).Register()
+	r.Group("/admin").WithResponseRef("403", "Forbidden").Get("/audit", noop).
+		WithErrorResponse("403", "Auditors only", nil).
+		Register()
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes())
+	generator.RegisterResponse("NotFound", map[string]any{"description": "not found"})
+	generator.RegisterResponse("Forbidden", map[string]any{"description": "forbidden"})
+	generator.RegisterResponse("Error", map[string]any{"description": "error"})
+
+	// registered paths match regardless of trailing slashes, constraints and method case
+	generator.RegisterRouteResponse("users/{id:[0-9]+}/", "get", "500", "Error")
+
+	paths := generator.Generate()["paths"].(map[string]any)
+	responses := func(path, method string) map[string]any {
+		return paths[path].(map[string]any)[method].(map[string]any)["responses"].(map[string]any)
+	}
+
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/NotFound"}, responses("/users/{id}", "get")["404"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/Error"}, responses("/users/{id}", "get")["500"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/Forbidden"}, responses("/admin/users", "post")["403"])
+	assert.Equal(t, map[string]any{"description": "Auditors only"}, responses("/admin/audit", "get")["403"])
+}
+
+func TestResponseStatus(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Post("/users", noop).WithResponse(UserResponse{}).WithResponseStatus(http.StatusCreated).Register()
+	r.Delete("/users/{id}", noop).WithResponse(UserResponse{}).WithResponseStatus(http.StatusNoContent).Register()
+	r.Get("/users/{id}", noop).WithResponse(UserResponse{}).Register()
+
+	paths := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	responses := func(path, method string) map[string]any {
+		return paths[path].(map[string]any)[method].(map[string]any)["responses"].(map[string]any)
+	}
+
+	created := responses("/users", "post")
+	assert.NotContains(t, created, "200")
+	assert.Contains(t, created["201"], "content")
+
+	assert.Equal(t, map[string]any{"description": "successful operation"}, responses("/users/{id}", "delete")["204"],
+		"204 responses have no content")
+	assert.Contains(t, responses("/users/{id}", "get"), "200")
+}
+
+func TestResponseFor(t *testing.T) {
+	t.Parallel()
+
+	type jobResponse struct {
+		JobID string `json:"job_id"`
+	}
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Post("/exports", noop).
+		WithResponseFor(http.StatusOK, "Export completed", UserList{}).
+		WithResponseFor(http.StatusAccepted, "Export deferred", jobResponse{}).
+		Register()
+	r.Post("/imports", noop).
+		WithResponseFor(http.StatusAccepted, "Import deferred", jobResponse{}).
+		Register()
+
+	paths := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	responses := func(path string) map[string]any {
+		return paths[path].(map[string]any)["post"].(map[string]any)["responses"].(map[string]any)
+	}
+
+	exports := responses("/exports")
+	want := map[string]any{
+		"200": map[string]any{
+			"description": "Export completed",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/UserList"},
+				},
+			},
+		},
+		"202": map[string]any{
+			"description": "Export deferred",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/jobResponse"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, exports); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+
+	imports := responses("/imports")
+	assert.NotContains(t, imports, "200", "no default success response")
+	assert.Contains(t, imports, "202")
+}
+
+func TestDefaultResponse(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithDefaultResponse("Unexpected error", ValidationErrorResponse{})
+	r.Get("/users", noop).WithResponse(UserList{}).Register()
+	r.Get("/users/{id}", noop).WithDefaultResponse("Anything else", nil).Register()
+	r.Delete("/users/{id}", noop).WithResponseRef("default", "Problem").Register()
+
+	paths := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	responses := func(path, method string) map[string]any {
+		return paths[path].(map[string]any)[method].(map[string]any)["responses"].(map[string]any)
+	}
+
+	assert.Equal(t, map[string]any{
+		"description": "Unexpected error",
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/ValidationErrorResponse"},
+			},
+		},
+	}, responses("/users", "get")["default"])
+	assert.Contains(t, responses("/users", "get"), "200")
+
+	assert.Equal(t, map[string]any{"description": "Anything else"}, responses("/users/{id}", "get")["default"],
+		"routes documenting their own default keep it")
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/Problem"}, responses("/users/{id}", "delete")["default"])
+
+	// the router's default isn't stored on the routes
+	assert.NotContains(t, r.routes[0].Responses, "default")
+}
@@ -0,0 +1,123 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithOperationID sets the operation ID of the route, which generated clients
+// usually name their methods after, instead of deriving it from the method
+// and path
+func (rc *RouteConfig) WithOperationID(id string) *RouteConfig {
+	rc.operationID = id
+	return rc
+}
+
+// WithOperationIDFunc sets the naming strategy of the operation IDs of routes
+// without one set with RouteConfig.WithOperationID, e.g. camel-cased route
+// names. Routes it returns an empty string for keep the ID derived from their
+// method and path.
+func (dr *DocRouter) WithOperationIDFunc(fn func(RouteInfo) string) *DocRouter {
+	dr.operationIDFunc = fn
+	return dr
+}
+
+// OperationIDCollision reports routes whose method and path derive the same
+// operation ID, e.g. "GET /todos/archived" and "GET /todos_archived", or
+// that were given the same one
+type OperationIDCollision struct {
+	OperationID string   // Operation ID derived from every route
+	Routes      []string // Colliding routes in registration order; all but the first get a suffixed ID
+}
+
+// String formats the collision for logging
+func (c OperationIDCollision) String() string {
+	return fmt.Sprintf("operation ID %q is derived from %s", c.OperationID, strings.Join(c.Routes, ", "))
+}
+
+// OperationIDCollisions returns the operation ID collisions found by the last
+// call to Generate
+func (g *OpenAPIGenerator) OperationIDCollisions() []OperationIDCollision {
+	return g.operationIDCollisions
+}
+
+// baseOperationID derives the operation ID of a route from its method and path
+func baseOperationID(method, path string) string {
+	return fmt.Sprintf("%s_%s", strings.ToLower(method), strings.ReplaceAll(path, "/", "_"))
+}
+
+// routeOperationID returns the operation ID of the routes of an operation:
+// the one set on any of them, else the one named by the naming strategy for
+// the operation's primary route, else the one derived from its method and
+// path
+func (g *OpenAPIGenerator) routeOperationID(key operationKey, routes []RouteInfo) string {
+	primary := routes[len(routes)-1]
+	for _, route := range routes {
+		if route.OperationID != "" {
+			return route.OperationID
+		}
+		if route.Version == "" {
+			primary = route
+		}
+	}
+
+	if g.OperationIDFunc != nil {
+		if id := g.OperationIDFunc(primary); id != "" {
+			return id
+		}
+	}
+	return baseOperationID(key.method, key.path)
+}
+
+// operationID returns the operation ID assigned to the operation with the
+// given method and path, or the derived one if none was assigned
+func (g *OpenAPIGenerator) operationID(method, path string) string {
+	if id, ok := g.operationIDs[operationKey{path: path, method: strings.ToLower(method)}]; ok {
+		return id
+	}
+	return baseOperationID(method, path)
+}
+
+// assignOperationIDs gives every operation a unique ID. The first operation
+// with an ID keeps it, later ones get the lowest numeric suffix not taken
+// by another operation, so IDs only depend on the registration order.
+func (g *OpenAPIGenerator) assignOperationIDs(keys []operationKey, groups map[operationKey][]RouteInfo) {
+	g.operationIDs = make(map[operationKey]string, len(keys))
+	g.operationIDCollisions = nil
+
+	bases := make(map[operationKey]string, len(keys))
+	derived := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		bases[key] = g.routeOperationID(key, groups[key])
+		derived[bases[key]] = true
+	}
+
+	owners := make(map[string]string, len(keys))
+	collisions := map[string]int{}
+
+	for _, key := range keys {
+		base := bases[key]
+		route := strings.ToUpper(key.method) + " " + key.path
+
+		id := base
+		if owner, taken := owners[base]; taken {
+			index, ok := collisions[base]
+			if !ok {
+				index = len(g.operationIDCollisions)
+				collisions[base] = index
+				g.operationIDCollisions = append(g.operationIDCollisions, OperationIDCollision{
+					OperationID: base,
+					Routes:      []string{owner},
+				})
+			}
+			g.operationIDCollisions[index].Routes = append(g.operationIDCollisions[index].Routes, route)
+
+			for n := 2; owners[id] != "" || derived[id]; n++ {
+				id = fmt.Sprintf("%s_%d", base, n)
+			}
+		}
+
+		owners[id] = route
+		g.operationIDs[key] = id
+	}
+}
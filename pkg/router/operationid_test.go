@@ -0,0 +1,89 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationIDCollisions(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "/todos/archived", noop).
+		WithLink("200", "Unarchived", Link{Method: "GET", Path: "/todos_archived"}).
+		Register()
+	r.Route("GET", "/todos_archived", noop).Register()
+	r.Route("GET", "/todos/archived_2", noop).Register()
+	r.Route("GET", "/todos_archived_2", noop).Register()
+	r.Route("POST", "/todos/archived", noop).Register()
+
+	generator := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes())
+	paths := generator.Generate()["paths"].(map[string]any)
+	operation := func(method, path string) map[string]any {
+		return paths[path].(map[string]any)[method].(map[string]any)
+	}
+
+	assert.Equal(t, "get__todos_archived", operation("get", "/todos/archived")["operationId"])
+	// the suffix skips IDs derived by other routes
+	assert.Equal(t, "get__todos_archived_3", operation("get", "/todos_archived")["operationId"])
+	assert.Equal(t, "get__todos_archived_2", operation("get", "/todos/archived_2")["operationId"])
+	assert.Equal(t, "get__todos_archived_2_2", operation("get", "/todos_archived_2")["operationId"])
+	assert.Equal(t, "post__todos_archived", operation("post", "/todos/archived")["operationId"])
+
+	// links reference the suffixed ID
+	links := operation("get", "/todos/archived")["responses"].(map[string]any)["200"].(map[string]any)["links"].(map[string]any)
+	assert.Equal(t, "get__todos_archived_3", links["Unarchived"].(map[string]any)["operationId"])
+
+	assert.Equal(t, []OperationIDCollision{
+		{OperationID: "get__todos_archived", Routes: []string{"GET /todos/archived", "GET /todos_archived"}},
+		{OperationID: "get__todos_archived_2", Routes: []string{"GET /todos/archived_2", "GET /todos_archived_2"}},
+	}, generator.OperationIDCollisions())
+}
+
+func TestOperationIDStrategy(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithOperationIDFunc(func(route RouteInfo) string {
+		return strings.ReplaceAll(route.Name, " ", "_")
+	})
+	r.Get("/todos", noop).WithName("list todos").Register()
+	r.Get("/todos/{id}", noop).WithName("get todo").WithOperationID("fetchTodo").
+		WithLink("200", "Self", Link{Method: "GET", Path: "/todos/{id}"}).
+		Register()
+	r.Get("/todos/{id}/comments", noop).WithName("get todo").Register()
+	r.Get("/health", noop).Register()
+	r.Get("/todos/{id}/attachments", noop).WithVersion("2").WithOperationID("listAttachments").Register()
+	r.Get("/todos/{id}/attachments", noop).WithName("attachments").Register()
+
+	generator := r.OpenAPI()
+	paths := generator.Generate()["paths"].(map[string]any)
+	operationID := func(path string) any {
+		return paths[path].(map[string]any)["get"].(map[string]any)["operationId"]
+	}
+
+	assert.Equal(t, "list_todos", operationID("/todos"))
+	assert.Equal(t, "fetchTodo", operationID("/todos/{id}"), "the route's own ID wins over the strategy")
+	assert.Equal(t, "get_todo", operationID("/todos/{id}/comments"))
+	assert.Equal(t, "get__health", operationID("/health"), "empty names fall back to the derived ID")
+	assert.Equal(t, "listAttachments", operationID("/todos/{id}/attachments"), "any version can set the ID")
+
+	links := paths["/todos/{id}"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)["200"].(map[string]any)["links"].(map[string]any)
+	assert.Equal(t, "fetchTodo", links["Self"].(map[string]any)["operationId"])
+
+	assert.Empty(t, generator.OperationIDCollisions())
+
+	r.Get("/todos/{id}/history", noop).WithOperationID("fetchTodo").Register()
+	generator = r.OpenAPI()
+	paths = generator.Generate()["paths"].(map[string]any)
+	assert.Equal(t, "fetchTodo_2", operationID("/todos/{id}/history"))
+	assert.Equal(t, []OperationIDCollision{
+		{OperationID: "fetchTodo", Routes: []string{"GET /todos/{id}", "GET /todos/{id}/history"}},
+	}, generator.OperationIDCollisions())
+}
@@ -0,0 +1,100 @@
+package router
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// sortArrays sorts the required, enum and tags arrays documented under the
+// value, so reordering struct fields, enum values or tags doesn't change the
+// spec. Examples, defaults and extensions are left untouched, and arrays are
+// replaced by sorted copies as they may be shared with the routes.
+func sortArrays(value any) {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, v := range value {
+			switch {
+			case key == "example", key == "examples", key == "default", strings.HasPrefix(key, "x-"):
+				continue
+			case key == "properties":
+				// property names are schemas' names, not keywords
+				if properties, ok := v.(map[string]any); ok {
+					for _, property := range properties {
+						sortArrays(property)
+					}
+					continue
+				}
+			case key == "required", key == "tags":
+				if strs, ok := v.([]string); ok {
+					value[key] = sortedStrings(strs)
+					continue
+				}
+			case key == "enum":
+				if enum, ok := sortedEnum(v); ok {
+					value[key] = enum
+					continue
+				}
+			}
+			sortArrays(v)
+		}
+	case map[string]map[string]any:
+		for _, v := range value {
+			sortArrays(v)
+		}
+	case []any:
+		for _, v := range value {
+			sortArrays(v)
+		}
+	}
+}
+
+// sortedStrings returns a sorted copy of strs
+func sortedStrings(strs []string) []string {
+	sorted := slices.Clone(strs)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// sortedEnum returns a sorted copy of an enum of strings or numbers; enums
+// mixing types, e.g. with null, keep their order
+func sortedEnum(enum any) (any, bool) {
+	switch enum := enum.(type) {
+	case []string:
+		return sortedStrings(enum), true
+	case []any:
+		var strs, nums int
+		for _, v := range enum {
+			switch v.(type) {
+			case string:
+				strs++
+			case int, int64, float64:
+				nums++
+			}
+		}
+		if strs != len(enum) && nums != len(enum) {
+			return enum, false
+		}
+
+		sorted := slices.Clone(enum)
+		slices.SortStableFunc(sorted, func(a, b any) int {
+			if strs == len(enum) {
+				return strings.Compare(a.(string), b.(string))
+			}
+			return cmp.Compare(enumNumber(a), enumNumber(b))
+		})
+		return sorted, true
+	}
+	return enum, false
+}
+
+// enumNumber converts the numeric enum values to float64 for comparison
+func enumNumber(v any) float64 {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return v.(float64)
+}
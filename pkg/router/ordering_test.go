@@ -0,0 +1,82 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderedTask declares its fields and enum values out of alphabetical order
+type orderedTask struct {
+	Title    string         `json:"title" enum:"write,review,ship"`
+	Priority int            `json:"priority" enum:"3,1,2"`
+	Assignee string         `json:"assignee"`
+	Labels   map[string]any `json:"labels" example:"{\"required\": [\"z\", \"a\"]}"`
+}
+
+func TestArrayOrdering(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		declarationOrder bool
+		wantRequired     []string
+		wantTitles       []string
+		wantPriorities   []string
+		wantTags         []string
+		wantVersions     []string
+	}{
+		"sorted": {
+			wantRequired:   []string{"assignee", "labels", "priority", "title"},
+			wantTitles:     []string{"review", "ship", "write"},
+			wantPriorities: []string{"1", "2", "3"},
+			wantTags:       []string{"Admin", "Users"},
+			wantVersions:   []string{"2023-01-01", "2024-01-01"},
+		},
+		"declaration order": {
+			declarationOrder: true,
+			wantRequired:     []string{"title", "priority", "assignee", "labels"},
+			wantTitles:       []string{"write", "review", "ship"},
+			wantPriorities:   []string{"3", "1", "2"},
+			wantTags:         []string{"Users", "Admin"},
+			wantVersions:     []string{"2024-01-01", "2023-01-01"},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			r.Post("/tasks", func(w http.ResponseWriter, r *http.Request) {}).
+				WithTags("Users", "Admin").
+				WithRequest(orderedTask{}).
+				WithParameter(Parameter{Name: "version", In: "header", Schema: "", Enum: []string{"2024-01-01", "2023-01-01"}}).
+				Register()
+			routes := r.GetRoutes()
+
+			generator := NewOpenAPIGenerator("Test API", "", "1.0.0", routes)
+			generator.DeclarationOrder = tc.declarationOrder
+			spec := generator.Generate()
+
+			schema := spec["components"].(map[string]any)["schemas"].(map[string]any)["orderedTask"].(map[string]any)
+			properties := schema["properties"].(map[string]any)
+			if diff := cmp.Diff(tc.wantRequired, schema["required"]); diff != "" {
+				t.Errorf("required mismatch (-want +got):\n%s", diff)
+			}
+			assert.Equal(t, tc.wantTitles, enumStrings(properties["title"].(map[string]any)["enum"]))
+			assert.Equal(t, tc.wantPriorities, enumStrings(properties["priority"].(map[string]any)["enum"]))
+			assert.Equal(t, map[string]any{"required": []any{"z", "a"}}, properties["labels"].(map[string]any)["example"],
+				"examples are left alone")
+
+			operation := spec["paths"].(map[string]any)["/tasks"].(map[string]any)["post"].(map[string]any)
+			assert.Equal(t, tc.wantTags, operation["tags"])
+			params := operation["parameters"].([]any)
+			require.Len(t, params, 1)
+			assert.Equal(t, tc.wantVersions, enumStrings(params[0].(map[string]any)["schema"].(map[string]any)["enum"]))
+
+			assert.Equal(t, []string{"Users", "Admin"}, routes[0].Tags, "routes keep their order")
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"reflect"
+	"sync"
+)
+
+// reflectedSchema is the schema of a root type reflected ahead of Generate,
+// with the example issues found along the way
+type reflectedSchema struct {
+	schema map[string]any
+	issues []ExampleIssue
+}
+
+// reflectRootTypes generates the schemas of the distinct root types of the
+// routes on Parallelism goroutines, for generateSchema to pick up. Only the
+// reflection runs concurrently: each worker fills its own slots of the
+// results, while registering the schemas, extracting their nested types and
+// merging their issues still happens in route order as paths are generated,
+// so the spec is the same as a sequential run's.
+func (g *OpenAPIGenerator) reflectRootTypes() {
+	types := g.rootTypes()
+	results := make([]reflectedSchema, len(types))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(g.Parallelism, len(types)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = g.reflectSchema(types[index].value)
+			}
+		}()
+	}
+	for i := range types {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	g.reflected = make(map[reflect.Type]reflectedSchema, len(types))
+	for i, root := range types {
+		g.reflected[root.typ] = results[i]
+	}
+}
+
+// rootType is a type documented by a route, with an example value of it
+type rootType struct {
+	typ   reflect.Type
+	value any
+}
+
+// rootTypes returns the distinct types documented by the routes and the
+// request body components whose schemas are yet to be generated
+func (g *OpenAPIGenerator) rootTypes() []rootType {
+	var types []rootType
+	seen := make(map[reflect.Type]bool)
+	add := func(value any) {
+		typ := reflect.TypeOf(value)
+		if typ == nil || seen[typ] {
+			return
+		}
+		seen[typ] = true
+
+		if name := getTypeName(value); name != "" {
+			if _, exists := g.schemaRegistry.schemas[name]; exists {
+				return
+			}
+		} else if _, exists := g.inlineSchemas[typ]; exists {
+			return
+		}
+		types = append(types, rootType{typ: typ, value: value})
+	}
+
+	for _, route := range g.Routes {
+		add(route.RequestType)
+		add(route.ResponseType)
+		for _, response := range route.Responses {
+			add(response.Schema)
+		}
+		for _, byMediaType := range route.AlternateContent {
+			for _, schema := range byMediaType {
+				add(schema)
+			}
+		}
+	}
+	for _, body := range g.customRequestBodies {
+		add(body)
+	}
+
+	return types
+}
@@ -0,0 +1,39 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelGeneration(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := benchRouter()
+	r.Get("/examples", noop).WithResponse([]withExamples{}).Register()
+	r.Get("/constraints", noop).WithResponse(withConstraints{}).
+		WithErrorResponse("404", "Not Found", withDocAndExample{}).Register()
+	r.Post("/pointers", noop).WithRequest(&withPointers{}).WithRequestRef("Pointers").
+		WithAlternateContent("200", "application/xml", withTime{}).Register()
+
+	generate := func(parallelism int) (map[string]any, []ExampleIssue) {
+		generator := r.OpenAPI()
+		generator.Parallelism = parallelism
+		generator.SchemaOptions = SchemaOptions{Pointers: PointersNullable}
+		generator.RegisterRequestBody("Pointers", withPointers{})
+		return generator.Generate(), generator.ExampleIssues()
+	}
+
+	sequential, sequentialIssues := generate(0)
+	parallel, parallelIssues := generate(4)
+
+	if diff := cmp.Diff(sequential, parallel); diff != "" {
+		t.Errorf("spec mismatch (-sequential +parallel):\n%s", diff)
+	}
+	assert.NotEmpty(t, parallelIssues)
+	assert.Equal(t, sequentialIssues, parallelIssues)
+}
@@ -0,0 +1,113 @@
+package router
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Serialization styles for array and object parameters
+const (
+	StyleForm           = "form"           // ?ids=1&ids=2 (explode) or ?ids=1,2
+	StyleSpaceDelimited = "spaceDelimited" // ?ids=1%202
+	StylePipeDelimited  = "pipeDelimited"  // ?ids=1|2
+	StyleDeepObject     = "deepObject"     // ?filter[completed]=true
+)
+
+// explode reports whether the parameter uses exploded serialization, which
+// OpenAPI defaults to true only for the form style
+func (p Parameter) explode() bool {
+	if p.Explode != nil {
+		return *p.Explode
+	}
+	return p.Style == "" || p.Style == StyleForm
+}
+
+// delimiter returns the separator of non-exploded array values
+func (p Parameter) delimiter() string {
+	switch p.Style {
+	case StyleSpaceDelimited:
+		return " "
+	case StylePipeDelimited:
+		return "|"
+	default:
+		return ","
+	}
+}
+
+// QueryArray returns the items of an array query parameter according to its
+// declared style, e.g. ?ids=1&ids=2, ?ids=1,2 or ?ids=1|2
+func QueryArray(r *http.Request, param Parameter) []string {
+	values := r.URL.Query()[param.Name]
+	if param.explode() {
+		return values
+	}
+
+	var items []string
+	for _, value := range values {
+		items = append(items, strings.Split(value, param.delimiter())...)
+	}
+	return items
+}
+
+// QueryObject returns the properties of an object query parameter according
+// to its declared style, e.g. ?filter[completed]=true for deepObject,
+// ?completed=true for exploded form (the default) or ?filter=completed,true
+// for non-exploded form. Exploded form objects only have the properties
+// their schema declares, as others can't be told apart from the other
+// parameters; maps are sent as deepObject or non-exploded form instead.
+func QueryObject(r *http.Request, param Parameter) map[string]string {
+	query := r.URL.Query()
+	object := map[string]string{}
+
+	if param.Style == StyleDeepObject {
+		prefix := param.Name + "["
+		for key, values := range query {
+			property, ok := strings.CutPrefix(key, prefix)
+			if !ok || !strings.HasSuffix(property, "]") || len(values) == 0 {
+				continue
+			}
+			object[strings.TrimSuffix(property, "]")] = values[0]
+		}
+		return object
+	}
+
+	if param.explode() {
+		for _, property := range objectProperties(param.Schema) {
+			if values := query[property]; len(values) > 0 {
+				object[property] = values[0]
+			}
+		}
+		return object
+	}
+
+	parts := strings.Split(query.Get(param.Name), param.delimiter())
+	for i := 0; i+1 < len(parts); i += 2 {
+		object[parts[i]] = parts[i+1]
+	}
+	return object
+}
+
+// propertyNames caches the declared properties of object parameter types
+var propertyNames sync.Map
+
+// objectProperties returns the names of the properties the schema of an
+// object parameter type declares
+func objectProperties(schemaType any) []string {
+	typ := reflect.TypeOf(schemaType)
+	if typ == nil {
+		return nil
+	}
+	if names, ok := propertyNames.Load(typ); ok {
+		return names.([]string)
+	}
+
+	properties, _ := jsonSchema(schemaType)["properties"].(map[string]any)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	propertyNames.Store(typ, names)
+	return names
+}
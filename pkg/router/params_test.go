@@ -0,0 +1,135 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryArray(t *testing.T) {
+	t.Parallel()
+
+	explode, noExplode := true, false
+
+	for name, tc := range map[string]struct {
+		query    string
+		param    Parameter
+		expected []string
+	}{
+		"form exploded by default": {
+			query:    "?ids=1&ids=2",
+			param:    Parameter{Name: "ids"},
+			expected: []string{"1", "2"},
+		},
+		"form not exploded": {
+			query:    "?ids=1,2,3",
+			param:    Parameter{Name: "ids", Style: StyleForm, Explode: &noExplode},
+			expected: []string{"1", "2", "3"},
+		},
+		"space delimited": {
+			query:    "?ids=1%202",
+			param:    Parameter{Name: "ids", Style: StyleSpaceDelimited},
+			expected: []string{"1", "2"},
+		},
+		"pipe delimited": {
+			query:    "?ids=1|2",
+			param:    Parameter{Name: "ids", Style: StylePipeDelimited},
+			expected: []string{"1", "2"},
+		},
+		"pipe delimited exploded": {
+			query:    "?ids=1|2&ids=3",
+			param:    Parameter{Name: "ids", Style: StylePipeDelimited, Explode: &explode},
+			expected: []string{"1|2", "3"},
+		},
+		"missing": {
+			query: "",
+			param: Parameter{Name: "ids"},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+
+			if diff := cmp.Diff(tc.expected, QueryArray(req, tc.param)); diff != "" {
+				t.Errorf("items mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryObject(t *testing.T) {
+	t.Parallel()
+
+	noExplode := false
+
+	for name, tc := range map[string]struct {
+		query    string
+		param    Parameter
+		expected map[string]string
+	}{
+		"deep object": {
+			query:    "?filter[completed]=true&filter[title]=milk&other=1",
+			param:    Parameter{Name: "filter", Style: StyleDeepObject},
+			expected: map[string]string{"completed": "true", "title": "milk"},
+		},
+		"form exploded by default": {
+			query: "?completed=true&title=milk&limit=10",
+			param: Parameter{Name: "filter", Schema: struct {
+				Completed bool   `json:"completed"`
+				Title     string `json:"title"`
+				Owner     string `json:"owner"`
+			}{}},
+			expected: map[string]string{"completed": "true", "title": "milk"},
+		},
+		"form not exploded": {
+			query:    "?filter=completed,true,title,milk",
+			param:    Parameter{Name: "filter", Style: StyleForm, Explode: &noExplode},
+			expected: map[string]string{"completed": "true", "title": "milk"},
+		},
+		"missing": {
+			query:    "",
+			param:    Parameter{Name: "filter", Style: StyleDeepObject},
+			expected: map[string]string{},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+
+			if diff := cmp.Diff(tc.expected, QueryObject(req, tc.param)); diff != "" {
+				t.Errorf("object mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParameterStyleDocumentation(t *testing.T) {
+	t.Parallel()
+
+	noExplode := false
+	params := generateParameters([]Parameter{
+		{Name: "ids", In: "query", Schema: []int{}, Style: StyleForm, Explode: &noExplode},
+	})
+	require.Len(t, params, 1)
+
+	expected := map[string]any{
+		"name":    "ids",
+		"in":      "query",
+		"style":   "form",
+		"explode": false,
+		"schema": map[string]any{
+			"type":  "array",
+			"items": map[string]any{"type": "integer"},
+		},
+	}
+	if diff := cmp.Diff(expected, params[0]); diff != "" {
+		t.Errorf("parameter mismatch (-want +got):\n%s", diff)
+	}
+}
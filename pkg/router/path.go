@@ -0,0 +1,130 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// normalizePath returns the canonical form of a route path: it starts with a
+// slash, has no empty segments and no trailing slash, so that "todos/" and
+// "/todos" register the same route. Paths containing whitespace are rejected.
+func normalizePath(path string) (string, error) {
+	if strings.IndexFunc(path, unicode.IsSpace) >= 0 {
+		return "", errors.New("path contains whitespace")
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return "/" + strings.Join(segments, "/"), nil
+}
+
+// routeKeyPath returns the path a route given by path is registered under,
+// or path itself when it isn't valid
+func routeKeyPath(path string) string {
+	normalized, err := normalizePath(path)
+	if err != nil {
+		return path
+	}
+	if normalized, _, err = pathConstraints(normalized); err != nil {
+		return path
+	}
+	return normalized
+}
+
+// pathConstraints strips the constraints of path parameters written as
+// {name:pattern}, returning the path in the form ServeMux expects and a path
+// parameter for every constraint, whose pattern matches whole values
+func pathConstraints(path string) (string, []Parameter, error) {
+	segments := strings.Split(path, "/")
+
+	var params []Parameter
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		name, pattern, constrained := strings.Cut(segment[1:len(segment)-1], ":")
+		if !constrained {
+			continue
+		}
+		if pattern == "" {
+			return "", nil, fmt.Errorf("parameter %s has an empty pattern", name)
+		}
+
+		pattern = "^(?:" + pattern + ")$"
+		if _, err := regexp.Compile(pattern); err != nil {
+			return "", nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+
+		segments[i] = "{" + name + "}"
+		params = append(params, Parameter{Name: name, In: "path", Schema: "", Pattern: pattern})
+	}
+
+	return strings.Join(segments, "/"), params, nil
+}
+
+// constrainPathParams adds the patterns of path constraints to the route's
+// parameters, completing the declared path parameter of the same name if any
+func constrainPathParams(params []Parameter, constraints []Parameter) []Parameter {
+	for _, constraint := range constraints {
+		index := slices.IndexFunc(params, func(param Parameter) bool {
+			return param.In == "path" && param.Name == constraint.Name
+		})
+		if index < 0 {
+			params = append(params, constraint)
+			continue
+		}
+		if params[index].Pattern == "" {
+			params[index].Pattern = constraint.Pattern
+		}
+	}
+
+	return params
+}
+
+// validatePath wraps a handler so that path parameters are checked against
+// their patterns before the handler runs, responding with a structured 400
+// otherwise. Patterns of the router's shared path parameters apply to routes
+// not declaring the parameter themselves.
+func validatePath(dr *DocRouter, params []Parameter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var errs []invalidValue
+		var seen []string
+		for _, declared := range [][]Parameter{params, dr.parameters} {
+			for _, param := range declared {
+				// the first declaration of a parameter wins, as in the documentation
+				if param.In != "path" || slices.Contains(seen, param.Name) {
+					continue
+				}
+				seen = append(seen, param.Name)
+
+				// parameters of other paths have no value
+				value := r.PathValue(param.Name)
+				if param.Pattern == "" || value == "" {
+					continue
+				}
+
+				if msg := validateValue(value, map[string]any{"pattern": param.Pattern}); msg != nil {
+					errs = append(errs, invalidValue{field: param.Name, in: "path", msg: msg})
+				}
+			}
+		}
+
+		if len(errs) > 0 {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request parameters", errs)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,126 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePath(t *testing.T) {
+	t.Parallel()
+
+	for path, tc := range map[string]struct {
+		want string
+		err  bool
+	}{
+		"/todos":            {want: "/todos"},
+		"todos":             {want: "/todos"},
+		"/todos/":           {want: "/todos"},
+		"//todos//{id}/":    {want: "/todos/{id}"},
+		"/":                 {want: "/"},
+		"":                  {want: "/"},
+		"/todos/{id} ":      {err: true},
+		"/todo items/{id}":  {err: true},
+		"/todos/\t{id}/tag": {err: true},
+	} {
+		path, tc := path, tc
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := normalizePath(path)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRegisterNormalizesPath(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "todos//{id}/", noop).Register()
+
+	require.Len(t, r.GetRoutes(), 1)
+	assert.Equal(t, "/todos/{id}", r.GetRoutes()[0].Path)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/todos/1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.PanicsWithValue(t, "router: multiple registrations for GET /todos/{id}", func() {
+		r.Route("GET", "/todos/{id}", noop).Register()
+	})
+	assert.PanicsWithValue(t, `router: invalid path "/todos/ {id}" of GET route: path contains whitespace`, func() {
+		r.Route("GET", "/todos/ {id}", noop).Register()
+	})
+}
+
+func TestPathConstraints(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter().WithParameter(Parameter{Name: "slug", In: "path", Schema: "", Pattern: "^[a-z-]+$"})
+	r.Route("GET", "/todos/{id:[0-9]+}/comments/{commentId}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("id")))
+	}).
+		WithPathParam("id", "Identifier of the todo").
+		Register()
+	r.Route("GET", "/tags/{slug}", func(w http.ResponseWriter, r *http.Request) {}).Register()
+
+	for path, tc := range map[string]struct {
+		wantStatus int
+		wantErrors []ValidationError
+	}{
+		"/todos/42/comments/abc": {wantStatus: http.StatusOK},
+		"/todos/abc/comments/1": {
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{{Field: "id", In: "path", Code: CodePattern, Message: "must match pattern ^(?:[0-9]+)$"}},
+		},
+		"/tags/open-source": {wantStatus: http.StatusOK},
+		"/tags/Open": {
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{{Field: "slug", In: "path", Code: CodePattern, Message: "must match pattern ^[a-z-]+$"}},
+		},
+	} {
+		path, tc := path, tc
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantErrors == nil {
+				return
+			}
+
+			var resp ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tc.wantErrors, resp.Errors)
+		})
+	}
+
+	paths := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	operation := paths["/todos/{id}/comments/{commentId}"].(map[string]any)["get"].(map[string]any)
+
+	assert.Equal(t, map[string]any{
+		"name":        "id",
+		"in":          "path",
+		"required":    true,
+		"description": "Identifier of the todo",
+		"schema":      map[string]any{"type": "string", "pattern": "^(?:[0-9]+)$"},
+	}, operation["parameters"].([]any)[0])
+	assert.Contains(t, operation["responses"], "400")
+
+	assert.PanicsWithValue(t, `router: invalid path "/todos/{id:[0-9}" of GET route: parameter id: error parsing regexp: missing closing ]: `+"`[0-9)$`", func() {
+		r.Route("GET", "/todos/{id:[0-9}", func(w http.ResponseWriter, r *http.Request) {}).Register()
+	})
+}
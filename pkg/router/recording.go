@@ -0,0 +1,315 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxRecordedBody is the largest payload, in bytes, promoted to an example;
+// larger payloads make poor documentation and are skipped
+const maxRecordedBody = 16 << 10
+
+// RecordedExampleName names the examples promoted from recorded traffic in
+// the spec
+const RecordedExampleName = "recorded"
+
+// redactedValue replaces the values of sensitive members in recorded payloads
+const redactedValue = "REDACTED"
+
+// DefaultRedactedFields are the object members whose values are redacted from
+// recorded payloads. Members match case-insensitively when their name
+// contains any of them, so access_token and X-Api-Key are redacted too.
+var DefaultRedactedFields = []string{"password", "secret", "token", "authorization", "api_key", "apikey", "api-key"}
+
+// RecordedExample is a sanitized JSON payload captured from real traffic to a
+// route, either its request body or the body of a response
+type RecordedExample struct {
+	Method string          `json:"method"`           // Method of the route
+	Path   string          `json:"path"`             // Documented path of the route, e.g. "/todos/{id}"
+	Status int             `json:"status,omitempty"` // Status code of the response, zero for the request body
+	Value  json.RawMessage `json:"value"`            // Sanitized payload
+}
+
+// ExampleRecorder promotes the JSON payloads a router serves into examples,
+// keeping the first request body and the first body of each response status
+// of every route so the examples don't churn between recordings. Members
+// named like sensitive fields are redacted before payloads are kept.
+type ExampleRecorder struct {
+	redact []string
+
+	mu       sync.Mutex
+	examples map[recordedKey]RecordedExample
+}
+
+// recordedKey identifies the payload of a route's request or response status
+type recordedKey struct {
+	method string
+	path   string
+	status int
+}
+
+// NewExampleRecorder creates a recorder redacting DefaultRedactedFields and
+// the given fields
+func NewExampleRecorder(redact ...string) *ExampleRecorder {
+	fields := slices.Clone(DefaultRedactedFields)
+	for _, field := range redact {
+		fields = append(fields, strings.ToLower(field))
+	}
+
+	return &ExampleRecorder{
+		redact:   fields,
+		examples: make(map[recordedKey]RecordedExample),
+	}
+}
+
+// WithExampleRecorder records the JSON payloads of the router's routes into
+// the recorder, e.g. while running a staging environment or an integration
+// suite. Only requests passing validation are recorded.
+func (dr *DocRouter) WithExampleRecorder(recorder *ExampleRecorder) *DocRouter {
+	dr.exampleRecorder = recorder
+	return dr
+}
+
+// Examples returns the recorded examples, ordered by path, method and status
+func (er *ExampleRecorder) Examples() []RecordedExample {
+	er.mu.Lock()
+	examples := make([]RecordedExample, 0, len(er.examples))
+	for _, example := range er.examples {
+		examples = append(examples, example)
+	}
+	er.mu.Unlock()
+
+	slices.SortFunc(examples, func(a, b RecordedExample) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Method, b.Method); c != 0 {
+			return c
+		}
+		return a.Status - b.Status
+	})
+
+	return examples
+}
+
+// WriteFile persists the recorded examples as JSON, for openapi-gen to merge
+// into the spec with ReadRecordedExamples
+func (er *ExampleRecorder) WriteFile(path string) error {
+	data, err := json.MarshalIndent(er.Examples(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal recorded examples: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write recorded examples to '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// ReadRecordedExamples reads examples persisted by ExampleRecorder.WriteFile
+func ReadRecordedExamples(path string) ([]RecordedExample, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read recorded examples from '%s': %w", path, err)
+	}
+
+	var examples []RecordedExample
+	if err := json.Unmarshal(data, &examples); err != nil {
+		return nil, fmt.Errorf("parse recorded examples from '%s': %w", path, err)
+	}
+
+	return examples, nil
+}
+
+// record keeps the sanitized payload unless the route already has one for
+// the same request or response status
+func (er *ExampleRecorder) record(method, path string, status int, payload []byte) {
+	key := recordedKey{method: method, path: path, status: status}
+
+	er.mu.Lock()
+	_, exists := er.examples[key]
+	er.mu.Unlock()
+	if exists {
+		return
+	}
+
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return
+	}
+	sanitized, err := json.Marshal(er.sanitize(value))
+	if err != nil {
+		return
+	}
+
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	if _, exists := er.examples[key]; !exists {
+		er.examples[key] = RecordedExample{Method: method, Path: path, Status: status, Value: sanitized}
+	}
+}
+
+// sanitize redacts the values of sensitive members throughout a payload
+func (er *ExampleRecorder) sanitize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, member := range v {
+			if er.redacted(name) {
+				v[name] = redactedValue
+				continue
+			}
+			v[name] = er.sanitize(member)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = er.sanitize(item)
+		}
+	}
+
+	return value
+}
+
+// redacted reports whether a member's value must be redacted
+func (er *ExampleRecorder) redacted(name string) bool {
+	name = strings.ToLower(name)
+	return slices.ContainsFunc(er.redact, func(field string) bool {
+		return strings.Contains(name, field)
+	})
+}
+
+// isJSONMediaType reports whether a Content-Type header is JSON
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// exampleRecordingMiddleware records the JSON payloads of requests to the
+// route when the router has an example recorder, looked up at request time so
+// it may be set after the route is registered
+func exampleRecordingMiddleware(dr *DocRouter, info *RouteInfo, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := dr.exampleRecorder
+		if recorder == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if isJSONMediaType(r.Header.Get("Content-Type")) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxRecordedBody+1))
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+			if err == nil && len(body) > 0 && len(body) <= maxRecordedBody {
+				recorder.record(info.Method, info.Path, 0, body)
+			}
+		}
+
+		captured := &recordingWriter{ResponseWriter: w}
+		next.ServeHTTP(captured, r)
+
+		if !captured.truncated && captured.body.Len() > 0 && isJSONMediaType(w.Header().Get("Content-Type")) {
+			recorder.record(info.Method, info.Path, captured.statusCode(), captured.body.Bytes())
+		}
+	})
+}
+
+// recordingWriter records the status code and the body written through it,
+// up to maxRecordedBody
+type recordingWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+// WriteHeader records the status code before writing it
+func (w *recordingWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the body, unless it's too large to promote, before writing it
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.body.Len()+len(b) > maxRecordedBody {
+		w.truncated = true
+	} else if !w.truncated {
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode returns the recorded status, defaulting to 200 like net/http
+func (w *recordingWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// addRecordedExamples adds the recorded examples to the JSON request bodies
+// and responses their routes document inline, naming them
+// RecordedExampleName. Examples of routes, statuses or media types the spec
+// doesn't document are ignored.
+func (g *OpenAPIGenerator) addRecordedExamples(paths map[string]any) {
+	for _, example := range g.RecordedExamples {
+		pathItem, _ := paths[routeKeyPath(example.Path)].(map[string]any)
+		operation, _ := pathItem[strings.ToLower(example.Method)].(map[string]any)
+		if operation == nil {
+			continue
+		}
+
+		var target map[string]any
+		if example.Status == 0 {
+			target, _ = operation["requestBody"].(map[string]any)
+		} else {
+			responses, _ := operation["responses"].(map[string]any)
+			target, _ = responses[strconv.Itoa(example.Status)].(map[string]any)
+		}
+		content, _ := target["content"].(map[string]any)
+		mediaType, _ := content["application/json"].(map[string]any)
+		if mediaType == nil {
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal(example.Value, &value); err != nil {
+			continue
+		}
+
+		examples, _ := mediaType["examples"].(map[string]any)
+		if examples == nil {
+			examples = map[string]any{}
+			mediaType["examples"] = examples
+		}
+		if _, exists := examples[RecordedExampleName]; !exists {
+			examples[RecordedExampleName] = map[string]any{
+				"summary": "Recorded from real traffic",
+				"value":   value,
+			}
+		}
+	}
+}
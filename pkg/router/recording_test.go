@@ -0,0 +1,105 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleRecorder(t *testing.T) {
+	t.Parallel()
+
+	type Login struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	type Session struct {
+		User        string `json:"user"`
+		AccessToken string `json:"access_token"`
+	}
+
+	login := func(w http.ResponseWriter, r *http.Request) {
+		var req Login
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if req.Password == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]any{"error": "missing password", "retry": []any{map[string]any{"otp_secret": "x"}}})
+			return
+		}
+		json.NewEncoder(w).Encode(Session{User: req.User, AccessToken: "s3cr3t"})
+	}
+
+	recorder := NewExampleRecorder("user")
+	r := NewDocRouter().WithExampleRecorder(recorder)
+	r.Post("/sessions", login).
+		WithRequest(Login{}).
+		WithResponse(Session{}).
+		WithErrorResponse("401", "Unauthorized", map[string]any{}).
+		Register()
+
+	for _, body := range []string{
+		`{"user": "ana", "password": "hunter2"}`,
+		`{"user": "bob", "password": "swordfish"}`,
+		`{"user": "ana"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/sessions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.NotEqual(t, http.StatusBadRequest, rec.Code, "the handler reads the whole body")
+	}
+
+	examples := recorder.Examples()
+	require.Len(t, examples, 3, "the first payload of the request and each status is kept")
+	for i, want := range []struct {
+		status int
+		value  string
+	}{
+		{0, `{"user": "REDACTED", "password": "REDACTED"}`},
+		{200, `{"user": "REDACTED", "access_token": "REDACTED"}`},
+		{401, `{"error": "missing password", "retry": [{"otp_secret": "REDACTED"}]}`},
+	} {
+		assert.Equal(t, "POST", examples[i].Method)
+		assert.Equal(t, "/sessions", examples[i].Path)
+		assert.Equal(t, want.status, examples[i].Status)
+		assert.JSONEq(t, want.value, string(examples[i].Value))
+	}
+
+	path := filepath.Join(t.TempDir(), "examples.json")
+	require.NoError(t, recorder.WriteFile(path))
+	recorded, err := ReadRecordedExamples(path)
+	require.NoError(t, err)
+	want, _ := json.Marshal(examples)
+	got, _ := json.Marshal(recorded)
+	assert.JSONEq(t, string(want), string(got))
+
+	generator := r.OpenAPI()
+	generator.RecordedExamples = append(recorded, RecordedExample{Method: "GET", Path: "/missing", Value: json.RawMessage(`{}`)})
+	operation := generator.Generate()["paths"].(map[string]any)["/sessions"].(map[string]any)["post"].(map[string]any)
+	mediaType := func(object any) map[string]any {
+		return object.(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)
+	}
+
+	assert.Equal(t, map[string]any{
+		RecordedExampleName: map[string]any{
+			"summary": "Recorded from real traffic",
+			"value":   map[string]any{"user": "REDACTED", "password": "REDACTED"},
+		},
+	}, mediaType(operation["requestBody"])["examples"])
+	responses := operation["responses"].(map[string]any)
+	assert.Contains(t, mediaType(responses["200"])["examples"], RecordedExampleName)
+	assert.Contains(t, mediaType(responses["401"])["examples"], RecordedExampleName)
+
+	_, err = ReadRecordedExamples(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
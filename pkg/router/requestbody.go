@@ -0,0 +1,38 @@
+package router
+
+// WithRequestRef documents the request body with a component registered on
+// the generator through RegisterRequestBody, in place of the request type,
+// for payloads shared by many routes. The request type, if any, is still
+// used to bind and validate requests.
+func (rc *RouteConfig) WithRequestRef(requestBodyName string) *RouteConfig {
+	rc.requestRef = requestBodyName
+	return rc
+}
+
+// RegisterRequestBody adds a reusable request body to the components
+// section, documenting the JSON payload of the given type; routes reference
+// it through WithRequestRef
+func (g *OpenAPIGenerator) RegisterRequestBody(name string, body any) {
+	if g.customRequestBodies == nil {
+		g.customRequestBodies = make(map[string]any)
+	}
+
+	g.customRequestBodies[name] = body
+}
+
+// generateRequestBodies creates the request body components, generated along
+// with the spec so they follow the SchemaOptions
+func (g *OpenAPIGenerator) generateRequestBodies() map[string]any {
+	requestBodies := make(map[string]any, len(g.customRequestBodies))
+	for name, body := range g.customRequestBodies {
+		requestBodies[name] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": g.schemaRef(body),
+				},
+			},
+		}
+	}
+	return requestBodies
+}
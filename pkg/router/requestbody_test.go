@@ -0,0 +1,52 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBodyRefs(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Post("/users", noop).WithRequestRef("User").Register()
+	r.Put("/users/{id}", noop).WithRequest(UserRequest{}).WithRequestRef("User").Register()
+	r.Get("/users", noop).WithRequestRef("User").Register()
+	r.Get("/users/search", noop).WithRequestRef("User").WithRequestOnAnyMethod().Register()
+
+	generator := NewOpenAPIGenerator("Test API", "", "1.0.0", r.GetRoutes())
+	generator.RegisterRequestBody("User", UserRequest{})
+	generator.Charset = "utf-8"
+
+	spec := generator.Generate()
+	paths := spec["paths"].(map[string]any)
+	ref := map[string]any{"$ref": "#/components/requestBodies/User"}
+
+	for path, method := range map[string]string{"/users": "post", "/users/{id}": "put", "/users/search": "get"} {
+		operation := paths[path].(map[string]any)[method].(map[string]any)
+		assert.Equal(t, ref, operation["requestBody"], "%s %s", method, path)
+	}
+	assert.NotContains(t, paths["/users"].(map[string]any)["get"], "requestBody",
+		"GET requests don't document their body by default")
+
+	components := spec["components"].(map[string]any)
+	want := map[string]any{
+		"User": map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json; charset=utf-8": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/UserRequest"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, components["requestBodies"]); diff != "" {
+		t.Errorf("request bodies mismatch (-want +got):\n%s", diff)
+	}
+	assert.Contains(t, components["schemas"], "UserRequest")
+}
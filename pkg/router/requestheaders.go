@@ -0,0 +1,76 @@
+package router
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// WithRequiredHeader documents a header requests to the route must carry,
+// e.g. X-Tenant-ID or X-Client-Version, and rejects requests missing it, or
+// whose value doesn't match the schema, with a structured 400 before the
+// handler runs. The schema is an example value of the header's type, e.g. ""
+// or 0 (defaults to a string).
+func (rc *RouteConfig) WithRequiredHeader(name, description string, schema any) *RouteConfig {
+	rc.parameters = append(rc.parameters, requiredHeader(name, description, schema))
+	rc.requiredHeaders = append(rc.requiredHeaders, name)
+	return rc
+}
+
+// WithRequiredHeader requires a header on every route of the group, like
+// RouteConfig.WithRequiredHeader
+func (g *RouteGroup) WithRequiredHeader(name, description string, schema any) *RouteGroup {
+	g.parameters = append(g.parameters, requiredHeader(name, description, schema))
+	g.requiredHeaders = append(g.requiredHeaders, name)
+	return g
+}
+
+// requiredHeader documents a required header parameter
+func requiredHeader(name, description string, schema any) Parameter {
+	if schema == nil {
+		schema = ""
+	}
+	return Parameter{Name: name, In: "header", Description: description, Schema: schema, Required: true}
+}
+
+// validateHeaders wraps a handler so that the required headers are checked
+// against their declared schemas before the handler runs
+func validateHeaders(params []Parameter, required []string, next http.Handler) http.Handler {
+	var headers []Parameter
+	schemas := map[string]map[string]any{}
+	for _, param := range params {
+		if param.In != "header" || !slices.ContainsFunc(required, func(name string) bool {
+			return strings.EqualFold(name, param.Name)
+		}) {
+			continue
+		}
+		headers = append(headers, param)
+		schemas[param.Name] = parameterSchema(param)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var errs []invalidValue
+		for _, param := range headers {
+			var msg *message
+			switch values := r.Header.Values(param.Name); {
+			case len(values) == 0 || strings.TrimSpace(values[0]) == "":
+				msg = newMessage("is required")
+			case len(values) > 1:
+				msg = newMessage("must not be repeated")
+			default:
+				msg = validateValue(strings.TrimSpace(values[0]), schemas[param.Name])
+			}
+
+			if msg != nil {
+				errs = append(errs, invalidValue{field: param.Name, in: "header", msg: msg})
+			}
+		}
+
+		if len(errs) > 0 {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request parameters", errs)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,86 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredHeaders(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Group("/reports").
+		WithRequiredHeader("X-Tenant-ID", "Tenant the request acts on", nil).
+		Get("", func(w http.ResponseWriter, r *http.Request) {}).
+		WithRequiredHeader("X-Client-Version", "Build number of the calling client", 0).
+		Register()
+
+	operation := r.OpenAPI().Generate()["paths"].(map[string]any)["/reports"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{
+			"name":        "X-Tenant-ID",
+			"in":          "header",
+			"description": "Tenant the request acts on",
+			"required":    true,
+			"schema":      map[string]any{"type": "string"},
+		},
+		map[string]any{
+			"name":        "X-Client-Version",
+			"in":          "header",
+			"description": "Build number of the calling client",
+			"required":    true,
+			"schema":      map[string]any{"type": "integer"},
+		},
+	}, operation["parameters"])
+	assert.Contains(t, operation["responses"], "400")
+
+	for name, tc := range map[string]struct {
+		headers    map[string][]string
+		wantErrors []ValidationError
+	}{
+		"valid": {
+			headers: map[string][]string{"X-Tenant-Id": {"acme"}, "X-Client-Version": {"42"}},
+		},
+		"missing": {
+			headers: map[string][]string{"X-Tenant-Id": {" "}},
+			wantErrors: []ValidationError{
+				{Field: "X-Tenant-ID", In: "header", Code: CodeRequired, Message: "is required"},
+				{Field: "X-Client-Version", In: "header", Code: CodeRequired, Message: "is required"},
+			},
+		},
+		"invalid": {
+			headers: map[string][]string{"X-Tenant-Id": {"acme", "globex"}, "X-Client-Version": {"latest"}},
+			wantErrors: []ValidationError{
+				{Field: "X-Tenant-ID", In: "header", Code: CodeNotRepeated, Message: "must not be repeated"},
+				{Field: "X-Client-Version", In: "header", Code: CodeType, Message: "must be an integer"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			for name, values := range tc.headers {
+				req.Header[name] = values
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if tc.wantErrors == nil {
+				assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+				return
+			}
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			var resp ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tc.wantErrors, resp.Errors)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// RequestIDHeader carries the ID of a request. The router takes it from the
+// request when the caller or a proxy set it, and sets it on every response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the IDs accepted from requests
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// RequestID returns the ID of the request being served, as written in error
+// responses and the X-Request-ID response header, so handlers can log it
+// along with their errors. It is empty outside of the router.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDMiddleware assigns every request an ID: the one the caller sent,
+// the trace ID of its traceparent header, or a new one
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// mounted routers keep the ID of the parent's request
+		if RequestID(r.Context()) != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id := incomingRequestID(r.Header)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// incomingRequestID returns the ID of the request set by the caller or a
+// proxy, preferring X-Request-ID over the trace ID of a W3C traceparent
+// header; malformed IDs are ignored
+func incomingRequestID(header http.Header) string {
+	if id := header.Get(RequestIDHeader); validRequestID(id) {
+		return id
+	}
+
+	// traceparent is version-traceid-parentid-flags
+	parts := strings.Split(header.Get("traceparent"), "-")
+	if len(parts) == 4 && len(parts[1]) == 32 && isLowerHex(parts[1]) && strings.Trim(parts[1], "0") != "" {
+		return parts[1]
+	}
+	return ""
+}
+
+// validRequestID reports whether a request ID can be echoed in responses
+// and logs as is
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("-_.:/+=", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isLowerHex reports whether s only has lowercase hexadecimal digits
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random ID in the format of W3C trace IDs
+func newRequestID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
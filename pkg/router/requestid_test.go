@@ -0,0 +1,100 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	for name, tc := range map[string]struct {
+		header http.Header
+		want   string // empty for a generated ID
+	}{
+		"generated": {},
+		"from the caller": {
+			header: http.Header{"X-Request-Id": {"req-1"}},
+			want:   "req-1",
+		},
+		"trace id of traceparent": {
+			header: http.Header{"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+			want:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		"caller's id over traceparent": {
+			header: http.Header{
+				"X-Request-Id": {"req-1"},
+				"Traceparent":  {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			},
+			want: "req-1",
+		},
+		"unsafe id replaced": {
+			header: http.Header{"X-Request-Id": {"req 1\r\nSet-Cookie: a=b"}},
+		},
+		"too long id replaced": {
+			header: http.Header{"X-Request-Id": {strings.Repeat("a", maxRequestIDLength+1)}},
+		},
+		"invalid traceparent ignored": {
+			header: http.Header{"Traceparent": {"00-00000000000000000000000000000000-00f067aa0ba902b7-01"}},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var seen string
+			r := NewDocRouter()
+			r.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestID(r.Context())
+			}).Register()
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			assert.Equal(t, id, seen, "handlers see the ID of the response")
+			if tc.want != "" {
+				assert.Equal(t, tc.want, id)
+			} else {
+				assert.Regexp(t, generated, id)
+			}
+		})
+	}
+
+	t.Run("error bodies", func(t *testing.T) {
+		t.Parallel()
+
+		child := NewDocRouter()
+		child.Get("/users", func(w http.ResponseWriter, r *http.Request) {}).
+			WithParameter(Parameter{Name: "limit", In: "query", Schema: 0}).
+			WithQueryValidation().
+			Register()
+
+		r := NewDocRouter()
+		r.Mount("/admin", child)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/users?limit=ten", nil))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body ValidationErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, rec.Header().Get(RequestIDHeader), body.RequestID, "mounted routers keep the parent's ID")
+		assert.Regexp(t, generated, body.RequestID)
+	})
+
+	assert.Empty(t, RequestID(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
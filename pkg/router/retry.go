@@ -0,0 +1,82 @@
+package router
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// RetryPolicy describes how clients should retry a route's failed calls. It
+// is not enforced by the router, only documented under the x-retry extension
+// of the route's operation for clients to configure themselves.
+type RetryPolicy struct {
+	MaxAttempts    int           // Calls made in total, including the first one
+	InitialBackoff time.Duration // Wait before the first retry, doubling on each retry after
+	MaxBackoff     time.Duration // Upper bound of the wait between retries (zero for no bound)
+	RetryOn        []int         // Status codes worth retrying (empty for the client's defaults)
+}
+
+// WithTimeoutHint documents the time clients should wait for the route to
+// respond under the x-timeout extension of its operation
+func (rc *RouteConfig) WithTimeoutHint(timeout time.Duration) *RouteConfig {
+	if timeout <= 0 {
+		panic(fmt.Sprintf("router: timeout hint of %s %s must be positive, got %s", rc.method, rc.path, timeout))
+	}
+
+	rc.timeoutHint = timeout
+	return rc
+}
+
+// WithRetryPolicy documents how clients should retry the route's failed
+// calls under the x-retry extension of its operation
+func (rc *RouteConfig) WithRetryPolicy(policy RetryPolicy) *RouteConfig {
+	if err := policy.validate(); err != nil {
+		panic(fmt.Sprintf("router: invalid retry policy of %s %s: %v", rc.method, rc.path, err))
+	}
+
+	rc.retryPolicy = &policy
+	return rc
+}
+
+// validate checks that clients can follow the policy
+func (p RetryPolicy) validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1, got %d", p.MaxAttempts)
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return fmt.Errorf("backoffs can't be negative")
+	}
+	if p.MaxBackoff > 0 && p.MaxBackoff < p.InitialBackoff {
+		return fmt.Errorf("max backoff %s is below the initial backoff %s", p.MaxBackoff, p.InitialBackoff)
+	}
+	for _, status := range p.RetryOn {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("invalid status code %d", status)
+		}
+	}
+	return nil
+}
+
+// timeoutExtension documents a timeout hint under x-timeout
+func timeoutExtension(timeout time.Duration) map[string]any {
+	return map[string]any{
+		"timeoutMs": timeout.Milliseconds(),
+	}
+}
+
+// retryExtension documents a retry policy under x-retry
+func retryExtension(p RetryPolicy) map[string]any {
+	extension := map[string]any{
+		"maxAttempts":      p.MaxAttempts,
+		"initialBackoffMs": p.InitialBackoff.Milliseconds(),
+	}
+	if p.MaxBackoff > 0 {
+		extension["maxBackoffMs"] = p.MaxBackoff.Milliseconds()
+	}
+	if len(p.RetryOn) > 0 {
+		retryOn := slices.Clone(p.RetryOn)
+		slices.Sort(retryOn)
+		extension["retryOn"] = slices.Compact(retryOn)
+	}
+	return extension
+}
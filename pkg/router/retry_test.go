@@ -0,0 +1,77 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientHints(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Get("/todos", noop).
+		WithTimeoutHint(2 * time.Second).
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     time.Second,
+			RetryOn:        []int{503, 429, 503},
+		}).
+		Register()
+	r.Post("/todos", noop).WithRetryPolicy(RetryPolicy{MaxAttempts: 1}).Register()
+	r.Delete("/todos/{id}", noop).Register()
+
+	paths := r.OpenAPI().Generate()["paths"].(map[string]any)
+	list := paths["/todos"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, map[string]any{"timeoutMs": int64(2000)}, list["x-timeout"])
+	assert.Equal(t, map[string]any{
+		"maxAttempts":      3,
+		"initialBackoffMs": int64(100),
+		"maxBackoffMs":     int64(1000),
+		"retryOn":          []int{429, 503},
+	}, list["x-retry"])
+
+	create := paths["/todos"].(map[string]any)["post"].(map[string]any)
+	assert.NotContains(t, create, "x-timeout")
+	assert.Equal(t, map[string]any{"maxAttempts": 1, "initialBackoffMs": int64(0)}, create["x-retry"],
+		"a single attempt tells clients not to retry")
+
+	remove := paths["/todos/{id}"].(map[string]any)["delete"].(map[string]any)
+	assert.NotContains(t, remove, "x-timeout")
+	assert.NotContains(t, remove, "x-retry")
+
+	for name, tc := range map[string]struct {
+		policy    RetryPolicy
+		wantPanic string
+	}{
+		"no attempts": {
+			wantPanic: "router: invalid retry policy of GET /items: max attempts must be at least 1, got 0",
+		},
+		"max backoff below initial": {
+			policy:    RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Second, MaxBackoff: time.Millisecond},
+			wantPanic: "router: invalid retry policy of GET /items: max backoff 1ms is below the initial backoff 1s",
+		},
+		"invalid status": {
+			policy:    RetryPolicy{MaxAttempts: 2, RetryOn: []int{5030}},
+			wantPanic: "router: invalid retry policy of GET /items: invalid status code 5030",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.PanicsWithValue(t, tc.wantPanic, func() {
+				NewDocRouter().Get("/items", noop).WithRetryPolicy(tc.policy)
+			})
+		})
+	}
+
+	assert.PanicsWithValue(t, "router: timeout hint of GET /items must be positive, got 0s", func() {
+		NewDocRouter().Get("/items", noop).WithTimeoutHint(0)
+	})
+}
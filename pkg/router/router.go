@@ -2,7 +2,19 @@
 package router
 
 import (
+	"context"
+	"fmt"
+	"maps"
 	"net/http"
+	"net/netip"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // RouteResponse represents a documented response for a specific HTTP status code
@@ -11,64 +23,316 @@ type RouteResponse struct {
 	Description string    // Description of the response
 	Schema      any       // Response schema/type (optional)
 	Examples    []Example // Example responses (optional)
+	ContentType string    // Media type of the response (defaults to application/json)
 }
 
-// Example represents an example response for documentation
+// Example represents an example payload for documentation
 type Example struct {
 	ContentType string // Content type of the example (e.g., "application/json")
 	Value       string // Example value as string
+	Ref         string // Name of a registered example component to reference instead (optional)
+}
+
+// Parameter represents a documented non-body parameter of an operation
+type Parameter struct {
+	Name        string   // Name of the parameter
+	In          string   // Location of the parameter ("query", "header", "path" or "cookie")
+	Description string   // Description of the parameter
+	Required    bool     // Whether the parameter must be present
+	Schema      any      // Parameter type (for schema generation), e.g. "" or []string{}
+	Enum        []string // Allowed values (applied to the items of array parameters)
+	Minimum     *float64 // Inclusive lower bound for numeric parameters (optional)
+	Maximum     *float64 // Inclusive upper bound for numeric parameters (optional)
+	Style       string   // Serialization style of arrays and objects (see StyleForm etc.)
+	Explode     *bool    // Whether arrays and objects are exploded (defaults per style)
+	Pattern     string   // Regular expression values must match (optional)
+	MaxItems    int      // Most values of array parameters (zero for no bound)
 }
 
 // RouteInfo stores documentation for a route
 type RouteInfo struct {
-	Method       string                   // HTTP method (GET, POST, etc.)
-	Path         string                   // URL path
-	Name         string                   // Friendly name for the endpoint
-	Description  string                   // Description of what the endpoint does
-	Handler      http.Handler             // The actual handler function
-	RequestType  any                      // Example request type (for schema generation)
-	ResponseType any                      // Example success response type (for schema generation)
-	Responses    map[string]RouteResponse // Map of HTTP status codes to responses
-	Tags         []string                 // Tags for grouping endpoints
+	Method             string                               // HTTP method (GET, POST, etc.)
+	Path               string                               // URL path
+	Name               string                               // Friendly name for the endpoint
+	Description        string                               // Description of what the endpoint does
+	OperationID        string                               // Operation ID in the spec (empty derives it)
+	Handler            http.Handler                         // The actual handler function
+	RequestType        any                                  // Example request type (for schema generation)
+	RequestExamples    []Example                            // Example request payloads (optional)
+	RequestContentType string                               // Media type of the request body (defaults to application/json)
+	RequestOnAnyMethod bool                                 // Whether the request body is documented for GET, HEAD and DELETE
+	RequestRef         string                               // Named request body component documenting the request, replacing RequestType
+	ResponseType       any                                  // Example success response type (for schema generation)
+	ResponseStatus     int                                  // Status code of the success response (zero for 200)
+	Responses          map[string]RouteResponse             // Map of HTTP status codes to responses
+	ResponseRefs       map[string]string                    // Named response components by HTTP status code
+	Parameters         []Parameter                          // Query, header and cookie parameters
+	Tags               []string                             // Tags for grouping endpoints
+	Links              map[string]map[string]Link           // Links from responses (by status code and name) to other operations
+	AlternateContent   map[string]map[string]any            // Additional response schemas by status code and media type
+	ContentLanguages   []string                             // Languages documented in the Content-Language header of responses
+	ResponseHeaders    map[string]map[string]ResponseHeader // Response headers by status code and name
+	ErrorCodes         []ErrorCode                          // Application error codes the route responds with
+	Security           []SecurityRequirement                // Alternative security requirements, any of which authenticates requests
+	ExternalDocs       *ExternalDocs                        // Documentation of the operation, if any
+	Servers            []Server                             // Servers of the operation, replacing the router's
+	CacheControl       string                               // Cache-Control directives of successful responses, if declared
+	RequiredHeaders    []string                             // Headers requests are rejected without, documented in Parameters
+	MinClientVersion   string                               // Oldest client version served, older ones get a 426 (empty if not gated)
+	Consumes           []string                             // Request body media types enforced with a 415, when negotiating content
+	Produces           []string                             // Success response media types enforced with a 406, when negotiating content
+
+	QueryValidation bool   // Whether query parameters are validated before the handler runs
+	BodyValidation  bool   // Whether request bodies are validated before the handler runs
+	Version         string // API version served by the handler (empty for the default)
+	VersionHeader   string // Request header used to select the version
+	TenantScoped    bool   // Whether the route lives under TenantPathPrefix
+	Paginated       bool   // Whether the route lists a collection under the router's collection policy
+	Shadowed        bool   // Whether requests are replayed against a shadow handler
+	Canary          bool   // Whether part of the traffic goes to a canary handler
+
+	Transport        *TransportPolicy // Transport the route must be served over, if restricted
+	LatencyThreshold time.Duration    // Latency above which requests are reported as slow (zero for the router's)
+	ConcurrencyLimit int              // Requests served at once before rejecting with 503 (zero for no limit)
+	TimeoutHint      time.Duration    // Time clients should wait for a response (zero if not documented)
+	RetryPolicy      *RetryPolicy     // How clients should retry failed calls, if documented
+
+	TypedHandler TypedHandler // The registered handler, if it knows its request and response types
+}
+
+// Key identifies the route by method, path and version, e.g. "GET /todos"
+// or "GET /todos version 2"
+func (ri RouteInfo) Key() string {
+	key := ri.Method + " " + ri.Path
+	if ri.Version != "" {
+		key += " version " + ri.Version
+	}
+	return key
+}
+
+// documents reports whether the route documents a response for the status
+// code, inline or referenced
+func (ri RouteInfo) documents(statusCode string) bool {
+	_, inline := ri.Responses[statusCode]
+	_, ref := ri.ResponseRefs[statusCode]
+	return inline || ref
+}
+
+// successStatus is the status code the success response is documented under
+func (ri RouteInfo) successStatus() string {
+	if ri.ResponseStatus == 0 {
+		return "200"
+	}
+	return strconv.Itoa(ri.ResponseStatus)
+}
+
+// routeInfoKey is the context key under which the matched route is stored
+type routeInfoKey struct{}
+
+// MatchedRoute returns the route serving the request, as registered. It is
+// available to handlers and route middleware, but not to router middleware
+// added with Use, which runs before the route is matched.
+func MatchedRoute(ctx context.Context) (RouteInfo, bool) {
+	info, ok := ctx.Value(routeInfoKey{}).(*RouteInfo)
+	if !ok {
+		return RouteInfo{}, false
+	}
+	return *info, true
+}
+
+// routeInfoMiddleware stores the route in the request context; it is bound
+// to the route's handler chain at registration, so requests never look it up
+func routeInfoMiddleware(info *RouteInfo, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeInfoKey{}, info)))
+	})
 }
 
 // RouteConfig is a builder for route configuration
 type RouteConfig struct {
-	router       *DocRouter
-	method       string
-	path         string
-	handler      http.HandlerFunc
-	name         string
-	description  string
-	requestType  any
-	responseType any
-	responses    map[string]RouteResponse
-	tags         []string
+	router             *DocRouter
+	method             string
+	path               string
+	handler            http.Handler
+	middleware         []func(http.Handler) http.Handler
+	name               string
+	description        string
+	operationID        string
+	requestType        any
+	requestExamples    []Example
+	requestContentType string
+	requestOnAnyMethod bool
+	requestRef         string
+	responseType       any
+	responseStatus     int
+	responses          map[string]RouteResponse
+	responseRefs       map[string]string
+	parameters         []Parameter
+	tags               []string
+	links              map[string]map[string]Link
+	alternateContent   map[string]map[string]any
+	contentLanguages   []string
+	responseHeaders    map[string]map[string]ResponseHeader
+	errorCodes         []ErrorCode
+	security           []SecurityRequirement
+	externalDocs       *ExternalDocs
+	servers            []Server
+	cacheControl       string
+	requiredHeaders    []string
+	clientGate         *clientGate
+	contentNegotiation bool
+	shadow             http.HandlerFunc
+	canary             http.HandlerFunc
+	canaryPercent      int
+	transport          *TransportPolicy
+	securityHeaders    *SecurityHeaders
+	ipFilter           *IPFilter
+	latencyThreshold   time.Duration
+	concurrencyLimit   int
+	timeoutHint        time.Duration
+	retryPolicy        *RetryPolicy
+
+	queryValidation bool
+	bodyValidation  bool
+	version         string
+	tenantScoped    bool
+	paginated       bool
 }
 
+// methods are the HTTP methods routes can be registered for, those of the
+// operations of an OpenAPI path item
+var methods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+// defaultResponse is the key of the response documenting every status code
+// not documented otherwise
+const defaultResponse = "default"
+
+// DefaultVersionHeader is the request header used to select a route version
+const DefaultVersionHeader = "Accept-Version"
+
 // DocRouter wraps http.ServeMux to add documentation capabilities
 type DocRouter struct {
-	mux    *http.ServeMux
-	routes []RouteInfo
+	mux             *http.ServeMux
+	handler         http.Handler
+	buildOnce       sync.Once
+	serving         atomic.Bool
+	middleware      []func(http.Handler) http.Handler
+	routes          []RouteInfo
+	unregistered    []*RouteConfig
+	parameters      []Parameter
+	versionHeader   string
+	dispatchers     map[string]*versionDispatcher
+	tenantValidator TenantValidator
+	shadowReporter  ShadowReporter
+	exampleRecorder *ExampleRecorder
+	canaryHeader    string
+	canaryMutex     sync.RWMutex
+	canaries        map[string]*canary
+	transport       *TransportPolicy
+	securityHeaders *SecurityHeaders
+	ipFilter        *IPFilter
+	cors            *CORSPolicy
+	operationIDFunc func(RouteInfo) string
+	messageCatalogs map[string]MessageCatalog
+	clientVersions  clientVersionCounter
+	fragments       []map[string]any
+	specTransforms  []SpecTransform
+	compression     *CompressionPolicy
+	securitySchemes map[string]SecurityScheme
+	collection      *CollectionPolicy
+	externalDocs    *ExternalDocs
+	tags            []TagInfo
+	servers         []Server
+	trustedProxies  []netip.Prefix
+	slowThreshold   time.Duration
+	slowReporter    SlowRequestReporter
+	timeRequests    bool
+	info            specInfo
+	mounts          []mount
+	defaultResponse *RouteResponse
+
+	concurrencyLimiter *concurrencyLimiter
+	routeLimiters      []*concurrencyLimiter
+
+	middlewareTiming bool
+	routerLayers     []*middlewareLayer
+	routeLayers      []*middlewareLayer
 }
 
 // NewDocRouter creates a new documented router
 func NewDocRouter() *DocRouter {
 	return &DocRouter{
-		mux:    http.NewServeMux(),
-		routes: []RouteInfo{},
+		mux:           http.NewServeMux(),
+		routes:        []RouteInfo{},
+		versionHeader: DefaultVersionHeader,
+		canaryHeader:  DefaultCanaryHeader,
+		dispatchers:   make(map[string]*versionDispatcher),
 	}
 }
 
+// WithVersionHeader changes the request header used to select route versions
+func (dr *DocRouter) WithVersionHeader(name string) *DocRouter {
+	dr.versionHeader = name
+	return dr
+}
+
 // Route starts a route configuration chain
 func (dr *DocRouter) Route(method, path string, handler http.HandlerFunc) *RouteConfig {
-	return &RouteConfig{
+	return dr.Handle(method, path, handler)
+}
+
+// Get starts a route configuration chain for a GET route
+func (dr *DocRouter) Get(path string, handler http.HandlerFunc) *RouteConfig {
+	return dr.Route(http.MethodGet, path, handler)
+}
+
+// Post starts a route configuration chain for a POST route
+func (dr *DocRouter) Post(path string, handler http.HandlerFunc) *RouteConfig {
+	return dr.Route(http.MethodPost, path, handler)
+}
+
+// Put starts a route configuration chain for a PUT route
+func (dr *DocRouter) Put(path string, handler http.HandlerFunc) *RouteConfig {
+	return dr.Route(http.MethodPut, path, handler)
+}
+
+// Patch starts a route configuration chain for a PATCH route
+func (dr *DocRouter) Patch(path string, handler http.HandlerFunc) *RouteConfig {
+	return dr.Route(http.MethodPatch, path, handler)
+}
+
+// Delete starts a route configuration chain for a DELETE route
+func (dr *DocRouter) Delete(path string, handler http.HandlerFunc) *RouteConfig {
+	return dr.Route(http.MethodDelete, path, handler)
+}
+
+// Head starts a route configuration chain for a HEAD route
+func (dr *DocRouter) Head(path string, handler http.HandlerFunc) *RouteConfig {
+	return dr.Route(http.MethodHead, path, handler)
+}
+
+// Options starts a route configuration chain for an OPTIONS route
+func (dr *DocRouter) Options(path string, handler http.HandlerFunc) *RouteConfig {
+	return dr.Route(http.MethodOptions, path, handler)
+}
+
+// Handle starts a route configuration chain for any http.Handler. Handlers
+// implementing TypedHandler are checked against the documentation by Verify.
+func (dr *DocRouter) Handle(method, path string, handler http.Handler) *RouteConfig {
+	rc := &RouteConfig{
 		router:    dr,
 		method:    method,
 		path:      path,
 		handler:   handler,
 		responses: make(map[string]RouteResponse),
 	}
+
+	// tracked until registered, for Verify to report forgotten Register calls
+	dr.unregistered = append(dr.unregistered, rc)
+	return rc
 }
 
 // WithName adds a name to the route
@@ -89,14 +353,64 @@ func (rc *RouteConfig) WithRequest(requestType any) *RouteConfig {
 	return rc
 }
 
+// WithRequestContentType changes the media type of the request body, e.g. to
+// multipart/form-data for file uploads
+func (rc *RouteConfig) WithRequestContentType(contentType string) *RouteConfig {
+	rc.requestContentType = contentType
+	return rc
+}
+
+// WithRequestOnAnyMethod documents the request type even when the method is
+// GET, HEAD or DELETE, for routes such as searches that take a GET body.
+// TRACE requests are never documented with one.
+func (rc *RouteConfig) WithRequestOnAnyMethod() *RouteConfig {
+	rc.requestOnAnyMethod = true
+	return rc
+}
+
+// WithRequestExamples adds example request payloads to the route
+func (rc *RouteConfig) WithRequestExamples(examples ...Example) *RouteConfig {
+	rc.requestExamples = append(rc.requestExamples, examples...)
+	return rc
+}
+
 // WithResponse adds a success response type to the route
 func (rc *RouteConfig) WithResponse(responseType any) *RouteConfig {
 	rc.responseType = responseType
 	return rc
 }
 
+// WithResponseStatus documents the success response under another status
+// code than 200, e.g. http.StatusCreated; 204 responses have no content
+func (rc *RouteConfig) WithResponseStatus(statusCode int) *RouteConfig {
+	rc.responseStatus = statusCode
+	return rc
+}
+
+// WithResponseFor documents one of several success responses, e.g. a 200
+// with the full resource and a 202 with a job reference when the work is
+// deferred; call it once per status code. Routes documenting their success
+// responses this way don't get the default 200.
+func (rc *RouteConfig) WithResponseFor(statusCode int, description string, schema any) *RouteConfig {
+	status := strconv.Itoa(statusCode)
+	delete(rc.responseRefs, status)
+	rc.responses[status] = RouteResponse{
+		StatusCode:  status,
+		Description: description,
+		Schema:      schema,
+	}
+	return rc
+}
+
+// WithDefaultResponse documents the response of every status code the route
+// doesn't document otherwise, under the OpenAPI "default" key
+func (rc *RouteConfig) WithDefaultResponse(description string, schema any, examples ...Example) *RouteConfig {
+	return rc.WithErrorResponse(defaultResponse, description, schema, examples...)
+}
+
 // WithErrorResponse adds an error response to the route
 func (rc *RouteConfig) WithErrorResponse(statusCode, description string, schema any, examples ...Example) *RouteConfig {
+	delete(rc.responseRefs, statusCode)
 	rc.responses[statusCode] = RouteResponse{
 		StatusCode:  statusCode,
 		Description: description,
@@ -106,57 +420,455 @@ func (rc *RouteConfig) WithErrorResponse(statusCode, description string, schema
 	return rc
 }
 
-// WithTags adds tags to the route
+// WithResponseRef documents a response with a component registered on the
+// generator through RegisterResponse, replacing any other response of the
+// status code
+func (rc *RouteConfig) WithResponseRef(statusCode, responseName string) *RouteConfig {
+	if rc.responseRefs == nil {
+		rc.responseRefs = make(map[string]string)
+	}
+
+	delete(rc.responses, statusCode)
+	rc.responseRefs[statusCode] = responseName
+	return rc
+}
+
+// WithContentResponse adds a response with a media type other than
+// application/json; a nil schema documents binary content
+func (rc *RouteConfig) WithContentResponse(statusCode, description, contentType string, schema any) *RouteConfig {
+	delete(rc.responseRefs, statusCode)
+	rc.responses[statusCode] = RouteResponse{
+		StatusCode:  statusCode,
+		Description: description,
+		Schema:      schema,
+		ContentType: contentType,
+	}
+	return rc
+}
+
+// WithAlternateContent documents another media type a response can be
+// served in, for handlers that negotiate the representation on the Accept
+// header. It only applies to status codes that are otherwise documented.
+func (rc *RouteConfig) WithAlternateContent(statusCode, contentType string, schema any) *RouteConfig {
+	if rc.alternateContent == nil {
+		rc.alternateContent = make(map[string]map[string]any)
+	}
+	if rc.alternateContent[statusCode] == nil {
+		rc.alternateContent[statusCode] = make(map[string]any)
+	}
+
+	rc.alternateContent[statusCode][contentType] = schema
+	return rc
+}
+
+// WithQueryParam documents an optional query parameter for the route
+func (rc *RouteConfig) WithQueryParam(name, description string, schema any) *RouteConfig {
+	return rc.WithParameter(Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      schema,
+	})
+}
+
+// WithParameter documents a parameter for the route
+func (rc *RouteConfig) WithParameter(param Parameter) *RouteConfig {
+	rc.parameters = append(rc.parameters, param)
+	return rc
+}
+
+// WithPathParam documents a parameter of the route's path
+func (rc *RouteConfig) WithPathParam(name, description string) *RouteConfig {
+	return rc.WithParameter(Parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Schema:      "",
+	})
+}
+
+// WithQueryValidation validates query parameters against their declared
+// schemas before the handler runs, responding with a structured 400 otherwise
+func (rc *RouteConfig) WithQueryValidation() *RouteConfig {
+	rc.queryValidation = true
+	return rc
+}
+
+// WithRequestValidation validates JSON request bodies against the schema of
+// the request type before the handler runs, responding with a structured 400
+// for malformed JSON and 422 for bodies not matching the schema. Bodies of
+// other media types are not validated.
+func (rc *RouteConfig) WithRequestValidation() *RouteConfig {
+	rc.bodyValidation = true
+	return rc
+}
+
+// WithVersion marks the handler as serving the given API version. Several
+// handlers can be registered for the same method and path with different
+// versions; requests are dispatched on the router's version header.
+func (rc *RouteConfig) WithVersion(version string) *RouteConfig {
+	rc.version = version
+	return rc
+}
+
+// WithTags adds tags to the route, after those of its group
 func (rc *RouteConfig) WithTags(tags ...string) *RouteConfig {
-	rc.tags = tags
+	for _, tag := range tags {
+		if !slices.Contains(rc.tags, tag) {
+			rc.tags = append(rc.tags, tag)
+		}
+	}
 	return rc
 }
 
-// Register finalizes the route configuration and registers it with the router
-func (rc *RouteConfig) Register() {
+// Register finalizes the route configuration and registers it with the router.
+// The path is normalized first, so "todos/" and "/todos" are the same route;
+// it panics if the method is not one OpenAPI can document, the path contains
+// whitespace or the route is already registered.
+// Path parameters written as {name:pattern} only accept values matching the
+// pattern, other values are rejected with a structured 400.
+// The returned RouteRef adjusts the route's documentation afterwards.
+func (rc *RouteConfig) Register() *RouteRef {
+	rc.router.unregistered = slices.DeleteFunc(rc.router.unregistered, func(pending *RouteConfig) bool {
+		return pending == rc
+	})
+
+	if !slices.Contains(methods, rc.method) {
+		panic(fmt.Sprintf("router: unknown method %q of route %s, must be one of %s",
+			rc.method, rc.path, strings.Join(methods, ", ")))
+	}
+
+	rc.parameters = addBindingParameters(rc.parameters, rc.requestType)
+
+	path, err := normalizePath(rc.path)
+	if err == nil {
+		var constraints []Parameter
+		path, constraints, err = pathConstraints(path)
+		rc.parameters = constrainPathParams(rc.parameters, constraints)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("router: invalid path %q of %s route: %v", rc.path, rc.method, err))
+	}
+	if rc.tenantScoped {
+		path = TenantPathPrefix + path
+	}
+
 	// Create the Go 1.22 pattern with method
-	pattern := rc.method + " " + rc.path
+	pattern := rc.method + " " + path
 
-	// Register the handler with ServeMux
-	rc.router.mux.Handle(pattern, rc.handler)
+	typedHandler, _ := rc.handler.(TypedHandler)
+
+	route := RouteInfo{Method: rc.method, Path: path, Version: rc.version}.Key()
+
+	// the documentation is complete once the handler chain is built
+	info := &RouteInfo{}
+
+	var handler http.Handler = rc.handler
+	if rc.canary != nil {
+		handler = canaryMiddleware(rc.router, route, rc.canaryPercent, rc.canary, handler)
+	}
+	if rc.cacheControl != "" {
+		handler = cacheControlMiddleware(rc.cacheControl, handler)
+	}
+	layers := rc.newRouteLayers(route)
+	handler = timedHandler(rc.router, layers[len(rc.middleware)], handler)
+	handler = timingMiddleware(rc.router, route, rc.latencyThreshold, handler)
+	if rc.latencyThreshold > 0 {
+		rc.router.timeRequests = true
+	}
+	if rc.shadow != nil {
+		handler = shadowMiddleware(rc.router, rc.shadow, handler)
+	}
+	handler = exampleRecordingMiddleware(rc.router, info, handler)
+	// only JSON bodies and JSON Patch documents are validated
+	bodyValidation := rc.bodyValidation && rc.requestType != nil &&
+		(rc.requestContentType == "" || rc.requestContentType == "application/json" ||
+			rc.requestContentType == JSONPatchContentType)
+	switch {
+	case bodyValidation && rc.requestContentType == JSONPatchContentType:
+		handler = validatePatch(handler)
+	case bodyValidation:
+		handler = validateBody(rc.requestType, handler)
+	}
+	if rc.queryValidation {
+		handler = validateQuery(rc.parameters, handler)
+	}
+	if len(rc.requiredHeaders) > 0 {
+		handler = validateHeaders(rc.parameters, rc.requiredHeaders, handler)
+	}
+	if rc.paginated {
+		handler = paginationMiddleware(rc.router, rc.parameters, handler)
+	}
+	handler = validatePath(rc.router, rc.parameters, handler)
+	var consumes, produces []string
+	if rc.contentNegotiation {
+		consumes, produces = rc.consumedMediaTypes(), rc.producedMediaTypes()
+		handler = contentNegotiationMiddleware(consumes, produces, handler)
+	}
+	// stale clients are told to upgrade before their requests are validated
+	handler = clientVersionMiddleware(rc.router, rc.clientGate, handler)
+	if rc.tenantScoped {
+		handler = tenantMiddleware(rc.router, handler)
+	}
+	for i := len(rc.middleware) - 1; i >= 0; i-- {
+		handler = timedMiddleware(rc.router, layers[i], rc.middleware[i], handler)
+	}
+	var limiter *concurrencyLimiter
+	if rc.concurrencyLimit != 0 {
+		limiter = newConcurrencyLimiter(route, rc.concurrencyLimit)
+		rc.router.routeLimiters = append(rc.router.routeLimiters, limiter)
+	}
+	handler = concurrencyMiddleware(rc.router, limiter, handler)
+	handler = ipFilterMiddleware(rc.router, rc.ipFilter, handler)
+	handler = transportMiddleware(rc.router, rc.transport, handler)
+	handler = securityHeadersMiddleware(rc.router, rc.securityHeaders, handler)
+	handler = routeInfoMiddleware(info, handler)
+
+	// Register the handler with ServeMux, through the pattern's version dispatcher
+	dispatcher, exists := rc.router.dispatchers[pattern]
+	if !exists {
+		dispatcher = &versionDispatcher{router: rc.router}
+		rc.router.dispatchers[pattern] = dispatcher
+		rc.router.mux.Handle(pattern, dispatcher)
+	}
+	dispatcher.add(pattern, rc.version, handler, info)
 
 	// Add documentation
-	rc.router.routes = append(rc.router.routes, RouteInfo{
-		Method:       rc.method,
-		Path:         rc.path,
-		Name:         rc.name,
-		Description:  rc.description,
-		Handler:      rc.handler,
-		RequestType:  rc.requestType,
-		ResponseType: rc.responseType,
-		Responses:    rc.responses,
-		Tags:         rc.tags,
+	var minClientVersion string
+	if rc.clientGate != nil {
+		minClientVersion = rc.clientGate.minVersion
+	}
+	*info = RouteInfo{
+		Method:             rc.method,
+		Path:               path,
+		Name:               rc.name,
+		Description:        rc.description,
+		OperationID:        rc.operationID,
+		Handler:            handler,
+		RequestType:        rc.requestType,
+		RequestExamples:    rc.requestExamples,
+		RequestContentType: rc.requestContentType,
+		RequestOnAnyMethod: rc.requestOnAnyMethod,
+		RequestRef:         rc.requestRef,
+		ResponseType:       rc.responseType,
+		ResponseStatus:     rc.responseStatus,
+		Responses:          rc.responses,
+		ResponseRefs:       rc.responseRefs,
+		Parameters:         rc.parameters,
+		Tags:               rc.tags,
+		Links:              rc.links,
+		AlternateContent:   rc.alternateContent,
+		ContentLanguages:   rc.contentLanguages,
+		ResponseHeaders:    rc.responseHeaders,
+		ErrorCodes:         rc.errorCodes,
+		Security:           rc.security,
+		ExternalDocs:       rc.externalDocs,
+		Servers:            rc.servers,
+		CacheControl:       rc.cacheControl,
+		RequiredHeaders:    rc.requiredHeaders,
+		MinClientVersion:   minClientVersion,
+		Consumes:           consumes,
+		Produces:           produces,
+
+		QueryValidation: rc.queryValidation,
+		BodyValidation:  bodyValidation,
+		Version:         rc.version,
+		VersionHeader:   rc.router.versionHeader,
+		TenantScoped:    rc.tenantScoped,
+		Paginated:       rc.paginated,
+		Shadowed:        rc.shadow != nil,
+		Canary:          rc.canary != nil,
+
+		Transport:        rc.transport,
+		LatencyThreshold: rc.latencyThreshold,
+		ConcurrencyLimit: rc.concurrencyLimit,
+		TimeoutHint:      rc.timeoutHint,
+		RetryPolicy:      rc.retryPolicy,
+
+		TypedHandler: typedHandler,
+	}
+	rc.router.routes = append(rc.router.routes, *info)
+
+	return &RouteRef{router: rc.router, index: len(rc.router.routes) - 1, info: info}
+}
+
+// WithParameter documents a parameter shared by every route of the router,
+// such as a header consumed by middleware
+func (dr *DocRouter) WithParameter(param Parameter) *DocRouter {
+	dr.parameters = append(dr.parameters, param)
+	return dr
+}
+
+// WithPathParam documents a path parameter shared by the routes whose path
+// uses it, unless a route documents the parameter itself
+func (dr *DocRouter) WithPathParam(name, description string) *DocRouter {
+	return dr.WithParameter(Parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Schema:      "",
 	})
 }
 
-// GetRoutes returns all documented routes
+// WithDefaultResponse documents the "default" response of every route that
+// doesn't document its own, e.g. the error body of unexpected failures, so
+// catch-all errors don't need to be listed on each route
+func (dr *DocRouter) WithDefaultResponse(description string, schema any, examples ...Example) *DocRouter {
+	dr.defaultResponse = &RouteResponse{
+		StatusCode:  defaultResponse,
+		Description: description,
+		Schema:      schema,
+		Examples:    examples,
+	}
+	return dr
+}
+
+// GetRoutes returns all documented routes, followed by those of mounted routers
 func (dr *DocRouter) GetRoutes() []RouteInfo {
-	return dr.routes
+	paginated := slices.ContainsFunc(dr.routes, func(route RouteInfo) bool { return route.Paginated })
+	if len(dr.parameters) == 0 && dr.transport == nil && dr.concurrencyLimiter == nil && len(dr.mounts) == 0 &&
+		dr.defaultResponse == nil && !paginated {
+		return dr.routes
+	}
+
+	routes := slices.Concat(dr.routes, dr.mountedRoutes())
+	for i, route := range routes[:len(dr.routes)] {
+		if route.Paginated {
+			routes[i].Parameters = dr.collectionPolicy().parameters(route.Parameters)
+		}
+	}
+	for i, route := range routes {
+		// add shared parameters after the route's own ones
+		if len(dr.parameters) > 0 {
+			route.Parameters = append(slices.Clip(route.Parameters), dr.parameters...)
+		}
+		if route.Transport == nil {
+			route.Transport = dr.transport
+		}
+		if route.ConcurrencyLimit == 0 && dr.concurrencyLimiter != nil {
+			route.ConcurrencyLimit = cap(dr.concurrencyLimiter.slots)
+		}
+		if dr.defaultResponse != nil && !route.documents(defaultResponse) {
+			route.Responses = maps.Clone(route.Responses)
+			if route.Responses == nil {
+				route.Responses = make(map[string]RouteResponse)
+			}
+			route.Responses[defaultResponse] = *dr.defaultResponse
+		}
+		routes[i] = route
+	}
+
+	return routes
+}
+
+// RouteForPattern returns the route registered for a ServeMux pattern such as
+// "GET /users/{id}", the one http.Request.Pattern holds since Go 1.23 once the
+// request is matched, so middleware can label, authorize or audit requests by
+// route. Routes of mounted routers are found under the mount prefix; for
+// versioned routes, it returns the route serving requests without a version.
+func (dr *DocRouter) RouteForPattern(pattern string) (RouteInfo, bool) {
+	if dispatcher, ok := dr.dispatchers[pattern]; ok {
+		return *dispatcher.route(), true
+	}
+
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return RouteInfo{}, false
+	}
+	for _, m := range dr.mounts {
+		rest, ok := strings.CutPrefix(path, m.prefix)
+		if !ok || !strings.HasPrefix(rest, "/") {
+			continue
+		}
+		if route, ok := m.router.RouteForPattern(method + " " + rest); ok {
+			route.Path = m.prefix + route.Path
+			return route, true
+		}
+	}
+
+	return RouteInfo{}, false
 }
 
 // ServeHTTP makes DocRouter implement the http.Handler interface
 func (dr *DocRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	dr.mux.ServeHTTP(w, r)
+	dr.buildOnce.Do(dr.buildHandler)
+
+	if dr.timeRequests {
+		dr.serveTimed(dr.handler, w, r)
+		return
+	}
+
+	dr.handler.ServeHTTP(w, r)
+}
+
+// MiddlewareNames returns the function names of the router's middleware, in
+// the order it runs
+func (dr *DocRouter) MiddlewareNames() []string {
+	names := make([]string, 0, len(dr.middleware))
+	for _, middleware := range dr.middleware {
+		names = append(names, funcName(middleware))
+	}
+
+	return names
+}
+
+// funcName returns the function name of a middleware, e.g.
+// "github.com/acme/api/internal/auth.Middleware"
+func funcName(middleware func(http.Handler) http.Handler) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "unknown"
 }
 
-// Use allows adding middleware to the router
+// Use adds middleware to the end of the router's middleware stack; it is the
+// same as UseLast.
 func (dr *DocRouter) Use(middleware ...func(http.Handler) http.Handler) {
-	// Create a chain of middleware
-	var handler http.Handler = dr.mux
-	for i := len(middleware) - 1; i >= 0; i-- {
-		handler = middleware[i](handler)
+	dr.UseLast(middleware...)
+}
+
+// UseFirst adds middleware to the start of the router's middleware stack, so
+// it runs before the middleware added so far, in the order given
+func (dr *DocRouter) UseFirst(middleware ...func(http.Handler) http.Handler) {
+	dr.checkNotServing()
+	dr.middleware = append(slices.Clip(middleware), dr.middleware...)
+}
+
+// UseLast adds middleware to the end of the router's middleware stack, so it
+// runs after the middleware added so far, in the order given, and right
+// before the route
+func (dr *DocRouter) UseLast(middleware ...func(http.Handler) http.Handler) {
+	dr.checkNotServing()
+	dr.middleware = append(dr.middleware, middleware...)
+}
+
+// checkNotServing panics once the middleware stack has been applied
+func (dr *DocRouter) checkNotServing() {
+	if dr.serving.Load() {
+		panic("router: middleware added after the router started serving")
 	}
+}
 
-	// Create a new mux that forwards requests to the middleware chain
-	dr.mux = http.NewServeMux()
+// buildHandler applies the middleware stack around the routes. It runs once,
+// on the first request, so the stack wraps every route whether it was
+// registered before or after the middleware was added.
+func (dr *DocRouter) buildHandler() {
+	dr.serving.Store(true)
+
+	var handler http.Handler = dr.mux
+	if dr.middlewareTiming {
+		handler = dr.timedRouterMiddleware(handler)
+	} else {
+		for i := len(dr.middleware) - 1; i >= 0; i-- {
+			handler = dr.middleware[i](handler)
+		}
+	}
+	if dr.compression != nil {
+		handler = compressionMiddleware(*dr.compression, handler)
+	}
+	if dr.cors != nil {
+		handler = corsMiddleware(dr, *dr.cors, handler)
+	}
 
-	// Add a catch-all handler that forwards to the middleware chain
-	dr.mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		handler.ServeHTTP(w, r)
-	}))
+	dr.handler = requestIDMiddleware(messageCatalogMiddleware(dr, handler))
 }
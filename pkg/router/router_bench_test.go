@@ -0,0 +1,38 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// BenchmarkMatchedRoute measures serving a request whose handler reads its
+// route, which should not depend on the number of routes registered
+func BenchmarkMatchedRoute(b *testing.B) {
+	for _, routes := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d routes", routes), func(b *testing.B) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := MatchedRoute(r.Context()); !ok {
+					b.Fatal("route not matched")
+				}
+			}
+
+			r := NewDocRouter()
+			for i := 0; i < routes; i++ {
+				r.Get(fmt.Sprintf("/resources%d/{id}", i), handler).
+					WithCanary(handler, 50).
+					Register()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/resources%d/1", routes-1), nil)
+			w := httptest.NewRecorder()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				r.ServeHTTP(w, req)
+			}
+		})
+	}
+}
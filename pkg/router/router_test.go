@@ -0,0 +1,313 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingMiddleware appends its name to calls whenever it handles a request
+func recordingMiddleware(name string, calls *[]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestUse(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	r := NewDocRouter()
+	r.Route("GET", "/before", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}).Register()
+
+	r.Use(recordingMiddleware("first", &calls))
+	r.Use(recordingMiddleware("second", &calls))
+
+	r.Route("GET", "/after", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}).Register()
+
+	for _, path := range []string{"/before", "/after"} {
+		calls = nil
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if diff := cmp.Diff([]string{"first", "second", "handler"}, calls); diff != "" {
+			t.Errorf("%s: call order mismatch (-want +got):\n%s", path, diff)
+		}
+	}
+}
+
+func TestUseFirstAndLast(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	r := NewDocRouter()
+	r.Use(recordingMiddleware("logger", &calls))
+	r.UseLast(recordingMiddleware("auth", &calls))
+	r.UseFirst(recordingMiddleware("recoverer", &calls), recordingMiddleware("request-id", &calls))
+	r.Route("GET", "/", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	}).Register()
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"recoverer", "request-id", "logger", "auth", "handler"}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Errorf("call order mismatch (-want +got):\n%s", diff)
+	}
+
+	// the stack is applied on the first request
+	assert.PanicsWithValue(t, "router: middleware added after the router started serving", func() {
+		r.Use(recordingMiddleware("late", &calls))
+	})
+}
+
+func TestSharedParameters(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Route("GET", "/items", func(w http.ResponseWriter, r *http.Request) {}).
+		WithQueryParam("q", "Search query", "").
+		Register()
+	r.WithParameter(Parameter{Name: "X-Trace", In: "header", Schema: ""})
+
+	routes := r.GetRoutes()
+	expected := []Parameter{
+		{Name: "q", In: "query", Description: "Search query", Schema: ""},
+		{Name: "X-Trace", In: "header", Schema: ""},
+	}
+
+	if diff := cmp.Diff(expected, routes[0].Parameters); diff != "" {
+		t.Errorf("parameters mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVersionRouting(t *testing.T) {
+	t.Parallel()
+
+	// writeBody returns a handler that writes the given body
+	writeBody := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		setup      func(r *DocRouter)
+		header     string
+		version    string
+		wantStatus int
+		wantBody   string
+	}{
+		"default without header": {
+			setup: func(r *DocRouter) {
+				r.Route("GET", "/items", writeBody("default")).Register()
+				r.Route("GET", "/items", writeBody("v2")).WithVersion("2").Register()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "default",
+		},
+		"requested version": {
+			setup: func(r *DocRouter) {
+				r.Route("GET", "/items", writeBody("default")).Register()
+				r.Route("GET", "/items", writeBody("v2")).WithVersion("2").Register()
+			},
+			header:     DefaultVersionHeader,
+			version:    "2",
+			wantStatus: http.StatusOK,
+			wantBody:   "v2",
+		},
+		"latest without default": {
+			setup: func(r *DocRouter) {
+				r.Route("GET", "/items", writeBody("v1")).WithVersion("1").Register()
+				r.Route("GET", "/items", writeBody("v2")).WithVersion("2").Register()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "v2",
+		},
+		"latest registered first": {
+			setup: func(r *DocRouter) {
+				r.Route("GET", "/items", writeBody("v10")).WithVersion("10").Register()
+				r.Route("GET", "/items", writeBody("v2")).WithVersion("2").Register()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "v10",
+		},
+		"latest date": {
+			setup: func(r *DocRouter) {
+				r.Route("GET", "/items", writeBody("2024")).WithVersion("2024-06-01").Register()
+				r.Route("GET", "/items", writeBody("2023")).WithVersion("2023-01-15").Register()
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "2024",
+		},
+		"custom header": {
+			setup: func(r *DocRouter) {
+				r.WithVersionHeader("X-API-Version")
+				r.Route("GET", "/items", writeBody("v1")).WithVersion("1").Register()
+				r.Route("GET", "/items", writeBody("v2")).WithVersion("2").Register()
+			},
+			header:     "X-API-Version",
+			version:    "1",
+			wantStatus: http.StatusOK,
+			wantBody:   "v1",
+		},
+		"unsupported version": {
+			setup: func(r *DocRouter) {
+				r.Route("GET", "/items", writeBody("v1")).WithVersion("1").Register()
+			},
+			header:     DefaultVersionHeader,
+			version:    "9",
+			wantStatus: http.StatusBadRequest,
+		},
+		"unversioned route ignores header": {
+			setup: func(r *DocRouter) {
+				r.Route("GET", "/items", writeBody("only")).Register()
+			},
+			header:     DefaultVersionHeader,
+			version:    "9",
+			wantStatus: http.StatusOK,
+			wantBody:   "only",
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			tc.setup(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/items", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.version)
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestVersionRoutingDuplicate(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Route("GET", "/items", func(w http.ResponseWriter, r *http.Request) {}).WithVersion("1").Register()
+
+	assert.Panics(t, func() {
+		r.Route("GET", "/items", func(w http.ResponseWriter, r *http.Request) {}).WithVersion("1").Register()
+	})
+}
+
+func TestMatchedRoute(t *testing.T) {
+	t.Parallel()
+
+	var matched []RouteInfo
+	record := func(w http.ResponseWriter, r *http.Request) {
+		info, ok := MatchedRoute(r.Context())
+		assert.True(t, ok)
+		matched = append(matched, info)
+	}
+
+	r := NewDocRouter()
+	r.Route("GET", "/users/{id}", record).WithName("Get User").WithTags("Users").Register()
+	r.Route("GET", "/users/{id}", record).WithName("Get User (v2)").WithVersion("2").Register()
+
+	for _, version := range []string{"", "2"} {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set(DefaultVersionHeader, version)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if assert.Len(t, matched, 2) {
+		assert.Equal(t, "Get User", matched[0].Name)
+		assert.Equal(t, []string{"Users"}, matched[0].Tags)
+		assert.Equal(t, "Get User (v2)", matched[1].Name)
+		assert.Equal(t, "2", matched[1].Version)
+	}
+
+	_, ok := MatchedRoute(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	assert.False(t, ok)
+}
+
+func TestRouteForPattern(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	admin := NewDocRouter()
+	admin.Get("/stats", noop).WithName("Stats").Register()
+
+	r := NewDocRouter()
+	r.Get("/users/{id}", noop).WithName("Get User").Register()
+	r.Get("/users/{id}", noop).WithName("Get User (v2)").WithVersion("2").Register()
+	r.Get("/items", noop).WithName("List Items (v1)").WithVersion("1").Register()
+	r.Get("/items", noop).WithName("List Items (v2)").WithVersion("2").Register()
+	r.Get("/things", noop).WithName("List Things (v2)").WithVersion("2").Register()
+	r.Get("/things", noop).WithName("List Things (v1)").WithVersion("1").Register()
+	r.Mount("/admin", admin)
+
+	for pattern, wantName := range map[string]string{
+		"GET /users/{id}":   "Get User",
+		"GET /items":        "List Items (v2)",
+		"GET /things":       "List Things (v2)",
+		"GET /admin/stats":  "Stats",
+		"POST /users/{id}":  "",
+		"GET /users/{name}": "",
+		"GET /adminstats":   "",
+		"/admin/":           "",
+	} {
+		route, ok := r.RouteForPattern(pattern)
+		assert.Equal(t, wantName != "", ok, pattern)
+		assert.Equal(t, wantName, route.Name, pattern)
+	}
+
+	route, _ := r.RouteForPattern("GET /admin/stats")
+	assert.Equal(t, "/admin/stats", route.Path)
+}
+
+func TestMethodHelpers(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Get("/users", noop).Register()
+	r.Post("/users", noop).Register()
+	r.Put("/users/{id}", noop).Register()
+	r.Patch("/users/{id}", noop).Register()
+	r.Delete("/users/{id}", noop).Register()
+	r.Head("/users", noop).Register()
+	r.Options("/users", noop).Register()
+
+	var keys []string
+	for _, route := range r.GetRoutes() {
+		keys = append(keys, route.Key())
+	}
+	assert.Equal(t, []string{
+		"GET /users", "POST /users", "PUT /users/{id}", "PATCH /users/{id}",
+		"DELETE /users/{id}", "HEAD /users", "OPTIONS /users",
+	}, keys)
+
+	assert.PanicsWithValue(t, `router: unknown method "GETT" of route /users, must be one of GET, PUT, POST, DELETE, OPTIONS, HEAD, PATCH, TRACE`, func() {
+		r.Route("GETT", "/users", noop).Register()
+	})
+}
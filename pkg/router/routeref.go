@@ -0,0 +1,99 @@
+package router
+
+import "slices"
+
+// RouteRef refers to a registered route, for code post-processing routes
+// after registration, e.g. adding the error responses of every route behind
+// an authentication middleware. Adjustments only change the documentation
+// and must be made before the router starts serving.
+type RouteRef struct {
+	router *DocRouter
+	index  int
+	info   *RouteInfo
+}
+
+// Key identifies the route like RouteInfo.Key, as expected by the router's
+// per-route APIs such as SetCanaryPercent and reported in ConcurrencyStatus
+// and SlowRequest
+func (ref *RouteRef) Key() string {
+	return ref.info.Key()
+}
+
+// Method returns the route's method
+func (ref *RouteRef) Method() string {
+	return ref.info.Method
+}
+
+// Path returns the route's path, normalized as registered
+func (ref *RouteRef) Path() string {
+	return ref.info.Path
+}
+
+// Info returns the route as currently documented
+func (ref *RouteRef) Info() RouteInfo {
+	return *ref.info
+}
+
+// WithName changes the route's name
+func (ref *RouteRef) WithName(name string) *RouteRef {
+	return ref.update(func(info *RouteInfo) {
+		info.Name = name
+	})
+}
+
+// WithDescription changes the route's description
+func (ref *RouteRef) WithDescription(description string) *RouteRef {
+	return ref.update(func(info *RouteInfo) {
+		info.Description = description
+	})
+}
+
+// WithTags adds tags to the route, skipping those it already has
+func (ref *RouteRef) WithTags(tags ...string) *RouteRef {
+	return ref.update(func(info *RouteInfo) {
+		for _, tag := range tags {
+			if !slices.Contains(info.Tags, tag) {
+				info.Tags = append(info.Tags, tag)
+			}
+		}
+	})
+}
+
+// WithErrorResponse documents an error response, replacing any other
+// response of the status code (see RouteConfig.WithErrorResponse)
+func (ref *RouteRef) WithErrorResponse(statusCode, description string, schema any, examples ...Example) *RouteRef {
+	return ref.update(func(info *RouteInfo) {
+		if info.Responses == nil {
+			info.Responses = make(map[string]RouteResponse)
+		}
+
+		delete(info.ResponseRefs, statusCode)
+		info.Responses[statusCode] = RouteResponse{
+			StatusCode:  statusCode,
+			Description: description,
+			Schema:      schema,
+			Examples:    examples,
+		}
+	})
+}
+
+// WithResponseRef documents a response with a component registered on the
+// generator (see RouteConfig.WithResponseRef)
+func (ref *RouteRef) WithResponseRef(statusCode, responseName string) *RouteRef {
+	return ref.update(func(info *RouteInfo) {
+		if info.ResponseRefs == nil {
+			info.ResponseRefs = make(map[string]string)
+		}
+
+		delete(info.Responses, statusCode)
+		info.ResponseRefs[statusCode] = responseName
+	})
+}
+
+// update changes the route's documentation, both as listed by GetRoutes and
+// as returned by MatchedRoute
+func (ref *RouteRef) update(fn func(info *RouteInfo)) *RouteRef {
+	fn(ref.info)
+	ref.router.routes[ref.index] = *ref.info
+	return ref
+}
@@ -0,0 +1,60 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteRef(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	var matched RouteInfo
+	refs := []*RouteRef{
+		r.Get("/users/", func(w http.ResponseWriter, r *http.Request) {
+			matched, _ = MatchedRoute(r.Context())
+		}).WithTags("Users").Register(),
+		r.Post("/users", func(w http.ResponseWriter, r *http.Request) {}).
+			WithErrorResponse("401", "Unauthorized", nil).
+			Register(),
+	}
+
+	assert.Equal(t, "GET /users", refs[0].Key())
+	assert.Equal(t, http.MethodPost, refs[1].Method())
+	assert.Equal(t, "/users", refs[1].Path())
+
+	// post-process every route, as a codebase-wide convention would
+	for _, ref := range refs {
+		ref.WithTags("Users", "Admin").
+			WithDescription("Requires an admin token").
+			WithResponseRef("401", "Unauthorized").
+			WithErrorResponse("403", "Forbidden", nil)
+	}
+
+	routes := r.GetRoutes()
+	require.Len(t, routes, 2)
+	for _, route := range routes {
+		assert.Equal(t, []string{"Users", "Admin"}, route.Tags)
+		assert.Equal(t, "Requires an admin token", route.Description)
+		assert.Equal(t, map[string]string{"401": "Unauthorized"}, route.ResponseRefs)
+		assert.NotContains(t, route.Responses, "401")
+		assert.Contains(t, route.Responses, "403")
+	}
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	assert.Equal(t, "Requires an admin token", matched.Description)
+
+	generator := NewOpenAPIGenerator("Test API", "", "1.0.0", routes)
+	generator.RegisterResponse("Unauthorized", map[string]any{"description": "Unauthorized"})
+	generator.RegisterResponse("NotFound", map[string]any{"description": "Not Found"})
+	generator.RegisterRouteRefResponse(refs[0], "404", "NotFound")
+
+	paths := generator.Generate()["paths"].(map[string]any)
+	responses := paths["/users"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/Unauthorized"}, responses["401"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/responses/NotFound"}, responses["404"])
+}
@@ -5,14 +5,59 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// OmitZeroPolicy decides how fields tagged omitzero are documented
+type OmitZeroPolicy int
+
+const (
+	// OmitZeroOptional documents omitzero fields as optional, like omitempty
+	OmitZeroOptional OmitZeroPolicy = iota
+
+	// OmitZeroNullable documents omitzero fields as optional and nullable,
+	// for clients that treat a missing field as null
+	OmitZeroNullable
+
+	// OmitZeroRequired ignores omitzero, documenting the field as required
+	OmitZeroRequired
+)
+
+// PointerPolicy decides how pointer fields without omitempty or omitzero
+// are documented
+type PointerPolicy int
+
+const (
+	// PointersRequired documents pointer fields as required, like values
+	PointersRequired PointerPolicy = iota
+
+	// PointersNullable documents pointer fields as required and nullable,
+	// as encoding/json always writes them and writes nil as null
+	PointersNullable
+
+	// PointersOptional documents pointer fields as optional and nullable
+	PointersOptional
+)
+
+// SchemaOptions controls how Go types are mapped to schemas. A field's
+// `required:"true"` or `required:"false"` tag overrides both policies.
+type SchemaOptions struct {
+	OmitZero OmitZeroPolicy // Requiredness of fields tagged omitzero
+	Pointers PointerPolicy  // Requiredness of pointer fields
+}
+
 // schemaGenerator handles the conversion of Go types to JSON Schema
 type schemaGenerator struct {
 	// processed tracks types already processed to detect circular references
 	processed map[reflect.Type]bool
+
+	// options controls how Go types map to schemas
+	options SchemaOptions
+
+	// issues collects the example tags that don't conform to their schema
+	issues []ExampleIssue
 }
 
 // newSchemaGenerator creates a new schema generator
@@ -33,6 +78,26 @@ func (g *schemaGenerator) generate(t any) map[string]any {
 		typ = typ.Elem()
 	}
 
+	if typ == jsonPatchType {
+		return jsonPatchSchema()
+	}
+
+	// handle collection types
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return g.processArrayField(typ)
+	case reflect.Map:
+		return g.processMapField(typ)
+	}
+
+	// handle time values (e.g. slice items), which lack field tags
+	switch typ {
+	case timeType:
+		return timeSchema(reflect.StructField{}, time.RFC3339)
+	case dateType:
+		return timeSchema(reflect.StructField{}, DateLayout)
+	}
+
 	// handle non-struct types
 	if typ.Kind() != reflect.Struct {
 		return basicTypeSchema(typ.Kind())
@@ -62,26 +127,58 @@ func (g *schemaGenerator) processStruct(typ reflect.Type) map[string]any {
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
 
-		// skip unexported fields
-		if field.PkgPath != "" {
+		// fields bound to parameters aren't part of the body
+		jsonTag := field.Tag.Get("json")
+		if in, _ := bindingTag(field); jsonTag == "-" || in != "" {
+			continue
+		}
+		tag := parseJsonTag(jsonTag, field.Name)
+
+		// untagged embedded structs are flattened, as encoding/json does,
+		// even when the embedded type itself is unexported; so are struct
+		// fields tagged inline, as encoding/json/v2 does
+		var inlined reflect.Type
+		switch {
+		case jsonTag == "":
+			inlined = embeddedStruct(field)
+		case tag.inline && (field.Anonymous || field.PkgPath == ""):
+			inlined = structType(field.Type)
+		}
+		if inlined != nil {
+			embeddedSchema := g.processStruct(inlined)
+			for name, property := range embeddedSchema["properties"].(map[string]any) {
+				properties[name] = property
+			}
+			if embeddedRequired, ok := embeddedSchema["required"].([]string); ok {
+				required = append(required, embeddedRequired...)
+			}
 			continue
 		}
 
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "-" {
+		// skip unexported fields
+		if field.PkgPath != "" {
 			continue
 		}
 
-		// get field name from json tag or field name
-		name, isRequired := parseJsonTag(jsonTag, field.Name)
+		isRequired, nullable := g.requiredness(field, tag)
 		if isRequired {
-			required = append(required, name)
+			required = append(required, tag.name)
 		}
 
 		// process field schema
 		fieldSchema := g.processField(field)
 		if fieldSchema != nil {
-			properties[name] = fieldSchema
+			if nullable {
+				fieldSchema = markNullable(fieldSchema)
+			}
+			properties[tag.name] = fieldSchema
+
+			if message := exampleIssue(field, fieldSchema); message != "" {
+				g.issues = append(g.issues, ExampleIssue{
+					Field:   strings.TrimPrefix(typ.Name()+"."+field.Name, "."),
+					Message: message,
+				})
+			}
 		}
 	}
 
@@ -97,19 +194,109 @@ func (g *schemaGenerator) processStruct(typ reflect.Type) map[string]any {
 	return schema
 }
 
-// parseJsonTag extracts name and required status from a json tag
-func parseJsonTag(jsonTag, fieldName string) (string, bool) {
-	if jsonTag == "" {
-		return fieldName, true
+// embeddedStruct returns the struct type of an embedded field, or nil if the
+// field is not an embedded struct
+func embeddedStruct(field reflect.StructField) reflect.Type {
+	if !field.Anonymous {
+		return nil
+	}
+	return structType(field.Type)
+}
+
+// structType returns the struct type behind a type or a pointer to it, or
+// nil for other types and for types documented as values (times and dates)
+func structType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType || typ == dateType {
+		return nil
+	}
+
+	return typ
+}
+
+// jsonTagInfo is a parsed json struct tag
+type jsonTagInfo struct {
+	name      string
+	omitEmpty bool // omitempty: empty values are left out
+	omitZero  bool // omitzero (Go 1.24 and json/v2): zero values are left out
+	inline    bool // inline (json/v2): the struct's fields are flattened into the parent
+	asString  bool // string: numbers and booleans are encoded as JSON strings
+}
+
+// parseJsonTag parses a json tag, accepting the single-quoted names of
+// encoding/json/v2 (e.g. 'name,with,commas'), and falls back to the field
+// name when the tag doesn't set one
+func parseJsonTag(tag, fieldName string) jsonTagInfo {
+	name, options := tag, ""
+	if quoted, ok := strings.CutPrefix(tag, "'"); ok {
+		if end := strings.Index(quoted, "'"); end >= 0 {
+			name = quoted[:end]
+			options = strings.TrimPrefix(quoted[end+1:], ",")
+		}
+	} else {
+		name, options, _ = strings.Cut(tag, ",")
+	}
+
+	parsed := jsonTagInfo{name: name}
+	if parsed.name == "" {
+		parsed.name = fieldName
+	}
+
+	for _, option := range strings.Split(options, ",") {
+		switch option {
+		case "omitempty":
+			parsed.omitEmpty = true
+		case "omitzero":
+			parsed.omitZero = true
+		case "inline":
+			parsed.inline = true
+		case "string":
+			parsed.asString = true
+		}
+	}
+
+	return parsed
+}
+
+// requiredness reports whether a field is required and whether it is
+// nullable, following the json tag, the generator's options, a required rule
+// of the validate tag and the field's required tag
+func (g *schemaGenerator) requiredness(field reflect.StructField, tag jsonTagInfo) (required, nullable bool) {
+	switch {
+	case tag.omitEmpty:
+		required = false
+	case tag.omitZero:
+		required = g.options.OmitZero == OmitZeroRequired
+		nullable = g.options.OmitZero == OmitZeroNullable
+	case field.Type.Kind() == reflect.Ptr:
+		required = g.options.Pointers != PointersOptional
+		nullable = g.options.Pointers != PointersRequired
+	default:
+		required = true
+	}
+
+	if validateRequired(field) {
+		required = true
+	}
+	if override, err := strconv.ParseBool(field.Tag.Get("required")); err == nil {
+		required = override
 	}
 
-	parts := strings.Split(jsonTag, ",")
-	name := parts[0]
-	if name == "" {
-		name = fieldName
+	return required, nullable
+}
+
+// markNullable allows null as a value of the schema. Struct schemas are
+// wrapped so that nullability stays a property of the field rather than of
+// the type extracted to components.
+func markNullable(schema map[string]any) map[string]any {
+	if schema["type"] == "object" && schema["properties"] != nil {
+		schema = map[string]any{"allOf": []any{schema}}
 	}
 
-	return name, !slices.Contains(parts[1:], "omitempty")
+	schema["nullable"] = true
+	return schema
 }
 
 // processField converts a struct field to a JSON Schema
@@ -121,35 +308,49 @@ func (g *schemaGenerator) processField(field reflect.StructField) map[string]any
 
 	// Check for special types first
 	switch {
-	case fieldType == reflect.TypeOf(time.Time{}):
-		return map[string]any{
-			"type":   "string",
-			"format": "date-time",
-		}
+	case fieldType == timeType:
+		return timeSchema(field, time.RFC3339)
+	case fieldType == dateType:
+		return timeSchema(field, DateLayout)
 	case fieldType == reflect.TypeOf(json.RawMessage{}):
-		return map[string]any{
+		schema := map[string]any{
 			"type": "object",
 		}
+		addFieldMetadata(schema, field)
+		return schema
 	}
 
-	// Then check for basic types
+	// Then check for basic types, encoded as strings with the string option
 	if schema := basicTypeSchema(fieldType.Kind()); schema != nil {
+		if parseJsonTag(field.Tag.Get("json"), field.Name).asString {
+			schema = map[string]any{"type": "string"}
+		}
 		addFieldMetadata(schema, field)
 		return schema
 	}
 
 	// Handle different complex types
+	var schema map[string]any
 	switch fieldType.Kind() {
 	case reflect.Struct:
 		fieldValue := reflect.New(fieldType).Elem().Interface()
-		return g.generate(fieldValue)
+		schema = g.generate(fieldValue)
+
+		// the struct's schema describes the type, so the field's own
+		// metadata goes on a wrapper around it
+		if hasFieldMetadata(field) {
+			schema = map[string]any{"allOf": []any{schema}}
+		}
 	case reflect.Slice, reflect.Array:
-		return g.processArrayField(fieldType)
+		schema = g.processArrayField(fieldType)
 	case reflect.Map:
-		return g.processMapField(fieldType)
+		schema = g.processMapField(fieldType)
 	default:
-		return map[string]any{"type": "object"}
+		schema = map[string]any{"type": "object"}
 	}
+
+	addFieldMetadata(schema, field)
+	return schema
 }
 
 // processArrayField handles array and slice fields
@@ -194,18 +395,61 @@ func (g *schemaGenerator) processMapField(fieldType reflect.Type) map[string]any
 	}
 }
 
-// addFieldMetadata adds documentation from struct tags to a schema
+// metadataTags are the struct tags documenting a field
+var metadataTags = []string{"doc", "example", "enum", "pattern", "format",
+	"minLength", "maxLength", "minimum", "maximum", "minItems", "maxItems"}
+
+// integerConstraintTags are the struct tags holding non-negative integer
+// constraints, copied to the schema keyword of the same name
+var integerConstraintTags = []string{"minLength", "maxLength", "minItems", "maxItems"}
+
+// hasFieldMetadata reports whether the field sets any metadata tag
+func hasFieldMetadata(field reflect.StructField) bool {
+	return slices.ContainsFunc(metadataTags, func(tag string) bool {
+		return field.Tag.Get(tag) != ""
+	})
+}
+
+// addFieldMetadata adds documentation and constraints from struct tags to a
+// schema. Enums constrain the items of arrays rather than the array itself.
+// Constraints of a validate tag are overridden by the dedicated tags.
 func addFieldMetadata(schema map[string]any, field reflect.StructField) {
+	addValidateConstraints(schema, field)
+
 	if docTag := field.Tag.Get("doc"); docTag != "" {
 		schema["description"] = docTag
 	}
 
 	if exampleTag := field.Tag.Get("example"); exampleTag != "" {
-		schema["example"] = exampleTag
+		schema["example"] = typedValue(schema["type"], exampleTag)
 	}
 
 	if enumTag := field.Tag.Get("enum"); enumTag != "" {
-		schema["enum"] = strings.Split(enumTag, ",")
+		target := schema
+		if items, ok := schema["items"].(map[string]any); ok && schema["type"] == "array" {
+			target = items
+		}
+		target["enum"] = typedEnum(target["type"], strings.Split(enumTag, ","))
+	}
+
+	if patternTag := field.Tag.Get("pattern"); patternTag != "" {
+		schema["pattern"] = patternTag
+	}
+
+	if formatTag := field.Tag.Get("format"); formatTag != "" {
+		schema["format"] = formatTag
+	}
+
+	// constraints that don't parse are left out
+	for _, tag := range integerConstraintTags {
+		if n, err := strconv.Atoi(field.Tag.Get(tag)); err == nil && n >= 0 {
+			schema[tag] = n
+		}
+	}
+	for _, tag := range []string{"minimum", "maximum"} {
+		if n, err := strconv.ParseFloat(field.Tag.Get(tag), 64); err == nil {
+			schema[tag] = n
+		}
 	}
 }
 
@@ -234,16 +478,23 @@ func jsonSchema(t any) map[string]any {
 func (g *OpenAPIGenerator) schemaRef(t any) map[string]any {
 	typeName := getTypeName(t)
 
-	// if we can't determine the type name, fall back to inline schema
+	// if we can't determine the type name, fall back to inline schema,
+	// generated once per type and shared by the operations documenting it,
+	// e.g. the []User of several list routes
 	if typeName == "" {
-		schema := jsonSchema(t)
-		extractNestedTypes(schema, "Anonymous", g.schemaRegistry)
+		typ := reflect.TypeOf(t)
+		schema, ok := g.inlineSchemas[typ]
+		if !ok {
+			schema = g.generateSchema(t)
+			extractNestedTypes(schema, "Anonymous", g.schemaRegistry)
+			g.inlineSchemas[typ] = schema
+		}
 		return schema
 	}
 
 	// register the schema if not already registered
 	if _, exists := g.schemaRegistry.schemas[typeName]; !exists {
-		schema := jsonSchema(t)
+		schema := g.generateSchema(t)
 		g.schemaRegistry.register(typeName, schema)
 		extractNestedTypes(schema, typeName, g.schemaRegistry)
 	}
@@ -254,6 +505,33 @@ func (g *OpenAPIGenerator) schemaRef(t any) map[string]any {
 	}
 }
 
+// generateSchema generates the schema of a Go type, or takes the one
+// reflected in parallel, keeping the example issues found along the way
+func (g *OpenAPIGenerator) generateSchema(t any) map[string]any {
+	reflected, ok := g.reflected[reflect.TypeOf(t)]
+	if ok {
+		delete(g.reflected, reflect.TypeOf(t))
+	} else {
+		reflected = g.reflectSchema(t)
+	}
+
+	for _, issue := range reflected.issues {
+		if !slices.Contains(g.exampleIssues, issue) {
+			g.exampleIssues = append(g.exampleIssues, issue)
+		}
+	}
+
+	return reflected.schema
+}
+
+// reflectSchema generates the schema of a Go type with the generator's
+// options; it doesn't touch the generator, so it can run concurrently
+func (g *OpenAPIGenerator) reflectSchema(t any) reflectedSchema {
+	sg := newSchemaGenerator()
+	sg.options = g.SchemaOptions
+	return reflectedSchema{schema: sg.generate(t), issues: sg.issues}
+}
+
 // getTypeName extracts the Go type name from an interface
 func getTypeName(t any) string {
 	if t == nil {
@@ -290,6 +568,17 @@ func extractNestedTypes(schema map[string]any, path string, registry *schemaRegi
 		propertyTitle := strings.Title(propName)
 		typeName := path + propertyTitle
 
+		// handle struct properties wrapped to carry the field's metadata
+		if allOf, ok := propSchemaMap["allOf"].([]any); ok && len(allOf) == 1 {
+			if inner, ok := allOf[0].(map[string]any); ok && inner["type"] == "object" && inner["properties"] != nil {
+				registry.register(typeName, inner)
+				allOf[0] = map[string]any{
+					"$ref": fmt.Sprintf("#/components/schemas/%s", typeName),
+				}
+				extractNestedTypes(inner, typeName, registry)
+			}
+		}
+
 		// handle object properties
 		if propSchemaMap["type"] == "object" && propSchemaMap["properties"] != nil {
 			registry.register(typeName, propSchemaMap)
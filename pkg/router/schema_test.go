@@ -12,6 +12,23 @@ import (
 )
 
 // Test types for schema generation
+type withConstraints struct {
+	Name  string   `json:"name" minLength:"1" maxLength:"64"`
+	Score float64  `json:"score" minimum:"0" maximum:"1.5"`
+	Tags  []string `json:"tags" minItems:"1" maxItems:"10"`
+	Size  int      `json:"size" minimum:"small"`
+}
+
+type withValidateTags struct {
+	Email    string         `json:"email,omitempty" validate:"required,email,max=64"`
+	Status   string         `json:"status" validate:"oneof=open done"`
+	Priority int            `json:"priority" validate:"gte=1,lt=10"`
+	Tags     []string       `json:"tags" validate:"min=1,dive,alpha"`
+	Labels   map[string]int `json:"labels" validate:"len=2"`
+	Code     string         `json:"code" validate:"alphanum,len=6" maxLength:"8"`
+	Note     string         `json:"note,omitempty" validate:"omitempty|email"`
+}
+
 type simpleType struct {
 	Name string `json:"name"`
 	Age  int    `json:"age"`
@@ -26,10 +43,54 @@ type withDocAndExample struct {
 	Status string `json:"status" doc:"Status of the resource" example:"active" enum:"active,inactive,pending"`
 }
 
+type withTypedEnums struct {
+	Priority int     `json:"priority" enum:"1,2,3"`
+	Weight   float64 `json:"weight" enum:"0.5, 1"`
+	Flag     bool    `json:"flag" enum:"true"`
+}
+
+type withComplexMetadata struct {
+	Owner  simpleType        `json:"owner" doc:"Owner of the resource"`
+	Tags   []string          `json:"tags" doc:"Labels" example:"[\"a\", \"b\"]" enum:"a,b,c"`
+	Labels map[string]string `json:"labels" doc:"Free-form labels"`
+}
+
+type withInlined struct {
+	ID int `json:"id"`
+}
+
+type withJSONv2Tags struct {
+	Name    string      `json:"name,omitzero"`
+	Count   int         `json:"count,string"`
+	Owner   simpleType  `json:"owner,omitzero"`
+	Details withInlined `json:",inline"`
+}
+
+type withPointers struct {
+	Nickname *string `json:"nickname"`
+	Age      *int    `json:"age,omitempty"`
+	Name     string  `json:"name"`
+	Manager  *string `json:"manager" required:"true"`
+	Legacy   string  `json:"legacy" required:"false"`
+}
+
 type withTime struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type withDates struct {
+	Birthday Date      `json:"birthday"`
+	DueDate  time.Time `json:"dueDate" timeFormat:"2006-01-02"`
+	Stamp    Date      `json:"stamp" timeFormat:"2006-01-02T15:04"`
+	Holidays []Date    `json:"holidays"`
+}
+
+type withEmbedded struct {
+	simpleType
+	Note   string     `json:"note,omitempty"`
+	Parent simpleType `json:"parent"`
+}
+
 type withRawJSON struct {
 	Data json.RawMessage `json:"data"`
 }
@@ -62,6 +123,62 @@ func TestJsonSchema(t *testing.T) {
 		input    any
 		expected map[string]any
 	}{
+		"with constraints": {
+			input: withConstraints{},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string", "minLength": 1, "maxLength": 64},
+					"score": map[string]any{"type": "number", "minimum": 0.0, "maximum": 1.5},
+					"tags": map[string]any{
+						"type":     "array",
+						"items":    map[string]any{"type": "string"},
+						"minItems": 1,
+						"maxItems": 10,
+					},
+					// constraints that don't parse are left out
+					"size": map[string]any{"type": "integer"},
+				},
+				"required": []string{"name", "score", "tags", "size"},
+			},
+		},
+		"with validate tags": {
+			input: withValidateTags{},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"email":  map[string]any{"type": "string", "format": "email", "maxLength": 64},
+					"status": map[string]any{"type": "string", "enum": []string{"open", "done"}},
+					"priority": map[string]any{
+						"type":             "integer",
+						"minimum":          1.0,
+						"maximum":          10.0,
+						"exclusiveMaximum": true,
+					},
+					// rules after dive apply to the items
+					"tags": map[string]any{
+						"type":     "array",
+						"items":    map[string]any{"type": "string"},
+						"minItems": 1,
+					},
+					"labels": map[string]any{
+						"type":                 "object",
+						"additionalProperties": map[string]any{"type": "integer"},
+						"minProperties":        2,
+						"maxProperties":        2,
+					},
+					// dedicated tags override the validate tag
+					"code": map[string]any{
+						"type":      "string",
+						"pattern":   "^[a-zA-Z0-9]+$",
+						"minLength": 6,
+						"maxLength": 8,
+					},
+					"note": map[string]any{"type": "string"},
+				},
+				"required": []string{"email", "status", "priority", "tags", "labels", "code"},
+			},
+		},
 		"simple type": {
 			input: simpleType{},
 			expected: map[string]any{
@@ -107,6 +224,49 @@ func TestJsonSchema(t *testing.T) {
 				"required": []string{"status"},
 			},
 		},
+		"with typed enums": {
+			input: withTypedEnums{},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"priority": map[string]any{"type": "integer", "enum": []any{int64(1), int64(2), int64(3)}},
+					"weight":   map[string]any{"type": "number", "enum": []any{0.5, 1.0}},
+					"flag":     map[string]any{"type": "boolean", "enum": []any{true}},
+				},
+				"required": []string{"priority", "weight", "flag"},
+			},
+		},
+		"with complex metadata": {
+			input: withComplexMetadata{},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"owner": map[string]any{
+						"description": "Owner of the resource",
+						"allOf": []any{map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"name": map[string]any{"type": "string"},
+								"age":  map[string]any{"type": "integer"},
+							},
+							"required": []string{"name", "age"},
+						}},
+					},
+					"tags": map[string]any{
+						"type":        "array",
+						"description": "Labels",
+						"example":     []any{"a", "b"},
+						"items":       map[string]any{"type": "string", "enum": []string{"a", "b", "c"}},
+					},
+					"labels": map[string]any{
+						"type":                 "object",
+						"description":          "Free-form labels",
+						"additionalProperties": map[string]any{"type": "string"},
+					},
+				},
+				"required": []string{"owner", "tags", "labels"},
+			},
+		},
 		"with time.Time": {
 			input: withTime{},
 			expected: map[string]any{
@@ -120,6 +280,54 @@ func TestJsonSchema(t *testing.T) {
 				"required": []string{"createdAt"},
 			},
 		},
+		"with dates": {
+			input: withDates{},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"birthday": map[string]any{
+						"type":   "string",
+						"format": "date",
+					},
+					"dueDate": map[string]any{
+						"type":   "string",
+						"format": "date",
+					},
+					"stamp": map[string]any{
+						"type":   "string",
+						"format": "date-time",
+					},
+					"holidays": map[string]any{
+						"type": "array",
+						"items": map[string]any{
+							"type":   "string",
+							"format": "date",
+						},
+					},
+				},
+				"required": []string{"birthday", "dueDate", "stamp", "holidays"},
+			},
+		},
+		"with embedded struct": {
+			input: withEmbedded{},
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{"type": "string"},
+					"age":  map[string]any{"type": "integer"},
+					"note": map[string]any{"type": "string"},
+					"parent": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"name": map[string]any{"type": "string"},
+							"age":  map[string]any{"type": "integer"},
+						},
+						"required": []string{"name", "age"},
+					},
+				},
+				"required": []string{"name", "age", "parent"},
+			},
+		},
 		"with json.RawMessage": {
 			input: withRawJSON{},
 			expected: map[string]any{
@@ -230,6 +438,120 @@ func TestJsonSchema(t *testing.T) {
 	}
 }
 
+func TestOmitZeroPolicy(t *testing.T) {
+	t.Parallel()
+
+	owner := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"age":  map[string]any{"type": "integer"},
+		},
+		"required": []string{"name", "age"},
+	}
+
+	for name, tc := range map[string]struct {
+		policy   OmitZeroPolicy
+		expected map[string]any
+	}{
+		"optional": {
+			policy: OmitZeroOptional,
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string"},
+					"count": map[string]any{"type": "string"},
+					"owner": owner,
+					"id":    map[string]any{"type": "integer"},
+				},
+				"required": []string{"count", "id"},
+			},
+		},
+		"nullable": {
+			policy: OmitZeroNullable,
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string", "nullable": true},
+					"count": map[string]any{"type": "string"},
+					"owner": map[string]any{"allOf": []any{owner}, "nullable": true},
+					"id":    map[string]any{"type": "integer"},
+				},
+				"required": []string{"count", "id"},
+			},
+		},
+		"required": {
+			policy: OmitZeroRequired,
+			expected: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string"},
+					"count": map[string]any{"type": "string"},
+					"owner": owner,
+					"id":    map[string]any{"type": "integer"},
+				},
+				"required": []string{"name", "count", "owner", "id"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			g := newSchemaGenerator()
+			g.options = SchemaOptions{OmitZero: tc.policy}
+
+			if diff := cmp.Diff(tc.expected, g.generate(withJSONv2Tags{})); diff != "" {
+				t.Errorf("schema mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPointerPolicy(t *testing.T) {
+	t.Parallel()
+
+	nullable := map[string]any{"type": "string", "nullable": true}
+
+	for name, tc := range map[string]struct {
+		policy       PointerPolicy
+		wantRequired []string
+		wantNullable []string
+	}{
+		"required": {
+			policy:       PointersRequired,
+			wantRequired: []string{"nickname", "name", "manager"},
+		},
+		"nullable": {
+			policy:       PointersNullable,
+			wantRequired: []string{"nickname", "name", "manager"},
+			wantNullable: []string{"nickname", "manager"},
+		},
+		"optional": {
+			policy:       PointersOptional,
+			wantRequired: []string{"name", "manager"},
+			wantNullable: []string{"nickname", "manager"},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			g := newSchemaGenerator()
+			g.options = SchemaOptions{Pointers: tc.policy}
+			schema := g.generate(withPointers{})
+
+			assert.Equal(t, tc.wantRequired, schema["required"])
+
+			properties := schema["properties"].(map[string]any)
+			for _, name := range tc.wantNullable {
+				assert.Equal(t, nullable, properties[name], name)
+			}
+			assert.Equal(t, map[string]any{"type": "integer"}, properties["age"])
+		})
+	}
+}
+
 func TestCircularReferenceHandling(t *testing.T) {
 	t.Parallel()
 
@@ -264,48 +586,54 @@ func TestParseJsonTag(t *testing.T) {
 	for name, tc := range map[string]struct {
 		jsonTag   string
 		fieldName string
-		wantName  string
-		required  bool
+		want      jsonTagInfo
 	}{
 		"empty tag": {
 			jsonTag:   "",
 			fieldName: "Field",
-			wantName:  "Field",
-			required:  true,
+			want:      jsonTagInfo{name: "Field"},
 		},
 		"with name": {
 			jsonTag:   "customName",
 			fieldName: "Field",
-			wantName:  "customName",
-			required:  true,
+			want:      jsonTagInfo{name: "customName"},
 		},
 		"with omitempty": {
 			jsonTag:   "field,omitempty",
 			fieldName: "Field",
-			wantName:  "field",
-			required:  false,
+			want:      jsonTagInfo{name: "field", omitEmpty: true},
 		},
 		"with empty name": {
 			jsonTag:   ",omitempty",
 			fieldName: "Field",
-			wantName:  "Field",
-			required:  false,
+			want:      jsonTagInfo{name: "Field", omitEmpty: true},
 		},
-		"with other options": {
+		"with string": {
 			jsonTag:   "field,string",
 			fieldName: "Field",
-			wantName:  "field",
-			required:  true,
+			want:      jsonTagInfo{name: "field", asString: true},
+		},
+		"with omitzero": {
+			jsonTag:   "field,omitzero",
+			fieldName: "Field",
+			want:      jsonTagInfo{name: "field", omitZero: true},
+		},
+		"with quoted name": {
+			jsonTag:   "'a,b',omitzero,inline",
+			fieldName: "Field",
+			want:      jsonTagInfo{name: "a,b", omitZero: true, inline: true},
+		},
+		"with v2 options": {
+			jsonTag:   "field,format:RFC3339,case:ignore",
+			fieldName: "Field",
+			want:      jsonTagInfo{name: "field"},
 		},
 	} {
 		tc := tc
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			gotName, isRequired := parseJsonTag(tc.jsonTag, tc.fieldName)
-
-			assert.Equal(t, tc.wantName, gotName, "Field name should match expected")
-			assert.Equal(t, tc.required, isRequired, "Required flag should match expected")
+			assert.Equal(t, tc.want, parseJsonTag(tc.jsonTag, tc.fieldName))
 		})
 	}
 }
@@ -427,3 +755,67 @@ func TestExtractNestedTypes(t *testing.T) {
 	assert.Contains(t, nestedProp, "$ref", "Nested property should have $ref")
 	assert.Equal(t, "#/components/schemas/TestNested", nestedProp["$ref"], "Reference should point to extracted schema")
 }
+
+func TestDateJSON(t *testing.T) {
+	t.Parallel()
+
+	type payload struct {
+		Birthday Date `json:"birthday"`
+	}
+
+	data, err := json.Marshal(payload{Birthday: NewDate(1990, time.March, 7)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"birthday":"1990-03-07"}`, string(data))
+
+	var decoded payload
+	require.NoError(t, json.Unmarshal([]byte(`{"birthday":"2001-12-31"}`), &decoded))
+	assert.Equal(t, NewDate(2001, time.December, 31), decoded.Birthday)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"birthday":null}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"birthday":"2001-12-31T10:00:00Z"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"birthday":20011231}`), &decoded))
+}
+
+type withExamples struct {
+	Count    int       `json:"count" example:"3"`
+	Ratio    float64   `json:"ratio" example:"0.5"`
+	Done     bool      `json:"done" example:"true"`
+	Priority int       `json:"priority" example:"high"`
+	Status   string    `json:"status" example:"archived" enum:"open,closed"`
+	Code     string    `json:"code" example:"abc" pattern:"^[0-9]+$"`
+	Email    string    `json:"email" example:"nobody" format:"email"`
+	ID       string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000" format:"uuid"`
+	Due      time.Time `json:"due" example:"tomorrow"`
+	Day      Date      `json:"day" example:"2024-02-30"`
+	Local    time.Time `json:"local" timeFormat:"2006-01-02 15:04" example:"2024-01-02 15:04"`
+	Percent  int       `json:"percent" example:"120" maximum:"100"`
+	Initials string    `json:"initials" example:"ABCD" maxLength:"3"`
+}
+
+func TestExampleIssues(t *testing.T) {
+	t.Parallel()
+
+	g := NewOpenAPIGenerator("Test", "", "1.0.0", []RouteInfo{{
+		Method:       "GET",
+		Path:         "/examples",
+		ResponseType: &withExamples{},
+	}})
+	spec := g.Generate()
+
+	properties := spec["components"].(map[string]any)["schemas"].(map[string]any)["withExamples"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, int64(3), properties["count"].(map[string]any)["example"])
+	assert.Equal(t, 0.5, properties["ratio"].(map[string]any)["example"])
+	assert.Equal(t, true, properties["done"].(map[string]any)["example"])
+	assert.Equal(t, "high", properties["priority"].(map[string]any)["example"])
+
+	assert.Equal(t, []ExampleIssue{
+		{Field: "withExamples.Code", Message: `example "abc" does not match pattern "^[0-9]+$"`},
+		{Field: "withExamples.Day", Message: `example "2024-02-30" is not a valid date`},
+		{Field: "withExamples.Due", Message: `example "tomorrow" is not a valid date-time`},
+		{Field: "withExamples.Email", Message: `example "nobody" is not a valid email`},
+		{Field: "withExamples.Initials", Message: `example "ABCD" must have at most 3 characters`},
+		{Field: "withExamples.Percent", Message: `example "120" must be less than or equal to 100`},
+		{Field: "withExamples.Priority", Message: `example "high" is not a valid integer`},
+		{Field: "withExamples.Status", Message: `example "archived" is not one of the enum values`},
+	}, g.ExampleIssues())
+}
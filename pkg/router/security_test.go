@@ -0,0 +1,88 @@
+package router
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "/things", noop).Register()
+	r.Route("GET", "/docs", noop).
+		WithSecurityHeaders(SecurityHeaders{ContentSecurityPolicy: DocsContentSecurityPolicy}).
+		Register()
+	r.Route("GET", "/secure", noop).
+		WithTransportPolicy(TransportPolicy{HSTSMaxAge: time.Hour}).
+		Register()
+	r.WithSecurityHeaders(DefaultSecurityHeaders())
+
+	for name, tc := range map[string]struct {
+		path string
+		tls  bool
+		want http.Header
+	}{
+		"defaults": {
+			path: "/things",
+			want: http.Header{
+				"X-Content-Type-Options":  {"nosniff"},
+				"X-Frame-Options":         {"DENY"},
+				"Referrer-Policy":         {"no-referrer"},
+				"Content-Security-Policy": {"default-src 'none'; frame-ancestors 'none'"},
+			},
+		},
+		"defaults over tls": {
+			path: "/things",
+			tls:  true,
+			want: http.Header{
+				"Strict-Transport-Security": {"max-age=31536000"},
+				"X-Content-Type-Options":    {"nosniff"},
+				"X-Frame-Options":           {"DENY"},
+				"Referrer-Policy":           {"no-referrer"},
+				"Content-Security-Policy":   {"default-src 'none'; frame-ancestors 'none'"},
+			},
+		},
+		"route override": {
+			path: "/docs",
+			want: http.Header{
+				"Content-Security-Policy": {DocsContentSecurityPolicy},
+			},
+		},
+		"transport policy hsts": {
+			path: "/secure",
+			tls:  true,
+			want: http.Header{
+				"Strict-Transport-Security": {"max-age=3600"},
+				"X-Content-Type-Options":    {"nosniff"},
+				"X-Frame-Options":           {"DENY"},
+				"Referrer-Policy":           {"no-referrer"},
+				"Content-Security-Policy":   {"default-src 'none'; frame-ancestors 'none'"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			// every response carries its request ID
+			assert.NotEmpty(t, rec.Header().Get(RequestIDHeader))
+			rec.Header().Del(RequestIDHeader)
+			assert.Equal(t, tc.want, rec.Header())
+		})
+	}
+}
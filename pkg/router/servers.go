@@ -0,0 +1,124 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Server is a server the API is served from, documented in the spec's servers
+type Server struct {
+	URL         string           // URL of the server, which may be templated, e.g. "https://{region}.api.example.com"
+	Description string           // Description of the server (optional)
+	Variables   []ServerVariable // Definitions of the URL's template variables
+}
+
+// ServerVariable defines a variable of a templated server URL
+type ServerVariable struct {
+	Name        string   // Name of the variable, as in {name} in the URL
+	Default     string   // Value used when clients don't pick one
+	Enum        []string // Values clients can pick from (optional)
+	Description string   // Description of the variable (optional)
+}
+
+// serverVariablePattern matches the {name} variables of server URLs
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// WithServer documents a server the API is served from, in the order they
+// are added. Templated URLs such as "https://{region}.api.example.com/{basePath}"
+// define each of their variables, with a default and optionally the values
+// clients can pick from; it panics on variables used but not defined, or
+// defined but not used.
+func (dr *DocRouter) WithServer(url, description string, variables ...ServerVariable) *DocRouter {
+	dr.servers = append(dr.servers, newServer(url, description, variables))
+	return dr
+}
+
+// WithServer documents a server the route is served from, replacing the
+// router's servers for its operation, e.g. an upload route served from a
+// different host; the URL may be templated like the router's
+func (rc *RouteConfig) WithServer(url, description string, variables ...ServerVariable) *RouteConfig {
+	rc.servers = append(rc.servers, newServer(url, description, variables))
+	return rc
+}
+
+// WithServer documents a server every route of the group is served from,
+// like RouteConfig.WithServer
+func (g *RouteGroup) WithServer(url, description string, variables ...ServerVariable) *RouteGroup {
+	g.servers = append(g.servers, newServer(url, description, variables))
+	return g
+}
+
+// newServer creates the server, checking its variables against its URL
+func newServer(url, description string, variables []ServerVariable) Server {
+	server := Server{URL: url, Description: description, Variables: variables}
+	if err := server.validate(); err != nil {
+		panic(fmt.Sprintf("router: invalid server %q: %v", url, err))
+	}
+	return server
+}
+
+// validate checks that the URL's variables are all defined, and that the
+// definitions are used and valid
+func (s Server) validate() error {
+	if s.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+
+	var used []string
+	for _, match := range serverVariablePattern.FindAllStringSubmatch(s.URL, -1) {
+		used = append(used, match[1])
+	}
+
+	for i, variable := range s.Variables {
+		switch {
+		case !slices.Contains(used, variable.Name):
+			return fmt.Errorf("variable %q is not used in the URL", variable.Name)
+		case slices.ContainsFunc(s.Variables[:i], func(defined ServerVariable) bool { return defined.Name == variable.Name }):
+			return fmt.Errorf("variable %q is defined more than once", variable.Name)
+		case len(variable.Enum) > 0 && !slices.Contains(variable.Enum, variable.Default):
+			return fmt.Errorf("default %q of variable %q is not one of its values", variable.Default, variable.Name)
+		}
+	}
+	for _, name := range used {
+		if !slices.ContainsFunc(s.Variables, func(defined ServerVariable) bool { return defined.Name == name }) {
+			return fmt.Errorf("variable %q is not defined", name)
+		}
+	}
+
+	return nil
+}
+
+// serverObjects documents the servers in order
+func serverObjects(servers []Server) []any {
+	objects := make([]any, 0, len(servers))
+	for _, server := range servers {
+		object := map[string]any{
+			"url": server.URL,
+		}
+		if server.Description != "" {
+			object["description"] = server.Description
+		}
+
+		if len(server.Variables) > 0 {
+			variables := make(map[string]any, len(server.Variables))
+			for _, variable := range server.Variables {
+				definition := map[string]any{
+					"default": variable.Default,
+				}
+				if len(variable.Enum) > 0 {
+					definition["enum"] = variable.Enum
+				}
+				if variable.Description != "" {
+					definition["description"] = variable.Description
+				}
+				variables[variable.Name] = definition
+			}
+			object["variables"] = variables
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects
+}
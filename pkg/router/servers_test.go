@@ -0,0 +1,98 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServers(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter().
+		WithServer("https://{region}.api.example.com/{basePath}", "Production",
+			ServerVariable{Name: "region", Default: "us", Enum: []string{"us", "eu"}, Description: "Region of the account"},
+			ServerVariable{Name: "basePath", Default: "v1"},
+		).
+		WithServer("http://localhost:8080", "")
+
+	assert.Equal(t, []any{
+		map[string]any{
+			"url":         "https://{region}.api.example.com/{basePath}",
+			"description": "Production",
+			"variables": map[string]any{
+				"region":   map[string]any{"default": "us", "enum": []string{"eu", "us"}, "description": "Region of the account"},
+				"basePath": map[string]any{"default": "v1"},
+			},
+		},
+		map[string]any{"url": "http://localhost:8080"},
+	}, r.OpenAPI().Generate()["servers"])
+
+	assert.NotContains(t, NewDocRouter().OpenAPI().Generate(), "servers")
+
+	for name, tc := range map[string]struct {
+		url       string
+		variables []ServerVariable
+		wantPanic string
+	}{
+		"undefined variable": {
+			url:       "https://{region}.api.example.com",
+			wantPanic: `router: invalid server "https://{region}.api.example.com": variable "region" is not defined`,
+		},
+		"unused variable": {
+			url:       "https://api.example.com",
+			variables: []ServerVariable{{Name: "region", Default: "us"}},
+			wantPanic: `router: invalid server "https://api.example.com": variable "region" is not used in the URL`,
+		},
+		"duplicate variable": {
+			url:       "https://{region}.api.example.com",
+			variables: []ServerVariable{{Name: "region", Default: "us"}, {Name: "region", Default: "eu"}},
+			wantPanic: `router: invalid server "https://{region}.api.example.com": variable "region" is defined more than once`,
+		},
+		"default not in enum": {
+			url:       "https://{region}.api.example.com",
+			variables: []ServerVariable{{Name: "region", Default: "ap", Enum: []string{"us", "eu"}}},
+			wantPanic: `router: invalid server "https://{region}.api.example.com": default "ap" of variable "region" is not one of its values`,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.PanicsWithValue(t, tc.wantPanic, func() {
+				NewDocRouter().WithServer(tc.url, "", tc.variables...)
+			})
+		})
+	}
+}
+
+func TestOperationServers(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithServer("https://api.example.com", "API")
+	r.Get("/files", noop).Register()
+	r.Post("/files", noop).WithServer("https://uploads.example.com", "Uploads").Register()
+	r.Group("/reports").
+		WithServer("https://{region}.reports.example.com", "", ServerVariable{Name: "region", Default: "us"}).
+		Get("", noop).
+		Register()
+
+	paths := r.OpenAPI().Generate()["paths"].(map[string]any)
+	operation := func(path, method string) map[string]any {
+		return paths[path].(map[string]any)[method].(map[string]any)
+	}
+
+	assert.NotContains(t, operation("/files", "get"), "servers", "operations use the router's servers by default")
+	assert.Equal(t, []any{
+		map[string]any{"url": "https://uploads.example.com", "description": "Uploads"},
+	}, operation("/files", "post")["servers"])
+	assert.Equal(t, []any{
+		map[string]any{
+			"url":       "https://{region}.reports.example.com",
+			"variables": map[string]any{"region": map[string]any{"default": "us"}},
+		},
+	}, operation("/reports", "get")["servers"])
+}
@@ -0,0 +1,191 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	// maxShadowBody limits how much of a request or response is buffered
+	// for shadowing; larger requests are only served by the primary handler
+	maxShadowBody = 1 << 20
+
+	// maxShadowInFlight limits the concurrent shadow requests of a route;
+	// requests arriving while the limit is reached are not shadowed
+	maxShadowInFlight = 64
+)
+
+// ShadowDiff describes how a shadow handler's response differed from the
+// response served to the client
+type ShadowDiff struct {
+	Method        string // Method of the request
+	Path          string // Path of the request
+	PrimaryStatus int    // Status code served to the client
+	ShadowStatus  int    // Status code of the shadow handler (0 if it panicked)
+	BodyDiffers   bool   // Whether the response bodies differ
+	Panic         string // Panic value of the shadow handler, if any
+}
+
+// ShadowReporter receives the differences found by shadow handlers
+type ShadowReporter func(diff ShadowDiff)
+
+// WithShadowReporter sets the function receiving shadow differences; by
+// default they are logged as warnings through log/slog
+func (dr *DocRouter) WithShadowReporter(reporter ShadowReporter) *DocRouter {
+	dr.shadowReporter = reporter
+	return dr
+}
+
+// WithShadow sends a copy of every request of the route to the given
+// handler after the route's own handler has responded. The shadow's response
+// is discarded and only reported when it differs from the primary one, so a
+// rewritten handler can be checked against real traffic before cutover.
+func (rc *RouteConfig) WithShadow(handler http.HandlerFunc) *RouteConfig {
+	rc.shadow = handler
+	return rc
+}
+
+// reportShadowDiff hands a difference to the router's reporter
+func (dr *DocRouter) reportShadowDiff(diff ShadowDiff) {
+	if dr.shadowReporter != nil {
+		dr.shadowReporter(diff)
+		return
+	}
+
+	slog.Warn("shadow response differs",
+		"method", diff.Method,
+		"path", diff.Path,
+		"primary_status", diff.PrimaryStatus,
+		"shadow_status", diff.ShadowStatus,
+		"body_differs", diff.BodyDiffers,
+		"panic", diff.Panic,
+	)
+}
+
+// shadowMiddleware serves requests with next and replays them
+// asynchronously against shadow, comparing the responses
+func shadowMiddleware(dr *DocRouter, shadow, next http.Handler) http.Handler {
+	inFlight := make(chan struct{}, maxShadowInFlight)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxShadowBody+1))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+		if err != nil || len(body) > maxShadowBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// the shadow outlives the request, so it must not share its
+		// cancellation or its body
+		shadowReq := r.Clone(context.WithoutCancel(r.Context()))
+		shadowReq.Body = io.NopCloser(bytes.NewReader(body))
+
+		primary := &captureWriter{ResponseWriter: w}
+		next.ServeHTTP(primary, r)
+
+		go func() {
+			defer func() { <-inFlight }()
+
+			diff := ShadowDiff{
+				Method:        r.Method,
+				Path:          r.URL.Path,
+				PrimaryStatus: primary.statusCode(),
+			}
+
+			recorded := &captureWriter{ResponseWriter: discardWriter{header: http.Header{}}}
+			if panicked := serveRecovered(shadow, recorded, shadowReq); panicked != nil {
+				diff.Panic = fmt.Sprint(panicked)
+				dr.reportShadowDiff(diff)
+				return
+			}
+
+			diff.ShadowStatus = recorded.statusCode()
+			diff.BodyDiffers = primary.truncated || recorded.truncated ||
+				!bytes.Equal(primary.body.Bytes(), recorded.body.Bytes())
+
+			if diff.ShadowStatus != diff.PrimaryStatus || diff.BodyDiffers {
+				dr.reportShadowDiff(diff)
+			}
+		}()
+	})
+}
+
+// serveRecovered serves a request, returning the value of any panic
+func serveRecovered(h http.Handler, w http.ResponseWriter, r *http.Request) (panicked any) {
+	defer func() {
+		panicked = recover()
+	}()
+
+	h.ServeHTTP(w, r)
+	return nil
+}
+
+// captureWriter records the status code and the start of the body written
+// through it
+type captureWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+// WriteHeader records the status code before writing it
+func (w *captureWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the body up to maxShadowBody before writing it
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if remaining := maxShadowBody - w.body.Len(); len(b) > remaining {
+		w.body.Write(b[:remaining])
+		w.truncated = true
+	} else {
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode returns the recorded status, defaulting to 200 like net/http
+func (w *captureWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// discardWriter is a response writer that drops everything written to it
+type discardWriter struct {
+	header http.Header
+}
+
+func (w discardWriter) Header() http.Header         { return w.header }
+func (w discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w discardWriter) WriteHeader(int)             {}
@@ -0,0 +1,79 @@
+package router
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShadow(t *testing.T) {
+	t.Parallel()
+
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}
+
+	for name, tc := range map[string]struct {
+		shadow   http.HandlerFunc
+		wantDiff *ShadowDiff
+	}{
+		"same response": {
+			shadow: echo,
+		},
+		"different status": {
+			shadow: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+				w.Write(body)
+			},
+			wantDiff: &ShadowDiff{Method: "POST", Path: "/echo", PrimaryStatus: 200, ShadowStatus: 201},
+		},
+		"different body": {
+			shadow: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("something else"))
+			},
+			wantDiff: &ShadowDiff{Method: "POST", Path: "/echo", PrimaryStatus: 200, ShadowStatus: 200, BodyDiffers: true},
+		},
+		"panic": {
+			shadow: func(w http.ResponseWriter, r *http.Request) {
+				panic("not implemented")
+			},
+			wantDiff: &ShadowDiff{Method: "POST", Path: "/echo", PrimaryStatus: 200, Panic: "not implemented"},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diffs := make(chan ShadowDiff, 1)
+			r := NewDocRouter().WithShadowReporter(func(diff ShadowDiff) {
+				diffs <- diff
+			})
+			r.Route("POST", "/echo", echo).
+				WithShadow(tc.shadow).
+				Register()
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello")))
+
+			// the client always gets the primary response
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "hello", rec.Body.String())
+
+			select {
+			case diff := <-diffs:
+				require.NotNil(t, tc.wantDiff, "unexpected diff %+v", diff)
+				assert.Equal(t, *tc.wantDiff, diff)
+			case <-time.After(100 * time.Millisecond):
+				assert.Nil(t, tc.wantDiff, "expected a diff to be reported")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package router
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// SlowRequest describes a request that took longer than its route's
+// latency threshold
+type SlowRequest struct {
+	Route      string        // Route that served the request, e.g. "GET /todos/{id}"
+	Threshold  time.Duration // Latency threshold of the route
+	Duration   time.Duration // Time from the router receiving the request until the response completed
+	Handler    time.Duration // Of which spent in the route's handler
+	Middleware time.Duration // Of which spent in router and route middleware
+}
+
+// SlowRequestReporter receives requests exceeding their latency threshold
+type SlowRequestReporter func(req SlowRequest)
+
+// WithSlowRequestThreshold reports requests taking longer than the threshold,
+// for routes without a threshold of their own. Slow requests are logged as
+// warnings through log/slog unless a reporter is set.
+func (dr *DocRouter) WithSlowRequestThreshold(threshold time.Duration) *DocRouter {
+	dr.slowThreshold = threshold
+	dr.timeRequests = true
+	return dr
+}
+
+// WithSlowRequestReporter sets the function receiving slow requests
+func (dr *DocRouter) WithSlowRequestReporter(reporter SlowRequestReporter) *DocRouter {
+	dr.slowReporter = reporter
+	return dr
+}
+
+// WithLatencyThreshold sets the latency the route is expected to stay within,
+// e.g. from its SLO tier, replacing the router's threshold
+func (rc *RouteConfig) WithLatencyThreshold(threshold time.Duration) *RouteConfig {
+	rc.latencyThreshold = threshold
+	return rc
+}
+
+// requestTiming collects the timing of a request as it passes the router
+type requestTiming struct {
+	start     time.Time
+	route     string
+	threshold time.Duration
+	handler   time.Duration
+}
+
+// requestTimingKey is the context key under which the request timing is stored
+type requestTimingKey struct{}
+
+// serveTimed serves the request through handler and reports it if it was slow
+func (dr *DocRouter) serveTimed(handler http.Handler, w http.ResponseWriter, r *http.Request) {
+	timing := &requestTiming{start: time.Now()}
+	handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestTimingKey{}, timing)))
+
+	// requests that matched no route aren't timed
+	if timing.route == "" || timing.threshold <= 0 {
+		return
+	}
+
+	duration := time.Since(timing.start)
+	if duration <= timing.threshold {
+		return
+	}
+
+	dr.reportSlowRequest(SlowRequest{
+		Route:      timing.route,
+		Threshold:  timing.threshold,
+		Duration:   duration,
+		Handler:    timing.handler,
+		Middleware: duration - timing.handler,
+	})
+}
+
+// reportSlowRequest hands a slow request to the reporter, or logs it
+func (dr *DocRouter) reportSlowRequest(req SlowRequest) {
+	if dr.slowReporter != nil {
+		dr.slowReporter(req)
+		return
+	}
+
+	slog.Warn("slow request",
+		"route", req.Route,
+		"threshold", req.Threshold.String(),
+		"duration", req.Duration.String(),
+		"handler", req.Handler.String(),
+		"middleware", req.Middleware.String(),
+	)
+}
+
+// timingMiddleware records the time spent in the route's handler, for the
+// router to compare against the route's threshold (or its own) at request time
+func timingMiddleware(dr *DocRouter, route string, threshold time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timing, ok := r.Context().Value(requestTimingKey{}).(*requestTiming)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		timing.route = route
+		timing.threshold = threshold
+		if timing.threshold <= 0 {
+			timing.threshold = dr.slowThreshold
+		}
+
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		timing.handler = time.Since(start)
+	})
+}
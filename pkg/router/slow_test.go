@@ -0,0 +1,58 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowRequests(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	var reported []SlowRequest
+
+	sleep := func(d time.Duration) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(d)
+		}
+	}
+
+	r := NewDocRouter().
+		WithSlowRequestThreshold(time.Hour).
+		WithSlowRequestReporter(func(req SlowRequest) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			reported = append(reported, req)
+		})
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(5 * time.Millisecond)
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Route("GET", "/fast", sleep(0)).Register()
+	r.Route("GET", "/slow/{id}", sleep(10*time.Millisecond)).
+		WithLatencyThreshold(time.Millisecond).
+		Register()
+
+	for _, path := range []string{"/fast", "/slow/1", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	require.Len(t, reported, 1)
+	req := reported[0]
+	assert.Equal(t, "GET /slow/{id}", req.Route)
+	assert.Equal(t, time.Millisecond, req.Threshold)
+	assert.GreaterOrEqual(t, req.Handler, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, req.Middleware, 5*time.Millisecond)
+	assert.Equal(t, req.Duration, req.Handler+req.Middleware)
+}
@@ -0,0 +1,203 @@
+package router
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithInfo sets the title, description and version of the API in the spec
+// served by ServeSpec
+func (dr *DocRouter) WithInfo(title, description, version string) *DocRouter {
+	dr.info.title = title
+	dr.info.description = description
+	dr.info.version = version
+	return dr
+}
+
+// WithBuildInfo adds the build metadata of the running binary to the spec
+// served by ServeSpec (see BuildInfo); typically set from variables stamped
+// with -ldflags, so the spec's version tracks releases
+func (dr *DocRouter) WithBuildInfo(version, commit, buildTime string) *DocRouter {
+	dr.info.build = BuildInfo{Version: version, Commit: commit, Time: buildTime}
+	return dr
+}
+
+// WithContact sets the contact of the API in the spec served by ServeSpec;
+// any of the name, email and URL may be empty
+func (dr *DocRouter) WithContact(name, email, url string) *DocRouter {
+	dr.info.contact = &Contact{Name: name, Email: email, URL: url}
+	return dr
+}
+
+// WithLicense sets the license of the API in the spec served by ServeSpec
+func (dr *DocRouter) WithLicense(name, url string) *DocRouter {
+	if name == "" {
+		panic(fmt.Sprintf("router: license %q without a name", url))
+	}
+
+	dr.info.license = &License{Name: name, URL: url}
+	return dr
+}
+
+// WithTermsOfService sets the URL of the API's terms of service in the spec
+// served by ServeSpec
+func (dr *DocRouter) WithTermsOfService(url string) *DocRouter {
+	dr.info.termsOfService = url
+	return dr
+}
+
+// specInfo is the info section of the served spec
+type specInfo struct {
+	title          string
+	description    string
+	version        string
+	build          BuildInfo
+	contact        *Contact
+	license        *License
+	termsOfService string
+}
+
+// Contact is who to reach about the API, documented in the spec's info
+type Contact struct {
+	Name  string // Person or organization
+	Email string // Email address
+	URL   string // Web page, e.g. a support form
+}
+
+// object returns the info.contact object
+func (c Contact) object() map[string]any {
+	contact := map[string]any{}
+	for key, value := range map[string]string{"name": c.Name, "email": c.Email, "url": c.URL} {
+		if value != "" {
+			contact[key] = value
+		}
+	}
+	return contact
+}
+
+// License is the license the API is provided under, documented in the
+// spec's info
+type License struct {
+	Name string // License name, e.g. "Apache 2.0"
+	URL  string // Full text of the license (optional)
+}
+
+// object returns the info.license object
+func (l License) object() map[string]any {
+	license := map[string]any{
+		"name": l.Name,
+	}
+	if l.URL != "" {
+		license["url"] = l.URL
+	}
+	return license
+}
+
+// BuildInfo is the build metadata of a release. A version replaces the
+// spec's info.version, and the metadata is emitted as info.x-build when a
+// commit or build time is known.
+type BuildInfo struct {
+	Version string // Released version, e.g. "v1.4.2"
+	Commit  string // VCS revision the release was built from
+	Time    string // Build time, e.g. in RFC 3339
+}
+
+// extension returns the x-build object, or nil without a commit or time
+func (b BuildInfo) extension() map[string]any {
+	if b.Commit == "" && b.Time == "" {
+		return nil
+	}
+
+	build := map[string]any{}
+	for key, value := range map[string]string{"version": b.Version, "commit": b.Commit, "time": b.Time} {
+		if value != "" {
+			build[key] = value
+		}
+	}
+	return build
+}
+
+// SpecTransform rewrites a generated spec, returning the spec to use; it may
+// modify the spec in place and return it
+type SpecTransform func(spec map[string]any) map[string]any
+
+// WithSpecTransform adds a transform applied to the router's spec after it's
+// generated, in the order added, e.g. to inject custom sections, rewrite
+// operation IDs or strip fields the generator emits
+func (dr *DocRouter) WithSpecTransform(transform SpecTransform) *DocRouter {
+	dr.specTransforms = append(dr.specTransforms, transform)
+	return dr
+}
+
+// OpenAPI creates a generator for the router's routes and info
+func (dr *DocRouter) OpenAPI() *OpenAPIGenerator {
+	generator := NewOpenAPIGenerator(dr.info.title, dr.info.description, dr.info.version, dr.GetRoutes())
+	generator.BuildInfo = dr.info.build
+	generator.Contact = dr.info.contact
+	generator.License = dr.info.license
+	generator.TermsOfService = dr.info.termsOfService
+	if dr.cors != nil && dr.cors.Document {
+		generator.CORS = dr.cors
+	}
+	if dr.compression != nil && dr.compression.Document {
+		generator.Compression = dr.compression
+	}
+	generator.SecuritySchemes = dr.allSecuritySchemes()
+	generator.ExternalDocs = dr.externalDocs
+	generator.Tags = dr.allTags()
+	generator.Servers = dr.servers
+	generator.OperationIDFunc = dr.operationIDFunc
+	generator.Fragments = slices.Clone(dr.fragments)
+	generator.Transforms = slices.Clone(dr.specTransforms)
+	return generator
+}
+
+// ServeSpec serves the OpenAPI spec of the router as JSON under the path. The
+// spec is generated on the first request, so routes registered after calling
+// ServeSpec are included, and served with an ETag for clients to revalidate
+// their copy. The path itself is not documented.
+func (dr *DocRouter) ServeSpec(path string) *DocRouter {
+	var handler http.Handler = &specHandler{router: dr}
+	handler = ipFilterMiddleware(dr, nil, handler)
+	handler = transportMiddleware(dr, nil, handler)
+	handler = securityHeadersMiddleware(dr, nil, handler)
+
+	dr.mux.Handle("GET "+path, handler)
+	return dr
+}
+
+// specHandler serves the spec generated on its first request
+type specHandler struct {
+	router *DocRouter
+	once   sync.Once
+	spec   []byte
+	etag   string
+	err    error
+}
+
+// ServeHTTP serves the spec, responding 304 to requests with a matching
+// If-None-Match header
+func (h *specHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(func() {
+		h.spec, h.err = json.Marshal(h.router.OpenAPI().Generate())
+		sum := sha256.Sum256(h.spec)
+		h.etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	})
+	if h.err != nil {
+		writeControllerJSON(w, controllerError{Error: "spec generation failed", RequestID: RequestID(r.Context())},
+			http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("ETag", h.etag)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(h.spec))
+}
@@ -0,0 +1,242 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeSpec(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().
+		WithInfo("Test API", "API for testing", "1.2.3").
+		ServeSpec("/openapi.json")
+	// routes registered after ServeSpec are part of the spec
+	r.Route("GET", "/users", noop).WithName("List Users").Register()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, map[string]any{"title": "Test API", "description": "API for testing", "version": "1.2.3"}, spec["info"])
+	assert.Contains(t, spec["paths"], "/users")
+	assert.NotContains(t, spec["paths"], "/openapi.json")
+
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestServeSpecGenerationFailure(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter().
+		WithInfo("Test API", "", "1.0.0").
+		WithSpecTransform(func(spec map[string]any) map[string]any {
+			spec["x-unencodable"] = func() {}
+			return spec
+		}).
+		ServeSpec("/openapi.json")
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error": "spec generation failed", "request_id": "req-1"}`, rec.Body.String())
+}
+
+func TestBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		build BuildInfo
+		want  map[string]any
+	}{
+		"none": {
+			want: map[string]any{"title": "Test API", "description": "", "version": "1.0.0"},
+		},
+		"version only": {
+			build: BuildInfo{Version: "v1.4.2"},
+			want:  map[string]any{"title": "Test API", "description": "", "version": "v1.4.2"},
+		},
+		"release": {
+			build: BuildInfo{Version: "v1.4.2", Commit: "9f2c1e7", Time: "2024-05-01T10:00:00Z"},
+			want: map[string]any{
+				"title":       "Test API",
+				"description": "",
+				"version":     "v1.4.2",
+				"x-build":     map[string]any{"version": "v1.4.2", "commit": "9f2c1e7", "time": "2024-05-01T10:00:00Z"},
+			},
+		},
+		"unversioned build": {
+			build: BuildInfo{Commit: "9f2c1e7"},
+			want: map[string]any{
+				"title":       "Test API",
+				"description": "",
+				"version":     "1.0.0",
+				"x-build":     map[string]any{"commit": "9f2c1e7"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter().
+				WithBuildInfo(tc.build.Version, tc.build.Commit, tc.build.Time).
+				WithInfo("Test API", "", "1.0.0")
+
+			assert.Equal(t, tc.want, r.OpenAPI().Generate()["info"])
+		})
+	}
+}
+
+func TestInfoMetadata(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter().
+		WithInfo("Test API", "", "1.0.0").
+		WithContact("API Team", "api@example.com", "").
+		WithLicense("Apache 2.0", "https://www.apache.org/licenses/LICENSE-2.0.html").
+		WithTermsOfService("https://example.com/terms")
+
+	assert.Equal(t, map[string]any{
+		"title":       "Test API",
+		"description": "",
+		"version":     "1.0.0",
+		"contact":     map[string]any{"name": "API Team", "email": "api@example.com"},
+		"license": map[string]any{
+			"name": "Apache 2.0",
+			"url":  "https://www.apache.org/licenses/LICENSE-2.0.html",
+		},
+		"termsOfService": "https://example.com/terms",
+	}, r.OpenAPI().Generate()["info"])
+
+	assert.PanicsWithValue(t, `router: license "https://example.com/license" without a name`, func() {
+		NewDocRouter().WithLicense("", "https://example.com/license")
+	})
+}
+
+func TestSpecTransform(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().
+		WithInfo("Test API", "", "1.0.0").
+		WithSpecTransform(func(spec map[string]any) map[string]any {
+			spec["x-generated-by"] = "internal tooling"
+			return spec
+		}).
+		WithSpecTransform(func(spec map[string]any) map[string]any {
+			for _, item := range spec["paths"].(map[string]any) {
+				for _, operation := range item.(map[string]any) {
+					operation := operation.(map[string]any)
+					operation["operationId"] = "v1_" + operation["operationId"].(string)
+					delete(operation, "summary")
+				}
+			}
+			return spec
+		}).
+		WithSpecTransform(func(spec map[string]any) map[string]any {
+			// transforms see the result of the previous ones
+			spec["x-generated-by"] = spec["x-generated-by"].(string) + ", reviewed"
+			return spec
+		}).
+		ServeSpec("/openapi.json")
+	r.Route("GET", "/users", noop).WithName("List Users").WithOperationID("listUsers").Register()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, "internal tooling, reviewed", spec["x-generated-by"])
+
+	operation := spec["paths"].(map[string]any)["/users"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "v1_listUsers", operation["operationId"])
+	assert.NotContains(t, operation, "summary")
+}
+
+func TestSpecTransformGenerateTwice(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter().WithSpecTransform(func(spec map[string]any) map[string]any {
+		// rename the schemas and annotate them, and the inline response
+		// schemas, in place
+		schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+		var names []string
+		for name := range schemas {
+			names = append(names, name)
+		}
+		for _, name := range names {
+			schemas[name].(map[string]any)["x-reviewed"] = true
+			schemas["Renamed"+name] = schemas[name]
+			delete(schemas, name)
+		}
+		for _, item := range spec["paths"].(map[string]any) {
+			for _, operation := range item.(map[string]any) {
+				schema := responseSchema(operation.(map[string]any))
+				reviews, _ := schema["x-reviews"].(int)
+				schema["x-reviews"] = reviews + 1
+			}
+		}
+		return spec
+	})
+	r.Route("GET", "/users", noop).WithName("List Users").WithResponse([]UserResponse{}).Register()
+	r.Route("GET", "/admins", noop).WithName("List Admins").WithResponse([]UserResponse{}).Register()
+	r.Route("GET", "/users/{id}", noop).WithName("Get User").WithResponse(UserResponse{}).Register()
+
+	generator := r.OpenAPI()
+	encode := func(spec map[string]any) string {
+		data, err := json.Marshal(spec)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	first := generator.Generate()
+	encoded := encode(first)
+	second := generator.Generate()
+
+	assert.Equal(t, encoded, encode(second), "generating again gives the same spec")
+	assert.Equal(t, encoded, encode(first), "generating again leaves the previous spec unchanged")
+
+	schemas := second["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Contains(t, schemas, "RenamedUserResponse")
+	assert.Equal(t, true, schemas["RenamedUserResponse"].(map[string]any)["x-reviewed"])
+
+	// the operations share the inline []UserResponse schema
+	for _, path := range []string{"/users", "/admins"} {
+		operation := second["paths"].(map[string]any)[path].(map[string]any)["get"].(map[string]any)
+		assert.Equal(t, 1, responseSchema(operation)["x-reviews"], path)
+	}
+}
+
+// responseSchema returns the schema of an operation's JSON 200 response
+func responseSchema(operation map[string]any) map[string]any {
+	response := operation["responses"].(map[string]any)["200"].(map[string]any)
+	content := response["content"].(map[string]any)["application/json"].(map[string]any)
+	return content["schema"].(map[string]any)
+}
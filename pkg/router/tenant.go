@@ -0,0 +1,90 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+const (
+	// TenantParam is the path parameter holding the tenant ID
+	TenantParam = "tenantId"
+
+	// TenantPathPrefix is prepended to the path of tenant-scoped routes
+	TenantPathPrefix = "/tenants/{" + TenantParam + "}"
+
+	// tenantComponent is the name of the shared parameter component
+	tenantComponent = "TenantId"
+)
+
+// tenantIDPattern restricts tenant IDs to URL-safe identifiers
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// TenantValidator checks that a tenant ID refers to a known tenant
+type TenantValidator func(ctx context.Context, tenantID string) error
+
+// tenantKey is the context key under which the tenant ID is stored
+type tenantKey struct{}
+
+// TenantID returns the tenant ID of a tenant-scoped request, or an empty string
+func TenantID(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
+
+// WithTenantValidator sets the function used to validate tenant IDs on
+// tenant-scoped routes, in addition to the built-in format check
+func (dr *DocRouter) WithTenantValidator(validator TenantValidator) *DocRouter {
+	dr.tenantValidator = validator
+	return dr
+}
+
+// WithTenantScope places the route under TenantPathPrefix
+// (/tenants/{tenantId}/...) and stores the validated tenant ID in the
+// request context, where handlers can read it through TenantID
+func (rc *RouteConfig) WithTenantScope() *RouteConfig {
+	rc.tenantScoped = true
+	return rc
+}
+
+// tenantMiddleware extracts and validates the tenant ID before the handler runs
+func tenantMiddleware(dr *DocRouter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID := r.PathValue(TenantParam)
+
+		var msg *message
+		if !tenantIDPattern.MatchString(tenantID) {
+			msg = newMessage("must be 1-64 letters, digits, '-' or '_'")
+		} else if dr.tenantValidator != nil {
+			if err := dr.tenantValidator(r.Context(), tenantID); err != nil {
+				msg = textMessage(err.Error())
+			}
+		}
+
+		if msg != nil {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid tenant", []invalidValue{{
+				field: TenantParam,
+				in:    "path",
+				msg:   msg,
+			}})
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), tenantKey{}, tenantID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// tenantParameterComponent documents the shared tenant ID path parameter
+func tenantParameterComponent() map[string]any {
+	return map[string]any{
+		"name":        TenantParam,
+		"in":          "path",
+		"required":    true,
+		"description": "Identifier of the tenant owning the resource",
+		"schema": map[string]any{
+			"type":    "string",
+			"pattern": tenantIDPattern.String(),
+		},
+	}
+}
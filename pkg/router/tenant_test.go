@@ -0,0 +1,91 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantScope(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.WithTenantValidator(func(ctx context.Context, tenantID string) error {
+		if tenantID == "blocked" {
+			return errors.New("tenant is suspended")
+		}
+		return nil
+	})
+	r.Route("GET", "/todos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(TenantID(r.Context()) + "/" + r.PathValue("id")))
+	}).WithTenantScope().Register()
+
+	for name, tc := range map[string]struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		"tenant in context": {
+			path:       "/tenants/acme/todos/1",
+			wantStatus: http.StatusOK,
+			wantBody:   "acme/1",
+		},
+		"invalid format": {
+			path:       "/tenants/-acme/todos/1",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"field":"tenantId"`,
+		},
+		"rejected by validator": {
+			path:       "/tenants/blocked/todos/1",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "tenant is suspended",
+		},
+		"unscoped path": {
+			path:       "/todos/1",
+			wantStatus: http.StatusNotFound,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.wantBody)
+		})
+	}
+}
+
+func TestTenantScopeDocumentation(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Route("GET", "/todos/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+		WithTenantScope().
+		Register()
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes())
+	spec := generator.Generate()
+
+	pathItem, ok := spec["paths"].(map[string]any)["/tenants/{tenantId}/todos/{id}"].(map[string]any)
+	require.True(t, ok, "tenant-prefixed path should be documented")
+
+	params := pathItem["get"].(map[string]any)["parameters"].([]any)
+	require.Len(t, params, 2)
+	assert.Equal(t, map[string]any{"$ref": "#/components/parameters/TenantId"}, params[0])
+	assert.Equal(t, "id", params[1].(map[string]any)["name"])
+
+	components := spec["components"].(map[string]any)
+	expected := map[string]any{"TenantId": tenantParameterComponent()}
+	if diff := cmp.Diff(expected, components["parameters"]); diff != "" {
+		t.Errorf("parameter components mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,100 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TransportPolicy describes the transport a route must be served over. The
+// same policy is enforced on requests and documented under the x-transport
+// extension of the route's operation.
+type TransportPolicy struct {
+	HTTPSOnly             bool          // Redirect GET and HEAD requests made over plain HTTP to HTTPS, reject others
+	MutualTLS             bool          // Require a verified client certificate
+	HSTSMaxAge            time.Duration // Send Strict-Transport-Security on HTTPS responses (zero disables it)
+	HSTSIncludeSubdomains bool          // Extend HSTS to subdomains
+	TrustForwardedProto   bool          // Treat requests with X-Forwarded-Proto: https as HTTPS, e.g. behind a TLS-terminating proxy
+}
+
+// WithTransportPolicy sets the transport policy of every route that doesn't
+// set its own
+func (dr *DocRouter) WithTransportPolicy(policy TransportPolicy) *DocRouter {
+	dr.transport = &policy
+	return dr
+}
+
+// WithTransportPolicy sets the transport policy of the route, replacing the
+// router's policy
+func (rc *RouteConfig) WithTransportPolicy(policy TransportPolicy) *RouteConfig {
+	rc.transport = &policy
+	return rc
+}
+
+// hstsHeader returns the Strict-Transport-Security value of the policy
+func (p TransportPolicy) hstsHeader() string {
+	value := fmt.Sprintf("max-age=%d", int64(p.HSTSMaxAge/time.Second))
+	if p.HSTSIncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	return value
+}
+
+// secure reports whether the request reached the application over HTTPS
+func (p TransportPolicy) secure(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return p.TrustForwardedProto && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// transportMiddleware enforces the route's transport policy, falling back to
+// the router's policy at request time
+func transportMiddleware(dr *DocRouter, policy *TransportPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := policy
+		if p == nil {
+			p = dr.transport
+		}
+		if p == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		secure := p.secure(r)
+		if p.HTTPSOnly && !secure {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				target := "https://" + r.Host + r.URL.RequestURI()
+				http.Redirect(w, r, target, http.StatusPermanentRedirect)
+				return
+			}
+			writeValidationError(w, r, http.StatusForbidden, "https required", nil)
+			return
+		}
+
+		// client certificates are only visible when TLS terminates here
+		if p.MutualTLS && (r.TLS == nil || len(r.TLS.PeerCertificates) == 0) {
+			writeValidationError(w, r, http.StatusForbidden, "client certificate required", nil)
+			return
+		}
+
+		if secure && p.HSTSMaxAge > 0 {
+			w.Header().Set("Strict-Transport-Security", p.hstsHeader())
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// transportExtension documents a transport policy under x-transport
+func transportExtension(p TransportPolicy) map[string]any {
+	extension := map[string]any{
+		"httpsOnly": p.HTTPSOnly,
+		"mutualTLS": p.MutualTLS,
+	}
+	if p.HSTSMaxAge > 0 {
+		extension["hsts"] = p.hstsHeader()
+	}
+	return extension
+}
@@ -0,0 +1,130 @@
+package router
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportPolicy(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		policy       TransportPolicy
+		method       string
+		tls          *tls.ConnectionState
+		header       http.Header
+		wantStatus   int
+		wantLocation string
+		wantHSTS     string
+	}{
+		"no restrictions": {
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+		},
+		"redirect get": {
+			policy:       TransportPolicy{HTTPSOnly: true},
+			method:       http.MethodGet,
+			wantStatus:   http.StatusPermanentRedirect,
+			wantLocation: "https://example.com/things?page=2",
+		},
+		"reject post": {
+			policy:     TransportPolicy{HTTPSOnly: true},
+			method:     http.MethodPost,
+			wantStatus: http.StatusForbidden,
+		},
+		"https with hsts": {
+			policy:     TransportPolicy{HTTPSOnly: true, HSTSMaxAge: 24 * time.Hour, HSTSIncludeSubdomains: true},
+			method:     http.MethodGet,
+			tls:        &tls.ConnectionState{},
+			wantStatus: http.StatusOK,
+			wantHSTS:   "max-age=86400; includeSubDomains",
+		},
+		"untrusted forwarded proto": {
+			policy:       TransportPolicy{HTTPSOnly: true},
+			method:       http.MethodGet,
+			header:       http.Header{"X-Forwarded-Proto": {"https"}},
+			wantStatus:   http.StatusPermanentRedirect,
+			wantLocation: "https://example.com/things?page=2",
+		},
+		"trusted forwarded proto": {
+			policy:     TransportPolicy{HTTPSOnly: true, TrustForwardedProto: true, HSTSMaxAge: time.Hour},
+			method:     http.MethodGet,
+			header:     http.Header{"X-Forwarded-Proto": {"https"}},
+			wantStatus: http.StatusOK,
+			wantHSTS:   "max-age=3600",
+		},
+		"missing client certificate": {
+			policy:     TransportPolicy{MutualTLS: true},
+			method:     http.MethodGet,
+			tls:        &tls.ConnectionState{},
+			wantStatus: http.StatusForbidden,
+		},
+		"client certificate": {
+			policy:     TransportPolicy{MutualTLS: true},
+			method:     http.MethodGet,
+			tls:        &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}},
+			wantStatus: http.StatusOK,
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			r.Route(tc.method, "/things", func(w http.ResponseWriter, r *http.Request) {}).
+				WithTransportPolicy(tc.policy).
+				Register()
+
+			req := httptest.NewRequest(tc.method, "http://example.com/things?page=2", nil)
+			req.TLS = tc.tls
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, tc.wantLocation, rec.Header().Get("Location"))
+			assert.Equal(t, tc.wantHSTS, rec.Header().Get("Strict-Transport-Security"))
+		})
+	}
+}
+
+func TestTransportPolicyDocumentation(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "/things", noop).Register()
+	r.Route("POST", "/things", noop).
+		WithTransportPolicy(TransportPolicy{HTTPSOnly: true, MutualTLS: true}).
+		Register()
+	r.WithTransportPolicy(TransportPolicy{HTTPSOnly: true, HSTSMaxAge: time.Hour})
+
+	// the router's policy applies to routes registered before it was set
+	req := httptest.NewRequest(http.MethodGet, "/things", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+
+	paths := NewOpenAPIGenerator("Test", "", "1.0.0", r.GetRoutes()).Generate()["paths"].(map[string]any)
+	things := paths["/things"].(map[string]any)
+
+	assert.Equal(t, map[string]any{
+		"httpsOnly": true,
+		"mutualTLS": false,
+		"hsts":      "max-age=3600",
+	}, things["get"].(map[string]any)["x-transport"])
+	assert.Equal(t, map[string]any{
+		"httpsOnly": true,
+		"mutualTLS": true,
+	}, things["post"].(map[string]any)["x-transport"])
+}
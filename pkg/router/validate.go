@@ -0,0 +1,136 @@
+package router
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// validatePatterns are the patterns of go-playground/validator rules checking
+// the characters of a string
+var validatePatterns = map[string]string{
+	"alpha":    "^[a-zA-Z]+$",
+	"alphanum": "^[a-zA-Z0-9]+$",
+	"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":   "^[0-9]+$",
+}
+
+// validateFormats are the schema formats of go-playground/validator rules
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// lengthKeywords are the keywords bounding the size of non-numeric schemas,
+// by schema type
+var lengthKeywords = map[string][2]string{
+	"string": {"minLength", "maxLength"},
+	"array":  {"minItems", "maxItems"},
+	"object": {"minProperties", "maxProperties"},
+}
+
+// validateRule is a rule of a go-playground/validator tag, e.g. max=64
+type validateRule struct {
+	name  string
+	param string
+}
+
+// validateRules splits a go-playground/validator tag into its rules. Rules
+// after "dive" apply to the items of a collection and are left out, as are
+// alternatives joined with "|".
+func validateRules(field reflect.StructField) []validateRule {
+	var rules []validateRule
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if rule == "dive" {
+			break
+		}
+		if rule == "" || strings.Contains(rule, "|") {
+			continue
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		rules = append(rules, validateRule{name: name, param: param})
+	}
+	return rules
+}
+
+// validateRequired reports whether the go-playground/validator tag of a field
+// marks it as required
+func validateRequired(field reflect.StructField) bool {
+	for _, rule := range validateRules(field) {
+		if rule.name == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// addValidateConstraints translates the rules of a go-playground/validator
+// tag (e.g. `validate:"required,email,max=64"`) into schema keywords. min,
+// max and len bound the value of numbers and the length of strings, arrays
+// and maps. Unknown rules are ignored.
+func addValidateConstraints(schema map[string]any, field reflect.StructField) {
+	numeric := schema["type"] == "integer" || schema["type"] == "number"
+
+	schemaType, _ := schema["type"].(string)
+	lengths, sized := lengthKeywords[schemaType]
+	if schemaType == "object" && schema["additionalProperties"] == nil {
+		sized = false
+	}
+
+	// bound sets the lower (0) or upper (1) bound of the schema
+	bound := func(side int, param string, exclusive bool) {
+		switch {
+		case numeric:
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return
+			}
+			keyword := [2]string{"minimum", "maximum"}[side]
+			schema[keyword] = n
+			if exclusive {
+				schema["exclusive"+strings.ToUpper(keyword[:1])+keyword[1:]] = true
+			}
+		case sized && !exclusive:
+			if n, err := strconv.Atoi(param); err == nil && n >= 0 {
+				schema[lengths[side]] = n
+			}
+		}
+	}
+
+	for _, rule := range validateRules(field) {
+		switch rule.name {
+		case "min", "gte":
+			bound(0, rule.param, false)
+		case "max", "lte":
+			bound(1, rule.param, false)
+		case "len":
+			bound(0, rule.param, false)
+			bound(1, rule.param, false)
+		case "gt":
+			bound(0, rule.param, true)
+		case "lt":
+			bound(1, rule.param, true)
+		case "oneof":
+			target := schema
+			if items, ok := schema["items"].(map[string]any); ok && schemaType == "array" {
+				target = items
+			}
+			target["enum"] = typedEnum(target["type"], strings.Fields(rule.param))
+		default:
+			if format, ok := validateFormats[rule.name]; ok {
+				schema["format"] = format
+			}
+			if pattern, ok := validatePatterns[rule.name]; ok {
+				schema["pattern"] = pattern
+			}
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidationError describes a single request value that failed validation
+type ValidationError struct {
+	Field   string `json:"field" doc:"Name of the invalid parameter or field" example:"limit"`
+	In      string `json:"in" doc:"Location of the invalid value" example:"query" enum:"query,header,path,body"`
+	Code    string `json:"code" doc:"Code of the failure, the same in every language so clients can branch on it" example:"type" enum:"required,not_repeated,not_null,type,format,enum,pattern,min_length,max_length,min_items,max_items,minimum,exclusive_minimum,maximum,exclusive_maximum,pointer,not_allowed,invalid"`
+	Message string `json:"message" doc:"Why the value was rejected, in the language requested through Accept-Language when the API translates it" example:"must be an integer"`
+}
+
+// invalidValue is a request value that failed validation, with the message
+// saying why, formatted when the error response is written
+type invalidValue struct {
+	field string
+	in    string
+	msg   *message
+}
+
+// validationError formats the invalid value's message, translated with the
+// catalog if any
+func (v invalidValue) validationError(catalog MessageCatalog) ValidationError {
+	return ValidationError{Field: v.field, In: v.in, Code: v.msg.code, Message: v.msg.format(catalog)}
+}
+
+// ValidationErrorResponse is written when a request fails validation
+type ValidationErrorResponse struct {
+	Error     string            `json:"error" doc:"Error message" example:"invalid request parameters"`
+	Errors    []ValidationError `json:"errors" doc:"Individual validation failures"`
+	RequestID string            `json:"request_id,omitempty" doc:"ID of the request, also sent in the X-Request-ID header, to correlate the error with logs" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+}
+
+// parameterSchema builds the JSON Schema of a parameter including its constraints
+func parameterSchema(param Parameter) map[string]any {
+	schema := jsonSchema(param.Schema)
+	if schema == nil {
+		schema = map[string]any{"type": "string"}
+	}
+
+	// constraints apply to the items of array parameters
+	target := schema
+	if items, ok := schema["items"].(map[string]any); ok && schema["type"] == "array" {
+		target = items
+	}
+
+	if len(param.Enum) > 0 {
+		target["enum"] = typedEnum(target["type"], param.Enum)
+	}
+	if param.Minimum != nil {
+		target["minimum"] = *param.Minimum
+	}
+	if param.Maximum != nil {
+		target["maximum"] = *param.Maximum
+	}
+	if param.Pattern != "" {
+		target["pattern"] = param.Pattern
+	}
+	if param.MaxItems > 0 && schema["type"] == "array" {
+		schema["maxItems"] = param.MaxItems
+	}
+
+	return schema
+}
+
+// validateQuery wraps a handler so that query parameters are checked against
+// their declared schemas before the handler runs
+func validateQuery(params []Parameter, next http.Handler) http.Handler {
+	var queryParams []Parameter
+	schemas := map[string]map[string]any{}
+	for _, param := range params {
+		if param.In != "query" {
+			continue
+		}
+		queryParams = append(queryParams, param)
+		schemas[param.Name] = parameterSchema(param)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var errs []invalidValue
+		for _, param := range queryParams {
+			schema := schemas[param.Name]
+
+			var msg *message
+			switch schema["type"] {
+			case "object":
+				object := QueryObject(r, param)
+				if len(object) == 0 {
+					if param.Required {
+						msg = newMessage("is required")
+					}
+					break
+				}
+				msg = validateObject(object, schema)
+			case "array":
+				if _, exists := query[param.Name]; !exists {
+					if param.Required {
+						msg = newMessage("is required")
+					}
+					break
+				}
+				msg = validateArray(QueryArray(r, param), schema)
+			default:
+				values, exists := query[param.Name]
+				if !exists {
+					if param.Required {
+						msg = newMessage("is required")
+					}
+					break
+				}
+				if len(values) > 1 {
+					msg = newMessage("must not be repeated")
+					break
+				}
+				msg = validateValue(values[0], schema)
+			}
+
+			if msg != nil {
+				errs = append(errs, invalidValue{field: param.Name, in: "query", msg: msg})
+			}
+		}
+
+		if len(errs) > 0 {
+			writeValidationError(w, r, http.StatusBadRequest, "invalid request parameters", errs)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateArray checks the items of an array parameter against the items schema,
+// returning a message describing the first violation or nil when valid
+func validateArray(items []string, schema map[string]any) *message {
+	if msg := checkCount(len(items), "items", nil, schema["maxItems"]); msg != nil {
+		return msg
+	}
+
+	itemSchema, _ := schema["items"].(map[string]any)
+	for _, item := range items {
+		if msg := validateValue(item, itemSchema); msg != nil {
+			return contextMessage("item '%s' %s", item, msg)
+		}
+	}
+	return nil
+}
+
+// validateObject checks the properties of an object parameter against the
+// declared property schemas (or additionalProperties for maps)
+func validateObject(object map[string]string, schema map[string]any) *message {
+	properties, _ := schema["properties"].(map[string]any)
+	additional, _ := schema["additionalProperties"].(map[string]any)
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		propertySchema, declared := properties[key].(map[string]any)
+		if !declared {
+			if additional == nil {
+				return newMessage("property '%s' is not allowed", key)
+			}
+			propertySchema = additional
+		}
+
+		if msg := validateValue(object[key], propertySchema); msg != nil {
+			return contextMessage("property '%s' %s", key, msg)
+		}
+	}
+	return nil
+}
+
+// validateValue coerces a raw value to the schema type and checks its constraints
+func validateValue(value string, schema map[string]any) *message {
+	if schema == nil {
+		return nil
+	}
+
+	switch schema["type"] {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return newMessage("must be an integer")
+		}
+		if msg := checkRange(float64(n), schema); msg != nil {
+			return msg
+		}
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return newMessage("must be a number")
+		}
+		if msg := checkRange(n, schema); msg != nil {
+			return msg
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return newMessage("must be a boolean")
+		}
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return newMessage("must be an RFC 3339 date-time")
+			}
+		case "date":
+			if _, err := time.Parse(DateLayout, value); err != nil {
+				return newMessage("must be a date (YYYY-MM-DD)")
+			}
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		if values := enumStrings(enum); !slices.Contains(values, value) {
+			return newMessage("must be one of: %s", strings.Join(values, ", "))
+		}
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := compilePattern(pattern); err == nil && !re.MatchString(value) {
+			return newMessage("must match pattern %s", pattern)
+		}
+	}
+
+	return nil
+}
+
+// patterns caches the compiled patterns of validated values
+var patterns sync.Map
+
+// compilePattern compiles a schema pattern, reusing earlier compilations
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// checkRange verifies a number against the schema's minimum and maximum
+func checkRange(n float64, schema map[string]any) *message {
+	if minimum, ok := schema["minimum"].(float64); ok {
+		if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive && n <= minimum {
+			return newMessage("must be greater than %v", minimum)
+		}
+		if n < minimum {
+			return newMessage("must be greater than or equal to %v", minimum)
+		}
+	}
+	if maximum, ok := schema["maximum"].(float64); ok {
+		if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive && n >= maximum {
+			return newMessage("must be less than %v", maximum)
+		}
+		if n > maximum {
+			return newMessage("must be less than or equal to %v", maximum)
+		}
+	}
+	return nil
+}
+
+// writeValidationError writes a structured validation error response
+func writeValidationError(w http.ResponseWriter, r *http.Request, statusCode int, summary string, errs []invalidValue) {
+	language, catalog := requestCatalog(r)
+	if translation, ok := catalog[summary]; ok {
+		summary = translation
+	}
+
+	validationErrors := make([]ValidationError, len(errs))
+	for i, err := range errs {
+		validationErrors[i] = err.validationError(catalog)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if catalog != nil {
+		w.Header().Set("Content-Language", language)
+	}
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ValidationErrorResponse{
+		Error:     summary,
+		Errors:    validationErrors,
+		RequestID: RequestID(r.Context()),
+	})
+}
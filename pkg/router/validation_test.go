@@ -0,0 +1,164 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryValidation(t *testing.T) {
+	t.Parallel()
+
+	minimum, maximum := 1.0, 100.0
+	explode := false
+	params := []Parameter{
+		{Name: "limit", In: "query", Schema: 0, Minimum: &minimum, Maximum: &maximum},
+		{Name: "status", In: "query", Schema: "", Enum: []string{"open", "done"}},
+		{Name: "fields", In: "query", Schema: []string{}, Enum: []string{"id", "title"}, Style: StyleForm, Explode: &explode, MaxItems: 2},
+		{Name: "verbose", In: "query", Schema: true, Required: true},
+		{Name: "filter", In: "query", Schema: map[string]bool{}, Style: StyleDeepObject},
+		{Name: "since", In: "query", Schema: time.Time{}},
+		{Name: "priority", In: "query", Schema: 0, Enum: []string{"1", "2", "3"}},
+	}
+
+	for name, tc := range map[string]struct {
+		query      string
+		wantStatus int
+		wantErrors []ValidationError
+	}{
+		"valid": {
+			query:      "?limit=10&status=open&fields=id,title&verbose=true&since=2023-01-01T00:00:00Z",
+			wantStatus: http.StatusOK,
+		},
+		"missing required": {
+			query:      "?limit=10",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "verbose", In: "query", Code: CodeRequired, Message: "is required"},
+			},
+		},
+		"type coercion failure": {
+			query:      "?limit=ten&verbose=yes",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "limit", In: "query", Code: CodeType, Message: "must be an integer"},
+				{Field: "verbose", In: "query", Code: CodeType, Message: "must be a boolean"},
+			},
+		},
+		"out of range": {
+			query:      "?limit=500&verbose=1",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "limit", In: "query", Code: CodeMaximum, Message: "must be less than or equal to 100"},
+			},
+		},
+		"deep object": {
+			query:      "?verbose=1&filter[completed]=true&filter[archived]=maybe",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "filter", In: "query", Code: CodeType, Message: "property 'archived' must be a boolean"},
+			},
+		},
+		"date-time format": {
+			query:      "?verbose=1&since=yesterday",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "since", In: "query", Code: CodeFormat, Message: "must be an RFC 3339 date-time"},
+			},
+		},
+		"integer enum violation": {
+			query:      "?verbose=1&priority=4",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "priority", In: "query", Code: CodeEnum, Message: "must be one of: 1, 2, 3"},
+			},
+		},
+		"too many items": {
+			query:      "?verbose=1&fields=id,title,id",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "fields", In: "query", Code: CodeMaxItems, Message: "must have at most 2 items"},
+			},
+		},
+		"enum violations": {
+			query:      "?status=archived&fields=id,secret&verbose=1",
+			wantStatus: http.StatusBadRequest,
+			wantErrors: []ValidationError{
+				{Field: "status", In: "query", Code: CodeEnum, Message: "must be one of: open, done"},
+				{Field: "fields", In: "query", Code: CodeEnum, Message: "item 'secret' must be one of: id, title"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			r.Route("GET", "/items", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}).
+				WithParameter(params[0]).
+				WithParameter(params[1]).
+				WithParameter(params[2]).
+				WithParameter(params[3]).
+				WithParameter(params[4]).
+				WithParameter(params[5]).
+				WithParameter(params[6]).
+				WithQueryValidation().
+				Register()
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items"+tc.query, nil))
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantErrors == nil {
+				return
+			}
+
+			var resp ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, "invalid request parameters", resp.Error)
+
+			if diff := cmp.Diff(tc.wantErrors, resp.Errors); diff != "" {
+				t.Errorf("validation errors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryValidationDocumentation(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	r.Route("GET", "/items", func(w http.ResponseWriter, r *http.Request) {}).
+		WithQueryParam("q", "Search query", "").
+		WithQueryValidation().
+		Register()
+
+	generator := NewOpenAPIGenerator("Test API", "API for testing", "1.0.0", r.GetRoutes())
+	spec := generator.Generate()
+
+	getOp := spec["paths"].(map[string]any)["/items"].(map[string]any)["get"].(map[string]any)
+	responses := getOp["responses"].(map[string]any)
+
+	resp400, ok := responses["400"].(map[string]any)
+	require.True(t, ok, "400 response should be documented")
+
+	expected := map[string]any{
+		"description": "invalid request parameters",
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/ValidationErrorResponse"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, resp400); diff != "" {
+		t.Errorf("400 response mismatch (-want +got):\n%s", diff)
+	}
+}
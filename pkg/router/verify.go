@@ -0,0 +1,103 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// TypedHandler is implemented by handlers that know the request and response
+// types they decode and encode, such as the handlers of controller methods.
+// Either type is nil when the handler reads no body or writes no body.
+type TypedHandler interface {
+	http.Handler
+	HandlerTypes() (request, response reflect.Type)
+}
+
+// VerificationIssue is a disagreement between a route's handler and its
+// documentation
+type VerificationIssue struct {
+	Route   string // Key of the route, e.g. "POST /users"
+	Message string // What disagrees
+}
+
+// String formats the issue for logs
+func (i VerificationIssue) String() string {
+	return i.Route + ": " + i.Message
+}
+
+// Verify cross-checks the documented request and response types of every
+// route registered with a TypedHandler against the types the handler actually
+// decodes and encodes. Routes with plain handlers carry no type information
+// and are skipped. Routes configured but never registered are reported too,
+// as a forgotten Register call silently drops the route. Mounted routers are
+// verified as well. Meant to run once at startup, after all routes are
+// registered, to warn or fail fast.
+func (dr *DocRouter) Verify() []VerificationIssue {
+	var issues []VerificationIssue
+
+	for _, rc := range dr.unregistered {
+		issues = append(issues, VerificationIssue{
+			Route:   RouteInfo{Method: rc.method, Path: rc.path, Version: rc.version}.Key(),
+			Message: "route is configured but Register was never called",
+		})
+	}
+
+	for _, route := range dr.routes {
+		if route.TypedHandler == nil {
+			continue
+		}
+
+		request, response := route.TypedHandler.HandlerTypes()
+		for _, message := range []string{
+			compareBodyTypes("request", documentedType(route.RequestType), derefType(request)),
+			compareBodyTypes("response", documentedType(route.ResponseType), derefType(response)),
+		} {
+			if message != "" {
+				issues = append(issues, VerificationIssue{Route: route.Key(), Message: message})
+			}
+		}
+	}
+
+	for _, m := range dr.mounts {
+		for _, issue := range m.router.Verify() {
+			method, path, _ := strings.Cut(issue.Route, " ")
+			issue.Route = method + " " + m.prefix + path
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// compareBodyTypes describes how a documented body type differs from the one
+// the handler uses, or returns an empty string if they agree
+func compareBodyTypes(body string, documented, actual reflect.Type) string {
+	switch {
+	case documented == actual:
+		return ""
+	case actual == nil:
+		return fmt.Sprintf("documents a %s body of type %s the handler never uses", body, documented)
+	case documented == nil:
+		return fmt.Sprintf("handler uses an undocumented %s body of type %s", body, actual)
+	default:
+		return fmt.Sprintf("documents a %s body of type %s but the handler uses %s", body, documented, actual)
+	}
+}
+
+// documentedType returns the type of a documented example value
+func documentedType(example any) reflect.Type {
+	if example == nil {
+		return nil
+	}
+	return derefType(reflect.TypeOf(example))
+}
+
+// derefType strips a pointer from a type
+func derefType(typ reflect.Type) reflect.Type {
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		return typ.Elem()
+	}
+	return typ
+}
@@ -0,0 +1,111 @@
+package router
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// typedTestHandler is a TypedHandler with fixed types
+type typedTestHandler struct {
+	request, response reflect.Type
+}
+
+func (typedTestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func (h typedTestHandler) HandlerTypes() (request, response reflect.Type) {
+	return h.request, h.response
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	userRequest := reflect.TypeOf(UserRequest{})
+	userResponse := reflect.TypeOf(&UserResponse{})
+
+	for name, tc := range map[string]struct {
+		handler      http.Handler
+		requestType  any
+		responseType any
+		want         []VerificationIssue
+	}{
+		"agreeing": {
+			handler:      typedTestHandler{request: userRequest, response: userResponse},
+			requestType:  &UserRequest{},
+			responseType: UserResponse{},
+		},
+		"plain handler": {
+			handler:      http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+			requestType:  &UserRequest{},
+			responseType: &UserList{},
+		},
+		"unused request body": {
+			handler:      typedTestHandler{response: userResponse},
+			requestType:  &UserRequest{},
+			responseType: &UserResponse{},
+			want: []VerificationIssue{{
+				Route:   "POST /users",
+				Message: "documents a request body of type router.UserRequest the handler never uses",
+			}},
+		},
+		"undocumented request body": {
+			handler:      typedTestHandler{request: userRequest, response: userResponse},
+			responseType: &UserResponse{},
+			want: []VerificationIssue{{
+				Route:   "POST /users",
+				Message: "handler uses an undocumented request body of type router.UserRequest",
+			}},
+		},
+		"different response": {
+			handler:      typedTestHandler{request: userRequest, response: userResponse},
+			requestType:  &UserRequest{},
+			responseType: &UserList{},
+			want: []VerificationIssue{{
+				Route:   "POST /users",
+				Message: "documents a response body of type router.UserList but the handler uses router.UserResponse",
+			}},
+		},
+	} {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewDocRouter()
+			r.Handle("POST", "/users", tc.handler).
+				WithRequest(tc.requestType).
+				WithResponse(tc.responseType).
+				Register()
+
+			assert.Equal(t, tc.want, r.Verify())
+		})
+	}
+}
+
+func TestVerifyController(t *testing.T) {
+	t.Parallel()
+
+	r := NewDocRouter()
+	require.NoError(t, r.RegisterController(userController{}))
+
+	assert.Empty(t, r.Verify())
+}
+
+func TestVerifyUnregistered(t *testing.T) {
+	t.Parallel()
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := NewDocRouter()
+	r.Route("GET", "/users", noop).WithName("List Users").Register()
+	r.Route("POST", "/users", noop).WithName("Create User")
+	r.Route("GET", "/users/{id}", noop).WithVersion("2")
+
+	assert.Equal(t, []VerificationIssue{
+		{Route: "POST /users", Message: "route is configured but Register was never called"},
+		{Route: "GET /users/{id} version 2", Message: "route is configured but Register was never called"},
+	}, r.Verify())
+	assert.Len(t, r.GetRoutes(), 1)
+}
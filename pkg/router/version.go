@@ -0,0 +1,112 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// versionDispatcher serves a single mux pattern, selecting between the
+// handlers registered for it based on the router's version header
+type versionDispatcher struct {
+	router   *DocRouter
+	fallback http.Handler            // handler registered without a version
+	versions map[string]http.Handler // handlers keyed by version
+	order    []string                // versions in registration order
+	routes   map[string]*RouteInfo   // routes keyed by version, "" for the unversioned one
+}
+
+// add registers a handler for a version, panicking on duplicates like ServeMux does
+func (d *versionDispatcher) add(pattern, version string, handler http.Handler, info *RouteInfo) {
+	if d.routes == nil {
+		d.routes = make(map[string]*RouteInfo)
+	}
+
+	if version == "" {
+		if d.fallback != nil {
+			panic(fmt.Sprintf("router: multiple registrations for %s", pattern))
+		}
+		d.fallback = handler
+		d.routes[version] = info
+		return
+	}
+
+	if d.versions == nil {
+		d.versions = make(map[string]http.Handler)
+	}
+	if _, exists := d.versions[version]; exists {
+		panic(fmt.Sprintf("router: multiple registrations for %s version %s", pattern, version))
+	}
+
+	d.versions[version] = handler
+	d.order = append(d.order, version)
+	d.routes[version] = info
+}
+
+// route returns the route serving requests without a version: the
+// unversioned one, or else the latest version, whatever the order the
+// versions were registered in
+func (d *versionDispatcher) route() *RouteInfo {
+	if info, ok := d.routes[""]; ok {
+		return info
+	}
+	return d.routes[d.latest()]
+}
+
+// latest returns the highest registered version, comparing dotted numeric
+// versions such as "2" or "v1.10" by their numbers and others, such as
+// dates, as text
+func (d *versionDispatcher) latest() string {
+	latest := d.order[0]
+	for _, version := range d.order[1:] {
+		if compareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// compareVersions compares two API versions, numerically when both are
+// dotted numeric versions
+func compareVersions(a, b string) int {
+	x, errA := parseClientVersion(a)
+	y, errB := parseClientVersion(b)
+	if errA == nil && errB == nil {
+		return compareClientVersions(x, y)
+	}
+	return strings.Compare(a, b)
+}
+
+// ServeHTTP dispatches to the requested version. Requests without a version
+// go to the unversioned handler, or to the latest version if there is none.
+func (d *versionDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// routes without versions ignore the header entirely
+	if len(d.versions) == 0 {
+		d.fallback.ServeHTTP(w, r)
+		return
+	}
+
+	header := d.router.versionHeader
+	version := r.Header.Get(header)
+
+	if version == "" {
+		if d.fallback != nil {
+			d.fallback.ServeHTTP(w, r)
+			return
+		}
+		d.versions[d.latest()].ServeHTTP(w, r)
+		return
+	}
+
+	handler, exists := d.versions[version]
+	if !exists {
+		writeValidationError(w, r, http.StatusBadRequest, "unsupported version", []invalidValue{{
+			field: header,
+			in:    "header",
+			msg:   newMessage("must be one of: %s", strings.Join(d.order, ", ")),
+		}})
+		return
+	}
+
+	handler.ServeHTTP(w, r)
+}